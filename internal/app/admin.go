@@ -0,0 +1,91 @@
+package app
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"strings"
+
+	"frappuccino/internal/config"
+	"frappuccino/internal/dto/auth"
+	"frappuccino/internal/server"
+	serviceAuth "frappuccino/internal/service/auth"
+	serviceStore "frappuccino/internal/service/store"
+)
+
+// createUser is the user create command. Without -password the password is read from the first
+// line of stdin, so it does not end up in the shell history.
+func createUser(cfg *config.Config, args []string) error {
+	flags := newFlagSet("user create")
+	username := flags.String("username", "", "login name (required)")
+	password := flags.String("password", "", "password; read from stdin when empty")
+	role := flags.String("role", "barista", "barista, shift_lead, manager or admin")
+	store := flags.String("store", "", "ID or code of the home store; empty lets the user work in any store")
+	if err := parseFlags(flags, args); err != nil {
+		return err
+	}
+	if *username == "" {
+		return fmt.Errorf("%w: -username is required", errUsage)
+	}
+	if *password == "" {
+		line, err := bufio.NewReader(os.Stdin).ReadString('\n')
+		if err != nil && line == "" {
+			return fmt.Errorf("read password from stdin: %w", err)
+		}
+		*password = strings.TrimRight(line, "\r\n")
+	}
+
+	return withRepositories(cfg, "", func(ctx context.Context, repos *server.Repositories, logger *slog.Logger) error {
+		authService, err := newAuthService(cfg, repos, logger)
+		if err != nil {
+			return err
+		}
+		id, err := authService.CreateUser(ctx, auth.CreateUserRequest{
+			Username: *username,
+			Password: *password,
+			Role:     *role,
+			Store:    *store,
+		})
+		if err != nil {
+			return err
+		}
+		fmt.Println(id)
+		return nil
+	})
+}
+
+// issueAPIKey is the apikey issue command; it prints the key, which cannot be shown again
+func issueAPIKey(cfg *config.Config, args []string) error {
+	flags := newFlagSet("apikey issue")
+	name := flags.String("name", "", "name of the integration (required)")
+	role := flags.String("role", "barista", "barista, shift_lead, manager or admin")
+	store := flags.String("store", "", "ID or code of the only store the key may work in")
+	if err := parseFlags(flags, args); err != nil {
+		return err
+	}
+
+	return withRepositories(cfg, "", func(ctx context.Context, repos *server.Repositories, logger *slog.Logger) error {
+		authService, err := newAuthService(cfg, repos, logger)
+		if err != nil {
+			return err
+		}
+		key, err := authService.CreateAPIKey(ctx, auth.CreateAPIKeyRequest{
+			Name:  *name,
+			Role:  *role,
+			Store: *store,
+		})
+		if err != nil {
+			return err
+		}
+		logger.Info("Issued API key", "key_id", key.KeyID, "name", key.Name, "role", key.Role)
+		fmt.Println(key.Key)
+		return nil
+	})
+}
+
+func newAuthService(cfg *config.Config, repos *server.Repositories, logger *slog.Logger) (*serviceAuth.AuthService, error) {
+	storeService := serviceStore.NewStoreService(repos.Store, logger)
+	return serviceAuth.NewAuthService(repos.Auth, storeService, cfg.Auth, logger)
+}
@@ -1,23 +1,34 @@
 package app
 
 import (
+	"errors"
+	"flag"
 	"fmt"
+	"os"
 
 	"frappuccino/internal/config"
-	"frappuccino/internal/server"
 )
 
-const cfgPath = "./config/config.json"
-
+// Start loads the configuration from the config file, the environment and the flags before the
+// command, then runs the subcommand named on the command line; without one it starts the server
 func Start() {
-	config, err := config.GetConfig(cfgPath)
-	if err != nil {
-		fmt.Println(err)
+	config, args, err := config.Load(os.Args[1:])
+	if errors.Is(err, flag.ErrHelp) {
+		printUsage(os.Stdout)
+		fmt.Println()
+		if err := printSettings(os.Stdout); err != nil {
+			fmt.Fprintln(os.Stderr, "Error:", err)
+			os.Exit(1)
+		}
 		return
 	}
-	app := server.NewApp(config)
-	if err := app.Initialize(); err != nil {
-		fmt.Println("Error during start: ", err)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Error:", err)
+		os.Exit(1)
+	}
+
+	if err := run(config, args); err != nil {
+		fmt.Fprintln(os.Stderr, "Error:", err)
+		os.Exit(1)
 	}
-	app.Run()
 }
@@ -0,0 +1,177 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+
+	"frappuccino/internal/config"
+	"frappuccino/internal/entity"
+	"frappuccino/internal/logging"
+	"frappuccino/internal/repository/postgres"
+	"frappuccino/internal/server"
+	"frappuccino/internal/service/access"
+	serviceStore "frappuccino/internal/service/store"
+	"frappuccino/internal/tracing"
+)
+
+// command is a subcommand of the binary; name may be two words, like "user create"
+type command struct {
+	name    string
+	summary string
+	run     func(cfg *config.Config, args []string) error
+}
+
+var commands = []command{
+	{"serve", "start the HTTP server (the default)", serve},
+	{"migrate", "migrate up | down [steps] | status", runMigrate},
+	{"seed", "load the demo menu, stock and orders", runSeed},
+	{"user create", "create a staff login", createUser},
+	{"apikey issue", "issue an API key for an integration", issueAPIKey},
+	{"inventory import", "add ingredients to a store from a CSV file", importInventory},
+	{"report export", "write a sales report as JSON or CSV", exportReport},
+}
+
+var errUsage = errors.New("invalid usage")
+
+// run finds the command named by args and runs it with the remaining arguments
+func run(cfg *config.Config, args []string) error {
+	if len(args) == 0 {
+		return serve(cfg, nil)
+	}
+	if args[0] == "help" || args[0] == "-h" || args[0] == "--help" {
+		printUsage(os.Stdout)
+		return nil
+	}
+
+	for _, cmd := range commands {
+		words := strings.Fields(cmd.name)
+		if len(args) < len(words) || strings.Join(args[:len(words)], " ") != cmd.name {
+			continue
+		}
+		err := cmd.run(cfg, args[len(words):])
+		if errors.Is(err, flag.ErrHelp) {
+			return nil
+		}
+		if errors.Is(err, errUsage) {
+			return fmt.Errorf("%s: %w", cmd.name, err)
+		}
+		return err
+	}
+
+	printUsage(os.Stderr)
+	return fmt.Errorf("unknown command %q", strings.Join(args, " "))
+}
+
+func printUsage(w io.Writer) {
+	fmt.Fprintln(w, "Usage: frappuccino [settings] [command] [flags]")
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "Commands:")
+	for _, cmd := range commands {
+		fmt.Fprintf(w, "  %-18s %s\n", cmd.name, cmd.summary)
+	}
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, `Run "frappuccino <command> -h" for the flags of a command`)
+	fmt.Fprintln(w, `and "frappuccino -h" for the settings.`)
+}
+
+// printSettings lists the settings that go before the command; each may also be given in the
+// config file or the environment
+func printSettings(w io.Writer) error {
+	fmt.Fprintln(w, "Settings, overriding the environment, which overrides the config file:")
+	return config.PrintSettings(w)
+}
+
+// serve migrates the database as configured and runs the HTTP server
+func serve(cfg *config.Config, args []string) error {
+	if len(args) > 0 {
+		return fmt.Errorf("%w: serve takes no arguments", errUsage)
+	}
+
+	// SIGTERM from the orchestrator or Ctrl-C drains the server; it also stops waiting for the
+	// database at startup
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGTERM, os.Interrupt)
+	defer stop()
+
+	if err := migrateOnStart(ctx, cfg); err != nil {
+		return fmt.Errorf("migrate database: %w", err)
+	}
+
+	logger := logging.New(cfg.Log, os.Stdout)
+	shutdownTracing, err := tracing.Setup(ctx, cfg.Tracing)
+	if err != nil {
+		return fmt.Errorf("set up tracing: %w", err)
+	}
+	// The spans of the last requests are flushed once the server has drained
+	defer func() {
+		flushCtx, cancel := context.WithTimeout(context.Background(), cfg.App.ShutdownTimeout)
+		defer cancel()
+		if err := shutdownTracing(flushCtx); err != nil {
+			logger.Error("Error flushing traces", "error", err)
+		}
+	}()
+
+	app := server.NewApp(cfg, logger)
+	if err := app.Initialize(ctx); err != nil {
+		return fmt.Errorf("start server: %w", err)
+	}
+	if err := app.Run(ctx); err != nil {
+		return fmt.Errorf("run server: %w", err)
+	}
+	return nil
+}
+
+// withRepositories connects to the database and hands the command the repositories the server
+// uses, a logger writing to stderr so that stdout only carries the output of the command, and a
+// context acting as an administrator, limited to a store when storeRef names one
+func withRepositories(cfg *config.Config, storeRef string, fn func(ctx context.Context, repos *server.Repositories, logger *slog.Logger) error) error {
+	logger := logging.New(cfg.Log, os.Stderr).With("component", "cli")
+	db, err := postgres.NewDbConnInstance(context.Background(), &cfg.Repository, logger)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	repos := server.NewRepositories(db)
+
+	ctx := access.WithPrincipal(context.Background(), entity.Principal{
+		Kind: "cli",
+		ID:   "cli",
+		Name: "command line",
+		Role: string(access.RoleAdmin),
+	})
+	if storeRef != "" {
+		store, err := serviceStore.NewStoreService(repos.Store, logger).ResolveStore(ctx, storeRef)
+		if err != nil {
+			return err
+		}
+		ctx = access.WithStore(ctx, store.StoreID)
+	}
+
+	return fn(ctx, repos, logger)
+}
+
+// newFlagSet returns the flags of a command; errors are reported by run, not by the flag package
+func newFlagSet(name string) *flag.FlagSet {
+	flags := flag.NewFlagSet(name, flag.ContinueOnError)
+	flags.SetOutput(os.Stderr)
+	return flags
+}
+
+// parseFlags parses the flags of a command, which takes no positional arguments
+func parseFlags(flags *flag.FlagSet, args []string) error {
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if flags.NArg() > 0 {
+		return fmt.Errorf("%w: unexpected argument %q", errUsage, flags.Arg(0))
+	}
+	return nil
+}
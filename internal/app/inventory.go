@@ -0,0 +1,142 @@
+package app
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"strconv"
+	"strings"
+
+	"frappuccino/internal/config"
+	"frappuccino/internal/dto/inventory"
+	"frappuccino/internal/metrics"
+	"frappuccino/internal/server"
+	serviceInv "frappuccino/internal/service/inventory"
+)
+
+// inventoryColumns is the header an import file must start with
+var inventoryColumns = []string{"name", "quantity", "unit", "unit_price", "reorder_point"}
+
+// importInventory is the inventory import command. Every row becomes an ingredient of the store
+// with its initial stock; ingredients the store already stocks are skipped, so an import can be
+// run again after fixing the rows that failed.
+func importInventory(cfg *config.Config, args []string) error {
+	flags := newFlagSet("inventory import")
+	store := flags.String("store", "", "ID or code of the store (required)")
+	file := flags.String("file", "-", "CSV file with the columns "+strings.Join(inventoryColumns, ",")+"; - reads stdin")
+	if err := parseFlags(flags, args); err != nil {
+		return err
+	}
+	if *store == "" {
+		return fmt.Errorf("%w: -store is required", errUsage)
+	}
+
+	var in io.Reader = os.Stdin
+	if *file != "-" {
+		f, err := os.Open(*file)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		in = f
+	}
+	rows, err := readInventoryCSV(in)
+	if err != nil {
+		return err
+	}
+
+	return withRepositories(cfg, *store, func(ctx context.Context, repos *server.Repositories, logger *slog.Logger) error {
+		// Nothing scrapes the command, so its metrics are left uncollected
+		inventoryService := serviceInv.NewInventoryService(repos.Inventory, repos.Store, metrics.New(), logger)
+
+		existing, err := inventoryService.GetInventory(ctx)
+		if err != nil {
+			return err
+		}
+		stocked := make(map[string]bool, len(existing))
+		for _, item := range existing {
+			stocked[strings.ToLower(item.Name)] = true
+		}
+
+		var created, skipped, failed int
+		for i, row := range rows {
+			line := i + 2 // after the header, counting from 1
+			if stocked[strings.ToLower(row.Name)] {
+				logger.Info("Ingredient already stocked, skipped", "line", line, "name", row.Name)
+				skipped++
+				continue
+			}
+			if _, err := inventoryService.CreateInventory(ctx, row); err != nil {
+				logger.Error("Error importing ingredient", "line", line, "name", row.Name, "error", err)
+				failed++
+				continue
+			}
+			stocked[strings.ToLower(row.Name)] = true
+			created++
+		}
+
+		fmt.Printf("Imported %d ingredients, skipped %d, failed %d\n", created, skipped, failed)
+		if failed > 0 {
+			return fmt.Errorf("%d rows failed", failed)
+		}
+		return nil
+	})
+}
+
+// readInventoryCSV parses an import file, rejecting it as a whole if any row is malformed
+func readInventoryCSV(in io.Reader) ([]inventory.CreateInventoryRequest, error) {
+	reader := csv.NewReader(in)
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if errors.Is(err, io.EOF) {
+		return nil, errors.New("import file is empty")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("read header: %w", err)
+	}
+	if len(header) != len(inventoryColumns) {
+		return nil, fmt.Errorf("header must be %s", strings.Join(inventoryColumns, ","))
+	}
+	for i, column := range header {
+		if strings.ToLower(strings.TrimSpace(column)) != inventoryColumns[i] {
+			return nil, fmt.Errorf("header must be %s", strings.Join(inventoryColumns, ","))
+		}
+	}
+
+	var rows []inventory.CreateInventoryRequest
+	for line := 2; ; line++ {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+
+		var numbers [3]float64
+		for i, field := range []string{record[1], record[3], record[4]} {
+			numbers[i], err = strconv.ParseFloat(strings.TrimSpace(field), 32)
+			if err != nil || numbers[i] < 0 {
+				return nil, fmt.Errorf("line %d: %q is not a number of zero or more", line, field)
+			}
+		}
+		name := strings.TrimSpace(record[0])
+		if name == "" {
+			return nil, fmt.Errorf("line %d: name is empty", line)
+		}
+
+		rows = append(rows, inventory.CreateInventoryRequest{
+			Name:         name,
+			Quantity:     float32(numbers[0]),
+			Unit:         strings.TrimSpace(record[2]),
+			UnitPrice:    float32(numbers[1]),
+			ReorderPoint: float32(numbers[2]),
+		})
+	}
+	return rows, nil
+}
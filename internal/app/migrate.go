@@ -0,0 +1,114 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+
+	"frappuccino/internal/config"
+	"frappuccino/internal/logging"
+	"frappuccino/internal/migrate"
+	"frappuccino/internal/repository/postgres"
+)
+
+// migrateOnStart brings the schema up to date before the server starts and loads the demo data
+// when configured to
+func migrateOnStart(ctx context.Context, cfg *config.Config) error {
+	if cfg.Repository.SkipMigrations && !cfg.Repository.Seed {
+		return nil
+	}
+
+	return withMigrator(ctx, cfg, func(ctx context.Context, migrator *migrate.Migrator) error {
+		if !cfg.Repository.SkipMigrations {
+			if _, err := migrator.Up(ctx); err != nil {
+				return err
+			}
+		}
+		if cfg.Repository.Seed {
+			if _, err := migrator.Seed(ctx); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// runMigrate is the migrate command: up applies pending migrations, down reverts the last one or
+// the given number, status lists them
+func runMigrate(cfg *config.Config, args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("%w: migrate up | down [steps] | status", errUsage)
+	}
+
+	return withMigrator(context.Background(), cfg, func(ctx context.Context, migrator *migrate.Migrator) error {
+		switch args[0] {
+		case "up":
+			applied, err := migrator.Up(ctx)
+			if err != nil {
+				return err
+			}
+			fmt.Printf("Applied %d migrations\n", applied)
+		case "down":
+			steps := 1
+			if len(args) > 1 {
+				n, err := strconv.Atoi(args[1])
+				if err != nil || n <= 0 {
+					return fmt.Errorf("steps must be a positive number, got %q", args[1])
+				}
+				steps = n
+			}
+			reverted, err := migrator.Down(ctx, steps)
+			if err != nil {
+				return err
+			}
+			fmt.Printf("Reverted %d migrations\n", reverted)
+		case "status":
+			statuses, err := migrator.Status(ctx)
+			if err != nil {
+				return err
+			}
+			for _, s := range statuses {
+				applied := "pending"
+				if s.AppliedAt != nil {
+					applied = s.AppliedAt.Format(time.RFC3339)
+				}
+				fmt.Printf("%04d  %-30s  %s\n", s.Version, s.Name, applied)
+			}
+		default:
+			return fmt.Errorf("%w: unknown migrate action %q", errUsage, args[0])
+		}
+		return nil
+	})
+}
+
+// runSeed is the seed command, which loads the demo data that has not been loaded yet
+func runSeed(cfg *config.Config, args []string) error {
+	if len(args) > 0 {
+		return fmt.Errorf("%w: seed takes no arguments", errUsage)
+	}
+	return withMigrator(context.Background(), cfg, func(ctx context.Context, migrator *migrate.Migrator) error {
+		loaded, err := migrator.Seed(ctx)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Loaded %d seeds\n", loaded)
+		return nil
+	})
+}
+
+func withMigrator(ctx context.Context, cfg *config.Config, fn func(ctx context.Context, migrator *migrate.Migrator) error) error {
+	logger := logging.New(cfg.Log, os.Stdout).With("component", "migrate")
+	db, err := postgres.NewDbConnInstance(ctx, &cfg.Repository, logger)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	migrator, err := migrate.New(db, logger)
+	if err != nil {
+		return err
+	}
+	return fn(ctx, migrator)
+}
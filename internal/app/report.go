@@ -0,0 +1,142 @@
+package app
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"strconv"
+	"time"
+
+	"frappuccino/internal/config"
+	"frappuccino/internal/dto/report"
+	"frappuccino/internal/server"
+	serviceReport "frappuccino/internal/service/report"
+)
+
+// exportReport is the report export command. Reports cover every store unless -store names one;
+// total sales across stores are broken down by store.
+func exportReport(cfg *config.Config, args []string) error {
+	flags := newFlagSet("report export")
+	name := flags.String("report", "total-sales", "total-sales, popular-items or service-times")
+	store := flags.String("store", "", "ID or code of the store; empty covers every store")
+	start := flags.String("start", "", "first day, YYYY-MM-DD")
+	end := flags.String("end", "", "last day, YYYY-MM-DD")
+	status := flags.String("status", "", "only orders with this status (total-sales)")
+	limit := flags.Int("limit", 10, "number of items (popular-items)")
+	format := flags.String("format", "json", "json or csv; service-times is only available as json")
+	out := flags.String("out", "-", "file to write; - writes stdout")
+	if err := parseFlags(flags, args); err != nil {
+		return err
+	}
+
+	startDate, err := parseDayFlag("start", *start)
+	if err != nil {
+		return err
+	}
+	endDate, err := parseDayFlag("end", *end)
+	if err != nil {
+		return err
+	}
+	if *format != "json" && *format != "csv" {
+		return fmt.Errorf("%w: -format must be json or csv", errUsage)
+	}
+	if *name == "service-times" && *format == "csv" {
+		return fmt.Errorf("%w: service-times is only available as json", errUsage)
+	}
+
+	return withRepositories(cfg, *store, func(ctx context.Context, repos *server.Repositories, logger *slog.Logger) error {
+		reportService := serviceReport.NewSearchService(repos.Search, repos.Order, cfg.Report, logger)
+
+		var result interface{}
+		var rows [][]string
+		switch *name {
+		case "total-sales":
+			sales, err := reportService.GetTotalSales(ctx, report.TotalSalesRequest{StartDate: startDate, EndDate: endDate, Status: *status})
+			if err != nil {
+				return err
+			}
+			result, rows = sales, totalSalesRows(sales)
+		case "popular-items":
+			items, err := reportService.GetPopularItems(ctx, report.PopularItemsRequest{StartDate: startDate, EndDate: endDate, Limit: *limit})
+			if err != nil {
+				return err
+			}
+			result, rows = items, popularItemRows(items)
+		case "service-times":
+			times, err := reportService.GetServiceTimes(ctx, report.ServiceTimesRequest{StartDate: startDate, EndDate: endDate})
+			if err != nil {
+				return err
+			}
+			result = times
+		default:
+			return fmt.Errorf("%w: unknown report %q", errUsage, *name)
+		}
+
+		var w io.Writer = os.Stdout
+		if *out != "-" {
+			f, err := os.Create(*out)
+			if err != nil {
+				return err
+			}
+			defer f.Close()
+			w = f
+		}
+
+		if *format == "csv" {
+			writer := csv.NewWriter(w)
+			if err := writer.WriteAll(rows); err != nil {
+				return fmt.Errorf("write csv: %w", err)
+			}
+			return nil
+		}
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(result)
+	})
+}
+
+// totalSalesRows lays out total sales with one row per store, or a single row for one store
+func totalSalesRows(sales report.TotalSalesResponse) [][]string {
+	rows := [][]string{{"store_id", "store_name", "order_count", "total_sales", "delivery_fees"}}
+	if len(sales.ByStore) == 0 {
+		return append(rows, []string{sales.StoreID, "", strconv.Itoa(sales.OrderCount), money(sales.TotalSales), money(sales.DeliveryFees)})
+	}
+	for _, s := range sales.ByStore {
+		rows = append(rows, []string{s.StoreID, s.StoreName, strconv.Itoa(s.OrderCount), money(s.TotalSales), money(s.DeliveryFees)})
+	}
+	return rows
+}
+
+func popularItemRows(items report.PopularItemsResponse) [][]string {
+	rows := [][]string{{"menu_item_id", "name", "quantity_sold", "total_revenue", "percent_of_sales"}}
+	for _, item := range items.Items {
+		rows = append(rows, []string{
+			item.MenuItemID,
+			item.Name,
+			strconv.Itoa(item.Quantity),
+			money(item.TotalRevenue),
+			strconv.FormatFloat(item.PercentOfSales, 'f', 2, 64),
+		})
+	}
+	return rows
+}
+
+func money(amount float64) string {
+	return strconv.FormatFloat(amount, 'f', 2, 64)
+}
+
+// parseDayFlag parses a YYYY-MM-DD flag; an empty flag means no limit
+func parseDayFlag(name, value string) (*time.Time, error) {
+	if value == "" {
+		return nil, nil
+	}
+	day, err := time.Parse("2006-01-02", value)
+	if err != nil {
+		return nil, fmt.Errorf("%w: -%s must be a date like 2025-03-28", errUsage, name)
+	}
+	return &day, nil
+}
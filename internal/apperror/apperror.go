@@ -0,0 +1,122 @@
+package apperror
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Kind says what went wrong with a request in terms its caller can act on; the HTTP layer turns
+// it into the status code
+type Kind int
+
+const (
+	// Internal errors are the server's fault; their message is not shown to the caller
+	Internal Kind = iota
+	// Invalid requests are malformed or break a rule on their own, whatever the data holds
+	Invalid
+	// Unauthorized requests lack valid credentials
+	Unauthorized
+	// Forbidden requests come from a caller whose role does not allow them
+	Forbidden
+	// NotFound requests name something that does not exist
+	NotFound
+	// Conflict requests clash with the current state of what they change
+	Conflict
+	// Unprocessable requests are well formed, but the business cannot serve them
+	Unprocessable
+)
+
+// Error is an error a service reports to its caller. Code is stable and machine-readable, like
+// "table_occupied"; Message is written for people. Errors declared as package variables are
+// compared with errors.Is, also when they carry details or are wrapped with fmt.Errorf.
+type Error struct {
+	Kind      Kind
+	Code      string
+	Message   string
+	Fields    []FieldError
+	Shortages []Shortage
+
+	// cause is the error this one details, so that errors.Is still matches it
+	cause error
+}
+
+// FieldError names a field of the request and what is wrong with it
+type FieldError struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+// Shortage is an ingredient there is not enough stock of
+type Shortage struct {
+	IngredientID string  `json:"ingredient_id"`
+	Name         string  `json:"name"`
+	Required     float32 `json:"required"`
+	Available    float32 `json:"available"`
+	Unit         string  `json:"unit"`
+}
+
+func New(kind Kind, code, message string) *Error {
+	return &Error{Kind: kind, Code: code, Message: message}
+}
+
+// InvalidField is an Invalid error about a single field of the request
+func InvalidField(code, field, message string) *Error {
+	return &Error{Kind: Invalid, Code: code, Message: message, Fields: []FieldError{{Field: field, Message: message}}}
+}
+
+// Field is a shorthand for a FieldError
+func Field(field, message string) FieldError {
+	return FieldError{Field: field, Message: message}
+}
+
+func (e *Error) Error() string {
+	return e.Message
+}
+
+func (e *Error) Unwrap() error {
+	return e.cause
+}
+
+// WithFields returns e naming the fields of the request at fault
+func (e *Error) WithFields(fields ...FieldError) *Error {
+	detailed := e.detail()
+	detailed.Fields = append(detailed.Fields, fields...)
+	return detailed
+}
+
+// WithShortages returns e listing the ingredients that ran short, which are also added to the message
+func (e *Error) WithShortages(shortages ...Shortage) *Error {
+	detailed := e.detail()
+	detailed.Shortages = append(detailed.Shortages, shortages...)
+
+	parts := make([]string, len(shortages))
+	for i, s := range shortages {
+		parts[i] = fmt.Sprintf("%s (need %.2f %s, have %.2f %s)", s.Name, s.Required, s.Unit, s.Available, s.Unit)
+	}
+	detailed.Message += ": " + strings.Join(parts, ", ")
+	return detailed
+}
+
+// WithDetail returns e with detail added to its message, for what sets this case apart from the
+// others the error covers
+func (e *Error) WithDetail(detail string) *Error {
+	detailed := e.detail()
+	detailed.Message += ": " + detail
+	return detailed
+}
+
+// WithMessage returns e with another message, for a case the general message does not describe well
+func (e *Error) WithMessage(message string) *Error {
+	detailed := e.detail()
+	detailed.Message = message
+	return detailed
+}
+
+// detail copies e for adding details, leaving the package variable it may be untouched
+func (e *Error) detail() *Error {
+	detailed := *e
+	detailed.Fields = append([]FieldError(nil), e.Fields...)
+	detailed.Shortages = append([]Shortage(nil), e.Shortages...)
+	detailed.cause = e
+	return &detailed
+}
@@ -1,45 +1,148 @@
 package config
 
-import (
-	"encoding/json"
-	"os"
-	"time"
-)
-
-func GetConfig(path string) (*Config, error) {
-	f, err := os.Open(path)
-	if err != nil {
-		return nil, err
-	}
-	defer f.Close()
-
-	var cfg Config
-	if err = json.NewDecoder(f).Decode(&cfg); err != nil {
-		return nil, err
-	}
-
-	return &cfg, nil
-}
+import "time"
 
 type Config struct {
 	App        App        `json:"app"`
 	Repository Repository `json:"repository"`
+	Report     Report     `json:"report"`
+	ETA        ETA        `json:"eta"`
+	Schedule   Schedule   `json:"schedule"`
+	Numbering  Numbering  `json:"numbering"`
+	Store      Store      `json:"store"`
+	Printing   Printing   `json:"printing"`
+	Auth       Auth       `json:"auth"`
+	Log        Log        `json:"log"`
+	Tracing    Tracing    `json:"tracing"`
 }
 
 type App struct {
+	// Host is the address to listen on; empty means every interface
+	Host string        `json:"host"`
 	Port int           `json:"port"`
 	RTO  time.Duration `json:"rto"`
 	WTO  time.Duration `json:"wto"`
+	// IdleTimeout is how long a keep-alive connection may wait for its next request
+	IdleTimeout time.Duration `json:"idle_timeout"`
+	// ShutdownTimeout is how long requests in flight and background jobs get to finish after
+	// SIGTERM or SIGINT before the server stops anyway
+	ShutdownTimeout time.Duration `json:"shutdown_timeout"`
 }
 
 type Repository struct {
-	DBHost      string `json:"db_host"`
+	DBHost      string `json:"db_host" env:"DB_HOST"`
 	DBSrv       string `json:"db_srv"`
-	DBPort      int    `json:"db_port"`
-	DBUsername  string `json:"db_username"`
-	DBPassword  string `json:"db_password"`
-	DBName      string `json:"db_name"`
-	DBSSLMode   string `json:"db_ssl_mode"`
+	DBPort      int    `json:"db_port" env:"DB_PORT"`
+	DBUsername  string `json:"db_username" env:"DB_USER"`
+	DBPassword  string `json:"db_password" env:"DB_PASSWORD" secret:"true"`
+	DBName      string `json:"db_name" env:"DB_NAME"`
+	DBSSLMode   string `json:"db_ssl_mode" env:"DB_SSL_MODE"`
 	MaxConn     int    `json:"max_conn"`
 	MaxIdleConn int    `json:"max_idle_conn"`
+	// ConnMaxLifetime closes pooled connections after this long so they are spread over
+	// database restarts and failovers; zero keeps them open
+	ConnMaxLifetime time.Duration `json:"conn_max_lifetime"`
+	// ConnectTimeout bounds establishing a single database connection
+	ConnectTimeout time.Duration `json:"connect_timeout"`
+	// ConnectAttempts is how often the database is tried at startup before giving up
+	ConnectAttempts int `json:"connect_attempts"`
+	// ConnectRetryDelay is the wait after the first failed attempt; it doubles with every further attempt
+	ConnectRetryDelay time.Duration `json:"connect_retry_delay"`
+	// SkipMigrations leaves the schema alone at startup, for deployments that run "migrate up" themselves
+	SkipMigrations bool `json:"skip_migrations"`
+	// Seed loads the demo menu, stock and orders at startup if they were not loaded yet
+	Seed bool `json:"seed"`
+}
+
+type Report struct {
+	// ServiceSLA is the longest acceptable time from order creation to ready
+	ServiceSLA time.Duration `json:"service_sla"`
+}
+
+type ETA struct {
+	// DefaultPrepTime is used for menu items without preparation history
+	DefaultPrepTime time.Duration `json:"default_prep_time"`
+	// ParallelOrders is how many orders the kitchen works on at the same time
+	ParallelOrders int `json:"parallel_orders"`
+	// HistoryWindow limits how far back preparation durations are sampled
+	HistoryWindow time.Duration `json:"history_window"`
+}
+
+type Schedule struct {
+	// OpeningTime and ClosingTime bound the accepted pickup times, formatted as "15:04"
+	OpeningTime string `json:"opening_time"`
+	ClosingTime string `json:"closing_time"`
+	// Location is the IANA time zone of the opening hours; empty means the server's local zone
+	Location string `json:"location"`
+	// LeadTime is how long before pickup a scheduled order is released to the kitchen
+	LeadTime time.Duration `json:"lead_time"`
+	// PollInterval is how often the scheduler looks for orders to release
+	PollInterval time.Duration `json:"poll_interval"`
+}
+
+type Numbering struct {
+	// BusinessDayCutoff is when order numbers start again from 1, formatted as "15:04";
+	// orders placed before it count towards the previous business day
+	BusinessDayCutoff string `json:"business_day_cutoff"`
+	// Location is the IANA time zone of the cutoff; empty means the server's local zone
+	Location string `json:"location"`
+}
+
+type Store struct {
+	// Name, Address and Phone are printed in the receipt header when the store of the order
+	// has none of its own
+	Name    string `json:"name"`
+	Address string `json:"address"`
+	Phone   string `json:"phone"`
+	// Footer is printed below the totals, e.g. a thank-you note or the Wi-Fi password
+	Footer string `json:"footer"`
+	// Currency is the symbol printed before amounts
+	Currency string `json:"currency"`
+	// ReceiptWidth is the number of characters per line of text and thermal printer receipts
+	ReceiptWidth int `json:"receipt_width"`
+}
+
+type Printing struct {
+	// PollInterval is how often the print worker looks for queued kitchen tickets
+	PollInterval time.Duration `json:"poll_interval"`
+	// DialTimeout bounds connecting to a printer and sending it a ticket
+	DialTimeout time.Duration `json:"dial_timeout"`
+	// MaxAttempts is how often a ticket is tried before the job is marked failed
+	MaxAttempts int `json:"max_attempts"`
+	// RetryDelay is the wait after the first failed attempt; it grows with every further attempt
+	RetryDelay time.Duration `json:"retry_delay"`
+}
+
+type Auth struct {
+	// JWTSecret signs access and refresh tokens; when empty a random secret is generated at
+	// startup, so tokens stop working on restart
+	JWTSecret string `json:"jwt_secret" secret:"true"`
+	// Issuer is the iss claim of issued tokens
+	Issuer string `json:"issuer"`
+	// AccessTokenTTL is how long an access token is accepted
+	AccessTokenTTL time.Duration `json:"access_token_ttl"`
+	// RefreshTokenTTL is how long a staff login lasts without signing in again
+	RefreshTokenTTL time.Duration `json:"refresh_token_ttl"`
+}
+
+type Log struct {
+	// Level is the least severe level written: debug, info, warn or error
+	Level string `json:"level" env:"LOG_LEVEL"`
+	// Format is json for log collectors or text for reading in a terminal
+	Format string `json:"format" env:"LOG_FORMAT"`
+}
+
+type Tracing struct {
+	// Exporter is where spans go: none, stdout for local use, or otlp for a collector
+	Exporter string `json:"exporter"`
+	// File is written by the stdout exporter instead of standard output when set
+	File string `json:"file"`
+	// Endpoint is the URL of the OTLP/HTTP collector, e.g. http://localhost:4318; when empty the
+	// exporter falls back to the OTEL_EXPORTER_OTLP_ENDPOINT variable or its default
+	Endpoint string `json:"endpoint"`
+	// SampleRatio is the share of traces recorded, from 0 to 1; a trace the caller sampled is
+	// always recorded
+	SampleRatio float64 `json:"sample_ratio"`
+	// ServiceName names the application in the traces
+	ServiceName string `json:"service_name" env:"OTEL_SERVICE_NAME"`
 }
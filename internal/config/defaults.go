@@ -0,0 +1,70 @@
+package config
+
+import "time"
+
+// Default is the configuration before any file, environment variable or flag is applied
+func Default() Config {
+	return Config{
+		App: App{
+			Port:            8080,
+			RTO:             30 * time.Second,
+			WTO:             30 * time.Second,
+			IdleTimeout:     2 * time.Minute,
+			ShutdownTimeout: 30 * time.Second,
+		},
+		Repository: Repository{
+			DBHost:            "localhost",
+			DBPort:            5432,
+			DBName:            "frappuccino",
+			DBSSLMode:         "disable",
+			MaxConn:           10,
+			MaxIdleConn:       10,
+			ConnMaxLifetime:   30 * time.Minute,
+			ConnectTimeout:    5 * time.Second,
+			ConnectAttempts:   10,
+			ConnectRetryDelay: time.Second,
+		},
+		Report: Report{
+			ServiceSLA: 15 * time.Minute,
+		},
+		ETA: ETA{
+			DefaultPrepTime: 5 * time.Minute,
+			ParallelOrders:  2,
+			HistoryWindow:   30 * 24 * time.Hour,
+		},
+		Schedule: Schedule{
+			OpeningTime:  "07:00",
+			ClosingTime:  "20:00",
+			LeadTime:     20 * time.Minute,
+			PollInterval: 30 * time.Second,
+		},
+		Numbering: Numbering{
+			BusinessDayCutoff: "04:00",
+		},
+		Store: Store{
+			Name:         "Frappuccino",
+			Currency:     "$",
+			ReceiptWidth: 42,
+		},
+		Printing: Printing{
+			PollInterval: 2 * time.Second,
+			DialTimeout:  5 * time.Second,
+			MaxAttempts:  5,
+			RetryDelay:   10 * time.Second,
+		},
+		Auth: Auth{
+			Issuer:          "frappuccino",
+			AccessTokenTTL:  15 * time.Minute,
+			RefreshTokenTTL: 7 * 24 * time.Hour,
+		},
+		Log: Log{
+			Level:  "info",
+			Format: "json",
+		},
+		Tracing: Tracing{
+			Exporter:    "none",
+			SampleRatio: 1,
+			ServiceName: "frappuccino",
+		},
+	}
+}
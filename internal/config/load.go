@@ -0,0 +1,347 @@
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	// DefaultPath is read when no config file is named, if it exists
+	DefaultPath = "./config/config.json"
+	// PathEnv names the config file, like the -config flag
+	PathEnv = envPrefix + "CONFIG"
+
+	envPrefix  = "FRAPPUCCINO_"
+	fileSuffix = "_file"
+)
+
+var durationType = reflect.TypeOf(time.Duration(0))
+
+// setting is one field of Config, addressed by its dotted key such as "app.port". The key is
+// also the name of its flag; its environment variables are the FRAPPUCCINO_ form of the key,
+// e.g. FRAPPUCCINO_APP_PORT, and the names in the env tag of the field.
+type setting struct {
+	key    string
+	env    []string // in increasing precedence
+	isBool bool
+	field  reflect.Value
+	set    func(raw string) error
+}
+
+// Load builds the configuration in layers, each overriding the one before: the defaults, the
+// JSON or YAML config file, environment variables and command line flags. args are the command
+// line arguments without the program name; Load returns the ones after the flags, which name the
+// command to run. The result is validated, so a bad setting stops the program before it starts.
+func Load(args []string) (*Config, []string, error) {
+	cfg := Default()
+	settings, err := settingsOf(reflect.ValueOf(&cfg).Elem(), "")
+	if err != nil {
+		return nil, nil, err
+	}
+	byKey := make(map[string]*setting, len(settings))
+	for _, s := range settings {
+		byKey[s.key] = s
+	}
+
+	// Flags are parsed first since they may name the config file, and applied last
+	type flagValue struct {
+		setting *setting
+		raw     string
+	}
+	var flagValues []flagValue
+	flags := flag.NewFlagSet("frappuccino", flag.ContinueOnError)
+	flags.SetOutput(io.Discard)
+	path := flags.String("config", "", "")
+	for _, s := range settings {
+		s := s
+		flags.Var(&settingFlag{s, func(raw string) {
+			flagValues = append(flagValues, flagValue{s, raw})
+		}}, s.key, "")
+	}
+	if err := flags.Parse(args); err != nil {
+		return nil, nil, err
+	}
+
+	if err := loadFile(*path, byKey); err != nil {
+		return nil, nil, err
+	}
+
+	for _, s := range settings {
+		for _, name := range s.env {
+			raw, ok := os.LookupEnv(name)
+			if !ok {
+				continue
+			}
+			if err := s.set(raw); err != nil {
+				return nil, nil, fmt.Errorf("environment variable %s: %w", name, err)
+			}
+		}
+	}
+
+	for _, v := range flagValues {
+		if err := v.setting.set(v.raw); err != nil {
+			return nil, nil, fmt.Errorf("flag -%s: %w", v.setting.key, err)
+		}
+	}
+
+	if err := cfg.Validate(); err != nil {
+		return nil, nil, fmt.Errorf("invalid configuration:\n  %s", strings.ReplaceAll(err.Error(), "\n", "\n  "))
+	}
+	return &cfg, flags.Args(), nil
+}
+
+// PrintSettings lists the flags that Load accepts, with their environment variables and defaults
+func PrintSettings(w io.Writer) error {
+	cfg := Default()
+	settings, err := settingsOf(reflect.ValueOf(&cfg).Elem(), "")
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(w, "  -config path\n        JSON or YAML config file (env %s; default %s if it exists)\n", PathEnv, DefaultPath)
+	for _, s := range settings {
+		line := "  -" + s.key
+		if !strings.HasSuffix(s.key, fileSuffix) {
+			if value := format(s.field); value != "" {
+				line += " (default " + value + ")"
+			}
+		} else {
+			line += " (file holding " + strings.TrimSuffix(s.key, fileSuffix) + ")"
+		}
+		fmt.Fprintf(w, "%s\n        env %s\n", line, strings.Join(s.env, ", "))
+	}
+	return nil
+}
+
+// loadFile applies the config file at path; without a path it reads the one named by PathEnv or,
+// when that is unset too, DefaultPath if it exists
+func loadFile(path string, byKey map[string]*setting) error {
+	if path == "" {
+		path = os.Getenv(PathEnv)
+	}
+	if path == "" {
+		if _, err := os.Stat(DefaultPath); errors.Is(err, os.ErrNotExist) {
+			return nil
+		}
+		path = DefaultPath
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("read config file: %w", err)
+	}
+
+	var tree map[string]interface{}
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		err = yaml.Unmarshal(data, &tree)
+	default:
+		decoder := json.NewDecoder(bytes.NewReader(data))
+		decoder.UseNumber()
+		err = decoder.Decode(&tree)
+	}
+	if err != nil {
+		return fmt.Errorf("parse config file %s: %w", path, err)
+	}
+
+	values := make(map[string]string)
+	flatten(tree, "", values)
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		s, ok := byKey[key]
+		if !ok {
+			return fmt.Errorf("config file %s: unknown setting %q", path, key)
+		}
+		if err := s.set(values[key]); err != nil {
+			return fmt.Errorf("config file %s: %s: %w", path, key, err)
+		}
+	}
+	return nil
+}
+
+// flatten turns the nested objects of a config file into dotted keys and their values as text
+func flatten(tree map[string]interface{}, prefix string, values map[string]string) {
+	for name, value := range tree {
+		key := prefix + name
+		switch v := value.(type) {
+		case map[string]interface{}:
+			flatten(v, key+".", values)
+		case nil:
+			// null keeps the value of the lower layer
+		case string:
+			values[key] = v
+		case float64:
+			values[key] = strconv.FormatFloat(v, 'f', -1, 64)
+		default:
+			values[key] = fmt.Sprint(v)
+		}
+	}
+}
+
+// settingsOf lists the settings of the struct v; fields are named by their json tag. Fields
+// tagged secret:"true" get a second setting with the _file suffix that reads the value from a
+// file, for secrets mounted by Docker or Kubernetes.
+func settingsOf(v reflect.Value, prefix string) ([]*setting, error) {
+	var settings []*setting
+	for i := 0; i < v.NumField(); i++ {
+		field := v.Type().Field(i)
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if name == "" || name == "-" {
+			continue
+		}
+		key := prefix + name
+
+		if field.Type.Kind() == reflect.Struct {
+			nested, err := settingsOf(v.Field(i), key+".")
+			if err != nil {
+				return nil, err
+			}
+			settings = append(settings, nested...)
+			continue
+		}
+
+		var aliases []string
+		if tag := field.Tag.Get("env"); tag != "" {
+			aliases = strings.Split(tag, ",")
+		}
+		s := &setting{
+			key:    key,
+			env:    append(aliases, envName(key)),
+			isBool: field.Type.Kind() == reflect.Bool,
+			field:  v.Field(i),
+		}
+		set, err := setter(s.field)
+		if err != nil {
+			return nil, fmt.Errorf("config: setting %s: %w", key, err)
+		}
+		s.set = set
+		settings = append(settings, s)
+
+		if field.Tag.Get("secret") == "true" {
+			fromFile := &setting{key: key + fileSuffix, field: s.field}
+			for _, name := range s.env {
+				fromFile.env = append(fromFile.env, name+strings.ToUpper(fileSuffix))
+			}
+			fromFile.set = func(path string) error {
+				secret, err := os.ReadFile(path)
+				if err != nil {
+					return fmt.Errorf("read secret file: %w", err)
+				}
+				return s.set(strings.TrimRight(string(secret), "\r\n"))
+			}
+			settings = append(settings, fromFile)
+		}
+	}
+	return settings, nil
+}
+
+func envName(key string) string {
+	return envPrefix + strings.ToUpper(strings.ReplaceAll(key, ".", "_"))
+}
+
+// setter parses text into the field. Durations are written like "30s" or "1h30m"; plain numbers
+// are still read as nanoseconds, as older config files have them. Fields of other types than
+// these cannot be set from text, which is an error in Config rather than in the settings.
+func setter(field reflect.Value) (func(raw string) error, error) {
+	switch {
+	case field.Type() == durationType:
+		return func(raw string) error {
+			d, err := time.ParseDuration(strings.TrimSpace(raw))
+			if err != nil {
+				ns, nsErr := strconv.ParseInt(strings.TrimSpace(raw), 10, 64)
+				if nsErr != nil {
+					return fmt.Errorf("%q is not a duration such as 30s, 15m or 2h", raw)
+				}
+				d = time.Duration(ns)
+			}
+			field.SetInt(int64(d))
+			return nil
+		}, nil
+	case field.Kind() == reflect.String:
+		return func(raw string) error {
+			field.SetString(raw)
+			return nil
+		}, nil
+	case field.Kind() == reflect.Bool:
+		return func(raw string) error {
+			b, err := strconv.ParseBool(strings.TrimSpace(raw))
+			if err != nil {
+				return fmt.Errorf("%q is not true or false", raw)
+			}
+			field.SetBool(b)
+			return nil
+		}, nil
+	case field.CanInt():
+		return func(raw string) error {
+			n, err := strconv.ParseInt(strings.TrimSpace(raw), 10, field.Type().Bits())
+			if err != nil {
+				return fmt.Errorf("%q is not a whole number", raw)
+			}
+			field.SetInt(n)
+			return nil
+		}, nil
+	case field.CanFloat():
+		return func(raw string) error {
+			f, err := strconv.ParseFloat(strings.TrimSpace(raw), field.Type().Bits())
+			if err != nil {
+				return fmt.Errorf("%q is not a number", raw)
+			}
+			field.SetFloat(f)
+			return nil
+		}, nil
+	}
+	return nil, fmt.Errorf("unsupported type %s", field.Type())
+}
+
+// format writes a field the way its setter reads it
+func format(field reflect.Value) string {
+	switch {
+	case field.Type() == durationType:
+		if field.Int() == 0 {
+			return ""
+		}
+		return time.Duration(field.Int()).String()
+	case field.Kind() == reflect.String:
+		return field.String()
+	default:
+		return fmt.Sprint(field.Interface())
+	}
+}
+
+// settingFlag collects the value of a setting's flag, to be applied after the file and environment
+type settingFlag struct {
+	setting *setting
+	collect func(raw string)
+}
+
+func (f *settingFlag) String() string {
+	return ""
+}
+
+func (f *settingFlag) Set(raw string) error {
+	f.collect(raw)
+	return nil
+}
+
+// IsBoolFlag lets boolean settings be switched on with a bare flag like -repository.seed
+func (f *settingFlag) IsBoolFlag() bool {
+	return f.setting.isBool
+}
@@ -0,0 +1,292 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// clearEnv unsets every environment variable Load reads, so a test only sees the ones it sets
+func clearEnv(t *testing.T) {
+	t.Helper()
+	cfg := Default()
+	settings, err := settingsOf(reflect.ValueOf(&cfg).Elem(), "")
+	if err != nil {
+		t.Fatalf("settingsOf: %v", err)
+	}
+
+	names := []string{PathEnv}
+	for _, s := range settings {
+		names = append(names, s.env...)
+	}
+	for _, name := range names {
+		if _, ok := os.LookupEnv(name); ok {
+			t.Setenv(name, "") // restores the variable after the test
+			os.Unsetenv(name)
+		}
+	}
+}
+
+// writeFile writes content to a file of the given name in a temporary directory
+func writeFile(t *testing.T, name, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatalf("write %s: %v", name, err)
+	}
+	return path
+}
+
+// values formats the settings of cfg by key, the way a config file writes them
+func values(t *testing.T, cfg *Config) map[string]string {
+	t.Helper()
+	settings, err := settingsOf(reflect.ValueOf(cfg).Elem(), "")
+	if err != nil {
+		t.Fatalf("settingsOf: %v", err)
+	}
+	byKey := make(map[string]string, len(settings))
+	for _, s := range settings {
+		byKey[s.key] = format(s.field)
+	}
+	return byKey
+}
+
+const jsonFile = `{
+	"app": {"port": 9000, "rto": "45s"},
+	"repository": {"db_host": "file-host", "db_username": "latte", "db_password": "file-password", "max_conn": null},
+	"log": {"level": "debug"}
+}`
+
+const yamlFile = `
+app:
+  port: 9001
+repository:
+  db_host: yaml-host
+  db_username: latte
+  connect_timeout: 2000000000 # nanoseconds, as older files have them
+`
+
+func TestLoadLayers(t *testing.T) {
+	secret := writeFile(t, "db_password", "secret-password\n")
+
+	tests := []struct {
+		name   string
+		file   string // name of the config file written from body, if any
+		body   string
+		viaEnv bool // name the file in PathEnv instead of -config
+		env    map[string]string
+		args   []string
+		want   map[string]string
+	}{
+		{
+			name: "defaults",
+			args: []string{"-repository.db_username=latte"},
+			want: map[string]string{"app.port": "8080", "app.rto": "30s", "repository.db_host": "localhost", "log.level": "info"},
+		},
+		{
+			name: "json file over defaults",
+			file: "config.json",
+			body: jsonFile,
+			want: map[string]string{
+				"app.port":               "9000",
+				"app.rto":                "45s",
+				"app.wto":                "30s",
+				"repository.db_host":     "file-host",
+				"repository.max_conn":    "10", // null keeps the default
+				"repository.db_ssl_mode": "disable",
+				"log.level":              "debug",
+			},
+		},
+		{
+			name: "yaml file over defaults",
+			file: "config.yaml",
+			body: yamlFile,
+			want: map[string]string{"app.port": "9001", "repository.db_host": "yaml-host", "repository.connect_timeout": "2s"},
+		},
+		{
+			name:   "file named by the environment",
+			file:   "config.json",
+			body:   jsonFile,
+			viaEnv: true,
+			want:   map[string]string{"app.port": "9000"},
+		},
+		{
+			name: "alias over file",
+			file: "config.json",
+			body: jsonFile,
+			env:  map[string]string{"DB_HOST": "alias-host"},
+			want: map[string]string{"repository.db_host": "alias-host", "app.port": "9000"},
+		},
+		{
+			name: "prefixed name over alias",
+			file: "config.json",
+			body: jsonFile,
+			env:  map[string]string{"DB_HOST": "alias-host", "FRAPPUCCINO_REPOSITORY_DB_HOST": "env-host"},
+			want: map[string]string{"repository.db_host": "env-host"},
+		},
+		{
+			name: "secret from a file",
+			file: "config.json",
+			body: jsonFile,
+			env:  map[string]string{"DB_PASSWORD_FILE": secret},
+			want: map[string]string{"repository.db_password": "secret-password"},
+		},
+		{
+			name: "flag over environment",
+			file: "config.json",
+			body: jsonFile,
+			env:  map[string]string{"FRAPPUCCINO_APP_PORT": "9100", "FRAPPUCCINO_LOG_LEVEL": "warn"},
+			args: []string{"-app.port", "9200", "-repository.db_password_file", secret},
+			want: map[string]string{"app.port": "9200", "log.level": "warn", "repository.db_password": "secret-password"},
+		},
+		{
+			name: "bare boolean flag",
+			args: []string{"-repository.db_username=latte", "-repository.seed"},
+			want: map[string]string{"repository.seed": "true"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clearEnv(t)
+			args := tt.args
+			if tt.file != "" {
+				path := writeFile(t, tt.file, tt.body)
+				if tt.viaEnv {
+					t.Setenv(PathEnv, path)
+				} else {
+					args = append([]string{"-config", path}, args...)
+				}
+			}
+			for name, value := range tt.env {
+				t.Setenv(name, value)
+			}
+
+			cfg, rest, err := Load(args)
+			if err != nil {
+				t.Fatalf("Load: %v", err)
+			}
+			if len(rest) != 0 {
+				t.Errorf("Load left arguments %q, want none", rest)
+			}
+			got := values(t, cfg)
+			for key, want := range tt.want {
+				if got[key] != want {
+					t.Errorf("%s = %q, want %q", key, got[key], want)
+				}
+			}
+		})
+	}
+}
+
+func TestLoadReturnsCommand(t *testing.T) {
+	clearEnv(t)
+
+	_, rest, err := Load([]string{"-repository.db_username=latte", "migrate", "down", "-steps", "2"})
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if want := []string{"migrate", "down", "-steps", "2"}; !reflect.DeepEqual(rest, want) {
+		t.Errorf("Load left %q, want %q", rest, want)
+	}
+}
+
+func TestLoadRejects(t *testing.T) {
+	tests := []struct {
+		name string
+		file string
+		body string
+		env  map[string]string
+		args []string
+		want []string // parts of the error message
+	}{
+		{
+			name: "unknown key in the file",
+			file: "config.json",
+			body: `{"app": {"prot": 9000}}`,
+			want: []string{`unknown setting "app.prot"`},
+		},
+		{
+			name: "unknown section in a yaml file",
+			file: "config.yaml",
+			body: "cache:\n  size: 10\n",
+			want: []string{`unknown setting "cache.size"`},
+		},
+		{
+			name: "malformed file",
+			file: "config.json",
+			body: `{"app": `,
+			want: []string{"parse config file"},
+		},
+		{
+			name: "bad value in the file",
+			file: "config.json",
+			body: `{"app": {"rto": "soon"}}`,
+			want: []string{"app.rto", `"soon" is not a duration`},
+		},
+		{
+			name: "bad value in the environment",
+			env:  map[string]string{"DB_PORT": "five"},
+			want: []string{"environment variable DB_PORT", `"five" is not a whole number`},
+		},
+		{
+			name: "missing secret file",
+			env:  map[string]string{"FRAPPUCCINO_AUTH_JWT_SECRET_FILE": "/nonexistent/jwt_secret"},
+			want: []string{"FRAPPUCCINO_AUTH_JWT_SECRET_FILE", "read secret file"},
+		},
+		{
+			name: "unknown flag",
+			args: []string{"-app.prot=9000"},
+			want: []string{"app.prot"},
+		},
+		{
+			name: "every invalid setting at once",
+			args: []string{"-app.port=0", "-log.level=loud", "-printing.max_attempts=0"},
+			want: []string{
+				"invalid configuration",
+				"app.port must be between 1 and 65535, got 0",
+				"repository.db_username must be set",
+				"printing.max_attempts must be at least 1, got 0",
+				`log.level must be one of debug, info, warn or error, got "loud"`,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clearEnv(t)
+			args := tt.args
+			if tt.file != "" {
+				args = append([]string{"-config", writeFile(t, tt.file, tt.body)}, args...)
+			}
+			for name, value := range tt.env {
+				t.Setenv(name, value)
+			}
+
+			_, _, err := Load(args)
+			if err == nil {
+				t.Fatal("Load succeeded, want an error")
+			}
+			for _, part := range tt.want {
+				if !strings.Contains(err.Error(), part) {
+					t.Errorf("Load error %q does not mention %q", err, part)
+				}
+			}
+		})
+	}
+}
+
+func TestSettingsOfRejectsUnsupportedTypes(t *testing.T) {
+	var cfg struct {
+		Store struct {
+			Hours []string `json:"hours"`
+		} `json:"store"`
+	}
+
+	_, err := settingsOf(reflect.ValueOf(&cfg).Elem(), "")
+	if err == nil || !strings.Contains(err.Error(), "store.hours") || !strings.Contains(err.Error(), "[]string") {
+		t.Fatalf("settingsOf error = %v, want one about store.hours of type []string", err)
+	}
+}
@@ -0,0 +1,129 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+)
+
+var (
+	logLevels  = map[string]bool{"debug": true, "info": true, "warn": true, "error": true}
+	logFormats = map[string]bool{"json": true, "text": true}
+	exporters  = map[string]bool{"none": true, "stdout": true, "otlp": true}
+)
+
+var sslModes = map[string]bool{
+	"disable": true, "allow": true, "prefer": true, "require": true, "verify-ca": true, "verify-full": true,
+}
+
+// Validate reports every setting that the program cannot run with, one per line
+func (c *Config) Validate() error {
+	var v validator
+
+	v.check(c.App.Port >= 1 && c.App.Port <= 65535, "app.port must be between 1 and 65535, got %d", c.App.Port)
+	v.positive("app.rto", c.App.RTO)
+	v.positive("app.wto", c.App.WTO)
+	v.notNegative("app.idle_timeout", c.App.IdleTimeout)
+	v.positive("app.shutdown_timeout", c.App.ShutdownTimeout)
+
+	r := c.Repository
+	v.check(r.DBHost != "", "repository.db_host must be set")
+	v.check(r.DBPort >= 1 && r.DBPort <= 65535, "repository.db_port must be between 1 and 65535, got %d", r.DBPort)
+	v.check(r.DBName != "", "repository.db_name must be set")
+	v.check(r.DBUsername != "", "repository.db_username must be set")
+	v.check(sslModes[r.DBSSLMode], "repository.db_ssl_mode must be one of disable, allow, prefer, require, verify-ca or verify-full, got %q", r.DBSSLMode)
+	v.check(r.MaxConn >= 1, "repository.max_conn must be at least 1, got %d", r.MaxConn)
+	v.check(r.MaxIdleConn >= 0 && r.MaxIdleConn <= r.MaxConn,
+		"repository.max_idle_conn must be between 0 and repository.max_conn (%d), got %d", r.MaxConn, r.MaxIdleConn)
+	v.notNegative("repository.conn_max_lifetime", r.ConnMaxLifetime)
+	// Postgres takes the connect timeout in whole seconds
+	v.check(r.ConnectTimeout == 0 || r.ConnectTimeout >= time.Second,
+		"repository.connect_timeout must be 0 or at least 1s, got %s", r.ConnectTimeout)
+	v.check(r.ConnectAttempts >= 1, "repository.connect_attempts must be at least 1, got %d", r.ConnectAttempts)
+	v.notNegative("repository.connect_retry_delay", r.ConnectRetryDelay)
+
+	v.positive("report.service_sla", c.Report.ServiceSLA)
+
+	v.positive("eta.default_prep_time", c.ETA.DefaultPrepTime)
+	v.check(c.ETA.ParallelOrders >= 1, "eta.parallel_orders must be at least 1, got %d", c.ETA.ParallelOrders)
+	v.positive("eta.history_window", c.ETA.HistoryWindow)
+
+	opening, openingOK := v.clock("schedule.opening_time", c.Schedule.OpeningTime)
+	closing, closingOK := v.clock("schedule.closing_time", c.Schedule.ClosingTime)
+	if openingOK && closingOK {
+		v.check(opening.Before(closing), "schedule.opening_time %s must be before schedule.closing_time %s",
+			c.Schedule.OpeningTime, c.Schedule.ClosingTime)
+	}
+	v.location("schedule.location", c.Schedule.Location)
+	v.notNegative("schedule.lead_time", c.Schedule.LeadTime)
+	v.positive("schedule.poll_interval", c.Schedule.PollInterval)
+
+	if c.Numbering.BusinessDayCutoff != "" {
+		v.clock("numbering.business_day_cutoff", c.Numbering.BusinessDayCutoff)
+	}
+	v.location("numbering.location", c.Numbering.Location)
+
+	v.check(c.Store.ReceiptWidth >= 0, "store.receipt_width must not be negative, got %d", c.Store.ReceiptWidth)
+
+	v.positive("printing.poll_interval", c.Printing.PollInterval)
+	v.positive("printing.dial_timeout", c.Printing.DialTimeout)
+	v.check(c.Printing.MaxAttempts >= 1, "printing.max_attempts must be at least 1, got %d", c.Printing.MaxAttempts)
+	v.notNegative("printing.retry_delay", c.Printing.RetryDelay)
+
+	v.check(c.Auth.Issuer != "", "auth.issuer must be set")
+	v.positive("auth.access_token_ttl", c.Auth.AccessTokenTTL)
+	v.check(c.Auth.RefreshTokenTTL > c.Auth.AccessTokenTTL,
+		"auth.refresh_token_ttl must be longer than auth.access_token_ttl (%s), got %s", c.Auth.AccessTokenTTL, c.Auth.RefreshTokenTTL)
+	v.check(c.Auth.JWTSecret == "" || len(c.Auth.JWTSecret) >= 32,
+		"auth.jwt_secret must be at least 32 characters, got %d", len(c.Auth.JWTSecret))
+
+	v.check(logLevels[strings.ToLower(c.Log.Level)], "log.level must be one of debug, info, warn or error, got %q", c.Log.Level)
+	v.check(logFormats[strings.ToLower(c.Log.Format)], "log.format must be json or text, got %q", c.Log.Format)
+
+	t := c.Tracing
+	v.check(exporters[strings.ToLower(t.Exporter)], "tracing.exporter must be none, stdout or otlp, got %q", t.Exporter)
+	if t.Endpoint != "" {
+		endpoint, err := url.Parse(t.Endpoint)
+		v.check(err == nil && (endpoint.Scheme == "http" || endpoint.Scheme == "https") && endpoint.Host != "",
+			"tracing.endpoint must be an http or https URL like http://localhost:4318, got %q", t.Endpoint)
+	}
+	v.check(t.SampleRatio >= 0 && t.SampleRatio <= 1, "tracing.sample_ratio must be between 0 and 1, got %g", t.SampleRatio)
+	v.check(t.ServiceName != "", "tracing.service_name must be set")
+
+	return errors.Join(v.problems...)
+}
+
+// validator collects the problems found in a configuration
+type validator struct {
+	problems []error
+}
+
+func (v *validator) check(ok bool, format string, args ...interface{}) {
+	if !ok {
+		v.problems = append(v.problems, fmt.Errorf(format, args...))
+	}
+}
+
+func (v *validator) positive(key string, d time.Duration) {
+	v.check(d > 0, "%s must be longer than 0, got %s", key, d)
+}
+
+func (v *validator) notNegative(key string, d time.Duration) {
+	v.check(d >= 0, "%s must not be negative, got %s", key, d)
+}
+
+func (v *validator) clock(key, value string) (time.Time, bool) {
+	t, err := time.Parse("15:04", value)
+	v.check(err == nil, "%s must be a time of day like 07:30, got %q", key, value)
+	return t, err == nil
+}
+
+func (v *validator) location(key, name string) {
+	if name == "" {
+		return
+	}
+	_, err := time.LoadLocation(name)
+	v.check(err == nil, "%s must be an IANA time zone like Europe/Berlin, got %q", key, name)
+}
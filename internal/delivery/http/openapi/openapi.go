@@ -0,0 +1,260 @@
+// Package openapi describes the API as an OpenAPI 3.1 document, built from the routes the
+// server registers and the DTOs their handlers read and write
+package openapi
+
+import (
+	"fmt"
+	"net/http"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+
+	"frappuccino/internal/delivery/http/problem"
+)
+
+// Version is the OpenAPI version of the documents Build returns
+const Version = "3.1.0"
+
+// Document is an OpenAPI document, with only the members this API needs
+type Document struct {
+	OpenAPI    string              `json:"openapi"`
+	Info       Info                `json:"info"`
+	Paths      map[string]PathItem `json:"paths"`
+	Components Components          `json:"components"`
+	Tags       []Tag               `json:"tags,omitempty"`
+}
+
+type Info struct {
+	Title       string `json:"title"`
+	Version     string `json:"version"`
+	Description string `json:"description,omitempty"`
+}
+
+type Tag struct {
+	Name string `json:"name"`
+}
+
+// PathItem holds the operations of a path by their lower case method
+type PathItem map[string]*Operation
+
+type Operation struct {
+	OperationID string                `json:"operationId,omitempty"`
+	Summary     string                `json:"summary,omitempty"`
+	Description string                `json:"description,omitempty"`
+	Tags        []string              `json:"tags,omitempty"`
+	Parameters  []Parameter           `json:"parameters,omitempty"`
+	RequestBody *RequestBody          `json:"requestBody,omitempty"`
+	Responses   map[string]Response   `json:"responses"`
+	Security    []map[string][]string `json:"security"`
+	// Permission is the permission of access a caller's role needs for the operation
+	Permission string `json:"x-permission,omitempty"`
+}
+
+type Parameter struct {
+	Name        string  `json:"name"`
+	In          string  `json:"in"`
+	Description string  `json:"description,omitempty"`
+	Required    bool    `json:"required,omitempty"`
+	Schema      *Schema `json:"schema"`
+}
+
+type RequestBody struct {
+	Required bool                 `json:"required"`
+	Content  map[string]MediaType `json:"content"`
+}
+
+type Response struct {
+	Description string               `json:"description"`
+	Content     map[string]MediaType `json:"content,omitempty"`
+}
+
+type MediaType struct {
+	Schema *Schema `json:"schema"`
+}
+
+type Components struct {
+	Schemas         map[string]*Schema        `json:"schemas"`
+	SecuritySchemes map[string]SecurityScheme `json:"securitySchemes"`
+}
+
+type SecurityScheme struct {
+	Type   string `json:"type"`
+	Scheme string `json:"scheme,omitempty"`
+	In     string `json:"in,omitempty"`
+	Name   string `json:"name,omitempty"`
+}
+
+// Route describes what a route registered on the router reads and answers
+type Route struct {
+	// Pattern is the pattern the route is registered with, like "GET /orders/{id}"
+	Pattern string
+	// ID names the operation, usually after the handler method
+	ID      string
+	Summary string
+	Tag     string
+	// Permission is what the route is authorized with; empty for routes any caller may use
+	Permission string
+	// Public routes can be called without credentials
+	Public bool
+	// Params are the query parameters, and the path parameters that are not plain strings
+	Params []Parameter
+	// Body is a value of the request DTO, nil when the route reads no body
+	Body any
+	// BodyOptional is set for routes that may be called without their body
+	BodyOptional bool
+	// Status is the status of success, 200 when left out
+	Status int
+	// Response is a value of what is encoded on success, nil when nothing is
+	Response any
+	// Media are other media types the route answers with instead of JSON, as text or bytes
+	Media []string
+}
+
+// Query is an optional query parameter
+func Query(name, description string, schema *Schema) Parameter {
+	return Parameter{Name: name, In: "query", Description: description, Schema: schema}
+}
+
+// Path is a path parameter with a schema other than a plain string
+func Path(name, description string, schema *Schema) Parameter {
+	return Parameter{Name: name, In: "path", Description: description, Required: true, Schema: schema}
+}
+
+var (
+	patternRE  = regexp.MustCompile(`^([A-Z]+) (/\S*)$`)
+	wildcardRE = regexp.MustCompile(`\{([^}.]+)(\.\.\.)?\}`)
+)
+
+const (
+	bearerScheme = "bearerAuth"
+	apiKeyScheme = "apiKey"
+)
+
+// Build describes routes as a document. Fields tagged enum= list the values enums returns for
+// the enum. It fails for a pattern it cannot read or one described twice.
+func Build(info Info, routes []Route, enums func(name string) ([]string, bool)) (*Document, error) {
+	schemas := newSchemas(enums)
+	doc := &Document{
+		OpenAPI: Version,
+		Info:    info,
+		Paths:   make(map[string]PathItem),
+		Components: Components{
+			Schemas: schemas.components,
+			SecuritySchemes: map[string]SecurityScheme{
+				bearerScheme: {Type: "http", Scheme: "bearer"},
+				apiKeyScheme: {Type: "apiKey", In: "header", Name: "X-API-Key"},
+			},
+		},
+	}
+	problemSchema := schemas.of(reflect.TypeOf(problem.Problem{}))
+
+	tags := make(map[string]bool)
+	for _, route := range routes {
+		m := patternRE.FindStringSubmatch(route.Pattern)
+		if m == nil {
+			return nil, fmt.Errorf("openapi: invalid pattern %q", route.Pattern)
+		}
+		method, path := strings.ToLower(m[1]), m[2]
+
+		item, ok := doc.Paths[path]
+		if !ok {
+			item = make(PathItem)
+			doc.Paths[path] = item
+		}
+		if _, ok := item[method]; ok {
+			return nil, fmt.Errorf("openapi: %q is described twice", route.Pattern)
+		}
+
+		op := &Operation{
+			OperationID: route.ID,
+			Summary:     route.Summary,
+			Permission:  route.Permission,
+			Parameters:  pathParams(path, route.Params),
+			Responses: map[string]Response{
+				"default": {
+					Description: "The problem that kept the request from succeeding",
+					Content:     map[string]MediaType{problem.ContentType: {Schema: problemSchema}},
+				},
+			},
+			Security: []map[string][]string{},
+		}
+		if route.Tag != "" {
+			op.Tags = []string{route.Tag}
+			tags[route.Tag] = true
+		}
+		for _, param := range route.Params {
+			if param.In != "path" {
+				op.Parameters = append(op.Parameters, param)
+			}
+		}
+		if !route.Public {
+			op.Security = []map[string][]string{{bearerScheme: {}}, {apiKeyScheme: {}}}
+			op.Parameters = append(op.Parameters, Parameter{
+				Name:        "X-Store-ID",
+				In:          "header",
+				Description: "ID or code of the store to work in, for callers without a home store",
+				Schema:      &Schema{Type: "string"},
+			})
+		}
+
+		if route.Body != nil {
+			op.RequestBody = &RequestBody{
+				Required: !route.BodyOptional,
+				Content:  map[string]MediaType{"application/json": {Schema: schemas.of(reflect.TypeOf(route.Body))}},
+			}
+		}
+
+		status := route.Status
+		if status == 0 {
+			status = http.StatusOK
+		}
+		response := Response{Description: http.StatusText(status)}
+		if route.Response != nil || len(route.Media) > 0 {
+			response.Content = make(map[string]MediaType)
+		}
+		if route.Response != nil {
+			response.Content["application/json"] = MediaType{Schema: schemas.of(reflect.TypeOf(route.Response))}
+		}
+		for _, media := range route.Media {
+			response.Content[media] = MediaType{Schema: &Schema{Type: "string"}}
+		}
+		op.Responses[fmt.Sprint(status)] = response
+
+		item[method] = op
+	}
+
+	for tag := range tags {
+		doc.Tags = append(doc.Tags, Tag{Name: tag})
+	}
+	sort.Slice(doc.Tags, func(i, j int) bool { return doc.Tags[i].Name < doc.Tags[j].Name })
+	return doc, nil
+}
+
+// pathParams lists the wildcards of path as parameters, using the schema of params where one is
+// given and a string otherwise
+func pathParams(path string, params []Parameter) []Parameter {
+	var list []Parameter
+	for _, m := range wildcardRE.FindAllStringSubmatch(path, -1) {
+		param := Parameter{Name: m[1], In: "path", Required: true, Schema: &Schema{Type: "string"}}
+		for _, p := range params {
+			if p.In == "path" && p.Name == m[1] {
+				param = p
+			}
+		}
+		list = append(list, param)
+	}
+	return list
+}
+
+// Operations lists the patterns doc describes, like "GET /orders/{id}"
+func (doc *Document) Operations() []string {
+	var patterns []string
+	for path, item := range doc.Paths {
+		for method := range item {
+			patterns = append(patterns, strings.ToUpper(method)+" "+path)
+		}
+	}
+	sort.Strings(patterns)
+	return patterns
+}
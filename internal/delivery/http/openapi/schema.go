@@ -0,0 +1,285 @@
+package openapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"path"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Schema is a JSON Schema, with only the keywords the DTOs need
+type Schema struct {
+	Ref                  string             `json:"$ref,omitempty"`
+	Type                 any                `json:"type,omitempty"` // a name, or a list of them
+	Format               string             `json:"format,omitempty"`
+	Description          string             `json:"description,omitempty"`
+	Enum                 []string           `json:"enum,omitempty"`
+	Properties           map[string]*Schema `json:"properties,omitempty"`
+	Required             []string           `json:"required,omitempty"`
+	Items                *Schema            `json:"items,omitempty"`
+	AdditionalProperties *Schema            `json:"additionalProperties,omitempty"`
+	AnyOf                []*Schema          `json:"anyOf,omitempty"`
+	Minimum              *float64           `json:"minimum,omitempty"`
+	Maximum              *float64           `json:"maximum,omitempty"`
+	ExclusiveMinimum     *float64           `json:"exclusiveMinimum,omitempty"`
+	ExclusiveMaximum     *float64           `json:"exclusiveMaximum,omitempty"`
+	MinLength            *int               `json:"minLength,omitempty"`
+	MaxLength            *int               `json:"maxLength,omitempty"`
+	MinItems             *int               `json:"minItems,omitempty"`
+	MaxItems             *int               `json:"maxItems,omitempty"`
+}
+
+// String, Integer, Number and Date are the schemas of simple parameters
+func String() *Schema  { return &Schema{Type: "string"} }
+func Integer() *Schema { return &Schema{Type: "integer"} }
+func Number() *Schema  { return &Schema{Type: "number"} }
+func Date() *Schema    { return &Schema{Type: "string", Format: "date"} }
+
+// Enum is the schema of a string that takes one of values
+func Enum(values ...string) *Schema {
+	return &Schema{Type: "string", Enum: values}
+}
+
+var (
+	timeType       = reflect.TypeOf(time.Time{})
+	durationType   = reflect.TypeOf(time.Duration(0))
+	rawMessageType = reflect.TypeOf(json.RawMessage{})
+)
+
+// schemas derives schemas from Go types. Named structs become components, referred to by the
+// package and name of the type, like order.CreateOrderRequest.
+type schemas struct {
+	components map[string]*Schema
+	enums      func(name string) ([]string, bool)
+}
+
+func newSchemas(enums func(name string) ([]string, bool)) *schemas {
+	return &schemas{
+		components: make(map[string]*Schema),
+		enums:      enums,
+	}
+}
+
+func (s *schemas) of(t reflect.Type) *Schema {
+	switch t {
+	case timeType:
+		return &Schema{Type: "string", Format: "date-time"}
+	case durationType:
+		return &Schema{Type: "integer", Description: "nanoseconds"}
+	case rawMessageType:
+		return &Schema{Description: "any JSON value"}
+	}
+
+	switch t.Kind() {
+	case reflect.Pointer:
+		return s.of(t.Elem())
+	case reflect.Bool:
+		return &Schema{Type: "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return &Schema{Type: "integer"}
+	case reflect.Float32, reflect.Float64:
+		return &Schema{Type: "number"}
+	case reflect.String:
+		return &Schema{Type: "string"}
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return &Schema{Type: "string", Format: "byte"}
+		}
+		schema := &Schema{Type: "array", Items: s.of(t.Elem())}
+		if t.Kind() == reflect.Array {
+			schema.MinItems, schema.MaxItems = intPtr(t.Len()), intPtr(t.Len())
+		}
+		return schema
+	case reflect.Map:
+		return &Schema{Type: "object", AdditionalProperties: s.of(t.Elem())}
+	case reflect.Struct:
+		if t.Name() == "" {
+			return s.object(t)
+		}
+		name := path.Base(t.PkgPath()) + "." + t.Name()
+		if _, ok := s.components[name]; !ok {
+			// Claimed before the fields are described, for types that refer to themselves
+			s.components[name] = &Schema{}
+			*s.components[name] = *s.object(t)
+		}
+		return &Schema{Ref: "#/components/schemas/" + name}
+	default:
+		// interface{} fields hold whatever the service puts there
+		return &Schema{}
+	}
+}
+
+// object describes a struct as encoding/json writes it
+func (s *schemas) object(t reflect.Type) *Schema {
+	schema := &Schema{Type: "object", Properties: make(map[string]*Schema)}
+	s.fields(t, schema)
+	return schema
+}
+
+func (s *schemas) fields(t reflect.Type, schema *Schema) {
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		if !sf.IsExported() {
+			continue
+		}
+		tag := sf.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, options, _ := strings.Cut(tag, ",")
+
+		if sf.Anonymous && name == "" {
+			embedded := sf.Type
+			if embedded.Kind() == reflect.Pointer {
+				embedded = embedded.Elem()
+			}
+			if embedded.Kind() == reflect.Struct {
+				s.fields(embedded, schema)
+				continue
+			}
+		}
+		if name == "" {
+			name = sf.Name
+		}
+
+		field := s.of(sf.Type)
+		if sf.Type.Kind() == reflect.Pointer && !strings.Contains(options, "omitempty") {
+			field = nullable(field)
+		}
+		if s.constrain(t, field, sf.Tag.Get("validate")) {
+			schema.Required = append(schema.Required, name)
+		}
+		schema.Properties[name] = field
+	}
+}
+
+// constrain adds the rules of a validate tag to the schema of a field, and reports whether the
+// tag requires the field. Rules after dive apply to the items of the field.
+func (s *schemas) constrain(parent reflect.Type, schema *Schema, tag string) (required bool) {
+	if tag == "" {
+		return false
+	}
+
+	target := schema
+	var notes []string
+	for _, part := range strings.Split(tag, ",") {
+		rule, param, _ := strings.Cut(strings.TrimSpace(part), "=")
+		switch rule {
+		case "dive":
+			if target.Items == nil {
+				return required
+			}
+			target = target.Items
+		case "required":
+			if target == schema {
+				required = true
+			}
+		case "required_if":
+			sibling, value, _ := strings.Cut(param, " ")
+			notes = append(notes, fmt.Sprintf("Required when %s is %s.", jsonName(parent, sibling), value))
+		case "required_without":
+			notes = append(notes, fmt.Sprintf("Required when %s is not set.", jsonName(parent, param)))
+		case "excluded_with":
+			notes = append(notes, fmt.Sprintf("Must not be set together with %s.", jsonName(parent, param)))
+		case "gt", "gte", "min", "lt", "lte", "max":
+			bound(target, rule, param)
+		case "oneof":
+			target.Enum = strings.Fields(param)
+		case "uuid":
+			target.Format = "uuid"
+		case "enum":
+			if values, ok := s.enums(param); ok {
+				target.Enum = values
+			} else {
+				notes = append(notes, fmt.Sprintf("One of the values of %s.", param))
+			}
+		}
+	}
+
+	if len(notes) > 0 {
+		schema.Description = strings.TrimSpace(schema.Description + " " + strings.Join(notes, " "))
+	}
+	return required
+}
+
+// bound sets the limit a comparison rule puts on a number, or on the length of a string or array
+func bound(schema *Schema, rule, param string) {
+	n, err := strconv.ParseFloat(param, 64)
+	if err != nil {
+		return
+	}
+
+	typ := typeName(schema)
+	switch typ {
+	case "integer", "number":
+		switch rule {
+		case "gt":
+			schema.ExclusiveMinimum = &n
+		case "gte", "min":
+			schema.Minimum = &n
+		case "lt":
+			schema.ExclusiveMaximum = &n
+		case "lte", "max":
+			schema.Maximum = &n
+		}
+	case "string", "array":
+		limit := int(n)
+		switch rule {
+		case "gt":
+			limit++
+		case "lt":
+			limit--
+		}
+		lower := rule == "gt" || rule == "gte" || rule == "min"
+		switch {
+		case typ == "string" && lower:
+			schema.MinLength = &limit
+		case typ == "string":
+			schema.MaxLength = &limit
+		case lower:
+			schema.MinItems = &limit
+		default:
+			schema.MaxItems = &limit
+		}
+	}
+}
+
+// typeName is the type of a schema, leaving out the null of a nullable one
+func typeName(schema *Schema) string {
+	switch t := schema.Type.(type) {
+	case string:
+		return t
+	case []string:
+		return t[0]
+	}
+	return ""
+}
+
+// nullable lets a schema also be null, as a nil pointer is encoded
+func nullable(schema *Schema) *Schema {
+	if t, ok := schema.Type.(string); ok {
+		schema.Type = []string{t, "null"}
+		return schema
+	}
+	return &Schema{AnyOf: []*Schema{schema, {Type: "null"}}}
+}
+
+// jsonName is the name a field of t, known by its Go name, has in JSON
+func jsonName(t reflect.Type, field string) string {
+	sf, ok := t.FieldByName(field)
+	if !ok {
+		return field
+	}
+	if name, _, _ := strings.Cut(sf.Tag.Get("json"), ","); name != "" && name != "-" {
+		return name
+	}
+	return field
+}
+
+func intPtr(n int) *int {
+	return &n
+}
@@ -0,0 +1,9 @@
+package openapi
+
+import _ "embed"
+
+// Viewer is a page that loads openapi.json from next to it and lists its operations, with their
+// parameters and schemas, and a form to send each request
+//
+//go:embed viewer.html
+var Viewer []byte
@@ -0,0 +1,100 @@
+package problem
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/lib/pq"
+
+	"frappuccino/internal/apperror"
+	"frappuccino/internal/logging"
+)
+
+// ContentType is the media type of problem details, RFC 7807
+const ContentType = "application/problem+json"
+
+// typePrefix makes the stable code of a problem the URI its type field needs
+const typePrefix = "urn:frappuccino:problem:"
+
+// Codes of problems that no service error describes
+const (
+	CodeNotFound         = "not_found"
+	CodeConflict         = "conflict"
+	CodeInvalidReference = "invalid_reference"
+	CodeInternal         = "internal_error"
+)
+
+// SQLSTATE codes of the constraint violations a request can cause
+const (
+	uniqueViolation     pq.ErrorCode = "23505"
+	foreignKeyViolation pq.ErrorCode = "23503"
+)
+
+var statuses = map[apperror.Kind]int{
+	apperror.Invalid:       http.StatusBadRequest,
+	apperror.Unauthorized:  http.StatusUnauthorized,
+	apperror.Forbidden:     http.StatusForbidden,
+	apperror.NotFound:      http.StatusNotFound,
+	apperror.Conflict:      http.StatusConflict,
+	apperror.Unprocessable: http.StatusUnprocessableEntity,
+}
+
+// Problem is the body of every error response. Code, the request ID and the lists of invalid
+// fields and short ingredients extend the members RFC 7807 defines.
+type Problem struct {
+	Type      string                `json:"type"`
+	Title     string                `json:"title"`
+	Status    int                   `json:"status"`
+	Detail    string                `json:"detail,omitempty"`
+	Instance  string                `json:"instance,omitempty"`
+	Code      string                `json:"code"`
+	RequestID string                `json:"request_id,omitempty"`
+	Fields    []apperror.FieldError `json:"fields,omitempty"`
+	Shortages []apperror.Shortage   `json:"shortages,omitempty"`
+}
+
+// From describes err as a problem. Service errors keep their code and message, without the
+// context wrapped around them on the way up. sql.ErrNoRows, which repositories return for rows
+// that do not exist or belong to another store, is a 404. Constraint violations the database
+// reports are the request's fault too: a duplicate of a unique value is a 409, a reference to
+// something that does not exist a 422. Deletes of something still in use say so themselves, as
+// the violation alone does not tell which side of the key failed. Anything else is an internal
+// error whose message stays in the logs.
+func From(err error) Problem {
+	var appErr *apperror.Error
+	var pqErr *pq.Error
+	switch {
+	case errors.As(err, &appErr) && appErr.Kind != apperror.Internal:
+		return Problem{
+			Status:    statuses[appErr.Kind],
+			Code:      appErr.Code,
+			Detail:    appErr.Message,
+			Fields:    appErr.Fields,
+			Shortages: appErr.Shortages,
+		}
+	case errors.Is(err, sql.ErrNoRows):
+		return Problem{Status: http.StatusNotFound, Code: CodeNotFound, Detail: "The requested resource does not exist"}
+	case errors.As(err, &pqErr) && pqErr.Code == uniqueViolation:
+		return Problem{Status: http.StatusConflict, Code: CodeConflict, Detail: "A resource with the same unique value already exists"}
+	case errors.As(err, &pqErr) && pqErr.Code == foreignKeyViolation:
+		return Problem{Status: http.StatusUnprocessableEntity, Code: CodeInvalidReference, Detail: "The request refers to a resource that does not exist"}
+	default:
+		return Problem{Status: http.StatusInternalServerError, Code: CodeInternal, Detail: "The server could not complete the request"}
+	}
+}
+
+// Write answers the request with the problem err describes
+func Write(w http.ResponseWriter, r *http.Request, err error) {
+	p := From(err)
+	p.Type = typePrefix + p.Code
+	p.Title = http.StatusText(p.Status)
+	p.Instance = r.URL.Path
+	p.RequestID = logging.RequestID(r.Context())
+
+	w.Header().Set("Content-Type", ContentType)
+	w.WriteHeader(p.Status)
+	// The body is plain data, so encoding only fails when the client has gone
+	json.NewEncoder(w).Encode(p)
+}
@@ -0,0 +1,92 @@
+package problem
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/lib/pq"
+
+	"frappuccino/internal/apperror"
+)
+
+func TestFrom(t *testing.T) {
+	tests := []struct {
+		name   string
+		err    error
+		status int
+		code   string
+		detail string // checked when set
+	}{
+		{
+			name:   "service error",
+			err:    fmt.Errorf("create table: %w", apperror.New(apperror.Conflict, "table_occupied", "the table is occupied")),
+			status: http.StatusConflict,
+			code:   "table_occupied",
+		},
+		{
+			name:   "service error with detail",
+			err:    fmt.Errorf("seat party: %w", apperror.New(apperror.Conflict, "table_capacity", "the table is too small").WithDetail("table 4 seats 2")),
+			status: http.StatusConflict,
+			code:   "table_capacity",
+			detail: "the table is too small: table 4 seats 2",
+		},
+		{
+			name:   "missing row",
+			err:    fmt.Errorf("get order: %w", sql.ErrNoRows),
+			status: http.StatusNotFound,
+			code:   CodeNotFound,
+		},
+		{
+			name: "duplicate username",
+			err: fmt.Errorf("insert user: %w", &pq.Error{
+				Code:   "23505",
+				Detail: "Key (username)=(ana) already exists.",
+			}),
+			status: http.StatusConflict,
+			code:   CodeConflict,
+		},
+		{
+			name: "unknown courier",
+			err: fmt.Errorf("assign courier: %w", &pq.Error{
+				Code:       "23503",
+				Table:      "deliveries",
+				Constraint: "deliveries_courier_id_fkey",
+			}),
+			status: http.StatusUnprocessableEntity,
+			code:   CodeInvalidReference,
+		},
+		{
+			name:   "other database error",
+			err:    fmt.Errorf("insert order: %w", &pq.Error{Code: "23514"}),
+			status: http.StatusInternalServerError,
+			code:   CodeInternal,
+		},
+		{
+			name:   "internal service error",
+			err:    apperror.New(apperror.Internal, "boom", "connection details"),
+			status: http.StatusInternalServerError,
+			code:   CodeInternal,
+		},
+		{
+			name:   "plain error",
+			err:    errors.New("connection refused"),
+			status: http.StatusInternalServerError,
+			code:   CodeInternal,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := From(tt.err)
+			if p.Status != tt.status || p.Code != tt.code {
+				t.Errorf("From() = %d %s, want %d %s", p.Status, p.Code, tt.status, tt.code)
+			}
+			if tt.detail != "" && p.Detail != tt.detail {
+				t.Errorf("From() detail = %q, want %q", p.Detail, tt.detail)
+			}
+		})
+	}
+}
@@ -0,0 +1,69 @@
+package v1
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"time"
+
+	"frappuccino/internal/service/access"
+)
+
+// authorize wraps a handler so it only runs for callers whose role holds the permission
+func authorize(permission access.Permission, next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if err := access.Check(r.Context(), permission); err != nil {
+			writeError(w, r, err)
+			return
+		}
+		next(w, r)
+	}
+}
+
+// GetAccessDenialsResponse handles the GET /reports/access-denials endpoint
+func (h *AccessHandler) GetAccessDenialsResponse(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+
+	var startDate, endDate *time.Time
+	if startDateStr := query.Get("startDate"); startDateStr != "" {
+		date, err := parseDate(startDateStr)
+		if err != nil {
+			writeError(w, r, invalidDate("startDate"))
+			return
+		}
+		startDate = &date
+	}
+	if endDateStr := query.Get("endDate"); endDateStr != "" {
+		date, err := parseDate(endDateStr)
+		if err != nil {
+			writeError(w, r, invalidDate("endDate"))
+			return
+		}
+		endDate = &date
+	}
+
+	limit := 0
+	if limitStr := query.Get("limit"); limitStr != "" {
+		var err error
+		limit, err = strconv.Atoi(limitStr)
+		if err != nil || limit <= 0 {
+			writeError(w, r, invalidParam("limit", "limit must be a positive integer"))
+			return
+		}
+	}
+
+	denials, err := h.accessService.GetAccessDenials(r.Context(), startDate, endDate, limit)
+	if err != nil {
+		h.logger.ErrorContext(r.Context(), "GetAccessDenials failed", "handler", "GetAccessDenialsResponse", "error", err)
+		writeError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(w).Encode(denials); err != nil {
+		h.logger.ErrorContext(r.Context(), "Error encoding response", "handler", "GetAccessDenialsResponse", "error", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+}
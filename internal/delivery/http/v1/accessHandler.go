@@ -0,0 +1,31 @@
+package v1
+
+import (
+	"log/slog"
+	"net/http"
+)
+
+// AccessHandler reports the requests refused for the caller's role
+type AccessHandler struct {
+	logger        *slog.Logger
+	accessService accessInterface
+}
+
+func NewAccessHandler(
+	accessService accessInterface,
+	logger *slog.Logger,
+) *AccessHandler {
+	return &AccessHandler{
+		accessService: accessService,
+		logger:        logger,
+	}
+}
+
+func SetAccessHandler(
+	router *http.ServeMux,
+	accessService accessInterface,
+	logger *slog.Logger,
+) {
+	handler := NewAccessHandler(accessService, logger)
+	setAccessRoutes(handler, router)
+}
@@ -6,6 +6,7 @@ import (
 	"strconv"
 
 	"frappuccino/internal/dto/report"
+	"frappuccino/internal/validate"
 )
 
 // GetTotalSales handles the GET /reports/total-sales endpoint
@@ -24,8 +25,8 @@ func (h *ReportHandler) GetTotalSales(w http.ResponseWriter, r *http.Request) {
 	if startDateStr != "" {
 		startDate, err := parseDate(startDateStr)
 		if err != nil {
-			h.logger.Printf("Invalid startDate format: %v", err)
-			http.Error(w, "Invalid startDate format. Please use YYYY-MM-DD format.", http.StatusBadRequest)
+			h.logger.WarnContext(r.Context(), "Invalid startDate format", "error", err)
+			writeError(w, r, invalidDate("startDate"))
 			return
 		}
 		req.StartDate = &startDate
@@ -34,25 +35,31 @@ func (h *ReportHandler) GetTotalSales(w http.ResponseWriter, r *http.Request) {
 	if endDateStr != "" {
 		endDate, err := parseDate(endDateStr)
 		if err != nil {
-			h.logger.Printf("Invalid endDate format: %v", err)
-			http.Error(w, "Invalid endDate format. Please use YYYY-MM-DD format.", http.StatusBadRequest)
+			h.logger.WarnContext(r.Context(), "Invalid endDate format", "error", err)
+			writeError(w, r, invalidDate("endDate"))
 			return
 		}
 		req.EndDate = &endDate
 	}
 
+	if err := validate.Struct(req); err != nil {
+		h.logger.WarnContext(r.Context(), "Invalid total sales request", "error", err)
+		writeError(w, r, err)
+		return
+	}
+
 	// Get total sales
 	response, err := h.reportService.GetTotalSales(r.Context(), req)
 	if err != nil {
-		h.logger.Printf("Error getting total sales: %v", err)
-		http.Error(w, "Error generating total sales report", http.StatusInternalServerError)
+		h.logger.ErrorContext(r.Context(), "Error getting total sales", "error", err)
+		writeError(w, r, err)
 		return
 	}
 
 	// Return response
 	w.Header().Set("Content-Type", "application/json")
 	if err := json.NewEncoder(w).Encode(response); err != nil {
-		h.logger.Printf("Error encoding total sales response: %v", err)
+		h.logger.ErrorContext(r.Context(), "Error encoding total sales response", "error", err)
 		http.Error(w, "Error encoding response", http.StatusInternalServerError)
 		return
 	}
@@ -72,8 +79,8 @@ func (h *ReportHandler) GetPopularItems(w http.ResponseWriter, r *http.Request)
 	if startDateStr != "" {
 		startDate, err := parseDate(startDateStr)
 		if err != nil {
-			h.logger.Printf("Invalid startDate format: %v", err)
-			http.Error(w, "Invalid startDate format. Please use YYYY-MM-DD format.", http.StatusBadRequest)
+			h.logger.WarnContext(r.Context(), "Invalid startDate format", "error", err)
+			writeError(w, r, invalidDate("startDate"))
 			return
 		}
 		req.StartDate = &startDate
@@ -82,8 +89,8 @@ func (h *ReportHandler) GetPopularItems(w http.ResponseWriter, r *http.Request)
 	if endDateStr != "" {
 		endDate, err := parseDate(endDateStr)
 		if err != nil {
-			h.logger.Printf("Invalid endDate format: %v", err)
-			http.Error(w, "Invalid endDate format. Please use YYYY-MM-DD format.", http.StatusBadRequest)
+			h.logger.WarnContext(r.Context(), "Invalid endDate format", "error", err)
+			writeError(w, r, invalidDate("endDate"))
 			return
 		}
 		req.EndDate = &endDate
@@ -93,8 +100,8 @@ func (h *ReportHandler) GetPopularItems(w http.ResponseWriter, r *http.Request)
 	if limitStr != "" {
 		limit, err := strconv.Atoi(limitStr)
 		if err != nil || limit < 1 {
-			h.logger.Printf("Invalid limit parameter: %v", err)
-			http.Error(w, "Invalid limit parameter. Must be a positive integer.", http.StatusBadRequest)
+			h.logger.WarnContext(r.Context(), "Invalid limit parameter", "error", err)
+			writeError(w, r, invalidParam("limit", "limit must be a positive integer"))
 			return
 		}
 		req.Limit = limit
@@ -103,15 +110,15 @@ func (h *ReportHandler) GetPopularItems(w http.ResponseWriter, r *http.Request)
 	// Get popular items
 	response, err := h.reportService.GetPopularItems(r.Context(), req)
 	if err != nil {
-		h.logger.Printf("Error getting popular items: %v", err)
-		http.Error(w, "Error generating popular items report", http.StatusInternalServerError)
+		h.logger.ErrorContext(r.Context(), "Error getting popular items", "error", err)
+		writeError(w, r, err)
 		return
 	}
 
 	// Return response
 	w.Header().Set("Content-Type", "application/json")
 	if err := json.NewEncoder(w).Encode(response); err != nil {
-		h.logger.Printf("Error encoding popular items response: %v", err)
+		h.logger.ErrorContext(r.Context(), "Error encoding popular items response", "error", err)
 		http.Error(w, "Error encoding response", http.StatusInternalServerError)
 		return
 	}
@@ -0,0 +1,221 @@
+package v1
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"frappuccino/internal/dto/auth"
+)
+
+// LoginRequest handles the POST /auth/login endpoint
+func (h *AuthHandler) LoginRequest(w http.ResponseWriter, r *http.Request) {
+	var request auth.LoginRequest
+	if err := decodeBody(r, &request); err != nil {
+		h.logger.WarnContext(r.Context(), "Invalid request body", "handler", "LoginRequest", "error", err)
+		writeError(w, r, err)
+		return
+	}
+
+	tokens, err := h.authService.Login(r.Context(), request)
+	if err != nil {
+		h.logger.ErrorContext(r.Context(), "Login failed", "handler", "LoginRequest", "error", err)
+		writeError(w, r, err)
+		return
+	}
+
+	h.writeTokens(w, r, "LoginRequest", tokens)
+}
+
+// RefreshRequest handles the POST /auth/refresh endpoint
+func (h *AuthHandler) RefreshRequest(w http.ResponseWriter, r *http.Request) {
+	var request auth.RefreshRequest
+	if err := decodeBody(r, &request); err != nil {
+		h.logger.WarnContext(r.Context(), "Invalid request body", "handler", "RefreshRequest", "error", err)
+		writeError(w, r, err)
+		return
+	}
+
+	tokens, err := h.authService.Refresh(r.Context(), request)
+	if err != nil {
+		h.logger.ErrorContext(r.Context(), "Refresh failed", "handler", "RefreshRequest", "error", err)
+		writeError(w, r, err)
+		return
+	}
+
+	h.writeTokens(w, r, "RefreshRequest", tokens)
+}
+
+// LogoutRequest handles the POST /auth/logout endpoint
+func (h *AuthHandler) LogoutRequest(w http.ResponseWriter, r *http.Request) {
+	var request auth.LogoutRequest
+	if err := decodeOptionalBody(r, &request); err != nil {
+		h.logger.WarnContext(r.Context(), "Invalid request body", "handler", "LogoutRequest", "error", err)
+		writeError(w, r, err)
+		return
+	}
+
+	if err := h.authService.Logout(r.Context(), request); err != nil {
+		h.logger.ErrorContext(r.Context(), "Logout failed", "handler", "LogoutRequest", "error", err)
+		writeError(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// ChangePasswordRequest handles the POST /auth/password endpoint
+func (h *AuthHandler) ChangePasswordRequest(w http.ResponseWriter, r *http.Request) {
+	var request auth.ChangePasswordRequest
+	if err := decodeBody(r, &request); err != nil {
+		h.logger.WarnContext(r.Context(), "Invalid request body", "handler", "ChangePasswordRequest", "error", err)
+		writeError(w, r, err)
+		return
+	}
+
+	if err := h.authService.ChangePassword(r.Context(), request); err != nil {
+		h.logger.ErrorContext(r.Context(), "ChangePassword failed", "handler", "ChangePasswordRequest", "error", err)
+		writeError(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *AuthHandler) CreateUserRequest(w http.ResponseWriter, r *http.Request) {
+	var request auth.CreateUserRequest
+	if err := decodeBody(r, &request); err != nil {
+		h.logger.WarnContext(r.Context(), "Invalid request body", "handler", "CreateUserRequest", "error", err)
+		writeError(w, r, err)
+		return
+	}
+
+	id, err := h.authService.CreateUser(r.Context(), request)
+	if err != nil {
+		h.logger.ErrorContext(r.Context(), "CreateUser failed", "handler", "CreateUserRequest", "error", err)
+		writeError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(w).Encode(id); err != nil {
+		h.logger.ErrorContext(r.Context(), "Error encoding response", "handler", "CreateUserRequest", "error", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+}
+
+func (h *AuthHandler) GetUsersResponse(w http.ResponseWriter, r *http.Request) {
+	users, err := h.authService.GetUsers(r.Context())
+	if err != nil {
+		h.logger.ErrorContext(r.Context(), "GetUsers failed", "handler", "GetUsersResponse", "error", err)
+		writeError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(w).Encode(users); err != nil {
+		h.logger.ErrorContext(r.Context(), "Error encoding response", "handler", "GetUsersResponse", "error", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+}
+
+// UpdateUserRequest handles the PATCH /users/{id} endpoint, which changes a user's role or deactivates them
+func (h *AuthHandler) UpdateUserRequest(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+	if id == "" {
+		h.logger.WarnContext(r.Context(), "Missing id parameter", "handler", "UpdateUserRequest")
+		writeError(w, r, missingParam("id"))
+		return
+	}
+
+	var request auth.UpdateUserRequest
+	if err := decodeBody(r, &request); err != nil {
+		h.logger.WarnContext(r.Context(), "Invalid request body", "handler", "UpdateUserRequest", "error", err)
+		writeError(w, r, err)
+		return
+	}
+
+	if err := h.authService.UpdateUser(r.Context(), id, request); err != nil {
+		h.logger.ErrorContext(r.Context(), "UpdateUser failed", "handler", "UpdateUserRequest", "error", err)
+		writeError(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// CreateAPIKeyRequest handles the POST /api-keys endpoint; the key is only ever shown in this response
+func (h *AuthHandler) CreateAPIKeyRequest(w http.ResponseWriter, r *http.Request) {
+	var request auth.CreateAPIKeyRequest
+	if err := decodeBody(r, &request); err != nil {
+		h.logger.WarnContext(r.Context(), "Invalid request body", "handler", "CreateAPIKeyRequest", "error", err)
+		writeError(w, r, err)
+		return
+	}
+
+	key, err := h.authService.CreateAPIKey(r.Context(), request)
+	if err != nil {
+		h.logger.ErrorContext(r.Context(), "CreateAPIKey failed", "handler", "CreateAPIKeyRequest", "error", err)
+		writeError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(w).Encode(key); err != nil {
+		h.logger.ErrorContext(r.Context(), "Error encoding response", "handler", "CreateAPIKeyRequest", "error", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+}
+
+func (h *AuthHandler) GetAPIKeysResponse(w http.ResponseWriter, r *http.Request) {
+	keys, err := h.authService.GetAPIKeys(r.Context())
+	if err != nil {
+		h.logger.ErrorContext(r.Context(), "GetAPIKeys failed", "handler", "GetAPIKeysResponse", "error", err)
+		writeError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(w).Encode(keys); err != nil {
+		h.logger.ErrorContext(r.Context(), "Error encoding response", "handler", "GetAPIKeysResponse", "error", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+}
+
+// RevokeAPIKeyRequest handles the DELETE /api-keys/{id} endpoint
+func (h *AuthHandler) RevokeAPIKeyRequest(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+	if id == "" {
+		h.logger.WarnContext(r.Context(), "Missing id parameter", "handler", "RevokeAPIKeyRequest")
+		writeError(w, r, missingParam("id"))
+		return
+	}
+
+	if err := h.authService.RevokeAPIKey(r.Context(), id); err != nil {
+		h.logger.ErrorContext(r.Context(), "RevokeAPIKey failed", "handler", "RevokeAPIKeyRequest", "error", err)
+		writeError(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// writeTokens encodes issued tokens, which must not end up in caches
+func (h *AuthHandler) writeTokens(w http.ResponseWriter, r *http.Request, method string, tokens auth.TokenResponse) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(w).Encode(tokens); err != nil {
+		h.logger.ErrorContext(r.Context(), "Error encoding response", "handler", method, "error", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+}
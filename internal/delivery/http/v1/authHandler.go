@@ -0,0 +1,31 @@
+package v1
+
+import (
+	"log/slog"
+	"net/http"
+)
+
+// AuthHandler handles staff logins, users and the API keys of integrations
+type AuthHandler struct {
+	logger      *slog.Logger
+	authService authInterface
+}
+
+func NewAuthHandler(
+	authService authInterface,
+	logger *slog.Logger,
+) *AuthHandler {
+	return &AuthHandler{
+		authService: authService,
+		logger:      logger,
+	}
+}
+
+func SetAuthHandler(
+	router *http.ServeMux,
+	authService authInterface,
+	logger *slog.Logger,
+) {
+	handler := NewAuthHandler(authService, logger)
+	setAuthRoutes(handler, router)
+}
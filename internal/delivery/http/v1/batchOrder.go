@@ -11,24 +11,17 @@ import (
 func (h *OrderHandler) BatchProcessOrdersRequest(w http.ResponseWriter, r *http.Request) {
 	// Parse request body
 	var request orderdto.BatchOrderRequest
-	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
-		h.logger.Println("method:BatchProcessOrdersRequest, function:json decode", err.Error())
-		http.Error(w, "Invalid request format", http.StatusBadRequest)
-		return
-	}
-
-	// Validate request
-	if len(request.Orders) == 0 {
-		h.logger.Println("method:BatchProcessOrdersRequest, error:empty orders list")
-		http.Error(w, "No orders provided", http.StatusBadRequest)
+	if err := decodeBody(r, &request); err != nil {
+		h.logger.WarnContext(r.Context(), "Invalid request body", "handler", "BatchProcessOrdersRequest", "error", err)
+		writeError(w, r, err)
 		return
 	}
 
 	// Process batch orders
 	response, err := h.orderService.BatchProcessOrders(r.Context(), request)
 	if err != nil {
-		h.logger.Println("method:BatchProcessOrdersRequest, function:BatchProcessOrders", err.Error())
-		http.Error(w, "Error processing batch orders", http.StatusInternalServerError)
+		h.logger.ErrorContext(r.Context(), "BatchProcessOrders failed", "handler", "BatchProcessOrdersRequest", "error", err)
+		writeError(w, r, err)
 		return
 	}
 
@@ -36,7 +29,7 @@ func (h *OrderHandler) BatchProcessOrdersRequest(w http.ResponseWriter, r *http.
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusOK)
 	if err := json.NewEncoder(w).Encode(response); err != nil {
-		h.logger.Println("method:BatchProcessOrdersRequest, function:json encode", err.Error())
+		h.logger.ErrorContext(r.Context(), "Error encoding response", "handler", "BatchProcessOrdersRequest", "error", err)
 		http.Error(w, "Error encoding response", http.StatusInternalServerError)
 		return
 	}
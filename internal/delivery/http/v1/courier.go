@@ -0,0 +1,33 @@
+package v1
+
+import (
+	"net/http"
+
+	"frappuccino/internal/dto/order"
+)
+
+// AssignCourierRequest handles the POST /orders/{id}/courier endpoint
+func (h *OrderHandler) AssignCourierRequest(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+	if id == "" {
+		h.logger.WarnContext(r.Context(), "Missing id parameter", "handler", "AssignCourierRequest")
+		writeError(w, r, missingParam("id"))
+		return
+	}
+
+	var request order.AssignCourierRequest
+	if err := decodeBody(r, &request); err != nil {
+		h.logger.WarnContext(r.Context(), "Invalid request body", "handler", "AssignCourierRequest", "error", err)
+		writeError(w, r, err)
+		return
+	}
+
+	updated, err := h.orderService.AssignCourier(r.Context(), id, request)
+	if err != nil {
+		h.logger.ErrorContext(r.Context(), "AssignCourier failed", "handler", "AssignCourierRequest", "error", err)
+		writeError(w, r, err)
+		return
+	}
+
+	h.writeOrder(w, r, "AssignCourierRequest", updated)
+}
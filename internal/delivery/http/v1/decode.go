@@ -0,0 +1,26 @@
+package v1
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+
+	"frappuccino/internal/validate"
+)
+
+// decodeBody reads the JSON body of a request into dst and checks it against the validate tags
+// of its DTO, so that services only see requests that are well formed
+func decodeBody(r *http.Request, dst any) error {
+	if err := json.NewDecoder(r.Body).Decode(dst); err != nil {
+		return errInvalidBody.WithDetail(err.Error())
+	}
+	return validate.Struct(dst)
+}
+
+// decodeOptionalBody is decodeBody for endpoints that may be called without a body
+func decodeOptionalBody(r *http.Request, dst any) error {
+	if err := json.NewDecoder(r.Body).Decode(dst); err != nil && err != io.EOF {
+		return errInvalidBody.WithDetail(err.Error())
+	}
+	return validate.Struct(dst)
+}
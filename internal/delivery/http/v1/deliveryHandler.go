@@ -0,0 +1,31 @@
+package v1
+
+import (
+	"log/slog"
+	"net/http"
+)
+
+// DeliveryHandler handles delivery zones and couriers
+type DeliveryHandler struct {
+	logger          *slog.Logger
+	deliveryService deliveryInterface
+}
+
+func NewDeliveryHandler(
+	deliveryService deliveryInterface,
+	logger *slog.Logger,
+) *DeliveryHandler {
+	return &DeliveryHandler{
+		deliveryService: deliveryService,
+		logger:          logger,
+	}
+}
+
+func SetDeliveryHandler(
+	router *http.ServeMux,
+	deliveryService deliveryInterface,
+	logger *slog.Logger,
+) {
+	handler := NewDeliveryHandler(deliveryService, logger)
+	setDeliveryRoutes(handler, router)
+}
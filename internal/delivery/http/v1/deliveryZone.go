@@ -0,0 +1,90 @@
+package v1
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"frappuccino/internal/dto/delivery"
+)
+
+func (h *DeliveryHandler) CreateZoneRequest(w http.ResponseWriter, r *http.Request) {
+	var request delivery.CreateZoneRequest
+	if err := decodeBody(r, &request); err != nil {
+		h.logger.WarnContext(r.Context(), "Invalid request body", "handler", "CreateZoneRequest", "error", err)
+		writeError(w, r, err)
+		return
+	}
+
+	id, err := h.deliveryService.CreateZone(r.Context(), request)
+	if err != nil {
+		h.logger.ErrorContext(r.Context(), "CreateZone failed", "handler", "CreateZoneRequest", "error", err)
+		writeError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(w).Encode(id); err != nil {
+		h.logger.ErrorContext(r.Context(), "Error encoding response", "handler", "CreateZoneRequest", "error", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+}
+
+func (h *DeliveryHandler) GetZonesResponse(w http.ResponseWriter, r *http.Request) {
+	zones, err := h.deliveryService.GetZones(r.Context())
+	if err != nil {
+		h.logger.ErrorContext(r.Context(), "GetZones failed", "handler", "GetZonesResponse", "error", err)
+		writeError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(w).Encode(zones); err != nil {
+		h.logger.ErrorContext(r.Context(), "Error encoding response", "handler", "GetZonesResponse", "error", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+}
+
+func (h *DeliveryHandler) CreateCourierRequest(w http.ResponseWriter, r *http.Request) {
+	var request delivery.CreateCourierRequest
+	if err := decodeBody(r, &request); err != nil {
+		h.logger.WarnContext(r.Context(), "Invalid request body", "handler", "CreateCourierRequest", "error", err)
+		writeError(w, r, err)
+		return
+	}
+
+	id, err := h.deliveryService.CreateCourier(r.Context(), request)
+	if err != nil {
+		h.logger.ErrorContext(r.Context(), "CreateCourier failed", "handler", "CreateCourierRequest", "error", err)
+		writeError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(w).Encode(id); err != nil {
+		h.logger.ErrorContext(r.Context(), "Error encoding response", "handler", "CreateCourierRequest", "error", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+}
+
+func (h *DeliveryHandler) GetCouriersResponse(w http.ResponseWriter, r *http.Request) {
+	couriers, err := h.deliveryService.GetCouriers(r.Context())
+	if err != nil {
+		h.logger.ErrorContext(r.Context(), "GetCouriers failed", "handler", "GetCouriersResponse", "error", err)
+		writeError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(w).Encode(couriers); err != nil {
+		h.logger.ErrorContext(r.Context(), "Error encoding response", "handler", "GetCouriersResponse", "error", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+}
@@ -0,0 +1,32 @@
+package v1
+
+import (
+	"net/http"
+
+	"frappuccino/internal/delivery/http/openapi"
+)
+
+// OpenAPIResponse handles the GET /openapi.json endpoint
+func (h *DocsHandler) OpenAPIResponse(w http.ResponseWriter, r *http.Request) {
+	spec, err := h.document()
+	if err != nil {
+		h.logger.ErrorContext(r.Context(), "Build OpenAPI document failed", "handler", "OpenAPIResponse", "error", err)
+		writeError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	if _, err := w.Write(spec); err != nil {
+		h.logger.ErrorContext(r.Context(), "Error writing response", "handler", "OpenAPIResponse", "error", err)
+	}
+}
+
+// DocsResponse handles the GET /docs endpoint, a page listing the operations of /openapi.json
+func (h *DocsHandler) DocsResponse(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	if _, err := w.Write(openapi.Viewer); err != nil {
+		h.logger.ErrorContext(r.Context(), "Error writing response", "handler", "DocsResponse", "error", err)
+	}
+}
@@ -0,0 +1,48 @@
+package v1
+
+import (
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"sync"
+
+	"frappuccino/internal/delivery/http/openapi"
+	"frappuccino/internal/validate"
+)
+
+// DocsHandler serves the OpenAPI document of the routes and a page to browse it
+type DocsHandler struct {
+	logger *slog.Logger
+
+	// The document is built on the first request, once the enums are loaded from the database
+	once sync.Once
+	spec []byte
+	err  error
+}
+
+func NewDocsHandler(logger *slog.Logger) *DocsHandler {
+	return &DocsHandler{
+		logger: logger,
+	}
+}
+
+func SetDocsHandler(
+	router *http.ServeMux,
+	logger *slog.Logger,
+) {
+	handler := NewDocsHandler(logger)
+	setDocsRoutes(handler, router)
+}
+
+// document builds the OpenAPI document of routes
+func (h *DocsHandler) document() ([]byte, error) {
+	h.once.Do(func() {
+		doc, err := openapi.Build(specInfo, routes, validate.Enum)
+		if err != nil {
+			h.err = err
+			return
+		}
+		h.spec, h.err = json.MarshalIndent(doc, "", "  ")
+	})
+	return h.spec, h.err
+}
@@ -2,50 +2,129 @@ package v1
 
 import (
 	"net/http"
+
+	"frappuccino/internal/service/access"
 )
 
 func setInventoryRoutes(handler *InventoryHandler, router *http.ServeMux) {
-	router.HandleFunc("POST /inventory", handler.CreateInventoryRequest)
-	router.HandleFunc("GET /inventory", handler.GetInventoryResponse)
-	router.HandleFunc("GET /inventory/{id}", handler.GetInventoryByIDResponse) // New endpoint
-	router.HandleFunc("DELETE /inventory/{id}", handler.DeleteInventoryRequest)
-	router.HandleFunc("PUT /inventory/{id}", handler.UpdateInventoryRequest)
-	router.HandleFunc("POST /inventory/transactions", handler.CreateInventoryTransactionRequest)
-	router.HandleFunc("GET /inventory/{id}/transactions", handler.GetInventoryTransactionsResponse)
-	router.HandleFunc("GET /inventory/getLeftOvers", handler.GetLeftOversResponse)
+	router.HandleFunc("POST /inventory", authorize(access.InventoryEdit, handler.CreateInventoryRequest))
+	router.HandleFunc("GET /inventory", authorize(access.InventoryView, handler.GetInventoryResponse))
+	router.HandleFunc("GET /inventory/{id}", authorize(access.InventoryView, handler.GetInventoryByIDResponse)) // New endpoint
+	router.HandleFunc("DELETE /inventory/{id}", authorize(access.InventoryDelete, handler.DeleteInventoryRequest))
+	router.HandleFunc("PUT /inventory/{id}", authorize(access.InventoryEdit, handler.UpdateInventoryRequest))
+	router.HandleFunc("POST /inventory/transactions", authorize(access.InventoryRecord, handler.CreateInventoryTransactionRequest))
+	router.HandleFunc("GET /inventory/{id}/transactions", authorize(access.InventoryView, handler.GetInventoryTransactionsResponse))
+	router.HandleFunc("GET /inventory/getLeftOvers", authorize(access.InventoryView, handler.GetLeftOversResponse))
+	router.HandleFunc("POST /inventory/transfers", authorize(access.InventoryTransfer, handler.TransferStockRequest))
 }
 
 func setMenuRoutes(handler *MenuHandler, router *http.ServeMux) {
-	router.HandleFunc("POST /menu", handler.CreateMenuItemRequest)
-	router.HandleFunc("GET /menu", handler.GetMenuResponse)
-	router.HandleFunc("GET /menu/{id}", handler.GetMenuByIDResponse) // New endpoint
-	router.HandleFunc("DELETE /menu/{id}", handler.DeleteMenuRequest)
-	router.HandleFunc("PUT /menu/{id}", handler.UpdateMenuRequest)
-	router.HandleFunc("GET /price-history", handler.GetAllPriceHistoryResponse)
+	router.HandleFunc("POST /menu", authorize(access.MenuManage, handler.CreateMenuItemRequest))
+	router.HandleFunc("GET /menu", authorize(access.MenuView, handler.GetMenuResponse))
+	router.HandleFunc("GET /menu/{id}", authorize(access.MenuView, handler.GetMenuByIDResponse)) // New endpoint
+	router.HandleFunc("DELETE /menu/{id}", authorize(access.MenuManage, handler.DeleteMenuRequest))
+	router.HandleFunc("PUT /menu/{id}", authorize(access.MenuEdit, handler.UpdateMenuRequest))
+	router.HandleFunc("PUT /menu/{id}/store", authorize(access.MenuEdit, handler.SetStoreMenuItemRequest))
+	router.HandleFunc("GET /price-history", authorize(access.PriceHistory, handler.GetAllPriceHistoryResponse))
 
 }
 
 func setOrderRoutes(handler *OrderHandler, router *http.ServeMux) {
 	// router.HandleFunc("GET /orders/", handler.GetOrderByID)
-	router.HandleFunc("GET /orders/{id}", handler.GetOrderByIDResponse)
-	router.HandleFunc("GET /orders", handler.GetOrderResponse)
-	router.HandleFunc("POST /orders", handler.CreateOrderRequest)
-	router.HandleFunc("PUT /orders/{id}", handler.UpdateOrderRequest)
-	router.HandleFunc("GET /order-status-history", handler.GetAllOrderStatusHistory)
-	router.HandleFunc("DELETE /orders/{id}", handler.DeleteOrderRequest)
-	router.HandleFunc("POST /orders/{id}/close", handler.CloseOrder)
-	router.HandleFunc("GET /orders/numberOfOrderedItems", handler.GetNumberOfOrderedItems)
-	router.HandleFunc("POST /orders/batch-process", handler.BatchProcessOrdersRequest)
+	router.HandleFunc("GET /orders/{id}", authorize(access.OrderView, handler.GetOrderByIDResponse))
+	router.HandleFunc("GET /orders/by-number/{n}", authorize(access.OrderView, handler.GetOrderByNumberResponse))
+	router.HandleFunc("GET /orders", authorize(access.OrderView, handler.GetOrderResponse))
+	router.HandleFunc("POST /orders", authorize(access.OrderTake, handler.CreateOrderRequest))
+	router.HandleFunc("PUT /orders/{id}", authorize(access.OrderTake, handler.UpdateOrderRequest))
+	router.HandleFunc("GET /order-status-history", authorize(access.OrderView, handler.GetAllOrderStatusHistory))
+	router.HandleFunc("DELETE /orders/{id}", authorize(access.OrderDelete, handler.DeleteOrderRequest))
+	router.HandleFunc("POST /orders/{id}/close", authorize(access.OrderTake, handler.CloseOrder))
+	router.HandleFunc("GET /orders/numberOfOrderedItems", authorize(access.ReportView, handler.GetNumberOfOrderedItems))
+	router.HandleFunc("POST /orders/batch-process", authorize(access.OrderBatch, handler.BatchProcessOrdersRequest))
+	router.HandleFunc("POST /orders/{id}/items", authorize(access.OrderTake, handler.AddOrderItemRequest))
+	router.HandleFunc("PUT /orders/{id}/items/{itemId}", authorize(access.OrderTake, handler.UpdateOrderItemRequest))
+	router.HandleFunc("DELETE /orders/{id}/items/{itemId}", authorize(access.OrderTake, handler.RemoveOrderItemRequest))
+	router.HandleFunc("GET /orders/{id}/{view}", authorize(access.OrderView, handler.GetOrderViewResponse)) // audit, receipt
+	router.HandleFunc("POST /orders/{id}/split", authorize(access.OrderTake, handler.SplitOrderRequest))
+	router.HandleFunc("POST /orders/merge", authorize(access.OrderTake, handler.MergeOrdersRequest))
+	router.HandleFunc("POST /orders/{id}/courier", authorize(access.OrderTake, handler.AssignCourierRequest))
 }
 
 func setReportRoutes(handler *ReportHandler, router *http.ServeMux) {
-	router.HandleFunc("GET /reports/search", handler.SearchReport)
-	router.HandleFunc("GET /reports/orderedItemsByPeriod", handler.GetOrderedItemsByPeriod)
-	router.HandleFunc("GET /reports/total-sales", handler.GetTotalSales)
-	router.HandleFunc("GET /reports/popular-items", handler.GetPopularItems)
+	router.HandleFunc("GET /reports/search", authorize(access.ReportView, handler.SearchReport))
+	router.HandleFunc("GET /reports/orderedItemsByPeriod", authorize(access.ReportView, handler.GetOrderedItemsByPeriod))
+	router.HandleFunc("GET /reports/total-sales", authorize(access.ReportView, handler.GetTotalSales))
+	router.HandleFunc("GET /reports/popular-items", authorize(access.ReportView, handler.GetPopularItems))
+	router.HandleFunc("GET /reports/service-times", authorize(access.ReportView, handler.GetServiceTimes))
+}
+
+func setStationRoutes(handler *StationHandler, router *http.ServeMux) {
+	router.HandleFunc("POST /stations", authorize(access.StationManage, handler.CreateStationRequest))
+	router.HandleFunc("GET /stations", authorize(access.StationView, handler.GetStationsResponse))
+	router.HandleFunc("POST /stations/{id}/routes", authorize(access.StationManage, handler.CreateStationRouteRequest))
+	router.HandleFunc("GET /stations/{id}/tickets", authorize(access.StationView, handler.GetStationTicketsResponse))
+	router.HandleFunc("PUT /tickets/{id}", authorize(access.StationWork, handler.UpdateTicketStatusRequest))
+}
+
+func setTableRoutes(handler *TableHandler, router *http.ServeMux) {
+	router.HandleFunc("POST /tables", authorize(access.TableManage, handler.CreateTableRequest))
+	router.HandleFunc("GET /tables", authorize(access.TableService, handler.GetTablesResponse))
+	router.HandleFunc("POST /tables/{id}/open", authorize(access.TableService, handler.OpenTabRequest))
+	router.HandleFunc("POST /tables/{id}/items", authorize(access.TableService, handler.AddTabItemRequest))
+	router.HandleFunc("POST /tables/{id}/close", authorize(access.TableService, handler.CloseTabRequest))
+	router.HandleFunc("GET /reports/table-turnover", authorize(access.ReportView, handler.GetTableTurnover))
 }
 
-// func SetOrderHandler(router *http.ServeMux, orderService order.ServiceInterface, logger *log.Logger) {
+func setDeliveryRoutes(handler *DeliveryHandler, router *http.ServeMux) {
+	router.HandleFunc("POST /delivery-zones", authorize(access.DeliveryManage, handler.CreateZoneRequest))
+	router.HandleFunc("GET /delivery-zones", authorize(access.DeliveryView, handler.GetZonesResponse))
+	router.HandleFunc("POST /couriers", authorize(access.DeliveryManage, handler.CreateCourierRequest))
+	router.HandleFunc("GET /couriers", authorize(access.DeliveryView, handler.GetCouriersResponse))
+}
+
+// func SetOrderHandler(router *http.ServeMux, orderService order.ServiceInterface, logger *slog.Logger) {
 // 	handler := NewOrderHandler(orderService)
 // 	setOrderRoutes(handler, router)
 // }
+
+func setPrintRoutes(handler *PrintHandler, router *http.ServeMux) {
+	router.HandleFunc("POST /printers", authorize(access.PrintManage, handler.CreatePrinterRequest))
+	router.HandleFunc("GET /printers", authorize(access.PrintView, handler.GetPrintersResponse))
+	router.HandleFunc("GET /print-jobs", authorize(access.PrintView, handler.GetPrintJobsResponse))
+	router.HandleFunc("POST /print-jobs/{id}/reprint", authorize(access.PrintReprint, handler.ReprintRequest))
+}
+
+// The /auth routes act on the caller's own session, so they need no permission
+func setAuthRoutes(handler *AuthHandler, router *http.ServeMux) {
+	router.HandleFunc("POST /auth/login", handler.LoginRequest)
+	router.HandleFunc("POST /auth/refresh", handler.RefreshRequest)
+	router.HandleFunc("POST /auth/logout", handler.LogoutRequest)
+	router.HandleFunc("POST /auth/password", handler.ChangePasswordRequest)
+	router.HandleFunc("POST /users", authorize(access.UserManage, handler.CreateUserRequest))
+	router.HandleFunc("GET /users", authorize(access.UserManage, handler.GetUsersResponse))
+	router.HandleFunc("PATCH /users/{id}", authorize(access.UserManage, handler.UpdateUserRequest))
+	router.HandleFunc("POST /api-keys", authorize(access.APIKeyManage, handler.CreateAPIKeyRequest))
+	router.HandleFunc("GET /api-keys", authorize(access.APIKeyManage, handler.GetAPIKeysResponse))
+	router.HandleFunc("DELETE /api-keys/{id}", authorize(access.APIKeyManage, handler.RevokeAPIKeyRequest))
+}
+
+func setAccessRoutes(handler *AccessHandler, router *http.ServeMux) {
+	router.HandleFunc("GET /reports/access-denials", authorize(access.AccessAudit, handler.GetAccessDenialsResponse))
+}
+
+func setStoreRoutes(handler *StoreHandler, router *http.ServeMux) {
+	router.HandleFunc("POST /stores", authorize(access.StoreManage, handler.CreateStoreRequest))
+	router.HandleFunc("GET /stores", authorize(access.StoreView, handler.GetStoresResponse))
+}
+
+// setHealthRoutes registers the probes without authorize; they are public routes
+func setHealthRoutes(handler *HealthHandler, router *http.ServeMux) {
+	router.HandleFunc("GET /healthz", handler.HealthzResponse)
+	router.HandleFunc("GET /readyz", handler.ReadyzResponse)
+}
+
+// setDocsRoutes registers the API description without authorize; they are public routes
+func setDocsRoutes(handler *DocsHandler, router *http.ServeMux) {
+	router.HandleFunc("GET /openapi.json", handler.OpenAPIResponse)
+	router.HandleFunc("GET /docs", handler.DocsResponse)
+}
@@ -0,0 +1,77 @@
+package v1
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"sort"
+	"strconv"
+	"testing"
+
+	"frappuccino/internal/delivery/http/openapi"
+	"frappuccino/internal/validate"
+)
+
+// registeredPatterns reads the patterns endpoints.go registers on the router. Routes the server
+// package adds itself, like GET /metrics, are not among them.
+func registeredPatterns(t *testing.T) []string {
+	t.Helper()
+
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, "endpoints.go", nil, 0)
+	if err != nil {
+		t.Fatalf("parse endpoints.go: %v", err)
+	}
+
+	var patterns []string
+	ast.Inspect(file, func(n ast.Node) bool {
+		call, ok := n.(*ast.CallExpr)
+		if !ok || len(call.Args) == 0 {
+			return true
+		}
+		sel, ok := call.Fun.(*ast.SelectorExpr)
+		if !ok || (sel.Sel.Name != "HandleFunc" && sel.Sel.Name != "Handle") {
+			return true
+		}
+		lit, ok := call.Args[0].(*ast.BasicLit)
+		if !ok || lit.Kind != token.STRING {
+			t.Errorf("%v: the pattern of a route must be a string literal", fset.Position(call.Pos()))
+			return true
+		}
+		pattern, err := strconv.Unquote(lit.Value)
+		if err != nil {
+			t.Fatalf("unquote %s: %v", lit.Value, err)
+		}
+		patterns = append(patterns, pattern)
+		return true
+	})
+	sort.Strings(patterns)
+	return patterns
+}
+
+// TestEveryV1RouteIsDescribed only covers the routes of this package; GET /metrics belongs to the
+// scraper and is left out of the document
+func TestEveryV1RouteIsDescribed(t *testing.T) {
+	doc, err := openapi.Build(specInfo, routes, validate.Enum)
+	if err != nil {
+		t.Fatalf("build OpenAPI document: %v", err)
+	}
+
+	described := make(map[string]bool)
+	for _, pattern := range doc.Operations() {
+		described[pattern] = true
+	}
+
+	registered := make(map[string]bool)
+	for _, pattern := range registeredPatterns(t) {
+		registered[pattern] = true
+		if !described[pattern] {
+			t.Errorf("%s is registered in endpoints.go but has no entry in the routes of spec.go", pattern)
+		}
+	}
+	for pattern := range described {
+		if !registered[pattern] {
+			t.Errorf("%s is described in spec.go but not registered in endpoints.go", pattern)
+		}
+	}
+}
@@ -0,0 +1,32 @@
+package v1
+
+import (
+	"net/http"
+
+	"frappuccino/internal/apperror"
+	"frappuccino/internal/delivery/http/problem"
+)
+
+// errInvalidBody answers a request body that is not the JSON the endpoint expects
+var errInvalidBody = apperror.New(apperror.Invalid, "invalid_body", "invalid request format")
+
+// writeError answers the request with the problem err describes. Service errors keep their
+// status and code, rows that do not exist are a 404 and anything else is a 500.
+func writeError(w http.ResponseWriter, r *http.Request, err error) {
+	problem.Write(w, r, err)
+}
+
+// invalidParam is the error for a path or query parameter the endpoint cannot use
+func invalidParam(name, message string) error {
+	return apperror.InvalidField("invalid_parameter", name, message)
+}
+
+// missingParam is the error for a path or query parameter the endpoint needs but did not get
+func missingParam(name string) error {
+	return apperror.InvalidField("missing_parameter", name, name+" is required")
+}
+
+// invalidDate is the error for a date parameter parseDate does not understand
+func invalidDate(name string) error {
+	return invalidParam(name, "invalid "+name+" format, use YYYY-MM-DD")
+}
@@ -0,0 +1,33 @@
+package v1
+
+import (
+	"encoding/json"
+	"net/http"
+)
+
+// HealthzResponse handles the GET /healthz endpoint
+func (h *HealthHandler) HealthzResponse(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(w).Encode(h.healthService.Liveness(r.Context())); err != nil {
+		h.logger.ErrorContext(r.Context(), "Error encoding response", "handler", "HealthzResponse", "error", err)
+	}
+}
+
+// ReadyzResponse handles the GET /readyz endpoint; it answers 503 until the database is
+// reachable and migrated
+func (h *HealthHandler) ReadyzResponse(w http.ResponseWriter, r *http.Request) {
+	response, ready := h.healthService.Readiness(r.Context())
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	if ready {
+		w.WriteHeader(http.StatusOK)
+	} else {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		h.logger.ErrorContext(r.Context(), "Error encoding response", "handler", "ReadyzResponse", "error", err)
+	}
+}
@@ -0,0 +1,31 @@
+package v1
+
+import (
+	"log/slog"
+	"net/http"
+)
+
+// HealthHandler answers the liveness and readiness probes
+type HealthHandler struct {
+	logger        *slog.Logger
+	healthService healthInterface
+}
+
+func NewHealthHandler(
+	healthService healthInterface,
+	logger *slog.Logger,
+) *HealthHandler {
+	return &HealthHandler{
+		healthService: healthService,
+		logger:        logger,
+	}
+}
+
+func SetHealthHandler(
+	router *http.ServeMux,
+	healthService healthInterface,
+	logger *slog.Logger,
+) {
+	handler := NewHealthHandler(healthService, logger)
+	setHealthRoutes(handler, router)
+}
@@ -4,43 +4,63 @@ import (
 	"context"
 	"time"
 
+	"frappuccino/internal/dto/access"
+	"frappuccino/internal/dto/auth"
+	"frappuccino/internal/dto/delivery"
+	"frappuccino/internal/dto/health"
 	"frappuccino/internal/dto/inventory"
+	"frappuccino/internal/dto/list"
 	"frappuccino/internal/dto/menu"
+	"frappuccino/internal/dto/printing"
+	"frappuccino/internal/dto/receipt"
 	"frappuccino/internal/dto/report"
+	"frappuccino/internal/dto/station"
+	"frappuccino/internal/dto/store"
+	"frappuccino/internal/dto/table"
 
 	orderdto "frappuccino/internal/dto/order"
 )
 
 type inventoryInterface interface {
 	CreateInventory(ctx context.Context, request inventory.CreateInventoryRequest) (string, error)
-	GetInventory(ctx context.Context) ([]inventory.GetInventoryResponse, error)
+	ListInventory(ctx context.Context, q list.Query) (inventory.ListInventoryResponse, error)
 	GetInventoryByID(ctx context.Context, id string) (inventory.GetInventoryResponse, error)
 	DeleteInventory(ctx context.Context, id string) (string, error)
 	UpdateInventory(ctx context.Context, request inventory.UpdateInventoryRequest, id string) (string, error)
 	RecordInventoryTransaction(ctx context.Context, request inventory.CreateTransactionRequest) error
-	GetInventoryTransactions(ctx context.Context, ingredientID string) ([]inventory.TransactionResponse, error)
-	GetLeftOvers(ctx context.Context, sortBy string, page, pageSize int) (inventory.GetLeftOversResponse, error)
+	ListInventoryTransactions(ctx context.Context, ingredientID string, q list.Query) (inventory.ListTransactionsResponse, error)
+	GetLeftOvers(ctx context.Context, q list.Query) (inventory.GetLeftOversResponse, error)
+	TransferStock(ctx context.Context, request inventory.TransferStockRequest) (inventory.TransferStockResponse, error)
 }
 
 type menuInterface interface {
 	CreateMenuItem(ctx context.Context, request menu.CreateMenuItemRequest) (string, error)
-	GetMenuItem(ctx context.Context) ([]menu.GetMenuResponse, error)
+	ListMenuItems(ctx context.Context, q list.Query) (menu.ListMenuResponse, error)
 	GetMenuByID(ctx context.Context, id string) (menu.GetMenuResponse, error)
 	DeleteMenu(ctx context.Context, id string) (string, error)
 	UpdateMenu(ctx context.Context, request menu.UpdateMenuRequest, id string) (string, error)
-	GetAllPriceHistory(ctx context.Context) ([]menu.GetPriceHistoryResponse, error)
+	ListPriceHistory(ctx context.Context, q list.Query) (menu.ListPriceHistoryResponse, error)
+	SetStoreMenuItem(ctx context.Context, id string, request menu.SetStoreMenuItemRequest) error
 }
 
 type orderInterface interface {
-	CreateOrder(ctx context.Context, req orderdto.CreateOrderRequest) (string, error)
+	CreateOrder(ctx context.Context, req orderdto.CreateOrderRequest) (orderdto.CreateOrderResponse, error)
 	GetOrderByID(ctx context.Context, id string) (orderdto.GetOrderResponse, error)
-	GetAllOrders(ctx context.Context) ([]orderdto.GetOrderResponse, error)
+	GetOrderByNumber(ctx context.Context, orderNumber int, date *time.Time) (orderdto.GetOrderResponse, error)
+	ListOrders(ctx context.Context, q list.Query) (orderdto.ListOrdersResponse, error)
 	UpdateOrder(ctx context.Context, orderID string, req orderdto.UpdateOrderRequest) error
-	GetAllOrderStatusHistory(ctx context.Context) ([]orderdto.OrderStatusHistoryResponse, error)
+	ListOrderStatusHistory(ctx context.Context, q list.Query) (orderdto.ListOrderStatusHistoryResponse, error)
 	DeleteOrder(ctx context.Context, id string) (string, error)
 	CloseOrder(ctx context.Context, orderID string, reason string) error
 	GetNumberOfOrderedItems(ctx context.Context, startDate, endDate *time.Time) (map[string]int, error)
 	BatchProcessOrders(ctx context.Context, req orderdto.BatchOrderRequest) (orderdto.BatchOrderResponse, error)
+	AddOrderItem(ctx context.Context, orderID string, req orderdto.AddOrderItemRequest) (orderdto.GetOrderResponse, error)
+	UpdateOrderItem(ctx context.Context, orderID, orderItemID string, req orderdto.UpdateOrderItemRequest) (orderdto.GetOrderResponse, error)
+	RemoveOrderItem(ctx context.Context, orderID, orderItemID, reason string) (orderdto.GetOrderResponse, error)
+	GetOrderAuditLog(ctx context.Context, orderID string) ([]orderdto.OrderAuditEntryResponse, error)
+	SplitOrder(ctx context.Context, orderID string, req orderdto.SplitOrderRequest) (orderdto.SplitOrderResponse, error)
+	MergeOrders(ctx context.Context, req orderdto.MergeOrdersRequest) (orderdto.GetOrderResponse, error)
+	AssignCourier(ctx context.Context, orderID string, req orderdto.AssignCourierRequest) (orderdto.GetOrderResponse, error)
 }
 
 type reportInterface interface {
@@ -48,4 +68,67 @@ type reportInterface interface {
 	GetOrderedItemsByPeriod(ctx context.Context, req report.OrderedItemsByPeriodRequest) (report.OrderedItemsByPeriodResponse, error)
 	GetTotalSales(ctx context.Context, req report.TotalSalesRequest) (report.TotalSalesResponse, error)
 	GetPopularItems(ctx context.Context, req report.PopularItemsRequest) (report.PopularItemsResponse, error)
+	GetServiceTimes(ctx context.Context, req report.ServiceTimesRequest) (report.ServiceTimesResponse, error)
+}
+
+type stationInterface interface {
+	CreateStation(ctx context.Context, request station.CreateStationRequest) (string, error)
+	GetStations(ctx context.Context) ([]station.GetStationResponse, error)
+	CreateStationRoute(ctx context.Context, stationID string, request station.CreateStationRouteRequest) (string, error)
+	GetStationTickets(ctx context.Context, stationID string, status string) ([]station.GetTicketResponse, error)
+	UpdateTicketStatus(ctx context.Context, ticketID string, request station.UpdateTicketStatusRequest) error
+}
+
+type tableInterface interface {
+	CreateTable(ctx context.Context, request table.CreateTableRequest) (string, error)
+	GetTables(ctx context.Context) ([]table.GetTableResponse, error)
+	OpenTab(ctx context.Context, tableID string, request table.OpenTabRequest) (orderdto.CreateOrderResponse, error)
+	AddTabItem(ctx context.Context, tableID string, request orderdto.AddOrderItemRequest) (orderdto.GetOrderResponse, error)
+	CloseTab(ctx context.Context, tableID string, reason string) error
+	GetTableTurnover(ctx context.Context, req table.TableTurnoverRequest) (table.TableTurnoverResponse, error)
+}
+
+type deliveryInterface interface {
+	CreateZone(ctx context.Context, request delivery.CreateZoneRequest) (string, error)
+	GetZones(ctx context.Context) ([]delivery.GetZoneResponse, error)
+	CreateCourier(ctx context.Context, request delivery.CreateCourierRequest) (string, error)
+	GetCouriers(ctx context.Context) ([]delivery.GetCourierResponse, error)
+}
+
+type receiptInterface interface {
+	GetReceipt(ctx context.Context, orderID, format string) (receipt.Receipt, error)
+}
+
+type printInterface interface {
+	CreatePrinter(ctx context.Context, request printing.CreatePrinterRequest) (string, error)
+	GetPrinters(ctx context.Context) ([]printing.GetPrinterResponse, error)
+	GetPrintJobs(ctx context.Context, status string) ([]printing.PrintJobResponse, error)
+	Reprint(ctx context.Context, jobID string) (printing.PrintJobResponse, error)
+}
+
+type authInterface interface {
+	Login(ctx context.Context, request auth.LoginRequest) (auth.TokenResponse, error)
+	Refresh(ctx context.Context, request auth.RefreshRequest) (auth.TokenResponse, error)
+	Logout(ctx context.Context, request auth.LogoutRequest) error
+	ChangePassword(ctx context.Context, request auth.ChangePasswordRequest) error
+	CreateUser(ctx context.Context, request auth.CreateUserRequest) (string, error)
+	GetUsers(ctx context.Context) ([]auth.UserResponse, error)
+	UpdateUser(ctx context.Context, userID string, request auth.UpdateUserRequest) error
+	CreateAPIKey(ctx context.Context, request auth.CreateAPIKeyRequest) (auth.CreateAPIKeyResponse, error)
+	GetAPIKeys(ctx context.Context) ([]auth.APIKeyResponse, error)
+	RevokeAPIKey(ctx context.Context, keyID string) error
+}
+
+type accessInterface interface {
+	GetAccessDenials(ctx context.Context, startDate, endDate *time.Time, limit int) ([]access.AccessDenialResponse, error)
+}
+
+type storeInterface interface {
+	CreateStore(ctx context.Context, request store.CreateStoreRequest) (string, error)
+	GetStores(ctx context.Context) ([]store.GetStoreResponse, error)
+}
+
+type healthInterface interface {
+	Liveness(ctx context.Context) health.HealthResponse
+	Readiness(ctx context.Context) (health.HealthResponse, bool)
 }
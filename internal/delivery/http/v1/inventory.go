@@ -1,45 +1,46 @@
 package v1
 
 import (
-	"database/sql"
 	"encoding/json"
 	"net/http"
-	"strconv"
-	"strings"
 
 	"frappuccino/internal/dto/inventory"
 )
 
 func (h *InventoryHandler) CreateInventoryRequest(w http.ResponseWriter, r *http.Request) {
 	var request inventory.CreateInventoryRequest
-	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
-		h.logger.Println("method:CreateInventoryRequest, function:json decode", err.Error())
-		w.WriteHeader(http.StatusBadRequest)
+	if err := decodeBody(r, &request); err != nil {
+		h.logger.WarnContext(r.Context(), "Invalid request body", "handler", "CreateInventoryRequest", "error", err)
+		writeError(w, r, err)
 		return
 	}
 	id, err := h.inventoryService.CreateInventory(r.Context(), request)
 	if err != nil {
-		h.logger.Println("method:CreateInventoryRequest, function:CreateInventory", err.Error())
-		w.WriteHeader(http.StatusInternalServerError)
+		h.logger.ErrorContext(r.Context(), "CreateInventory failed", "handler", "CreateInventoryRequest", "error", err)
+		writeError(w, r, err)
 		return
 	}
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusOK)
 	if err := json.NewEncoder(w).Encode(id); err != nil {
-		h.logger.Println("method:CreateInventoryRequest, function:json encode", err.Error())
+		h.logger.ErrorContext(r.Context(), "Error encoding response", "handler", "CreateInventoryRequest", "error", err)
 		w.WriteHeader(http.StatusInternalServerError)
 		return
 	}
 }
 
 func (h *InventoryHandler) GetInventoryResponse(w http.ResponseWriter, r *http.Request) {
-	// For GET requests, we typically don't need to decode the request body
-	// Instead, we directly call the service
+	q, err := parseListQuery(r)
+	if err != nil {
+		h.logger.WarnContext(r.Context(), "Invalid list query", "handler", "GetInventoryRequest", "error", err)
+		writeError(w, r, err)
+		return
+	}
 
-	inventoryItems, err := h.inventoryService.GetInventory(r.Context())
+	inventoryItems, err := h.inventoryService.ListInventory(r.Context(), q)
 	if err != nil {
-		h.logger.Println("method:GetInventoryRequest, function:GetInventory", err.Error())
-		w.WriteHeader(http.StatusInternalServerError)
+		h.logger.ErrorContext(r.Context(), "ListInventory failed", "handler", "GetInventoryRequest", "error", err)
+		writeError(w, r, err)
 		return
 	}
 
@@ -47,7 +48,7 @@ func (h *InventoryHandler) GetInventoryResponse(w http.ResponseWriter, r *http.R
 	w.WriteHeader(http.StatusOK)
 
 	if err := json.NewEncoder(w).Encode(inventoryItems); err != nil {
-		h.logger.Println("method:GetInventoryRequest, function:json encode", err.Error())
+		h.logger.ErrorContext(r.Context(), "Error encoding response", "handler", "GetInventoryRequest", "error", err)
 		w.WriteHeader(http.StatusInternalServerError)
 		return
 	}
@@ -57,22 +58,16 @@ func (h *InventoryHandler) GetInventoryByIDResponse(w http.ResponseWriter, r *ht
 	// Extract ID from URL path (Go 1.22+ pattern matching)
 	id := r.PathValue("id")
 	if id == "" {
-		h.logger.Println("method:GetInventoryByIDRequest, function: missing id parameter")
-		w.WriteHeader(http.StatusBadRequest)
+		h.logger.WarnContext(r.Context(), "Missing id parameter", "handler", "GetInventoryByIDRequest")
+		writeError(w, r, missingParam("id"))
 		return
 	}
 
 	// Call service to get the inventory item
 	inventoryItem, err := h.inventoryService.GetInventoryByID(r.Context(), id)
 	if err != nil {
-		h.logger.Println("method:GetInventoryByIDRequest, function:GetInventoryByID", err.Error())
-
-		// Check if it's a "not found" error
-		if err == sql.ErrNoRows {
-			w.WriteHeader(http.StatusNotFound)
-		} else {
-			w.WriteHeader(http.StatusInternalServerError)
-		}
+		h.logger.ErrorContext(r.Context(), "GetInventoryByID failed", "handler", "GetInventoryByIDRequest", "error", err)
+		writeError(w, r, err)
 		return
 	}
 
@@ -80,7 +75,7 @@ func (h *InventoryHandler) GetInventoryByIDResponse(w http.ResponseWriter, r *ht
 	w.WriteHeader(http.StatusOK)
 
 	if err := json.NewEncoder(w).Encode(inventoryItem); err != nil {
-		h.logger.Println("method:GetInventoryByIDRequest, function:json encode", err.Error())
+		h.logger.ErrorContext(r.Context(), "Error encoding response", "handler", "GetInventoryByIDRequest", "error", err)
 		w.WriteHeader(http.StatusInternalServerError)
 		return
 	}
@@ -89,21 +84,21 @@ func (h *InventoryHandler) GetInventoryByIDResponse(w http.ResponseWriter, r *ht
 func (h *InventoryHandler) DeleteInventoryRequest(w http.ResponseWriter, r *http.Request) {
 	id := r.PathValue("id")
 	if id == "" {
-		h.logger.Println("method:DeleteInventoryRequest, function: missing id parameter")
-		w.WriteHeader(http.StatusBadRequest)
+		h.logger.WarnContext(r.Context(), "Missing id parameter", "handler", "DeleteInventoryRequest")
+		writeError(w, r, missingParam("id"))
 		return
 	}
 
 	ingredient_id, err := h.inventoryService.DeleteInventory(r.Context(), id)
 	if err != nil {
-		h.logger.Println("method:DeleteInventoryRequest, function:DeleteInventory", err.Error())
-		w.WriteHeader(http.StatusInternalServerError)
+		h.logger.ErrorContext(r.Context(), "DeleteInventory failed", "handler", "DeleteInventoryRequest", "error", err)
+		writeError(w, r, err)
 		return
 	}
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusOK)
 	if err := json.NewEncoder(w).Encode(ingredient_id); err != nil {
-		h.logger.Println("method:DeleteInventoryRequest, function:json encode", err.Error())
+		h.logger.ErrorContext(r.Context(), "Error encoding response", "handler", "DeleteInventoryRequest", "error", err)
 		w.WriteHeader(http.StatusInternalServerError)
 		return
 	}
@@ -111,86 +106,46 @@ func (h *InventoryHandler) DeleteInventoryRequest(w http.ResponseWriter, r *http
 
 func (h *InventoryHandler) UpdateInventoryRequest(w http.ResponseWriter, r *http.Request) {
 	var request inventory.UpdateInventoryRequest
-	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
-		h.logger.Println("method:UpdateInventoryRequest, function:json decode", err.Error())
-		http.Error(w, "Invalid request format", http.StatusBadRequest)
+	if err := decodeBody(r, &request); err != nil {
+		h.logger.WarnContext(r.Context(), "Invalid request body", "handler", "UpdateInventoryRequest", "error", err)
+		writeError(w, r, err)
 		return
 	}
 
 	id := r.PathValue("id")
 	if id == "" {
-		h.logger.Println("method:UpdateInventoryRequest, function: missing id parameter")
-		http.Error(w, "Missing inventory ID", http.StatusBadRequest)
+		h.logger.WarnContext(r.Context(), "Missing id parameter", "handler", "UpdateInventoryRequest")
+		writeError(w, r, missingParam("id"))
 		return
 	}
 
 	ingredient_id, err := h.inventoryService.UpdateInventory(r.Context(), request, id)
 	if err != nil {
-		h.logger.Println("method:UpdateInventoryRequest, function:UpdateInventory", err.Error())
-		statusCode := http.StatusInternalServerError
-		errorMessage := "Internal server error"
-
-		// Check for specific errors to provide better responses
-		if err.Error() == "no fields to update" {
-			statusCode = http.StatusBadRequest
-			errorMessage = "No fields to update"
-		}
-
-		http.Error(w, errorMessage, statusCode)
+		h.logger.ErrorContext(r.Context(), "UpdateInventory failed", "handler", "UpdateInventoryRequest", "error", err)
+		writeError(w, r, err)
 		return
 	}
 
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusOK)
 	if err := json.NewEncoder(w).Encode(ingredient_id); err != nil {
-		h.logger.Println("method:UpdateInventoryRequest, function:json encode", err.Error())
+		h.logger.ErrorContext(r.Context(), "Error encoding response", "handler", "UpdateInventoryRequest", "error", err)
 		http.Error(w, "Error encoding response", http.StatusInternalServerError)
 	}
 }
 
 func (h *InventoryHandler) CreateInventoryTransactionRequest(w http.ResponseWriter, r *http.Request) {
 	var request inventory.CreateTransactionRequest
-	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
-		h.logger.Println("method:CreateInventoryTransactionRequest, function:json decode", err.Error())
-		w.WriteHeader(http.StatusBadRequest)
-		return
-	}
-
-	// Validate transaction type
-	validTypes := map[string]bool{
-		"addition":   true,
-		"deduction":  true,
-		"adjustment": true,
-		"waste":      true,
-	}
-
-	if !validTypes[request.TransactionType] {
-		h.logger.Println("method:CreateInventoryTransactionRequest, invalid transaction type:", request.TransactionType)
-		http.Error(w, "Invalid transaction type", http.StatusBadRequest)
-		return
-	}
-
-	if request.QuantityChange <= 0 {
-		h.logger.Println("method:CreateInventoryTransactionRequest, quantity must be positive")
-		http.Error(w, "Quantity change must be a positive value", http.StatusBadRequest)
+	if err := decodeBody(r, &request); err != nil {
+		h.logger.WarnContext(r.Context(), "Invalid request body", "handler", "CreateInventoryTransactionRequest", "error", err)
+		writeError(w, r, err)
 		return
 	}
 
 	err := h.inventoryService.RecordInventoryTransaction(r.Context(), request)
 	if err != nil {
-		h.logger.Println("method:CreateInventoryTransactionRequest, function:RecordInventoryTransaction", err.Error())
-
-		if err.Error() == "ingredient not found" {
-			http.Error(w, "Ingredient not found", http.StatusNotFound)
-			return
-		}
-
-		if err.Error() == "insufficient inventory" {
-			http.Error(w, "Insufficient inventory", http.StatusBadRequest)
-			return
-		}
-
-		w.WriteHeader(http.StatusInternalServerError)
+		h.logger.ErrorContext(r.Context(), "RecordInventoryTransaction failed", "handler", "CreateInventoryTransactionRequest", "error", err)
+		writeError(w, r, err)
 		return
 	}
 
@@ -198,7 +153,7 @@ func (h *InventoryHandler) CreateInventoryTransactionRequest(w http.ResponseWrit
 	w.WriteHeader(http.StatusOK)
 	response := map[string]string{"message": "Transaction recorded successfully"}
 	if err := json.NewEncoder(w).Encode(response); err != nil {
-		h.logger.Println("method:CreateInventoryTransactionRequest, function:json encode", err.Error())
+		h.logger.ErrorContext(r.Context(), "Error encoding response", "handler", "CreateInventoryTransactionRequest", "error", err)
 		w.WriteHeader(http.StatusInternalServerError)
 	}
 }
@@ -206,21 +161,22 @@ func (h *InventoryHandler) CreateInventoryTransactionRequest(w http.ResponseWrit
 func (h *InventoryHandler) GetInventoryTransactionsResponse(w http.ResponseWriter, r *http.Request) {
 	id := r.PathValue("id")
 	if id == "" {
-		h.logger.Println("method:GetInventoryTransactionsResponse, function: missing id parameter")
-		w.WriteHeader(http.StatusBadRequest)
+		h.logger.WarnContext(r.Context(), "Missing id parameter", "handler", "GetInventoryTransactionsResponse")
+		writeError(w, r, missingParam("id"))
 		return
 	}
 
-	transactions, err := h.inventoryService.GetInventoryTransactions(r.Context(), id)
+	q, err := parseListQuery(r)
 	if err != nil {
-		h.logger.Println("method:GetInventoryTransactionsResponse, function:GetInventoryTransactions", err.Error())
-
-		if err.Error() == "ingredient not found" {
-			w.WriteHeader(http.StatusNotFound)
-			return
-		}
+		h.logger.WarnContext(r.Context(), "Invalid list query", "handler", "GetInventoryTransactionsResponse", "error", err)
+		writeError(w, r, err)
+		return
+	}
 
-		w.WriteHeader(http.StatusInternalServerError)
+	transactions, err := h.inventoryService.ListInventoryTransactions(r.Context(), id, q)
+	if err != nil {
+		h.logger.ErrorContext(r.Context(), "ListInventoryTransactions failed", "handler", "GetInventoryTransactionsResponse", "error", err)
+		writeError(w, r, err)
 		return
 	}
 
@@ -228,71 +184,58 @@ func (h *InventoryHandler) GetInventoryTransactionsResponse(w http.ResponseWrite
 	w.WriteHeader(http.StatusOK)
 
 	if err := json.NewEncoder(w).Encode(transactions); err != nil {
-		h.logger.Println("method:GetInventoryTransactionsResponse, function:json encode", err.Error())
+		h.logger.ErrorContext(r.Context(), "Error encoding response", "handler", "GetInventoryTransactionsResponse", "error", err)
 		w.WriteHeader(http.StatusInternalServerError)
 		return
 	}
 }
 
 func (h *InventoryHandler) GetLeftOversResponse(w http.ResponseWriter, r *http.Request) {
-	// Parse query parameters
-	query := r.URL.Query()
-	sortBy := query.Get("sortBy")
-	pageStr := query.Get("page")
-	pageSizeStr := query.Get("pageSize")
-
-	// Set defaults and parse page parameters
-	page := 1
-	pageSize := 10
-
-	if pageStr != "" {
-		parsedPage, err := strconv.Atoi(pageStr)
-		if err != nil || parsedPage < 1 {
-			h.logger.Println("method:GetLeftOversResponse, invalid page parameter:", pageStr)
-			http.Error(w, "Invalid page parameter, must be a positive integer", http.StatusBadRequest)
-			return
-		}
-		page = parsedPage
+	q, err := parseListQuery(r)
+	if err != nil {
+		h.logger.WarnContext(r.Context(), "Invalid list query", "handler", "GetLeftOversResponse", "error", err)
+		writeError(w, r, err)
+		return
 	}
 
-	if pageSizeStr != "" {
-		parsedPageSize, err := strconv.Atoi(pageSizeStr)
-		if err != nil || parsedPageSize < 1 {
-			h.logger.Println("method:GetLeftOversResponse, invalid pageSize parameter:", pageSizeStr)
-			http.Error(w, "Invalid pageSize parameter, must be a positive integer", http.StatusBadRequest)
-			return
-		}
-		pageSize = parsedPageSize
+	// Call service to get leftovers
+	response, err := h.inventoryService.GetLeftOvers(r.Context(), q)
+	if err != nil {
+		h.logger.ErrorContext(r.Context(), "GetLeftOvers failed", "handler", "GetLeftOversResponse", "error", err)
+		writeError(w, r, err)
+		return
 	}
 
-	// Clean and validate sortBy parameter
-	// The issue here is that the URL might have a format like "sortBy=quantity?page=1"
-	// where the query string contains another '?' instead of '&'
-	if strings.Contains(sortBy, "?") {
-		// Extract the actual sortBy value before any additional '?'
-		sortBy = strings.Split(sortBy, "?")[0]
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		h.logger.ErrorContext(r.Context(), "Error encoding response", "handler", "GetLeftOversResponse", "error", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
 	}
+}
 
-	// Now validate the clean sortBy parameter
-	if sortBy != "" && sortBy != "price" && sortBy != "quantity" {
-		h.logger.Println("method:GetLeftOversResponse, invalid sortBy parameter:", sortBy)
-		http.Error(w, "Invalid sortBy parameter. Must be 'price' or 'quantity'", http.StatusBadRequest)
+// TransferStockRequest handles the POST /inventory/transfers endpoint
+func (h *InventoryHandler) TransferStockRequest(w http.ResponseWriter, r *http.Request) {
+	var request inventory.TransferStockRequest
+	if err := decodeBody(r, &request); err != nil {
+		h.logger.WarnContext(r.Context(), "Invalid request body", "handler", "TransferStockRequest", "error", err)
+		writeError(w, r, err)
 		return
 	}
 
-	// Call service to get leftovers
-	response, err := h.inventoryService.GetLeftOvers(r.Context(), sortBy, page, pageSize)
+	transfer, err := h.inventoryService.TransferStock(r.Context(), request)
 	if err != nil {
-		h.logger.Println("method:GetLeftOversResponse, function:GetLeftOvers", err.Error())
-		w.WriteHeader(http.StatusInternalServerError)
+		h.logger.ErrorContext(r.Context(), "TransferStock failed", "handler", "TransferStockRequest", "error", err)
+		writeError(w, r, err)
 		return
 	}
 
 	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(http.StatusOK)
-
-	if err := json.NewEncoder(w).Encode(response); err != nil {
-		h.logger.Println("method:GetLeftOversResponse, function:json encode", err.Error())
+	w.WriteHeader(http.StatusCreated)
+	if err := json.NewEncoder(w).Encode(transfer); err != nil {
+		h.logger.ErrorContext(r.Context(), "Error encoding response", "handler", "TransferStockRequest", "error", err)
 		w.WriteHeader(http.StatusInternalServerError)
 		return
 	}
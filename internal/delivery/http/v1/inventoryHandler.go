@@ -1,18 +1,18 @@
 package v1
 
 import (
-	"log"
+	"log/slog"
 	"net/http"
 )
 
 type InventoryHandler struct {
-	logger           *log.Logger
+	logger           *slog.Logger
 	inventoryService inventoryInterface
 }
 
 func NewInventoryHandler(
 	inventoryService inventoryInterface,
-	logger *log.Logger,
+	logger *slog.Logger,
 ) *InventoryHandler {
 	return &InventoryHandler{
 		inventoryService: inventoryService,
@@ -23,7 +23,7 @@ func NewInventoryHandler(
 func SetInventoryHandler(
 	router *http.ServeMux,
 	inventoryService inventoryInterface,
-	logger *log.Logger,
+	logger *slog.Logger,
 ) {
 	handler := NewInventoryHandler(inventoryService, logger)
 	setInventoryRoutes(handler, router)
@@ -0,0 +1,67 @@
+package v1
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"frappuccino/internal/dto/list"
+)
+
+// parseListQuery reads the page, sort and filters a list endpoint was asked for. Which sorts
+// and filters a list supports is up to its repository, which rejects the others.
+func parseListQuery(r *http.Request) (list.Query, error) {
+	query := r.URL.Query()
+
+	q := list.Query{
+		Cursor: query.Get("cursor"),
+		Sort:   query.Get("sort"),
+		Filter: list.Filter{
+			Status:          query.Get("status"),
+			Customer:        query.Get("customer"),
+			Category:        query.Get("category"),
+			TransactionType: query.Get("transactionType"),
+		},
+	}
+
+	if limitStr := query.Get("limit"); limitStr != "" {
+		limit, err := strconv.Atoi(limitStr)
+		if err != nil || limit < 1 || limit > list.MaxLimit {
+			return list.Query{}, invalidParam("limit", fmt.Sprintf("limit must be an integer from 1 to %d", list.MaxLimit))
+		}
+		q.Limit = limit
+	}
+
+	dates := []struct {
+		name string
+		dst  **time.Time
+	}{{"startDate", &q.Filter.From}, {"endDate", &q.Filter.To}}
+	for _, d := range dates {
+		name, dst := d.name, d.dst
+		if dateStr := query.Get(name); dateStr != "" {
+			date, err := parseDate(dateStr)
+			if err != nil {
+				return list.Query{}, invalidDate(name)
+			}
+			*dst = &date
+		}
+	}
+
+	prices := []struct {
+		name string
+		dst  **float64
+	}{{"minPrice", &q.Filter.MinPrice}, {"maxPrice", &q.Filter.MaxPrice}}
+	for _, p := range prices {
+		name, dst := p.name, p.dst
+		if priceStr := query.Get(name); priceStr != "" {
+			price, err := strconv.ParseFloat(priceStr, 64)
+			if err != nil || price < 0 {
+				return list.Query{}, invalidParam(name, name+" must be a non-negative number")
+			}
+			*dst = &price
+		}
+	}
+
+	return q, nil
+}
@@ -1,7 +1,6 @@
 package v1
 
 import (
-	"database/sql"
 	"encoding/json"
 	"net/http"
 
@@ -10,36 +9,40 @@ import (
 
 func (h *MenuHandler) CreateMenuItemRequest(w http.ResponseWriter, r *http.Request) {
 	var request menu.CreateMenuItemRequest
-	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
-		h.logger.Println("method:CreateMenuItemRequest, function:json decode", err.Error())
-		w.WriteHeader(http.StatusBadRequest)
+	if err := decodeBody(r, &request); err != nil {
+		h.logger.WarnContext(r.Context(), "Invalid request body", "handler", "CreateMenuItemRequest", "error", err)
+		writeError(w, r, err)
 		return
 	}
 
 	id, err := h.menuService.CreateMenuItem(r.Context(), request)
 	if err != nil {
-		h.logger.Println("method:CreateMenuItemRequest, function:CreateMenuItem", err.Error())
-		w.WriteHeader(http.StatusInternalServerError)
+		h.logger.ErrorContext(r.Context(), "CreateMenuItem failed", "handler", "CreateMenuItemRequest", "error", err)
+		writeError(w, r, err)
 		return
 	}
 
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusOK)
 	if err := json.NewEncoder(w).Encode(id); err != nil {
-		h.logger.Println("method:CreateMenuItemRequest, function:json encode", err.Error())
+		h.logger.ErrorContext(r.Context(), "Error encoding response", "handler", "CreateMenuItemRequest", "error", err)
 		w.WriteHeader(http.StatusInternalServerError)
 		return
 	}
 }
 
 func (h *MenuHandler) GetMenuResponse(w http.ResponseWriter, r *http.Request) {
-	// For GET requests, we typically don't need to decode the request body
-	// Instead, we directly call the service
+	q, err := parseListQuery(r)
+	if err != nil {
+		h.logger.WarnContext(r.Context(), "Invalid list query", "handler", "GetMenuItemRequest", "error", err)
+		writeError(w, r, err)
+		return
+	}
 
-	menuItems, err := h.menuService.GetMenuItem(r.Context())
+	menuItems, err := h.menuService.ListMenuItems(r.Context(), q)
 	if err != nil {
-		h.logger.Println("method:GetMenuItemRequest, function:GetMenuItem", err.Error())
-		w.WriteHeader(http.StatusInternalServerError)
+		h.logger.ErrorContext(r.Context(), "ListMenuItems failed", "handler", "GetMenuItemRequest", "error", err)
+		writeError(w, r, err)
 		return
 	}
 
@@ -47,7 +50,7 @@ func (h *MenuHandler) GetMenuResponse(w http.ResponseWriter, r *http.Request) {
 	w.WriteHeader(http.StatusOK)
 
 	if err := json.NewEncoder(w).Encode(menuItems); err != nil {
-		h.logger.Println("method:GetMenuItemRequest, function:json encode", err.Error())
+		h.logger.ErrorContext(r.Context(), "Error encoding response", "handler", "GetMenuItemRequest", "error", err)
 		w.WriteHeader(http.StatusInternalServerError)
 		return
 	}
@@ -57,22 +60,16 @@ func (h *MenuHandler) GetMenuByIDResponse(w http.ResponseWriter, r *http.Request
 	// Extract ID from URL path (Go 1.22+ pattern matching)
 	id := r.PathValue("id")
 	if id == "" {
-		h.logger.Println("method:GetMenuByIDRequest, function: missing id parameter")
-		w.WriteHeader(http.StatusBadRequest)
+		h.logger.WarnContext(r.Context(), "Missing id parameter", "handler", "GetMenuByIDRequest")
+		writeError(w, r, missingParam("id"))
 		return
 	}
 
 	// Call service to get the menu item
 	menuItem, err := h.menuService.GetMenuByID(r.Context(), id)
 	if err != nil {
-		h.logger.Println("method:GetMenuByIDRequest, function:GetMenuByID", err.Error())
-
-		// Check if it's a "not found" error
-		if err == sql.ErrNoRows {
-			w.WriteHeader(http.StatusNotFound)
-		} else {
-			w.WriteHeader(http.StatusInternalServerError)
-		}
+		h.logger.ErrorContext(r.Context(), "GetMenuByID failed", "handler", "GetMenuByIDRequest", "error", err)
+		writeError(w, r, err)
 		return
 	}
 
@@ -80,7 +77,7 @@ func (h *MenuHandler) GetMenuByIDResponse(w http.ResponseWriter, r *http.Request
 	w.WriteHeader(http.StatusOK)
 
 	if err := json.NewEncoder(w).Encode(menuItem); err != nil {
-		h.logger.Println("method:GetMenuByIDRequest, function:json encode", err.Error())
+		h.logger.ErrorContext(r.Context(), "Error encoding response", "handler", "GetMenuByIDRequest", "error", err)
 		w.WriteHeader(http.StatusInternalServerError)
 		return
 	}
@@ -89,21 +86,21 @@ func (h *MenuHandler) GetMenuByIDResponse(w http.ResponseWriter, r *http.Request
 func (h *MenuHandler) DeleteMenuRequest(w http.ResponseWriter, r *http.Request) {
 	id := r.PathValue("id")
 	if id == "" {
-		h.logger.Println("method:DeleteMenuRequest, function: missing id parameter")
-		w.WriteHeader(http.StatusBadRequest)
+		h.logger.WarnContext(r.Context(), "Missing id parameter", "handler", "DeleteMenuRequest")
+		writeError(w, r, missingParam("id"))
 		return
 	}
 
 	ingredient_id, err := h.menuService.DeleteMenu(r.Context(), id)
 	if err != nil {
-		h.logger.Println("method:DeleteMenuRequest, function:DeleteMenu", err.Error())
-		w.WriteHeader(http.StatusInternalServerError)
+		h.logger.ErrorContext(r.Context(), "DeleteMenu failed", "handler", "DeleteMenuRequest", "error", err)
+		writeError(w, r, err)
 		return
 	}
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusOK)
 	if err := json.NewEncoder(w).Encode(ingredient_id); err != nil {
-		h.logger.Println("method:DeleteMenuRequest, function:json encode", err.Error())
+		h.logger.ErrorContext(r.Context(), "Error encoding response", "handler", "DeleteMenuRequest", "error", err)
 		w.WriteHeader(http.StatusInternalServerError)
 		return
 	}
@@ -111,51 +108,46 @@ func (h *MenuHandler) DeleteMenuRequest(w http.ResponseWriter, r *http.Request)
 
 func (h *MenuHandler) UpdateMenuRequest(w http.ResponseWriter, r *http.Request) {
 	var request menu.UpdateMenuRequest
-	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
-		h.logger.Println("method:UpdateMenuRequest, function:json decode", err.Error())
-		http.Error(w, "Invalid request format", http.StatusBadRequest)
+	if err := decodeBody(r, &request); err != nil {
+		h.logger.WarnContext(r.Context(), "Invalid request body", "handler", "UpdateMenuRequest", "error", err)
+		writeError(w, r, err)
 		return
 	}
 
 	id := r.PathValue("id")
 	if id == "" {
-		h.logger.Println("method:UpdateMenuRequest, function: missing id parameter")
-		http.Error(w, "Missing menu ID", http.StatusBadRequest)
+		h.logger.WarnContext(r.Context(), "Missing id parameter", "handler", "UpdateMenuRequest")
+		writeError(w, r, missingParam("id"))
 		return
 	}
 
 	ingredient_id, err := h.menuService.UpdateMenu(r.Context(), request, id)
 	if err != nil {
-		h.logger.Println("method:UpdateMenuRequest, function:UpdateMenu", err.Error())
-		statusCode := http.StatusInternalServerError
-		errorMessage := "Internal server error"
-
-		// Check for specific errors to provide better responses
-		if err.Error() == "no fields to update" {
-			statusCode = http.StatusBadRequest
-			errorMessage = "No fields to update"
-		}
-
-		http.Error(w, errorMessage, statusCode)
+		h.logger.ErrorContext(r.Context(), "UpdateMenu failed", "handler", "UpdateMenuRequest", "error", err)
+		writeError(w, r, err)
 		return
 	}
 
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusOK)
 	if err := json.NewEncoder(w).Encode(ingredient_id); err != nil {
-		h.logger.Println("method:UpdateMenuRequest, function:json encode", err.Error())
+		h.logger.ErrorContext(r.Context(), "Error encoding response", "handler", "UpdateMenuRequest", "error", err)
 		http.Error(w, "Error encoding response", http.StatusInternalServerError)
 	}
 }
 
 func (h *MenuHandler) GetAllPriceHistoryResponse(w http.ResponseWriter, r *http.Request) {
-	// For GET requests, we typically don't need to decode the request body
-	// Instead, we directly call the service
+	q, err := parseListQuery(r)
+	if err != nil {
+		h.logger.WarnContext(r.Context(), "Invalid list query", "handler", "GetAllPriceHistoryRequest", "error", err)
+		writeError(w, r, err)
+		return
+	}
 
-	priceHistory, err := h.menuService.GetAllPriceHistory(r.Context())
+	priceHistory, err := h.menuService.ListPriceHistory(r.Context(), q)
 	if err != nil {
-		h.logger.Println("method:GetAllPriceHistoryRequest, function:GetAllPriceHistory", err.Error())
-		w.WriteHeader(http.StatusInternalServerError)
+		h.logger.ErrorContext(r.Context(), "ListPriceHistory failed", "handler", "GetAllPriceHistoryRequest", "error", err)
+		writeError(w, r, err)
 		return
 	}
 
@@ -163,8 +155,27 @@ func (h *MenuHandler) GetAllPriceHistoryResponse(w http.ResponseWriter, r *http.
 	w.WriteHeader(http.StatusOK)
 
 	if err := json.NewEncoder(w).Encode(priceHistory); err != nil {
-		h.logger.Println("method:GetAllPriceHistoryRequest, function:json encode", err.Error())
+		h.logger.ErrorContext(r.Context(), "Error encoding response", "handler", "GetAllPriceHistoryRequest", "error", err)
 		w.WriteHeader(http.StatusInternalServerError)
 		return
 	}
 }
+
+// SetStoreMenuItemRequest handles the PUT /menu/{id}/store endpoint
+func (h *MenuHandler) SetStoreMenuItemRequest(w http.ResponseWriter, r *http.Request) {
+	var request menu.SetStoreMenuItemRequest
+	if err := decodeBody(r, &request); err != nil {
+		h.logger.WarnContext(r.Context(), "Invalid request body", "handler", "SetStoreMenuItemRequest", "error", err)
+		writeError(w, r, err)
+		return
+	}
+
+	id := r.PathValue("id")
+	if err := h.menuService.SetStoreMenuItem(r.Context(), id, request); err != nil {
+		h.logger.ErrorContext(r.Context(), "SetStoreMenuItem failed", "handler", "SetStoreMenuItemRequest", "error", err)
+		writeError(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
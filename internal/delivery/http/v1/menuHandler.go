@@ -1,18 +1,18 @@
 package v1
 
 import (
-	"log"
+	"log/slog"
 	"net/http"
 )
 
 type MenuHandler struct {
-	logger      *log.Logger
+	logger      *slog.Logger
 	menuService menuInterface
 }
 
 func NewMenuHandler(
 	menuService menuInterface,
-	logger *log.Logger,
+	logger *slog.Logger,
 ) *MenuHandler {
 	return &MenuHandler{
 		menuService: menuService,
@@ -23,7 +23,7 @@ func NewMenuHandler(
 func SetMenuHandler(
 	router *http.ServeMux,
 	menuService menuInterface,
-	logger *log.Logger,
+	logger *slog.Logger,
 ) {
 	handler := NewMenuHandler(menuService, logger)
 	setMenuRoutes(handler, router)
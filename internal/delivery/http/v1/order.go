@@ -1,10 +1,9 @@
 package v1
 
 import (
-	"database/sql"
 	"encoding/json"
-	"io"
 	"net/http"
+	"strconv"
 	"time"
 
 	"frappuccino/internal/dto/order"
@@ -12,23 +11,23 @@ import (
 
 func (h *OrderHandler) CreateOrderRequest(w http.ResponseWriter, r *http.Request) {
 	var request order.CreateOrderRequest
-	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
-		h.logger.Println("method:CreateOrderRequest, function:json decode", err.Error())
-		w.WriteHeader(http.StatusBadRequest)
+	if err := decodeBody(r, &request); err != nil {
+		h.logger.WarnContext(r.Context(), "Invalid request body", "handler", "CreateOrderRequest", "error", err)
+		writeError(w, r, err)
 		return
 	}
 
-	id, err := h.orderService.CreateOrder(r.Context(), request)
+	response, err := h.orderService.CreateOrder(r.Context(), request)
 	if err != nil {
-		h.logger.Println("method:CreateOrderRequest, function:CreateOrder", err.Error())
-		w.WriteHeader(http.StatusInternalServerError)
+		h.logger.ErrorContext(r.Context(), "CreateOrder failed", "handler", "CreateOrderRequest", "error", err)
+		writeError(w, r, err)
 		return
 	}
 
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusOK)
-	if err := json.NewEncoder(w).Encode(id); err != nil {
-		h.logger.Println("method:CreateOrderRequest, function:json encode", err.Error())
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		h.logger.ErrorContext(r.Context(), "Error encoding response", "handler", "CreateOrderRequest", "error", err)
 		w.WriteHeader(http.StatusInternalServerError)
 		return
 	}
@@ -38,43 +37,91 @@ func (h *OrderHandler) GetOrderByIDResponse(w http.ResponseWriter, r *http.Reque
 	// Extract ID from URL path (Go 1.22+ pattern matching)
 	id := r.PathValue("id")
 	if id == "" {
-		h.logger.Println("method:GetOrderByIDRequest, function: missing id parameter")
-		w.WriteHeader(http.StatusBadRequest)
+		h.logger.WarnContext(r.Context(), "Missing id parameter", "handler", "GetOrderByIDRequest")
+		writeError(w, r, missingParam("id"))
 		return
 	}
 
 	// Call service to get the order item
 	orderItem, err := h.orderService.GetOrderByID(r.Context(), id)
 	if err != nil {
-		h.logger.Println("method:GetOrderByIDRequest, function:GetOrderByID", err.Error())
+		h.logger.ErrorContext(r.Context(), "GetOrderByID failed", "handler", "GetOrderByIDRequest", "error", err)
+		writeError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+
+	if err := json.NewEncoder(w).Encode(orderItem); err != nil {
+		h.logger.ErrorContext(r.Context(), "Error encoding response", "handler", "GetOrderByIDRequest", "error", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+}
+
+// GetOrderByNumberResponse handles the GET /orders/by-number/{n}?date= endpoint
+func (h *OrderHandler) GetOrderByNumberResponse(w http.ResponseWriter, r *http.Request) {
+	orderNumber, err := strconv.Atoi(r.PathValue("n"))
+	if err != nil || orderNumber <= 0 {
+		h.logger.WarnContext(r.Context(), "Invalid order number", "handler", "GetOrderByNumberResponse", "number", r.PathValue("n"))
+		writeError(w, r, invalidParam("n", "order number must be a positive integer"))
+		return
+	}
 
-		// Check if it's a "not found" error
-		if err == sql.ErrNoRows {
-			w.WriteHeader(http.StatusNotFound)
-		} else {
-			w.WriteHeader(http.StatusInternalServerError)
+	var date *time.Time
+	if dateStr := r.URL.Query().Get("date"); dateStr != "" {
+		parsed, err := parseDate(dateStr)
+		if err != nil {
+			h.logger.WarnContext(r.Context(), "Invalid date format", "error", err)
+			writeError(w, r, invalidDate("date"))
+			return
 		}
+		date = &parsed
+	}
+
+	orderItem, err := h.orderService.GetOrderByNumber(r.Context(), orderNumber, date)
+	if err != nil {
+		h.logger.ErrorContext(r.Context(), "GetOrderByNumber failed", "handler", "GetOrderByNumberResponse", "error", err)
+		writeError(w, r, err)
 		return
 	}
 
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusOK)
-
 	if err := json.NewEncoder(w).Encode(orderItem); err != nil {
-		h.logger.Println("method:GetOrderByIDRequest, function:json encode", err.Error())
+		h.logger.ErrorContext(r.Context(), "Error encoding response", "handler", "GetOrderByNumberResponse", "error", err)
 		w.WriteHeader(http.StatusInternalServerError)
 		return
 	}
 }
 
+// GetOrderViewResponse handles the GET /orders/{id}/{view} endpoints. ServeMux cannot register
+// GET /orders/{id}/audit next to GET /orders/by-number/{n}, as neither pattern is more specific
+// than the other, so the per-order views share a single pattern.
+func (h *OrderHandler) GetOrderViewResponse(w http.ResponseWriter, r *http.Request) {
+	switch r.PathValue("view") {
+	case "audit":
+		h.GetOrderAuditLogResponse(w, r)
+	case "receipt":
+		h.GetOrderReceiptResponse(w, r)
+	default:
+		http.NotFound(w, r)
+	}
+}
+
 func (h *OrderHandler) GetOrderResponse(w http.ResponseWriter, r *http.Request) {
-	// For GET requests, we typically don't need to decode the request body
-	// Instead, we directly call the service
+	q, err := parseListQuery(r)
+	if err != nil {
+		h.logger.WarnContext(r.Context(), "Invalid list query", "handler", "GetOrderItemRequest", "error", err)
+		writeError(w, r, err)
+		return
+	}
 
-	orderItems, err := h.orderService.GetAllOrders(r.Context())
+	orderItems, err := h.orderService.ListOrders(r.Context(), q)
 	if err != nil {
-		h.logger.Println("method:GetOrderItemRequest, function:GetOrderItem", err.Error())
-		w.WriteHeader(http.StatusInternalServerError)
+		h.logger.ErrorContext(r.Context(), "GetOrderItem failed", "handler", "GetOrderItemRequest", "error", err)
+		writeError(w, r, err)
 		return
 	}
 
@@ -82,7 +129,7 @@ func (h *OrderHandler) GetOrderResponse(w http.ResponseWriter, r *http.Request)
 	w.WriteHeader(http.StatusOK)
 
 	if err := json.NewEncoder(w).Encode(orderItems); err != nil {
-		h.logger.Println("method:GetOrderItemRequest, function:json encode", err.Error())
+		h.logger.ErrorContext(r.Context(), "Error encoding response", "handler", "GetOrderItemRequest", "error", err)
 		w.WriteHeader(http.StatusInternalServerError)
 		return
 	}
@@ -90,39 +137,30 @@ func (h *OrderHandler) GetOrderResponse(w http.ResponseWriter, r *http.Request)
 
 func (h *OrderHandler) UpdateOrderRequest(w http.ResponseWriter, r *http.Request) {
 	var request order.UpdateOrderRequest
-	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
-		h.logger.Println("method:UpdateOrderRequest, function:json decode", err.Error())
-		http.Error(w, "Invalid request format", http.StatusBadRequest)
+	if err := decodeBody(r, &request); err != nil {
+		h.logger.WarnContext(r.Context(), "Invalid request body", "handler", "UpdateOrderRequest", "error", err)
+		writeError(w, r, err)
 		return
 	}
 
 	id := r.PathValue("id")
 	if id == "" {
-		h.logger.Println("method:UpdateOrderRequest, function: missing id parameter")
-		http.Error(w, "Missing order ID", http.StatusBadRequest)
+		h.logger.WarnContext(r.Context(), "Missing id parameter", "handler", "UpdateOrderRequest")
+		writeError(w, r, missingParam("id"))
 		return
 	}
 
 	err := h.orderService.UpdateOrder(r.Context(), id, request)
 	if err != nil {
-		h.logger.Println("method:UpdateOrderRequest, function:UpdateOrder", err.Error())
-		statusCode := http.StatusInternalServerError
-		errorMessage := "Internal server error"
-
-		// Check for specific errors to provide better responses
-		if err.Error() == "no fields to update" {
-			statusCode = http.StatusBadRequest
-			errorMessage = "No fields to update"
-		}
-
-		http.Error(w, errorMessage, statusCode)
+		h.logger.ErrorContext(r.Context(), "UpdateOrder failed", "handler", "UpdateOrderRequest", "error", err)
+		writeError(w, r, err)
 		return
 	}
 
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusOK)
 	if err := json.NewEncoder(w).Encode(id); err != nil {
-		h.logger.Println("method:UpdateOrderRequest, function:json encode", err.Error())
+		h.logger.ErrorContext(r.Context(), "Error encoding response", "handler", "UpdateOrderRequest", "error", err)
 		http.Error(w, "Error encoding response", http.StatusInternalServerError)
 	}
 }
@@ -131,17 +169,24 @@ func (h *OrderHandler) UpdateOrderRequest(w http.ResponseWriter, r *http.Request
 func (h *OrderHandler) GetAllOrderStatusHistory(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 
-	history, err := h.orderService.GetAllOrderStatusHistory(ctx)
+	q, err := parseListQuery(r)
+	if err != nil {
+		h.logger.WarnContext(r.Context(), "Invalid list query", "handler", "GetAllOrderStatusHistory", "error", err)
+		writeError(w, r, err)
+		return
+	}
+
+	history, err := h.orderService.ListOrderStatusHistory(ctx, q)
 	if err != nil {
-		h.logger.Println("Error getting order status history:", err)
-		http.Error(w, "Failed to get order status history", http.StatusInternalServerError)
+		h.logger.ErrorContext(r.Context(), "Error getting order status history", "error", err)
+		writeError(w, r, err)
 		return
 	}
 
 	// Respond with JSON
 	w.Header().Set("Content-Type", "application/json")
 	if err := json.NewEncoder(w).Encode(history); err != nil {
-		h.logger.Println("Error encoding response:", err)
+		h.logger.ErrorContext(r.Context(), "Error encoding response", "error", err)
 		http.Error(w, "Internal server error", http.StatusInternalServerError)
 		return
 	}
@@ -150,21 +195,21 @@ func (h *OrderHandler) GetAllOrderStatusHistory(w http.ResponseWriter, r *http.R
 func (h *OrderHandler) DeleteOrderRequest(w http.ResponseWriter, r *http.Request) {
 	id := r.PathValue("id")
 	if id == "" {
-		h.logger.Println("method:DeleteOrderRequest, function: missing id parameter")
-		w.WriteHeader(http.StatusBadRequest)
+		h.logger.WarnContext(r.Context(), "Missing id parameter", "handler", "DeleteOrderRequest")
+		writeError(w, r, missingParam("id"))
 		return
 	}
 
 	ingredient_id, err := h.orderService.DeleteOrder(r.Context(), id)
 	if err != nil {
-		h.logger.Println("method:DeleteOrderRequest, function:DeleteOrder", err.Error())
-		w.WriteHeader(http.StatusInternalServerError)
+		h.logger.ErrorContext(r.Context(), "DeleteOrder failed", "handler", "DeleteOrderRequest", "error", err)
+		writeError(w, r, err)
 		return
 	}
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusOK)
 	if err := json.NewEncoder(w).Encode(ingredient_id); err != nil {
-		h.logger.Println("method:DeleteOrderRequest, function:json encode", err.Error())
+		h.logger.ErrorContext(r.Context(), "Error encoding response", "handler", "DeleteOrderRequest", "error", err)
 		w.WriteHeader(http.StatusInternalServerError)
 		return
 	}
@@ -174,27 +219,24 @@ func (h *OrderHandler) CloseOrder(w http.ResponseWriter, r *http.Request) {
 	// Extract order ID from URL path
 	id := r.PathValue("id")
 	if id == "" {
-		h.logger.Println("method:CloseOrder, function: missing id parameter")
-		http.Error(w, "Missing order ID", http.StatusBadRequest)
+		h.logger.WarnContext(r.Context(), "Missing id parameter", "handler", "CloseOrder")
+		writeError(w, r, missingParam("id"))
 		return
 	}
 
 	// Decode request body
 	var req order.CloseOrderRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		// If body is empty, continue with empty reason
-		if err != io.EOF {
-			h.logger.Println("method:CloseOrder, function:json decode", err.Error())
-			http.Error(w, "Invalid request format", http.StatusBadRequest)
-			return
-		}
+	if err := decodeOptionalBody(r, &req); err != nil {
+		h.logger.WarnContext(r.Context(), "Invalid request body", "handler", "CloseOrder", "error", err)
+		writeError(w, r, err)
+		return
 	}
 
 	// Call service to close the order
 	err := h.orderService.CloseOrder(r.Context(), id, req.Reason)
 	if err != nil {
-		h.logger.Println("method:CloseOrder, function:CloseOrder", err.Error())
-		http.Error(w, "Failed to close order", http.StatusInternalServerError)
+		h.logger.ErrorContext(r.Context(), "CloseOrder failed", "handler", "CloseOrder", "error", err)
+		writeError(w, r, err)
 		return
 	}
 
@@ -207,7 +249,7 @@ func (h *OrderHandler) CloseOrder(w http.ResponseWriter, r *http.Request) {
 	}
 
 	if err := json.NewEncoder(w).Encode(response); err != nil {
-		h.logger.Println("method:CloseOrder, function:json encode", err.Error())
+		h.logger.ErrorContext(r.Context(), "Error encoding response", "handler", "CloseOrder", "error", err)
 		http.Error(w, "Error encoding response", http.StatusInternalServerError)
 	}
 }
@@ -224,8 +266,8 @@ func (h *OrderHandler) GetNumberOfOrderedItems(w http.ResponseWriter, r *http.Re
 	if startDateStr != "" {
 		parsedStartDate, err := parseDate(startDateStr)
 		if err != nil {
-			h.logger.Printf("Invalid startDate format: %v", err)
-			http.Error(w, "Invalid startDate format. Please use YYYY-MM-DD format.", http.StatusBadRequest)
+			h.logger.WarnContext(r.Context(), "Invalid startDate format", "error", err)
+			writeError(w, r, invalidDate("startDate"))
 			return
 		}
 		startDate = &parsedStartDate
@@ -235,8 +277,8 @@ func (h *OrderHandler) GetNumberOfOrderedItems(w http.ResponseWriter, r *http.Re
 	if endDateStr != "" {
 		parsedEndDate, err := parseDate(endDateStr)
 		if err != nil {
-			h.logger.Printf("Invalid endDate format: %v", err)
-			http.Error(w, "Invalid endDate format. Please use YYYY-MM-DD format.", http.StatusBadRequest)
+			h.logger.WarnContext(r.Context(), "Invalid endDate format", "error", err)
+			writeError(w, r, invalidDate("endDate"))
 			return
 		}
 		endDate = &parsedEndDate
@@ -245,15 +287,15 @@ func (h *OrderHandler) GetNumberOfOrderedItems(w http.ResponseWriter, r *http.Re
 	// Call service method to get the data
 	itemCounts, err := h.orderService.GetNumberOfOrderedItems(r.Context(), startDate, endDate)
 	if err != nil {
-		h.logger.Printf("Error getting number of ordered items: %v", err)
-		http.Error(w, "Failed to retrieve ordered item counts", http.StatusInternalServerError)
+		h.logger.ErrorContext(r.Context(), "Error getting number of ordered items", "error", err)
+		writeError(w, r, err)
 		return
 	}
 
 	// Return the response
 	w.Header().Set("Content-Type", "application/json")
 	if err := json.NewEncoder(w).Encode(itemCounts); err != nil {
-		h.logger.Printf("Error encoding response: %v", err)
+		h.logger.ErrorContext(r.Context(), "Error encoding response", "error", err)
 		http.Error(w, "Internal server error", http.StatusInternalServerError)
 		return
 	}
@@ -1,31 +1,35 @@
 package v1
 
 import (
-	"log"
+	"log/slog"
 	"net/http"
 )
 
 type OrderHandler struct {
-	logger       *log.Logger
-	orderService orderInterface
+	logger         *slog.Logger
+	orderService   orderInterface
+	receiptService receiptInterface
 }
 
 func NewOrderHandler(
 	orderService orderInterface,
-	logger *log.Logger,
+	receiptService receiptInterface,
+	logger *slog.Logger,
 ) *OrderHandler {
 	return &OrderHandler{
-		orderService: orderService,
-		logger:       logger,
+		orderService:   orderService,
+		receiptService: receiptService,
+		logger:         logger,
 	}
 }
 
 func SetOrderHandler(
 	router *http.ServeMux,
 	orderService orderInterface,
-	logger *log.Logger,
+	receiptService receiptInterface,
+	logger *slog.Logger,
 ) {
-	handler := NewOrderHandler(orderService, logger)
+	handler := NewOrderHandler(orderService, receiptService, logger)
 	setOrderRoutes(handler, router)
 }
 
@@ -0,0 +1,125 @@
+package v1
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"frappuccino/internal/dto/order"
+)
+
+// AddOrderItemRequest handles the POST /orders/{id}/items endpoint
+func (h *OrderHandler) AddOrderItemRequest(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+	if id == "" {
+		h.logger.WarnContext(r.Context(), "Missing id parameter", "handler", "AddOrderItemRequest")
+		writeError(w, r, missingParam("id"))
+		return
+	}
+
+	var request order.AddOrderItemRequest
+	if err := decodeBody(r, &request); err != nil {
+		h.logger.WarnContext(r.Context(), "Invalid request body", "handler", "AddOrderItemRequest", "error", err)
+		writeError(w, r, err)
+		return
+	}
+
+	updated, err := h.orderService.AddOrderItem(r.Context(), id, request)
+	if err != nil {
+		h.logger.ErrorContext(r.Context(), "AddOrderItem failed", "handler", "AddOrderItemRequest", "error", err)
+		writeError(w, r, err)
+		return
+	}
+
+	h.writeOrder(w, r, "AddOrderItemRequest", updated)
+}
+
+// UpdateOrderItemRequest handles the PUT /orders/{id}/items/{itemId} endpoint
+func (h *OrderHandler) UpdateOrderItemRequest(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+	itemID := r.PathValue("itemId")
+	if id == "" || itemID == "" {
+		h.logger.WarnContext(r.Context(), "Missing id parameter", "handler", "UpdateOrderItemRequest")
+		writeError(w, r, missingParam("id"))
+		return
+	}
+
+	var request order.UpdateOrderItemRequest
+	if err := decodeBody(r, &request); err != nil {
+		h.logger.WarnContext(r.Context(), "Invalid request body", "handler", "UpdateOrderItemRequest", "error", err)
+		writeError(w, r, err)
+		return
+	}
+
+	updated, err := h.orderService.UpdateOrderItem(r.Context(), id, itemID, request)
+	if err != nil {
+		h.logger.ErrorContext(r.Context(), "UpdateOrderItem failed", "handler", "UpdateOrderItemRequest", "error", err)
+		writeError(w, r, err)
+		return
+	}
+
+	h.writeOrder(w, r, "UpdateOrderItemRequest", updated)
+}
+
+// RemoveOrderItemRequest handles the DELETE /orders/{id}/items/{itemId} endpoint
+func (h *OrderHandler) RemoveOrderItemRequest(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+	itemID := r.PathValue("itemId")
+	if id == "" || itemID == "" {
+		h.logger.WarnContext(r.Context(), "Missing id parameter", "handler", "RemoveOrderItemRequest")
+		writeError(w, r, missingParam("id"))
+		return
+	}
+
+	// The reason is optional, so an empty body is fine
+	var request order.RemoveOrderItemRequest
+	if err := decodeOptionalBody(r, &request); err != nil {
+		h.logger.WarnContext(r.Context(), "Invalid request body", "handler", "RemoveOrderItemRequest", "error", err)
+		writeError(w, r, err)
+		return
+	}
+
+	updated, err := h.orderService.RemoveOrderItem(r.Context(), id, itemID, request.Reason)
+	if err != nil {
+		h.logger.ErrorContext(r.Context(), "RemoveOrderItem failed", "handler", "RemoveOrderItemRequest", "error", err)
+		writeError(w, r, err)
+		return
+	}
+
+	h.writeOrder(w, r, "RemoveOrderItemRequest", updated)
+}
+
+// GetOrderAuditLogResponse handles the GET /orders/{id}/audit endpoint
+func (h *OrderHandler) GetOrderAuditLogResponse(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+	if id == "" {
+		h.logger.WarnContext(r.Context(), "Missing id parameter", "handler", "GetOrderAuditLogResponse")
+		writeError(w, r, missingParam("id"))
+		return
+	}
+
+	entries, err := h.orderService.GetOrderAuditLog(r.Context(), id)
+	if err != nil {
+		h.logger.ErrorContext(r.Context(), "GetOrderAuditLog failed", "handler", "GetOrderAuditLogResponse", "error", err)
+		writeError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(w).Encode(entries); err != nil {
+		h.logger.ErrorContext(r.Context(), "Error encoding response", "handler", "GetOrderAuditLogResponse", "error", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+}
+
+// writeOrder encodes an order as the JSON response
+func (h *OrderHandler) writeOrder(w http.ResponseWriter, r *http.Request, method string, response order.GetOrderResponse) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		h.logger.ErrorContext(r.Context(), "Error encoding response", "handler", method, "error", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+}
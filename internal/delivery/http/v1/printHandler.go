@@ -0,0 +1,31 @@
+package v1
+
+import (
+	"log/slog"
+	"net/http"
+)
+
+// PrintHandler handles the kitchen printers and their print queue
+type PrintHandler struct {
+	logger       *slog.Logger
+	printService printInterface
+}
+
+func NewPrintHandler(
+	printService printInterface,
+	logger *slog.Logger,
+) *PrintHandler {
+	return &PrintHandler{
+		printService: printService,
+		logger:       logger,
+	}
+}
+
+func SetPrintHandler(
+	router *http.ServeMux,
+	printService printInterface,
+	logger *slog.Logger,
+) {
+	handler := NewPrintHandler(printService, logger)
+	setPrintRoutes(handler, router)
+}
@@ -0,0 +1,92 @@
+package v1
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"frappuccino/internal/dto/printing"
+)
+
+func (h *PrintHandler) CreatePrinterRequest(w http.ResponseWriter, r *http.Request) {
+	var request printing.CreatePrinterRequest
+	if err := decodeBody(r, &request); err != nil {
+		h.logger.WarnContext(r.Context(), "Invalid request body", "handler", "CreatePrinterRequest", "error", err)
+		writeError(w, r, err)
+		return
+	}
+
+	id, err := h.printService.CreatePrinter(r.Context(), request)
+	if err != nil {
+		h.logger.ErrorContext(r.Context(), "CreatePrinter failed", "handler", "CreatePrinterRequest", "error", err)
+		writeError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(w).Encode(id); err != nil {
+		h.logger.ErrorContext(r.Context(), "Error encoding response", "handler", "CreatePrinterRequest", "error", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+}
+
+func (h *PrintHandler) GetPrintersResponse(w http.ResponseWriter, r *http.Request) {
+	printers, err := h.printService.GetPrinters(r.Context())
+	if err != nil {
+		h.logger.ErrorContext(r.Context(), "GetPrinters failed", "handler", "GetPrintersResponse", "error", err)
+		writeError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(w).Encode(printers); err != nil {
+		h.logger.ErrorContext(r.Context(), "Error encoding response", "handler", "GetPrintersResponse", "error", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+}
+
+// GetPrintJobsResponse handles the GET /print-jobs?status= endpoint
+func (h *PrintHandler) GetPrintJobsResponse(w http.ResponseWriter, r *http.Request) {
+	jobs, err := h.printService.GetPrintJobs(r.Context(), r.URL.Query().Get("status"))
+	if err != nil {
+		h.logger.ErrorContext(r.Context(), "GetPrintJobs failed", "handler", "GetPrintJobsResponse", "error", err)
+		writeError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(w).Encode(jobs); err != nil {
+		h.logger.ErrorContext(r.Context(), "Error encoding response", "handler", "GetPrintJobsResponse", "error", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+}
+
+// ReprintRequest handles the POST /print-jobs/{id}/reprint endpoint
+func (h *PrintHandler) ReprintRequest(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+	if id == "" {
+		h.logger.WarnContext(r.Context(), "Missing id parameter", "handler", "ReprintRequest")
+		writeError(w, r, missingParam("id"))
+		return
+	}
+
+	job, err := h.printService.Reprint(r.Context(), id)
+	if err != nil {
+		h.logger.ErrorContext(r.Context(), "Reprint failed", "handler", "ReprintRequest", "error", err)
+		writeError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(w).Encode(job); err != nil {
+		h.logger.ErrorContext(r.Context(), "Error encoding response", "handler", "ReprintRequest", "error", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+}
@@ -0,0 +1,33 @@
+package v1
+
+import (
+	"fmt"
+	"net/http"
+)
+
+// GetOrderReceiptResponse handles the GET /orders/{id}/receipt?format=text|html|escpos endpoint
+func (h *OrderHandler) GetOrderReceiptResponse(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+	if id == "" {
+		h.logger.WarnContext(r.Context(), "Missing id parameter", "handler", "GetOrderReceiptResponse")
+		writeError(w, r, missingParam("id"))
+		return
+	}
+
+	receipt, err := h.receiptService.GetReceipt(r.Context(), id, r.URL.Query().Get("format"))
+	if err != nil {
+		h.logger.ErrorContext(r.Context(), "GetReceipt failed", "handler", "GetOrderReceiptResponse", "error", err)
+		writeError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", receipt.ContentType)
+	if receipt.Format == "escpos" {
+		// Raw printer bytes are downloaded and sent to the printer rather than shown
+		w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=receipt-%s.bin", id))
+	}
+	w.WriteHeader(http.StatusOK)
+	if _, err := w.Write(receipt.Body); err != nil {
+		h.logger.ErrorContext(r.Context(), "Error writing response", "handler", "GetOrderReceiptResponse", "error", err)
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"strconv"
 
 	"frappuccino/internal/dto/report"
+	serviceReport "frappuccino/internal/service/report"
 )
 
 // SearchReport handles the GET /reports/search endpoint
@@ -13,8 +14,8 @@ func (h *ReportHandler) SearchReport(w http.ResponseWriter, r *http.Request) {
 	// Extract query parameters
 	query := r.URL.Query().Get("q")
 	if query == "" {
-		h.logger.Println("Search query is required")
-		http.Error(w, "Search query is required", http.StatusBadRequest)
+		h.logger.WarnContext(r.Context(), "Search query is required")
+		writeError(w, r, missingParam("q"))
 		return
 	}
 
@@ -32,8 +33,8 @@ func (h *ReportHandler) SearchReport(w http.ResponseWriter, r *http.Request) {
 	if minPriceStr != "" {
 		minPrice, err := strconv.ParseFloat(minPriceStr, 64)
 		if err != nil {
-			h.logger.Printf("Invalid minPrice parameter: %v", err)
-			http.Error(w, "Invalid minPrice parameter", http.StatusBadRequest)
+			h.logger.WarnContext(r.Context(), "Invalid minPrice parameter", "error", err)
+			writeError(w, r, invalidParam("minPrice", "minPrice must be a number"))
 			return
 		}
 		req.MinPrice = minPrice
@@ -42,8 +43,8 @@ func (h *ReportHandler) SearchReport(w http.ResponseWriter, r *http.Request) {
 	if maxPriceStr != "" {
 		maxPrice, err := strconv.ParseFloat(maxPriceStr, 64)
 		if err != nil {
-			h.logger.Printf("Invalid maxPrice parameter: %v", err)
-			http.Error(w, "Invalid maxPrice parameter", http.StatusBadRequest)
+			h.logger.WarnContext(r.Context(), "Invalid maxPrice parameter", "error", err)
+			writeError(w, r, invalidParam("maxPrice", "maxPrice must be a number"))
 			return
 		}
 		req.MaxPrice = maxPrice
@@ -52,15 +53,15 @@ func (h *ReportHandler) SearchReport(w http.ResponseWriter, r *http.Request) {
 	// Perform search
 	response, err := h.reportService.Search(r.Context(), req)
 	if err != nil {
-		h.logger.Printf("Search error: %v", err)
-		http.Error(w, "Error performing search", http.StatusInternalServerError)
+		h.logger.ErrorContext(r.Context(), "Search error", "error", err)
+		writeError(w, r, err)
 		return
 	}
 
 	// Return response
 	w.Header().Set("Content-Type", "application/json")
 	if err := json.NewEncoder(w).Encode(response); err != nil {
-		h.logger.Printf("Error encoding search response: %v", err)
+		h.logger.ErrorContext(r.Context(), "Error encoding search response", "error", err)
 		http.Error(w, "Error encoding search response", http.StatusInternalServerError)
 		return
 	}
@@ -70,8 +71,8 @@ func (h *ReportHandler) GetOrderedItemsByPeriod(w http.ResponseWriter, r *http.R
 	// Extract query parameters
 	period := r.URL.Query().Get("period")
 	if period == "" {
-		h.logger.Println("Period parameter is required")
-		http.Error(w, "Period parameter is required", http.StatusBadRequest)
+		h.logger.WarnContext(r.Context(), "Period parameter is required")
+		writeError(w, r, missingParam("period"))
 		return
 	}
 
@@ -80,8 +81,8 @@ func (h *ReportHandler) GetOrderedItemsByPeriod(w http.ResponseWriter, r *http.R
 
 	// Validate required parameters
 	if period == "day" && month == "" {
-		h.logger.Println("Month parameter is required when period is day")
-		http.Error(w, "Month parameter is required when period is day", http.StatusBadRequest)
+		h.logger.WarnContext(r.Context(), "Month parameter is required when period is day")
+		writeError(w, r, serviceReport.ErrMonthRequired)
 		return
 	}
 
@@ -95,15 +96,15 @@ func (h *ReportHandler) GetOrderedItemsByPeriod(w http.ResponseWriter, r *http.R
 	// Perform the query
 	response, err := h.reportService.GetOrderedItemsByPeriod(r.Context(), req)
 	if err != nil {
-		h.logger.Printf("Error getting ordered items by period: %v", err)
-		http.Error(w, "Error retrieving ordered items data", http.StatusInternalServerError)
+		h.logger.ErrorContext(r.Context(), "Error getting ordered items by period", "error", err)
+		writeError(w, r, err)
 		return
 	}
 
 	// Return response
 	w.Header().Set("Content-Type", "application/json")
 	if err := json.NewEncoder(w).Encode(response); err != nil {
-		h.logger.Printf("Error encoding ordered items response: %v", err)
+		h.logger.ErrorContext(r.Context(), "Error encoding ordered items response", "error", err)
 		http.Error(w, "Error encoding response", http.StatusInternalServerError)
 		return
 	}
@@ -1,20 +1,20 @@
 package v1
 
 import (
-	"log"
+	"log/slog"
 	"net/http"
 )
 
 // ReportHandler handles report-related operations
 type ReportHandler struct {
-	logger        *log.Logger
+	logger        *slog.Logger
 	reportService reportInterface
 }
 
 // NewReportHandler creates a new report handler
 func NewReportHandler(
 	reportService reportInterface,
-	logger *log.Logger,
+	logger *slog.Logger,
 ) *ReportHandler {
 	return &ReportHandler{
 		reportService: reportService,
@@ -26,7 +26,7 @@ func NewReportHandler(
 func SetReportHandler(
 	router *http.ServeMux,
 	reportService reportInterface,
-	logger *log.Logger,
+	logger *slog.Logger,
 ) {
 	handler := NewReportHandler(reportService, logger)
 	setReportRoutes(handler, router)
@@ -0,0 +1,68 @@
+package v1
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"frappuccino/internal/dto/report"
+)
+
+// GetServiceTimes handles the GET /reports/service-times endpoint
+func (h *ReportHandler) GetServiceTimes(w http.ResponseWriter, r *http.Request) {
+	// Parse query parameters
+	startDateStr := r.URL.Query().Get("startDate")
+	endDateStr := r.URL.Query().Get("endDate")
+	slaStr := r.URL.Query().Get("sla")
+
+	// Initialize request
+	req := report.ServiceTimesRequest{}
+
+	// Parse dates if provided
+	if startDateStr != "" {
+		startDate, err := parseDate(startDateStr)
+		if err != nil {
+			h.logger.WarnContext(r.Context(), "Invalid startDate format", "error", err)
+			writeError(w, r, invalidDate("startDate"))
+			return
+		}
+		req.StartDate = &startDate
+	}
+
+	if endDateStr != "" {
+		endDate, err := parseDate(endDateStr)
+		if err != nil {
+			h.logger.WarnContext(r.Context(), "Invalid endDate format", "error", err)
+			writeError(w, r, invalidDate("endDate"))
+			return
+		}
+		req.EndDate = &endDate
+	}
+
+	// Parse SLA override if provided, e.g. "10m" or "90s"
+	if slaStr != "" {
+		sla, err := time.ParseDuration(slaStr)
+		if err != nil || sla <= 0 {
+			h.logger.WarnContext(r.Context(), "Invalid sla parameter", "sla", slaStr)
+			writeError(w, r, invalidParam("sla", "sla must be a positive duration such as 15m"))
+			return
+		}
+		req.SLA = sla
+	}
+
+	// Get service times
+	response, err := h.reportService.GetServiceTimes(r.Context(), req)
+	if err != nil {
+		h.logger.ErrorContext(r.Context(), "Error getting service times", "error", err)
+		writeError(w, r, err)
+		return
+	}
+
+	// Return response
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		h.logger.ErrorContext(r.Context(), "Error encoding service times response", "error", err)
+		http.Error(w, "Error encoding response", http.StatusInternalServerError)
+		return
+	}
+}
@@ -0,0 +1,195 @@
+package v1
+
+import (
+	"fmt"
+	"net/http"
+
+	"frappuccino/internal/delivery/http/openapi"
+	"frappuccino/internal/dto/access"
+	"frappuccino/internal/dto/auth"
+	"frappuccino/internal/dto/delivery"
+	"frappuccino/internal/dto/health"
+	"frappuccino/internal/dto/inventory"
+	"frappuccino/internal/dto/list"
+	"frappuccino/internal/dto/menu"
+	"frappuccino/internal/dto/printing"
+	"frappuccino/internal/dto/report"
+	"frappuccino/internal/dto/station"
+	"frappuccino/internal/dto/store"
+	"frappuccino/internal/dto/table"
+
+	orderdto "frappuccino/internal/dto/order"
+	perm "frappuccino/internal/service/access"
+)
+
+// Parameters several routes share
+var (
+	startDateParam = openapi.Query("startDate", "First day included, YYYY-MM-DD", openapi.Date())
+	endDateParam   = openapi.Query("endDate", "Last day included, YYYY-MM-DD", openapi.Date())
+	limitParam     = openapi.Query("limit", "Most entries to return", openapi.Integer())
+
+	statusParam          = openapi.Query("status", "Only list entries with this status", openapi.String())
+	customerParam        = openapi.Query("customer", "Part of the customer name, in any case", openapi.String())
+	categoryParam        = openapi.Query("category", "Category of the menu items", openapi.String())
+	minPriceParam        = openapi.Query("minPrice", "Lowest price included", openapi.Number())
+	maxPriceParam        = openapi.Query("maxPrice", "Highest price included", openapi.Number())
+	transactionTypeParam = openapi.Query("transactionType", "Kind of stock movement", openapi.String())
+)
+
+// listParams are the parameters of a list endpoint sorted by one of sorts, followed by the
+// filters it supports
+func listParams(sorts []string, filters ...openapi.Parameter) []openapi.Parameter {
+	keys := make([]string, 0, 2*len(sorts))
+	for _, sort := range sorts {
+		keys = append(keys, sort, "-"+sort)
+	}
+	params := []openapi.Parameter{
+		openapi.Query("limit", fmt.Sprintf("Entries per page, from 1 to %d, %d by default", list.MaxLimit, list.DefaultLimit), openapi.Integer()),
+		openapi.Query("cursor", "next_cursor of the page before", openapi.String()),
+		openapi.Query("sort", "Sort key, descending when it starts with -", openapi.Enum(keys...)),
+	}
+	return append(params, filters...)
+}
+
+// specInfo heads the OpenAPI document
+var specInfo = openapi.Info{
+	Title:       "Frappuccino API",
+	Version:     "1.0.0",
+	Description: "Orders, menu, inventory and reports of the coffee shops. Errors are answered as RFC 7807 problems.",
+}
+
+// routes describes every route endpoints.go registers, for the OpenAPI document. A route
+// registered without an entry here fails TestEveryRouteIsDescribed.
+var routes = []openapi.Route{
+	// Inventory
+	{Pattern: "POST /inventory", ID: "CreateInventoryRequest", Summary: "Add an ingredient", Tag: "inventory", Permission: string(perm.InventoryEdit), Body: inventory.CreateInventoryRequest{}, Response: ""},
+	{Pattern: "GET /inventory", ID: "GetInventoryResponse", Summary: "List the ingredients", Tag: "inventory", Permission: string(perm.InventoryView),
+		Params: listParams([]string{"name", "quantity", "price", "last_updated"}, startDateParam, endDateParam, minPriceParam, maxPriceParam), Response: inventory.ListInventoryResponse{}},
+	{Pattern: "GET /inventory/{id}", ID: "GetInventoryByIDResponse", Summary: "Get an ingredient", Tag: "inventory", Permission: string(perm.InventoryView), Response: inventory.GetInventoryResponse{}},
+	{Pattern: "DELETE /inventory/{id}", ID: "DeleteInventoryRequest", Summary: "Delete an ingredient", Tag: "inventory", Permission: string(perm.InventoryDelete), Response: ""},
+	{Pattern: "PUT /inventory/{id}", ID: "UpdateInventoryRequest", Summary: "Update an ingredient", Tag: "inventory", Permission: string(perm.InventoryEdit), Body: inventory.UpdateInventoryRequest{}, Response: ""},
+	{Pattern: "POST /inventory/transactions", ID: "CreateInventoryTransactionRequest", Summary: "Record a stock movement", Tag: "inventory", Permission: string(perm.InventoryRecord), Body: inventory.CreateTransactionRequest{}, Response: map[string]string{}},
+	{Pattern: "GET /inventory/{id}/transactions", ID: "GetInventoryTransactionsResponse", Summary: "List the stock movements of an ingredient", Tag: "inventory", Permission: string(perm.InventoryView),
+		Params: listParams([]string{"created_at", "quantity_change"}, startDateParam, endDateParam, transactionTypeParam), Response: inventory.ListTransactionsResponse{}},
+	{Pattern: "GET /inventory/getLeftOvers", ID: "GetLeftOversResponse", Summary: "Page through the stock left", Tag: "inventory", Permission: string(perm.InventoryView),
+		Params: listParams([]string{"name", "quantity", "price", "last_updated"}, startDateParam, endDateParam, minPriceParam, maxPriceParam), Response: inventory.GetLeftOversResponse{}},
+	{Pattern: "POST /inventory/transfers", ID: "TransferStockRequest", Summary: "Move stock to another store", Tag: "inventory", Permission: string(perm.InventoryTransfer), Body: inventory.TransferStockRequest{}, Status: http.StatusCreated, Response: inventory.TransferStockResponse{}},
+
+	// Menu
+	{Pattern: "POST /menu", ID: "CreateMenuItemRequest", Summary: "Add a menu item", Tag: "menu", Permission: string(perm.MenuManage), Body: menu.CreateMenuItemRequest{}, Response: ""},
+	{Pattern: "GET /menu", ID: "GetMenuResponse", Summary: "List the menu", Tag: "menu", Permission: string(perm.MenuView),
+		Params: listParams([]string{"name", "price", "updated_at"}, categoryParam, minPriceParam, maxPriceParam), Response: menu.ListMenuResponse{}},
+	{Pattern: "GET /menu/{id}", ID: "GetMenuByIDResponse", Summary: "Get a menu item", Tag: "menu", Permission: string(perm.MenuView), Response: menu.GetMenuResponse{}},
+	{Pattern: "DELETE /menu/{id}", ID: "DeleteMenuRequest", Summary: "Delete a menu item", Tag: "menu", Permission: string(perm.MenuManage), Response: ""},
+	{Pattern: "PUT /menu/{id}", ID: "UpdateMenuRequest", Summary: "Update a menu item", Tag: "menu", Permission: string(perm.MenuEdit), Body: menu.UpdateMenuRequest{}, Response: ""},
+	{Pattern: "PUT /menu/{id}/store", ID: "SetStoreMenuItemRequest", Summary: "Set the price or availability of a menu item in the store", Tag: "menu", Permission: string(perm.MenuEdit), Body: menu.SetStoreMenuItemRequest{}, Status: http.StatusNoContent},
+	{Pattern: "GET /price-history", ID: "GetAllPriceHistoryResponse", Summary: "List the price changes", Tag: "menu", Permission: string(perm.PriceHistory),
+		Params: listParams([]string{"changed_at", "new_price"}, startDateParam, endDateParam, minPriceParam, maxPriceParam), Response: menu.ListPriceHistoryResponse{}},
+
+	// Orders
+	{Pattern: "GET /orders/{id}", ID: "GetOrderByIDResponse", Summary: "Get an order", Tag: "orders", Permission: string(perm.OrderView), Response: orderdto.GetOrderResponse{}},
+	{Pattern: "GET /orders/by-number/{n}", ID: "GetOrderByNumberResponse", Summary: "Get an order by the number on its ticket", Tag: "orders", Permission: string(perm.OrderView), Params: []openapi.Parameter{
+		openapi.Path("n", "Number of the order within its day", openapi.Integer()),
+		openapi.Query("date", "Day of the order, today by default", openapi.Date()),
+	}, Response: orderdto.GetOrderResponse{}},
+	{Pattern: "GET /orders", ID: "GetOrderResponse", Summary: "List the orders", Tag: "orders", Permission: string(perm.OrderView),
+		Params: listParams([]string{"created_at", "order_number", "total_amount", "customer_name"}, statusParam, startDateParam, endDateParam, customerParam, minPriceParam, maxPriceParam), Response: orderdto.ListOrdersResponse{}},
+	{Pattern: "POST /orders", ID: "CreateOrderRequest", Summary: "Take an order", Tag: "orders", Permission: string(perm.OrderTake), Body: orderdto.CreateOrderRequest{}, Response: orderdto.CreateOrderResponse{}},
+	{Pattern: "PUT /orders/{id}", ID: "UpdateOrderRequest", Summary: "Update an order", Tag: "orders", Permission: string(perm.OrderTake), Body: orderdto.UpdateOrderRequest{}, Response: ""},
+	{Pattern: "GET /order-status-history", ID: "GetAllOrderStatusHistory", Summary: "List the status changes of the orders", Tag: "orders", Permission: string(perm.OrderView),
+		Params: listParams([]string{"changed_at"}, statusParam, startDateParam, endDateParam), Response: orderdto.ListOrderStatusHistoryResponse{}},
+	{Pattern: "DELETE /orders/{id}", ID: "DeleteOrderRequest", Summary: "Delete an order", Tag: "orders", Permission: string(perm.OrderDelete), Response: ""},
+	{Pattern: "POST /orders/{id}/close", ID: "CloseOrder", Summary: "Close an order", Tag: "orders", Permission: string(perm.OrderTake), Body: orderdto.CloseOrderRequest{}, BodyOptional: true, Response: map[string]string{}},
+	{Pattern: "GET /orders/numberOfOrderedItems", ID: "GetNumberOfOrderedItems", Summary: "Count the ordered items by menu item", Tag: "reports", Permission: string(perm.ReportView), Params: []openapi.Parameter{startDateParam, endDateParam}, Response: map[string]int{}},
+	{Pattern: "POST /orders/batch-process", ID: "BatchProcessOrdersRequest", Summary: "Take several orders at once", Tag: "orders", Permission: string(perm.OrderBatch), Body: orderdto.BatchOrderRequest{}, Response: orderdto.BatchOrderResponse{}},
+	{Pattern: "POST /orders/{id}/items", ID: "AddOrderItemRequest", Summary: "Add an item to an order", Tag: "orders", Permission: string(perm.OrderTake), Body: orderdto.AddOrderItemRequest{}, Response: orderdto.GetOrderResponse{}},
+	{Pattern: "PUT /orders/{id}/items/{itemId}", ID: "UpdateOrderItemRequest", Summary: "Change an item of an order", Tag: "orders", Permission: string(perm.OrderTake), Body: orderdto.UpdateOrderItemRequest{}, Response: orderdto.GetOrderResponse{}},
+	{Pattern: "DELETE /orders/{id}/items/{itemId}", ID: "RemoveOrderItemRequest", Summary: "Remove an item from an order", Tag: "orders", Permission: string(perm.OrderTake), Body: orderdto.RemoveOrderItemRequest{}, BodyOptional: true, Response: orderdto.GetOrderResponse{}},
+	{Pattern: "GET /orders/{id}/{view}", ID: "GetOrderViewResponse", Summary: "Get the audit log of an order as JSON, or its receipt", Tag: "orders", Permission: string(perm.OrderView), Params: []openapi.Parameter{
+		openapi.Path("view", "audit for the changes made to the order, which needs order:audit, receipt for its receipt", openapi.Enum("audit", "receipt")),
+		openapi.Query("format", "Format of the receipt, text by default", openapi.Enum("text", "html", "escpos")),
+	}, Response: []orderdto.OrderAuditEntryResponse{}, Media: []string{"text/plain", "text/html", "application/octet-stream"}},
+	{Pattern: "POST /orders/{id}/split", ID: "SplitOrderRequest", Summary: "Split an order into several", Tag: "orders", Permission: string(perm.OrderTake), Body: orderdto.SplitOrderRequest{}, Response: orderdto.SplitOrderResponse{}},
+	{Pattern: "POST /orders/merge", ID: "MergeOrdersRequest", Summary: "Merge orders into one", Tag: "orders", Permission: string(perm.OrderTake), Body: orderdto.MergeOrdersRequest{}, Response: orderdto.GetOrderResponse{}},
+	{Pattern: "POST /orders/{id}/courier", ID: "AssignCourierRequest", Summary: "Assign a courier to a delivery order", Tag: "delivery", Permission: string(perm.OrderTake), Body: orderdto.AssignCourierRequest{}, Response: orderdto.GetOrderResponse{}},
+
+	// Reports
+	{Pattern: "GET /reports/search", ID: "SearchReport", Summary: "Search the menu and the orders", Tag: "reports", Permission: string(perm.ReportView), Params: []openapi.Parameter{
+		{Name: "q", In: "query", Description: "Words to search for", Required: true, Schema: openapi.String()},
+		openapi.Query("filter", "Comma separated list of menu, orders or all", openapi.String()),
+		openapi.Query("minPrice", "Lowest price of the results", openapi.Number()),
+		openapi.Query("maxPrice", "Highest price of the results", openapi.Number()),
+	}, Response: report.SearchResponse{}},
+	{Pattern: "GET /reports/orderedItemsByPeriod", ID: "GetOrderedItemsByPeriod", Summary: "Count the ordered items by day or month", Tag: "reports", Permission: string(perm.ReportView), Params: []openapi.Parameter{
+		{Name: "period", In: "query", Description: "day counts the days of a month, month the months of a year", Required: true, Schema: openapi.Enum("day", "month")},
+		openapi.Query("month", "Month counted by day, like january; required when period is day", openapi.String()),
+		openapi.Query("year", "Year counted, this year by default", openapi.Integer()),
+	}, Response: report.OrderedItemsByPeriodResponse{}},
+	{Pattern: "GET /reports/total-sales", ID: "GetTotalSales", Summary: "Total the sales", Tag: "reports", Permission: string(perm.ReportView), Params: []openapi.Parameter{
+		startDateParam, endDateParam,
+		openapi.Query("status", "Only count orders with this status", openapi.String()),
+	}, Response: report.TotalSalesResponse{}},
+	{Pattern: "GET /reports/popular-items", ID: "GetPopularItems", Summary: "Rank the menu items by sales", Tag: "reports", Permission: string(perm.ReportView), Params: []openapi.Parameter{startDateParam, endDateParam, limitParam}, Response: report.PopularItemsResponse{}},
+	{Pattern: "GET /reports/service-times", ID: "GetServiceTimes", Summary: "Measure how long orders take", Tag: "reports", Permission: string(perm.ReportView), Params: []openapi.Parameter{
+		startDateParam, endDateParam,
+		openapi.Query("sla", "Service level to report breaches of, as a duration like 15m", openapi.String()),
+	}, Response: report.ServiceTimesResponse{}},
+
+	// Stations
+	{Pattern: "POST /stations", ID: "CreateStationRequest", Summary: "Add a kitchen station", Tag: "stations", Permission: string(perm.StationManage), Body: station.CreateStationRequest{}, Response: ""},
+	{Pattern: "GET /stations", ID: "GetStationsResponse", Summary: "List the stations and their routes", Tag: "stations", Permission: string(perm.StationView), Response: []station.GetStationResponse{}},
+	{Pattern: "POST /stations/{id}/routes", ID: "CreateStationRouteRequest", Summary: "Route a menu item or category to a station", Tag: "stations", Permission: string(perm.StationManage), Body: station.CreateStationRouteRequest{}, Response: ""},
+	{Pattern: "GET /stations/{id}/tickets", ID: "GetStationTicketsResponse", Summary: "List the tickets of a station", Tag: "stations", Permission: string(perm.StationView), Params: []openapi.Parameter{
+		openapi.Query("status", "Only list tickets with this status", openapi.String()),
+	}, Response: []station.GetTicketResponse{}},
+	{Pattern: "PUT /tickets/{id}", ID: "UpdateTicketStatusRequest", Summary: "Move a ticket along", Tag: "stations", Permission: string(perm.StationWork), Body: station.UpdateTicketStatusRequest{}, Response: ""},
+
+	// Tables
+	{Pattern: "POST /tables", ID: "CreateTableRequest", Summary: "Add a table", Tag: "tables", Permission: string(perm.TableManage), Body: table.CreateTableRequest{}, Response: ""},
+	{Pattern: "GET /tables", ID: "GetTablesResponse", Summary: "List the tables and their open tabs", Tag: "tables", Permission: string(perm.TableService), Response: []table.GetTableResponse{}},
+	{Pattern: "POST /tables/{id}/open", ID: "OpenTabRequest", Summary: "Open a tab at a table", Tag: "tables", Permission: string(perm.TableService), Body: table.OpenTabRequest{}, Response: orderdto.CreateOrderResponse{}},
+	{Pattern: "POST /tables/{id}/items", ID: "AddTabItemRequest", Summary: "Add an item to the open tab of a table", Tag: "tables", Permission: string(perm.TableService), Body: orderdto.AddOrderItemRequest{}, Response: orderdto.GetOrderResponse{}},
+	{Pattern: "POST /tables/{id}/close", ID: "CloseTabRequest", Summary: "Close the open tab of a table", Tag: "tables", Permission: string(perm.TableService), Body: table.CloseTabRequest{}, BodyOptional: true, Response: map[string]string{}},
+	{Pattern: "GET /reports/table-turnover", ID: "GetTableTurnover", Summary: "Measure how often tables turn over", Tag: "reports", Permission: string(perm.ReportView), Params: []openapi.Parameter{startDateParam, endDateParam}, Response: table.TableTurnoverResponse{}},
+
+	// Delivery
+	{Pattern: "POST /delivery-zones", ID: "CreateZoneRequest", Summary: "Add a delivery zone", Tag: "delivery", Permission: string(perm.DeliveryManage), Body: delivery.CreateZoneRequest{}, Response: ""},
+	{Pattern: "GET /delivery-zones", ID: "GetZonesResponse", Summary: "List the delivery zones", Tag: "delivery", Permission: string(perm.DeliveryView), Response: []delivery.GetZoneResponse{}},
+	{Pattern: "POST /couriers", ID: "CreateCourierRequest", Summary: "Add a courier", Tag: "delivery", Permission: string(perm.DeliveryManage), Body: delivery.CreateCourierRequest{}, Response: ""},
+	{Pattern: "GET /couriers", ID: "GetCouriersResponse", Summary: "List the couriers", Tag: "delivery", Permission: string(perm.DeliveryView), Response: []delivery.GetCourierResponse{}},
+
+	// Printing
+	{Pattern: "POST /printers", ID: "CreatePrinterRequest", Summary: "Add a printer", Tag: "printing", Permission: string(perm.PrintManage), Body: printing.CreatePrinterRequest{}, Response: ""},
+	{Pattern: "GET /printers", ID: "GetPrintersResponse", Summary: "List the printers", Tag: "printing", Permission: string(perm.PrintView), Response: []printing.GetPrinterResponse{}},
+	{Pattern: "GET /print-jobs", ID: "GetPrintJobsResponse", Summary: "List the latest print jobs", Tag: "printing", Permission: string(perm.PrintView), Params: []openapi.Parameter{
+		openapi.Query("status", "Only list jobs with this status", openapi.Enum("pending", "printing", "printed", "failed")),
+	}, Response: []printing.PrintJobResponse{}},
+	{Pattern: "POST /print-jobs/{id}/reprint", ID: "ReprintRequest", Summary: "Print a job again", Tag: "printing", Permission: string(perm.PrintReprint), Response: printing.PrintJobResponse{}},
+
+	// Auth
+	{Pattern: "POST /auth/login", ID: "LoginRequest", Summary: "Log in for an access and a refresh token", Tag: "auth", Public: true, Body: auth.LoginRequest{}, Response: auth.TokenResponse{}},
+	{Pattern: "POST /auth/refresh", ID: "RefreshRequest", Summary: "Trade a refresh token for new tokens", Tag: "auth", Public: true, Body: auth.RefreshRequest{}, Response: auth.TokenResponse{}},
+	{Pattern: "POST /auth/logout", ID: "LogoutRequest", Summary: "End the session", Tag: "auth", Body: auth.LogoutRequest{}, BodyOptional: true, Status: http.StatusNoContent},
+	{Pattern: "POST /auth/password", ID: "ChangePasswordRequest", Summary: "Change the caller's password", Tag: "auth", Body: auth.ChangePasswordRequest{}, Status: http.StatusNoContent},
+	{Pattern: "POST /users", ID: "CreateUserRequest", Summary: "Add a staff user", Tag: "auth", Permission: string(perm.UserManage), Body: auth.CreateUserRequest{}, Response: ""},
+	{Pattern: "GET /users", ID: "GetUsersResponse", Summary: "List the staff users", Tag: "auth", Permission: string(perm.UserManage), Response: []auth.UserResponse{}},
+	{Pattern: "PATCH /users/{id}", ID: "UpdateUserRequest", Summary: "Change the role or status of a user", Tag: "auth", Permission: string(perm.UserManage), Body: auth.UpdateUserRequest{}, Status: http.StatusNoContent},
+	{Pattern: "POST /api-keys", ID: "CreateAPIKeyRequest", Summary: "Issue an API key", Tag: "auth", Permission: string(perm.APIKeyManage), Body: auth.CreateAPIKeyRequest{}, Response: auth.CreateAPIKeyResponse{}},
+	{Pattern: "GET /api-keys", ID: "GetAPIKeysResponse", Summary: "List the API keys", Tag: "auth", Permission: string(perm.APIKeyManage), Response: []auth.APIKeyResponse{}},
+	{Pattern: "DELETE /api-keys/{id}", ID: "RevokeAPIKeyRequest", Summary: "Revoke an API key", Tag: "auth", Permission: string(perm.APIKeyManage), Status: http.StatusNoContent},
+
+	// Access
+	{Pattern: "GET /reports/access-denials", ID: "GetAccessDenialsResponse", Summary: "List the requests refused for the caller's role", Tag: "reports", Permission: string(perm.AccessAudit), Params: []openapi.Parameter{startDateParam, endDateParam, limitParam}, Response: []access.AccessDenialResponse{}},
+
+	// Stores
+	{Pattern: "POST /stores", ID: "CreateStoreRequest", Summary: "Add a store", Tag: "stores", Permission: string(perm.StoreManage), Body: store.CreateStoreRequest{}, Response: ""},
+	{Pattern: "GET /stores", ID: "GetStoresResponse", Summary: "List the stores", Tag: "stores", Permission: string(perm.StoreView), Response: []store.GetStoreResponse{}},
+
+	// Health
+	{Pattern: "GET /healthz", ID: "HealthzResponse", Summary: "Liveness probe", Tag: "health", Public: true, Response: health.HealthResponse{}},
+	{Pattern: "GET /readyz", ID: "ReadyzResponse", Summary: "Readiness probe; 503 until the database is reachable and migrated", Tag: "health", Public: true, Response: health.HealthResponse{}},
+
+	// Docs
+	{Pattern: "GET /openapi.json", ID: "OpenAPIResponse", Summary: "This document", Tag: "docs", Public: true, Media: []string{"application/json"}},
+	{Pattern: "GET /docs", ID: "DocsResponse", Summary: "Browse this document", Tag: "docs", Public: true, Media: []string{"text/html"}},
+}
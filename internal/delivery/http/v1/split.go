@@ -0,0 +1,59 @@
+package v1
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"frappuccino/internal/dto/order"
+)
+
+// SplitOrderRequest handles the POST /orders/{id}/split endpoint
+func (h *OrderHandler) SplitOrderRequest(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+	if id == "" {
+		h.logger.WarnContext(r.Context(), "Missing id parameter", "handler", "SplitOrderRequest")
+		writeError(w, r, missingParam("id"))
+		return
+	}
+
+	var request order.SplitOrderRequest
+	if err := decodeBody(r, &request); err != nil {
+		h.logger.WarnContext(r.Context(), "Invalid request body", "handler", "SplitOrderRequest", "error", err)
+		writeError(w, r, err)
+		return
+	}
+
+	response, err := h.orderService.SplitOrder(r.Context(), id, request)
+	if err != nil {
+		h.logger.ErrorContext(r.Context(), "SplitOrder failed", "handler", "SplitOrderRequest", "error", err)
+		writeError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		h.logger.ErrorContext(r.Context(), "Error encoding response", "handler", "SplitOrderRequest", "error", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+}
+
+// MergeOrdersRequest handles the POST /orders/merge endpoint
+func (h *OrderHandler) MergeOrdersRequest(w http.ResponseWriter, r *http.Request) {
+	var request order.MergeOrdersRequest
+	if err := decodeBody(r, &request); err != nil {
+		h.logger.WarnContext(r.Context(), "Invalid request body", "handler", "MergeOrdersRequest", "error", err)
+		writeError(w, r, err)
+		return
+	}
+
+	response, err := h.orderService.MergeOrders(r.Context(), request)
+	if err != nil {
+		h.logger.ErrorContext(r.Context(), "MergeOrders failed", "handler", "MergeOrdersRequest", "error", err)
+		writeError(w, r, err)
+		return
+	}
+
+	h.writeOrder(w, r, "MergeOrdersRequest", response)
+}
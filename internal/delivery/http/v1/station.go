@@ -0,0 +1,138 @@
+package v1
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"frappuccino/internal/dto/station"
+)
+
+func (h *StationHandler) CreateStationRequest(w http.ResponseWriter, r *http.Request) {
+	var request station.CreateStationRequest
+	if err := decodeBody(r, &request); err != nil {
+		h.logger.WarnContext(r.Context(), "Invalid request body", "handler", "CreateStationRequest", "error", err)
+		writeError(w, r, err)
+		return
+	}
+
+	id, err := h.stationService.CreateStation(r.Context(), request)
+	if err != nil {
+		h.logger.ErrorContext(r.Context(), "CreateStation failed", "handler", "CreateStationRequest", "error", err)
+		writeError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(w).Encode(id); err != nil {
+		h.logger.ErrorContext(r.Context(), "Error encoding response", "handler", "CreateStationRequest", "error", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+}
+
+func (h *StationHandler) GetStationsResponse(w http.ResponseWriter, r *http.Request) {
+	stations, err := h.stationService.GetStations(r.Context())
+	if err != nil {
+		h.logger.ErrorContext(r.Context(), "GetStations failed", "handler", "GetStationsResponse", "error", err)
+		writeError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(w).Encode(stations); err != nil {
+		h.logger.ErrorContext(r.Context(), "Error encoding response", "handler", "GetStationsResponse", "error", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+}
+
+func (h *StationHandler) CreateStationRouteRequest(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+	if id == "" {
+		h.logger.WarnContext(r.Context(), "Missing id parameter", "handler", "CreateStationRouteRequest")
+		writeError(w, r, missingParam("id"))
+		return
+	}
+
+	var request station.CreateStationRouteRequest
+	if err := decodeBody(r, &request); err != nil {
+		h.logger.WarnContext(r.Context(), "Invalid request body", "handler", "CreateStationRouteRequest", "error", err)
+		writeError(w, r, err)
+		return
+	}
+
+	routeID, err := h.stationService.CreateStationRoute(r.Context(), id, request)
+	if err != nil {
+		h.logger.ErrorContext(r.Context(), "CreateStationRoute failed", "handler", "CreateStationRouteRequest", "error", err)
+		writeError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(w).Encode(routeID); err != nil {
+		h.logger.ErrorContext(r.Context(), "Error encoding response", "handler", "CreateStationRouteRequest", "error", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+}
+
+// GetStationTicketsResponse handles the GET /stations/{id}/tickets endpoint
+func (h *StationHandler) GetStationTicketsResponse(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+	if id == "" {
+		h.logger.WarnContext(r.Context(), "Missing id parameter", "handler", "GetStationTicketsResponse")
+		writeError(w, r, missingParam("id"))
+		return
+	}
+
+	status := r.URL.Query().Get("status")
+
+	tickets, err := h.stationService.GetStationTickets(r.Context(), id, status)
+	if err != nil {
+		h.logger.ErrorContext(r.Context(), "GetStationTickets failed", "handler", "GetStationTicketsResponse", "error", err)
+		writeError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(w).Encode(tickets); err != nil {
+		h.logger.ErrorContext(r.Context(), "Error encoding response", "handler", "GetStationTicketsResponse", "error", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+}
+
+func (h *StationHandler) UpdateTicketStatusRequest(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+	if id == "" {
+		h.logger.WarnContext(r.Context(), "Missing id parameter", "handler", "UpdateTicketStatusRequest")
+		writeError(w, r, missingParam("id"))
+		return
+	}
+
+	var request station.UpdateTicketStatusRequest
+	if err := decodeBody(r, &request); err != nil {
+		h.logger.WarnContext(r.Context(), "Invalid request body", "handler", "UpdateTicketStatusRequest", "error", err)
+		writeError(w, r, err)
+		return
+	}
+
+	err := h.stationService.UpdateTicketStatus(r.Context(), id, request)
+	if err != nil {
+		h.logger.ErrorContext(r.Context(), "UpdateTicketStatus failed", "handler", "UpdateTicketStatusRequest", "error", err)
+		writeError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(w).Encode(id); err != nil {
+		h.logger.ErrorContext(r.Context(), "Error encoding response", "handler", "UpdateTicketStatusRequest", "error", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+}
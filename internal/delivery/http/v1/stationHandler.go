@@ -0,0 +1,31 @@
+package v1
+
+import (
+	"log/slog"
+	"net/http"
+)
+
+// StationHandler handles kitchen station and ticket operations
+type StationHandler struct {
+	logger         *slog.Logger
+	stationService stationInterface
+}
+
+func NewStationHandler(
+	stationService stationInterface,
+	logger *slog.Logger,
+) *StationHandler {
+	return &StationHandler{
+		stationService: stationService,
+		logger:         logger,
+	}
+}
+
+func SetStationHandler(
+	router *http.ServeMux,
+	stationService stationInterface,
+	logger *slog.Logger,
+) {
+	handler := NewStationHandler(stationService, logger)
+	setStationRoutes(handler, router)
+}
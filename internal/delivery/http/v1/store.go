@@ -0,0 +1,51 @@
+package v1
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"frappuccino/internal/dto/store"
+)
+
+// CreateStoreRequest handles the POST /stores endpoint
+func (h *StoreHandler) CreateStoreRequest(w http.ResponseWriter, r *http.Request) {
+	var request store.CreateStoreRequest
+	if err := decodeBody(r, &request); err != nil {
+		h.logger.WarnContext(r.Context(), "Invalid request body", "handler", "CreateStoreRequest", "error", err)
+		writeError(w, r, err)
+		return
+	}
+
+	id, err := h.storeService.CreateStore(r.Context(), request)
+	if err != nil {
+		h.logger.ErrorContext(r.Context(), "CreateStore failed", "handler", "CreateStoreRequest", "error", err)
+		writeError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(w).Encode(id); err != nil {
+		h.logger.ErrorContext(r.Context(), "Error encoding response", "handler", "CreateStoreRequest", "error", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+}
+
+// GetStoresResponse handles the GET /stores endpoint
+func (h *StoreHandler) GetStoresResponse(w http.ResponseWriter, r *http.Request) {
+	stores, err := h.storeService.GetStores(r.Context())
+	if err != nil {
+		h.logger.ErrorContext(r.Context(), "GetStores failed", "handler", "GetStoresResponse", "error", err)
+		writeError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(w).Encode(stores); err != nil {
+		h.logger.ErrorContext(r.Context(), "Error encoding response", "handler", "GetStoresResponse", "error", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+}
@@ -0,0 +1,31 @@
+package v1
+
+import (
+	"log/slog"
+	"net/http"
+)
+
+// StoreHandler handles the locations of the business
+type StoreHandler struct {
+	logger       *slog.Logger
+	storeService storeInterface
+}
+
+func NewStoreHandler(
+	storeService storeInterface,
+	logger *slog.Logger,
+) *StoreHandler {
+	return &StoreHandler{
+		storeService: storeService,
+		logger:       logger,
+	}
+}
+
+func SetStoreHandler(
+	router *http.ServeMux,
+	storeService storeInterface,
+	logger *slog.Logger,
+) {
+	handler := NewStoreHandler(storeService, logger)
+	setStoreRoutes(handler, router)
+}
@@ -0,0 +1,192 @@
+package v1
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"frappuccino/internal/dto/order"
+	"frappuccino/internal/dto/table"
+)
+
+func (h *TableHandler) CreateTableRequest(w http.ResponseWriter, r *http.Request) {
+	var request table.CreateTableRequest
+	if err := decodeBody(r, &request); err != nil {
+		h.logger.WarnContext(r.Context(), "Invalid request body", "handler", "CreateTableRequest", "error", err)
+		writeError(w, r, err)
+		return
+	}
+
+	id, err := h.tableService.CreateTable(r.Context(), request)
+	if err != nil {
+		h.logger.ErrorContext(r.Context(), "CreateTable failed", "handler", "CreateTableRequest", "error", err)
+		writeError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(w).Encode(id); err != nil {
+		h.logger.ErrorContext(r.Context(), "Error encoding response", "handler", "CreateTableRequest", "error", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+}
+
+// GetTablesResponse handles the GET /tables endpoint with current occupancy and running totals
+func (h *TableHandler) GetTablesResponse(w http.ResponseWriter, r *http.Request) {
+	tables, err := h.tableService.GetTables(r.Context())
+	if err != nil {
+		h.logger.ErrorContext(r.Context(), "GetTables failed", "handler", "GetTablesResponse", "error", err)
+		writeError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(w).Encode(tables); err != nil {
+		h.logger.ErrorContext(r.Context(), "Error encoding response", "handler", "GetTablesResponse", "error", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+}
+
+// OpenTabRequest handles the POST /tables/{id}/open endpoint
+func (h *TableHandler) OpenTabRequest(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+	if id == "" {
+		h.logger.WarnContext(r.Context(), "Missing id parameter", "handler", "OpenTabRequest")
+		writeError(w, r, missingParam("id"))
+		return
+	}
+
+	var request table.OpenTabRequest
+	if err := decodeBody(r, &request); err != nil {
+		h.logger.WarnContext(r.Context(), "Invalid request body", "handler", "OpenTabRequest", "error", err)
+		writeError(w, r, err)
+		return
+	}
+
+	response, err := h.tableService.OpenTab(r.Context(), id, request)
+	if err != nil {
+		h.logger.ErrorContext(r.Context(), "OpenTab failed", "handler", "OpenTabRequest", "error", err)
+		writeError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		h.logger.ErrorContext(r.Context(), "Error encoding response", "handler", "OpenTabRequest", "error", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+}
+
+// AddTabItemRequest handles the POST /tables/{id}/items endpoint
+func (h *TableHandler) AddTabItemRequest(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+	if id == "" {
+		h.logger.WarnContext(r.Context(), "Missing id parameter", "handler", "AddTabItemRequest")
+		writeError(w, r, missingParam("id"))
+		return
+	}
+
+	var request order.AddOrderItemRequest
+	if err := decodeBody(r, &request); err != nil {
+		h.logger.WarnContext(r.Context(), "Invalid request body", "handler", "AddTabItemRequest", "error", err)
+		writeError(w, r, err)
+		return
+	}
+
+	updated, err := h.tableService.AddTabItem(r.Context(), id, request)
+	if err != nil {
+		h.logger.ErrorContext(r.Context(), "AddTabItem failed", "handler", "AddTabItemRequest", "error", err)
+		writeError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(w).Encode(updated); err != nil {
+		h.logger.ErrorContext(r.Context(), "Error encoding response", "handler", "AddTabItemRequest", "error", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+}
+
+// CloseTabRequest handles the POST /tables/{id}/close endpoint
+func (h *TableHandler) CloseTabRequest(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+	if id == "" {
+		h.logger.WarnContext(r.Context(), "Missing id parameter", "handler", "CloseTabRequest")
+		writeError(w, r, missingParam("id"))
+		return
+	}
+
+	var req table.CloseTabRequest
+	if err := decodeOptionalBody(r, &req); err != nil {
+		h.logger.WarnContext(r.Context(), "Invalid request body", "handler", "CloseTabRequest", "error", err)
+		writeError(w, r, err)
+		return
+	}
+
+	if err := h.tableService.CloseTab(r.Context(), id, req.Reason); err != nil {
+		h.logger.ErrorContext(r.Context(), "CloseTab failed", "handler", "CloseTabRequest", "error", err)
+		writeError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	response := map[string]string{
+		"message":  "Tab closed successfully",
+		"table_id": id,
+	}
+
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		h.logger.ErrorContext(r.Context(), "Error encoding response", "handler", "CloseTabRequest", "error", err)
+		http.Error(w, "Error encoding response", http.StatusInternalServerError)
+	}
+}
+
+// GetTableTurnover handles the GET /reports/table-turnover endpoint
+func (h *TableHandler) GetTableTurnover(w http.ResponseWriter, r *http.Request) {
+	startDateStr := r.URL.Query().Get("startDate")
+	endDateStr := r.URL.Query().Get("endDate")
+
+	req := table.TableTurnoverRequest{}
+
+	if startDateStr != "" {
+		startDate, err := parseDate(startDateStr)
+		if err != nil {
+			h.logger.WarnContext(r.Context(), "Invalid startDate format", "error", err)
+			writeError(w, r, invalidDate("startDate"))
+			return
+		}
+		req.StartDate = &startDate
+	}
+
+	if endDateStr != "" {
+		endDate, err := parseDate(endDateStr)
+		if err != nil {
+			h.logger.WarnContext(r.Context(), "Invalid endDate format", "error", err)
+			writeError(w, r, invalidDate("endDate"))
+			return
+		}
+		req.EndDate = &endDate
+	}
+
+	response, err := h.tableService.GetTableTurnover(r.Context(), req)
+	if err != nil {
+		h.logger.ErrorContext(r.Context(), "Error getting table turnover", "error", err)
+		writeError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		h.logger.ErrorContext(r.Context(), "Error encoding response", "error", err)
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+}
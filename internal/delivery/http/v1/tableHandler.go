@@ -0,0 +1,31 @@
+package v1
+
+import (
+	"log/slog"
+	"net/http"
+)
+
+// TableHandler handles dining tables, their tabs and the turnover report
+type TableHandler struct {
+	logger       *slog.Logger
+	tableService tableInterface
+}
+
+func NewTableHandler(
+	tableService tableInterface,
+	logger *slog.Logger,
+) *TableHandler {
+	return &TableHandler{
+		tableService: tableService,
+		logger:       logger,
+	}
+}
+
+func SetTableHandler(
+	router *http.ServeMux,
+	tableService tableInterface,
+	logger *slog.Logger,
+) {
+	handler := NewTableHandler(tableService, logger)
+	setTableRoutes(handler, router)
+}
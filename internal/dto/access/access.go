@@ -0,0 +1,15 @@
+package access
+
+import "time"
+
+type AccessDenialResponse struct {
+	DenialID      string    `json:"denial_id"`
+	PrincipalKind string    `json:"principal_kind"`
+	PrincipalID   string    `json:"principal_id"`
+	PrincipalName string    `json:"principal_name"`
+	Role          string    `json:"role"`
+	Permission    string    `json:"permission"`
+	Method        string    `json:"method"`
+	Path          string    `json:"path"`
+	CreatedAt     time.Time `json:"created_at"`
+}
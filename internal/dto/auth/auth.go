@@ -0,0 +1,80 @@
+package auth
+
+import "time"
+
+type LoginRequest struct {
+	Username string `json:"username" validate:"required"`
+	Password string `json:"password" validate:"required"`
+}
+
+type RefreshRequest struct {
+	RefreshToken string `json:"refresh_token" validate:"required"`
+}
+
+// LogoutRequest optionally names the refresh token of the session to end along with the access token
+type LogoutRequest struct {
+	RefreshToken string `json:"refresh_token,omitempty"`
+}
+
+type TokenResponse struct {
+	AccessToken      string `json:"access_token"`
+	RefreshToken     string `json:"refresh_token"`
+	TokenType        string `json:"token_type"`
+	ExpiresIn        int    `json:"expires_in"`         // seconds until the access token expires
+	RefreshExpiresIn int    `json:"refresh_expires_in"` // seconds until the refresh token expires
+}
+
+type CreateUserRequest struct {
+	Username string `json:"username" validate:"required"`
+	Password string `json:"password" validate:"required"`
+	Role     string `json:"role,omitempty" validate:"omitempty,enum=staff_role"` // barista, shift_lead, manager or admin; defaults to barista
+	Store    string `json:"store,omitempty"`                                     // ID or code of the home store; empty lets the user work in any store
+}
+
+// UpdateUserRequest changes only the fields that are set
+type UpdateUserRequest struct {
+	Role     *string `json:"role,omitempty" validate:"enum=staff_role"`
+	IsActive *bool   `json:"is_active,omitempty"`
+}
+
+type UserResponse struct {
+	UserID    string    `json:"user_id"`
+	Username  string    `json:"username"`
+	Role      string    `json:"role"`
+	StoreID   *string   `json:"store_id,omitempty"`
+	IsActive  bool      `json:"is_active"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type ChangePasswordRequest struct {
+	CurrentPassword string `json:"current_password" validate:"required"`
+	NewPassword     string `json:"new_password" validate:"required"`
+}
+
+type CreateAPIKeyRequest struct {
+	Name  string `json:"name" validate:"required"`
+	Role  string `json:"role,omitempty" validate:"omitempty,enum=staff_role"` // defaults to barista
+	Store string `json:"store,omitempty"`                                     // ID or code of the only store the key may work in
+}
+
+// CreateAPIKeyResponse carries the key itself, which is shown only this once
+type CreateAPIKeyResponse struct {
+	KeyID   string  `json:"key_id"`
+	Name    string  `json:"name"`
+	Prefix  string  `json:"prefix"`
+	Role    string  `json:"role"`
+	StoreID *string `json:"store_id,omitempty"`
+	Key     string  `json:"key"`
+}
+
+type APIKeyResponse struct {
+	KeyID      string     `json:"key_id"`
+	Name       string     `json:"name"`
+	Prefix     string     `json:"prefix"`
+	Role       string     `json:"role"`
+	StoreID    *string    `json:"store_id,omitempty"`
+	CreatedBy  *string    `json:"created_by,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
+	LastUsedAt *time.Time `json:"last_used_at,omitempty"`
+	RevokedAt  *time.Time `json:"revoked_at,omitempty"`
+}
@@ -0,0 +1,38 @@
+package delivery
+
+import "time"
+
+// CreateZoneRequest defines a zone by a postcode list, a polygon of [latitude, longitude] points, or both
+type CreateZoneRequest struct {
+	Name         string       `json:"name" validate:"required"`
+	Postcodes    []string     `json:"postcodes,omitempty" validate:"required_without=Polygon"`
+	Polygon      [][2]float64 `json:"polygon,omitempty" validate:"omitempty,min=3"`
+	Fee          float64      `json:"fee" validate:"gte=0"`
+	MinimumOrder float64      `json:"minimum_order" validate:"gte=0"`
+	IsActive     *bool        `json:"is_active,omitempty"` // defaults to true
+}
+
+type GetZoneResponse struct {
+	ZoneID       string       `json:"zone_id"`
+	Name         string       `json:"name"`
+	Postcodes    []string     `json:"postcodes"`
+	Polygon      [][2]float64 `json:"polygon,omitempty"`
+	Fee          float64      `json:"fee"`
+	MinimumOrder float64      `json:"minimum_order"`
+	IsActive     bool         `json:"is_active"`
+	CreatedAt    time.Time    `json:"created_at"`
+}
+
+type CreateCourierRequest struct {
+	Name     string `json:"name" validate:"required"`
+	Phone    string `json:"phone,omitempty"`
+	IsActive *bool  `json:"is_active,omitempty"` // defaults to true
+}
+
+type GetCourierResponse struct {
+	CourierID string    `json:"courier_id"`
+	Name      string    `json:"name"`
+	Phone     string    `json:"phone,omitempty"`
+	IsActive  bool      `json:"is_active"`
+	CreatedAt time.Time `json:"created_at"`
+}
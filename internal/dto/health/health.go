@@ -0,0 +1,7 @@
+package health
+
+type HealthResponse struct {
+	Status string `json:"status"` // ok or unavailable
+	// Checks tells what each dependency reported, e.g. "ok" or the error it failed with
+	Checks map[string]string `json:"checks,omitempty"`
+}
@@ -1,19 +1,24 @@
 package inventory
 
-import "time"
+import (
+	"time"
+
+	"frappuccino/internal/dto/list"
+)
 
 // DTO = Data Transfer Object
 
 type CreateInventoryRequest struct {
-	Name         string  `json:"name"`
-	Quantity     float32 `json:"quantity"`
-	Unit         string  `json:"unit"`
-	UnitPrice    float32 `json:"unit_price"`
-	ReorderPoint float32 `json:"reorder_point"`
+	Name         string  `json:"name" validate:"required"`
+	Quantity     float32 `json:"quantity" validate:"gte=0"`
+	Unit         string  `json:"unit" validate:"required,enum=unit_type"`
+	UnitPrice    float32 `json:"unit_price" validate:"gte=0"`
+	ReorderPoint float32 `json:"reorder_point" validate:"gte=0"`
 }
 
 type GetInventoryResponse struct {
 	IngredientID string    `json:"ingredient_id"`
+	StoreID      string    `json:"store_id"`
 	Name         string    `json:"name"`
 	Quantity     float32   `json:"quantity"`
 	Unit         string    `json:"unit"`
@@ -23,17 +28,17 @@ type GetInventoryResponse struct {
 }
 
 type UpdateInventoryRequest struct {
-	Name         *string  `json:"name"`
-	Quantity     *float32 `json:"quantity"`
-	Unit         *string  `json:"unit"`
-	UnitPrice    *float32 `json:"unit_price"`
-	ReorderPoint *float32 `json:"reorder_point"`
+	Name         *string  `json:"name" validate:"min=1"`
+	Quantity     *float32 `json:"quantity" validate:"gte=0"`
+	Unit         *string  `json:"unit" validate:"enum=unit_type"`
+	UnitPrice    *float32 `json:"unit_price" validate:"gte=0"`
+	ReorderPoint *float32 `json:"reorder_point" validate:"gte=0"`
 }
 
 type CreateTransactionRequest struct {
-	IngredientID    string  `json:"ingredient_id"`
-	QuantityChange  float32 `json:"quantity_change"`
-	TransactionType string  `json:"transaction_type"`
+	IngredientID    string  `json:"ingredient_id" validate:"required,uuid"`
+	QuantityChange  float32 `json:"quantity_change" validate:"gt=0"`
+	TransactionType string  `json:"transaction_type" validate:"required,oneof=addition deduction adjustment waste"`
 	Reason          string  `json:"reason"`
 }
 
@@ -43,20 +48,50 @@ type TransactionResponse struct {
 	QuantityChange  float32   `json:"quantity_change"`
 	TransactionType string    `json:"transaction_type"`
 	Reason          string    `json:"reason"`
+	TransferID      *string   `json:"transfer_id,omitempty"`
 	CreatedAt       time.Time `json:"created_at"`
 }
 type LeftOverItem struct {
+	StoreID  string  `json:"store_id"`
 	Name     string  `json:"name"`
 	Quantity float32 `json:"quantity"`
 	Price    float32 `json:"price"`
 }
 
 type GetLeftOversResponse struct {
-	CurrentPage int            `json:"currentPage"`
-	HasNextPage bool           `json:"hasNextPage"`
-	PageSize    int            `json:"pageSize"`
-	TotalPages  int            `json:"totalPages"`
-	Data        []LeftOverItem `json:"data"`
+	Data []LeftOverItem `json:"data"`
+	list.Page
+}
+
+// ListInventoryResponse is a page of the stock of a store
+type ListInventoryResponse struct {
+	Items []GetInventoryResponse `json:"items"`
+	list.Page
+}
+
+// ListTransactionsResponse is a page of the stock movements of an ingredient
+type ListTransactionsResponse struct {
+	Items []TransactionResponse `json:"items"`
+	list.Page
+}
+
+// TransferStockRequest moves stock from the ingredient row of the current store to another store
+type TransferStockRequest struct {
+	IngredientID string  `json:"ingredient_id" validate:"required,uuid"`
+	ToStore      string  `json:"to_store" validate:"required"` // store ID or code
+	Quantity     float32 `json:"quantity" validate:"gt=0"`
+	Reason       string  `json:"reason"`
+}
+
+type TransferStockResponse struct {
+	TransferID       string    `json:"transfer_id"`
+	FromStoreID      string    `json:"from_store_id"`
+	ToStoreID        string    `json:"to_store_id"`
+	FromIngredientID string    `json:"from_ingredient_id"`
+	ToIngredientID   string    `json:"to_ingredient_id"`
+	Quantity         float32   `json:"quantity"`
+	Reason           string    `json:"reason"`
+	CreatedAt        time.Time `json:"created_at"`
 }
 
 // type UpdateInventoryRequest struct {
@@ -0,0 +1,101 @@
+// Package list holds what the list endpoints share: the query that asks for one page of a list,
+// narrowed by filters and ordered by a sort key, and what a page says about the rest of the list
+package list
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"strings"
+	"time"
+
+	"frappuccino/internal/apperror"
+)
+
+const (
+	// DefaultLimit is the size of a page when the query does not ask for one
+	DefaultLimit = 50
+	// MaxLimit is the largest page a query may ask for
+	MaxLimit = 200
+)
+
+// Errors of queries a list cannot answer. The sort and filter errors name the field at fault,
+// as what a list supports differs from list to list.
+var (
+	ErrInvalidCursor = apperror.InvalidField("invalid_cursor", "cursor", "cursor is not one this list returned for the same sort")
+	ErrInvalidSort   = apperror.New(apperror.Invalid, "invalid_sort", "the list cannot be sorted by this key")
+	ErrInvalidFilter = apperror.New(apperror.Invalid, "invalid_filter", "the list cannot be filtered by this field")
+)
+
+// Query asks for a page of a list. Pages are found by keyset: the cursor holds the sort value
+// and ID of the last row of the page before, so rows written meanwhile do not shift the pages.
+type Query struct {
+	// Limit is the size of the page; DefaultLimit when zero
+	Limit int
+	// Cursor is the NextCursor of the page before, empty for the first page
+	Cursor string
+	// Sort is a sort key of the list, like created_at, or -created_at to sort descending;
+	// the default sort of the list when empty
+	Sort   string
+	Filter Filter
+}
+
+// Filter narrows a list. Fields left at their zero value do not filter; each list supports the
+// fields that make sense for its rows.
+type Filter struct {
+	Status          string
+	From            *time.Time // first day included
+	To              *time.Time // last day included
+	Customer        string     // part of the customer name, in any case
+	Category        string
+	MinPrice        *float64
+	MaxPrice        *float64
+	TransactionType string
+}
+
+// Page is what a page of a list says about the rest of it
+type Page struct {
+	// NextCursor asks for the next page; it is left out on the last page
+	NextCursor string `json:"next_cursor,omitempty"`
+	// TotalCount is the number of rows that match the filters, on every page
+	TotalCount int `json:"total_count"`
+}
+
+// PageSize is the number of rows a page holds
+func (q Query) PageSize() int {
+	if q.Limit <= 0 {
+		return DefaultLimit
+	}
+	if q.Limit > MaxLimit {
+		return MaxLimit
+	}
+	return q.Limit
+}
+
+// Cursor is where a page ends: the sort it was read with, and the sort value and ID of its last row
+type Cursor struct {
+	Sort  string `json:"s"`
+	Value string `json:"v"`
+	ID    string `json:"id"`
+}
+
+// Encode makes the cursor an opaque string for clients to send back
+func (c Cursor) Encode() string {
+	data, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// DecodeCursor reads a cursor Encode wrote, and checks that it was written for sort
+func DecodeCursor(s, sort string) (Cursor, error) {
+	data, err := base64.RawURLEncoding.DecodeString(strings.TrimSpace(s))
+	if err != nil {
+		return Cursor{}, ErrInvalidCursor
+	}
+	var c Cursor
+	if err := json.Unmarshal(data, &c); err != nil || c.ID == "" {
+		return Cursor{}, ErrInvalidCursor
+	}
+	if c.Sort != sort {
+		return Cursor{}, ErrInvalidCursor
+	}
+	return c, nil
+}
@@ -0,0 +1,87 @@
+package list
+
+import (
+	"encoding/base64"
+	"errors"
+	"testing"
+
+	"frappuccino/internal/apperror"
+)
+
+func TestCursorRoundTrip(t *testing.T) {
+	cursors := []Cursor{
+		{Sort: "-created_at", Value: "2026-03-14 08:05:00.123456+00", ID: "7f9c2ba4-e88f-41d2-9e5a-8a1d3b0c6f21"},
+		{Sort: "customer_name", Value: "Ana & Bo, \"Zoë\" <3", ID: "a0000000-0000-4000-8000-000000000001"},
+		{Sort: "total_amount", Value: "", ID: "a0000000-0000-4000-8000-000000000002"},
+	}
+
+	for _, want := range cursors {
+		encoded := want.Encode()
+		if _, err := base64.RawURLEncoding.DecodeString(encoded); err != nil {
+			t.Errorf("Encode() = %q, which is not unpadded URL-safe base64: %v", encoded, err)
+		}
+
+		got, err := DecodeCursor(encoded, want.Sort)
+		if err != nil {
+			t.Fatalf("DecodeCursor(%q, %q): %v", encoded, want.Sort, err)
+		}
+		if got != want {
+			t.Errorf("DecodeCursor(Encode()) = %+v, want %+v", got, want)
+		}
+	}
+}
+
+func TestDecodeCursorRejects(t *testing.T) {
+	cursor := Cursor{Sort: "-created_at", Value: "2026-03-14 08:05:00+00", ID: "7f9c2ba4-e88f-41d2-9e5a-8a1d3b0c6f21"}
+	encoded := cursor.Encode()
+	raw := func(s string) string { return base64.RawURLEncoding.EncodeToString([]byte(s)) }
+
+	tests := []struct {
+		name   string
+		cursor string
+		sort   string
+	}{
+		{"another direction", encoded, "created_at"},
+		{"another sort key", encoded, "-total_amount"},
+		{"the default sort", encoded, ""},
+		{"invalid base64", "not a cursor!", "-created_at"},
+		{"padded base64", base64.URLEncoding.EncodeToString([]byte(`{"s":"-created_at","v":"x","id":"yz"}`)), "-created_at"},
+		{"truncated", encoded[:len(encoded)-4], "-created_at"},
+		{"not JSON", raw("created_at|2026-03-14|7f9c2ba4"), "-created_at"},
+		{"JSON of another shape", raw(`["-created_at", "x", "y"]`), "-created_at"},
+		{"no ID", raw(`{"s":"-created_at","v":"2026-03-14 08:05:00+00"}`), "-created_at"},
+		{"sort edited to match", raw(`{"s":"-total_amount","v":"2026-03-14 08:05:00+00","id":"7f9c2ba4-e88f-41d2-9e5a-8a1d3b0c6f21"}`), "-created_at"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := DecodeCursor(tt.cursor, tt.sort)
+			if !errors.Is(err, ErrInvalidCursor) {
+				t.Fatalf("DecodeCursor() error = %v, want ErrInvalidCursor", err)
+			}
+
+			// Invalid is answered with 400, naming the cursor parameter
+			var appErr *apperror.Error
+			if !errors.As(err, &appErr) || appErr.Kind != apperror.Invalid || len(appErr.Fields) != 1 || appErr.Fields[0].Field != "cursor" {
+				t.Errorf("DecodeCursor() error = %#v, want an Invalid error about the cursor", err)
+			}
+		})
+	}
+}
+
+func TestPageSize(t *testing.T) {
+	tests := []struct {
+		limit, want int
+	}{
+		{0, DefaultLimit},
+		{-1, DefaultLimit},
+		{1, 1},
+		{MaxLimit, MaxLimit},
+		{MaxLimit + 1, MaxLimit},
+	}
+	for _, tt := range tests {
+		if got := (Query{Limit: tt.limit}).PageSize(); got != tt.want {
+			t.Errorf("PageSize() with limit %d = %d, want %d", tt.limit, got, tt.want)
+		}
+	}
+}
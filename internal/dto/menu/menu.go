@@ -3,12 +3,14 @@ package menu
 import (
 	"encoding/json"
 	"time"
+
+	"frappuccino/internal/dto/list"
 )
 
 type MenuItemIngredient struct {
-	IngredientID string  `json:"ingredient_id"`
-	Quantity     float64 `json:"quantity"`
-	Unit         string  `json:"unit"`
+	IngredientID string  `json:"ingredient_id" validate:"required,uuid"`
+	Quantity     float64 `json:"quantity" validate:"gt=0"`
+	Unit         string  `json:"unit" validate:"required,enum=unit_type"`
 }
 
 // CreateMenuItemRequest represents the request for creating a new menu item
@@ -19,9 +21,9 @@ type CreateMenuItemRequest struct {
 	Price                float32              `json:"price" validate:"required,gt=0"`
 	Categories           []string             `json:"categories"`
 	Allergens            []string             `json:"allergens"`
-	Size                 string               `json:"size" validate:"required,oneof=small medium large"`
+	Size                 string               `json:"size" validate:"required,enum=item_size"`
 	CustomizationOptions json.RawMessage      `json:"customization_options"`
-	Ingredients          []MenuItemIngredient `json:"ingredients"`
+	Ingredients          []MenuItemIngredient `json:"ingredients" validate:"dive"`
 }
 
 type GetMenuResponse struct {
@@ -33,16 +35,17 @@ type GetMenuResponse struct {
 	Allergens            []string        `json:"allergens"`
 	Size                 string          `json:"size"`
 	CustomizationOptions json.RawMessage `json:"customization_options,omitempty"`
+	IsAvailable          bool            `json:"is_available"`
 	UpdatedAt            time.Time       `json:"updated_at"`
 }
 
 type UpdateMenuRequest struct {
-	Name                 *string          `json:"name"`
+	Name                 *string          `json:"name" validate:"omitempty,min=1"`
 	Description          *string          `json:"description"`
-	Price                *float32         `json:"price" validate:"required,gt=0"`
+	Price                *float32         `json:"price" validate:"gt=0"`
 	Categories           *[]string        `json:"categories"`
 	Allergens            *[]string        `json:"allergens"`
-	Size                 *string          `json:"size" validate:"required,oneof=small medium large"`
+	Size                 *string          `json:"size" validate:"enum=item_size"`
 	CustomizationOptions *json.RawMessage `json:"customization_options"`
 }
 
@@ -55,8 +58,29 @@ type MenuItemIngredientDTO struct {
 type GetPriceHistoryResponse struct {
 	ID           string    `json:"id"`
 	MenuItemID   string    `json:"menu_item_id"`
+	StoreID      *string   `json:"store_id,omitempty"` // nil for a change of the menu price
 	OldPrice     float32   `json:"old_price"`
 	NewPrice     float32   `json:"new_price"`
 	ChangedAt    time.Time `json:"changed_at"`
 	ChangeReason string    `json:"change_reason"`
 }
+
+// SetStoreMenuItemRequest overrides the price or availability of a menu item at the current store.
+// A field left out keeps its current value; reset_price goes back to the menu price.
+type SetStoreMenuItemRequest struct {
+	Price       *float32 `json:"price" validate:"gt=0"`
+	ResetPrice  bool     `json:"reset_price" validate:"excluded_with=Price"`
+	IsAvailable *bool    `json:"is_available"`
+}
+
+// ListMenuResponse is a page of the menu of a store
+type ListMenuResponse struct {
+	Items []GetMenuResponse `json:"items"`
+	list.Page
+}
+
+// ListPriceHistoryResponse is a page of the price changes of menu items
+type ListPriceHistoryResponse struct {
+	Items []GetPriceHistoryResponse `json:"items"`
+	list.Page
+}
@@ -2,12 +2,13 @@ package order
 
 // BatchOrderRequest represents multiple orders to be processed in a single batch
 type BatchOrderRequest struct {
-	Orders []CreateOrderRequest `json:"orders"`
+	Orders []CreateOrderRequest `json:"orders" validate:"required"`
 }
 
 // BatchOrderResult represents the processing result for a single order
 type BatchOrderResult struct {
 	OrderID      string  `json:"order_id,omitempty"`
+	OrderNumber  int     `json:"order_number,omitempty"`
 	CustomerName string  `json:"customer_name"`
 	Status       string  `json:"status"`           // "accepted" or "rejected"
 	Reason       string  `json:"reason,omitempty"` // reason for rejection if status is "rejected"
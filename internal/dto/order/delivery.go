@@ -0,0 +1,29 @@
+package order
+
+import "time"
+
+// DeliveryAddress is where a delivery order is taken. Coordinates are optional and let the
+// address match zones defined by a polygon.
+type DeliveryAddress struct {
+	Street    string   `json:"street" validate:"required"`
+	City      string   `json:"city" validate:"required"`
+	Postcode  string   `json:"postcode" validate:"required"`
+	Latitude  *float64 `json:"latitude,omitempty" validate:"gte=-90,lte=90"`
+	Longitude *float64 `json:"longitude,omitempty" validate:"gte=-180,lte=180"`
+	Notes     string   `json:"notes,omitempty"`
+}
+
+type DeliveryResponse struct {
+	ZoneID       string          `json:"zone_id"`
+	ZoneName     string          `json:"zone_name"`
+	Address      DeliveryAddress `json:"address"`
+	CourierID    *string         `json:"courier_id,omitempty"`
+	CourierName  string          `json:"courier_name,omitempty"`
+	AssignedAt   *time.Time      `json:"assigned_at,omitempty"`
+	DispatchedAt *time.Time      `json:"dispatched_at,omitempty"`
+	DeliveredAt  *time.Time      `json:"delivered_at,omitempty"`
+}
+
+type AssignCourierRequest struct {
+	CourierID string `json:"courier_id" validate:"required,uuid"`
+}
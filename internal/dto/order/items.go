@@ -0,0 +1,38 @@
+package order
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// AddOrderItemRequest adds a line item to a pending order
+type AddOrderItemRequest struct {
+	MenuItemID     string          `json:"menu_item_id" validate:"required,uuid"`
+	Quantity       int             `json:"quantity" validate:"gt=0"`
+	Customizations json.RawMessage `json:"customizations,omitempty"`
+	Reason         string          `json:"reason,omitempty"`
+}
+
+// UpdateOrderItemRequest changes a line item of a pending order; omitted fields are kept
+type UpdateOrderItemRequest struct {
+	Quantity       *int            `json:"quantity,omitempty" validate:"gt=0"`
+	Customizations json.RawMessage `json:"customizations,omitempty"`
+	Reason         string          `json:"reason,omitempty"`
+}
+
+// RemoveOrderItemRequest is the optional body of a line item removal
+type RemoveOrderItemRequest struct {
+	Reason string `json:"reason,omitempty"`
+}
+
+type OrderAuditEntryResponse struct {
+	AuditID     string          `json:"audit_id"`
+	OrderItemID string          `json:"order_item_id,omitempty"`
+	Action      string          `json:"action"`
+	OldValue    json.RawMessage `json:"old_value,omitempty"`
+	NewValue    json.RawMessage `json:"new_value,omitempty"`
+	TotalBefore float64         `json:"total_before"`
+	TotalAfter  float64         `json:"total_after"`
+	Reason      string          `json:"reason,omitempty"`
+	CreatedAt   time.Time       `json:"created_at"`
+}
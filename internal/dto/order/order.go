@@ -3,22 +3,45 @@ package order
 import (
 	"encoding/json"
 	"time"
+
+	"frappuccino/internal/dto/list"
 )
 
 type CreateOrderItem struct {
-	MenuItemID     string          `json:"menu_item_id"`
-	Quantity       int             `json:"quantity"`
+	MenuItemID     string          `json:"menu_item_id" validate:"required,uuid"`
+	Quantity       int             `json:"quantity" validate:"gt=0"`
 	Customizations json.RawMessage `json:"customizations,omitempty"`
 }
 
 type CreateOrderRequest struct {
-	CustomerName        string            `json:"customer_name"`
+	CustomerName        string            `json:"customer_name" validate:"required"`
 	SpecialInstructions json.RawMessage   `json:"special_instructions,omitempty"`
-	Items               []CreateOrderItem `json:"items"`
+	Items               []CreateOrderItem `json:"items" validate:"required,dive"`
+	PickupAt            *time.Time        `json:"pickup_at,omitempty"`                                                  // makes the order a scheduled pre-order
+	OrderType           string            `json:"order_type,omitempty" validate:"omitempty,enum=order_type"`            // takeaway (default), dine_in or delivery
+	TableID             *string           `json:"table_id,omitempty" validate:"required_if=OrderType dine_in,uuid"`     // required for dine_in orders
+	Guests              int               `json:"guests,omitempty" validate:"gte=0"`                                    // party size for dine_in orders
+	DeliveryAddress     *DeliveryAddress  `json:"delivery_address,omitempty" validate:"required_if=OrderType delivery"` // required for delivery orders
+}
+
+// CreateOrderResponse is returned when an order is placed
+type CreateOrderResponse struct {
+	OrderID              string     `json:"order_id"`
+	OrderNumber          int        `json:"order_number"`  // called out at the counter
+	BusinessDate         string     `json:"business_date"` // YYYY-MM-DD; order numbers restart every business day
+	Status               string     `json:"status"`
+	PickupAt             *time.Time `json:"pickup_at,omitempty"`
+	Subtotal             float64    `json:"subtotal"`
+	DeliveryFee          float64    `json:"delivery_fee,omitempty"`
+	TotalAmount          float64    `json:"total_amount"`
+	EstimatedReadyAt     *time.Time `json:"estimated_ready_at,omitempty"`
+	EstimatedWaitSeconds int        `json:"estimated_wait_seconds,omitempty"`
 }
 
 type GetOrderItemResponse struct {
+	OrderItemID    string          `json:"order_item_id"`
 	MenuItemID     string          `json:"menu_item_id"`
+	Name           string          `json:"name"`
 	Quantity       int             `json:"quantity"`
 	PriceAtTime    float64         `json:"price_at_time"`
 	Customizations json.RawMessage `json:"customizations,omitempty"`
@@ -26,19 +49,29 @@ type GetOrderItemResponse struct {
 
 type GetOrderResponse struct {
 	OrderID             string                 `json:"order_id"`
+	StoreID             string                 `json:"store_id"`
+	OrderNumber         int                    `json:"order_number"`
+	BusinessDate        string                 `json:"business_date"`
 	CustomerName        string                 `json:"customer_name"`
 	SpecialInstructions json.RawMessage        `json:"special_instructions,omitempty"` // JSONB
-	TotalAmount         float64                `json:"total_amount"`
+	Subtotal            float64                `json:"subtotal"`
+	DeliveryFee         float64                `json:"delivery_fee,omitempty"`
+	TotalAmount         float64                `json:"total_amount"` // subtotal plus delivery fee
 	Status              string                 `json:"status"`
+	OrderType           string                 `json:"order_type"`
+	TableID             *string                `json:"table_id,omitempty"`
+	Delivery            *DeliveryResponse      `json:"delivery,omitempty"`
+	PickupAt            *time.Time             `json:"pickup_at,omitempty"`
 	CreatedAt           time.Time              `json:"created_at"`
 	UpdatedAt           time.Time              `json:"updated_at"`
+	EstimatedReadyAt    *time.Time             `json:"estimated_ready_at,omitempty"` // only while pending or preparing
 	Items               []GetOrderItemResponse `json:"items"`
 }
 
 type UpdateOrderRequest struct {
-	CustomerName        *string         `json:"customer_name,omitempty"`
+	CustomerName        *string         `json:"customer_name,omitempty" validate:"min=1"`
 	SpecialInstructions json.RawMessage `json:"special_instructions,omitempty"`
-	Status              *string         `json:"status,omitempty"`
+	Status              *string         `json:"status,omitempty" validate:"enum=order_status"`
 	ChangeReason        *string         `json:"change_reason,omitempty"`
 }
 type OrderStatusHistoryResponse struct {
@@ -54,3 +87,15 @@ type OrderStatusHistoryResponse struct {
 type CloseOrderRequest struct {
 	Reason string `json:"reason,omitempty"`
 }
+
+// ListOrdersResponse is a page of the orders of a store
+type ListOrdersResponse struct {
+	Items []GetOrderResponse `json:"items"`
+	list.Page
+}
+
+// ListOrderStatusHistoryResponse is a page of the status changes of orders
+type ListOrderStatusHistoryResponse struct {
+	Items []OrderStatusHistoryResponse `json:"items"`
+	list.Page
+}
@@ -0,0 +1,32 @@
+package order
+
+// SplitOrderRequest splits an order either by line items or evenly into a number of parts
+type SplitOrderRequest struct {
+	Mode   string       `json:"mode" validate:"required,oneof=items even"`               // "items" or "even"
+	Groups []SplitGroup `json:"groups,omitempty" validate:"required_if=Mode items,dive"` // used by "items"
+	Parts  int          `json:"parts,omitempty" validate:"required_if=Mode even,gte=0"`  // used by "even"
+	Reason string       `json:"reason,omitempty"`
+}
+
+// SplitGroup lists the line items that make up one of the new orders
+type SplitGroup struct {
+	CustomerName string           `json:"customer_name,omitempty"` // defaults to the original customer
+	Items        []SplitGroupItem `json:"items" validate:"required,dive"`
+}
+
+// SplitGroupItem takes some or all units of a line item; a zero quantity takes the whole line
+type SplitGroupItem struct {
+	OrderItemID string `json:"order_item_id" validate:"required,uuid"`
+	Quantity    int    `json:"quantity,omitempty" validate:"gte=0"`
+}
+
+type SplitOrderResponse struct {
+	SourceOrderID string             `json:"source_order_id"`
+	Orders        []GetOrderResponse `json:"orders"`
+}
+
+// MergeOrdersRequest merges orders of the same customer into a new order
+type MergeOrdersRequest struct {
+	OrderIDs []string `json:"order_ids" validate:"min=2,dive,uuid"`
+	Reason   string   `json:"reason,omitempty"`
+}
@@ -0,0 +1,37 @@
+package printing
+
+import "time"
+
+type CreatePrinterRequest struct {
+	Name      string  `json:"name" validate:"required"`
+	Address   string  `json:"address" validate:"required"` // host:port of a network ESC/POS printer, usually port 9100
+	StationID *string `json:"station_id,omitempty" validate:"uuid"`
+	IsActive  *bool   `json:"is_active,omitempty"` // defaults to true
+}
+
+type GetPrinterResponse struct {
+	PrinterID string    `json:"printer_id"`
+	StoreID   string    `json:"store_id"`
+	Name      string    `json:"name"`
+	Address   string    `json:"address"`
+	StationID *string   `json:"station_id,omitempty"`
+	IsActive  bool      `json:"is_active"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type PrintJobResponse struct {
+	JobID         string     `json:"job_id"`
+	PrinterID     string     `json:"printer_id"`
+	StoreID       string     `json:"store_id"`
+	PrinterName   string     `json:"printer_name"`
+	OrderID       string     `json:"order_id"`
+	StationID     *string    `json:"station_id,omitempty"`
+	StationName   string     `json:"station_name,omitempty"`
+	Status        string     `json:"status"`
+	Attempts      int        `json:"attempts"`
+	LastError     string     `json:"last_error,omitempty"`
+	ReprintOf     *string    `json:"reprint_of,omitempty"`
+	NextAttemptAt *time.Time `json:"next_attempt_at,omitempty"` // only while the job is pending
+	CreatedAt     time.Time  `json:"created_at"`
+	PrintedAt     *time.Time `json:"printed_at,omitempty"`
+}
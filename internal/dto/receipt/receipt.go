@@ -0,0 +1,16 @@
+package receipt
+
+// Receipt is a rendered receipt ready to be sent to a browser, a terminal or a thermal printer
+type Receipt struct {
+	Format      string
+	ContentType string
+	Body        []byte
+}
+
+// KitchenTicketRequest selects what goes on a kitchen ticket
+type KitchenTicketRequest struct {
+	OrderID      string
+	Station      string   // printed in the ticket header; empty for whole-order tickets
+	OrderItemIDs []string // items to print; all items of the order when empty
+	Reprint      bool
+}
@@ -6,17 +6,30 @@ import "time"
 type TotalSalesRequest struct {
 	StartDate *time.Time `json:"start_date,omitempty"`
 	EndDate   *time.Time `json:"end_date,omitempty"`
-	Status    string     `json:"status,omitempty"` // Filter by order status (e.g., "delivered")
+	Status    string     `json:"status,omitempty" validate:"omitempty,enum=order_status"` // Filter by order status (e.g., "delivered")
 }
 
 // TotalSalesResponse represents the response for total sales report
 type TotalSalesResponse struct {
-	TotalSales       float64   `json:"total_sales"`
-	OrderCount       int       `json:"order_count"`
-	AverageOrderSize float64   `json:"average_order_size"`
-	StartDate        time.Time `json:"start_date,omitempty"`
-	EndDate          time.Time `json:"end_date,omitempty"`
-	Status           string    `json:"status,omitempty"`
+	TotalSales       float64      `json:"total_sales"`
+	ItemSales        float64      `json:"item_sales"`    // total sales without delivery fees
+	DeliveryFees     float64      `json:"delivery_fees"` // included in total sales
+	OrderCount       int          `json:"order_count"`
+	AverageOrderSize float64      `json:"average_order_size"`
+	StartDate        time.Time    `json:"start_date,omitempty"`
+	EndDate          time.Time    `json:"end_date,omitempty"`
+	Status           string       `json:"status,omitempty"`
+	StoreID          string       `json:"store_id,omitempty"` // empty when the report covers every store
+	ByStore          []StoreSales `json:"by_store,omitempty"` // set when the report covers every store
+}
+
+// StoreSales is the part of the total sales made at one store
+type StoreSales struct {
+	StoreID      string  `json:"store_id"`
+	StoreName    string  `json:"store_name"`
+	TotalSales   float64 `json:"total_sales"`
+	DeliveryFees float64 `json:"delivery_fees"`
+	OrderCount   int     `json:"order_count"`
 }
 
 // PopularItemsRequest represents the request parameters for popular items report
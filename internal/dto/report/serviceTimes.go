@@ -0,0 +1,71 @@
+package report
+
+import "time"
+
+// ServiceTimesRequest represents the parameters for the service times report
+type ServiceTimesRequest struct {
+	StartDate *time.Time
+	EndDate   *time.Time
+	SLA       time.Duration // Overrides the configured SLA when set
+}
+
+// DurationStats summarizes a set of durations in seconds
+type DurationStats struct {
+	Count          int     `json:"count"`
+	AverageSeconds float64 `json:"average_seconds"`
+	P50Seconds     float64 `json:"p50_seconds"`
+	P90Seconds     float64 `json:"p90_seconds"`
+	P99Seconds     float64 `json:"p99_seconds"`
+}
+
+// TransitionTimes contains the time spent in each kitchen state
+type TransitionTimes struct {
+	PendingToPreparing DurationStats `json:"pending_to_preparing"`
+	PreparingToReady   DurationStats `json:"preparing_to_ready"`
+	ReadyToDelivered   DurationStats `json:"ready_to_delivered"`
+}
+
+// HourServiceTimes groups transition times by the hour of day the order was placed
+type HourServiceTimes struct {
+	Hour int `json:"hour"`
+	TransitionTimes
+}
+
+// MenuItemServiceTimes groups transition times of the orders containing a menu item
+type MenuItemServiceTimes struct {
+	MenuItemID string `json:"menu_item_id"`
+	Name       string `json:"name"`
+	TransitionTimes
+}
+
+// StationServiceTimes describes how long tickets wait at and are worked on by a station
+type StationServiceTimes struct {
+	StationID string        `json:"station_id"`
+	Name      string        `json:"name"`
+	QueueTime DurationStats `json:"queue_time"` // ticket created -> work started
+	PrepTime  DurationStats `json:"prep_time"`  // work started -> ticket done
+}
+
+// SLABreach describes an order whose time to ready exceeded the SLA
+type SLABreach struct {
+	OrderID           string     `json:"order_id"`
+	CustomerName      string     `json:"customer_name"`
+	Status            string     `json:"status"`
+	CreatedAt         time.Time  `json:"created_at"`
+	ReadyAt           *time.Time `json:"ready_at,omitempty"` // empty while the order is still being prepared
+	ElapsedSeconds    float64    `json:"elapsed_seconds"`
+	ExceededBySeconds float64    `json:"exceeded_by_seconds"`
+}
+
+// ServiceTimesResponse represents the response for the service times report
+type ServiceTimesResponse struct {
+	StartDate   time.Time              `json:"start_date,omitempty"`
+	EndDate     time.Time              `json:"end_date,omitempty"`
+	SLASeconds  float64                `json:"sla_seconds"`
+	Overall     TransitionTimes        `json:"overall"`
+	ByHour      []HourServiceTimes     `json:"by_hour"`
+	ByMenuItem  []MenuItemServiceTimes `json:"by_menu_item"`
+	ByStation   []StationServiceTimes  `json:"by_station"`
+	BreachCount int                    `json:"breach_count"`
+	SLABreaches []SLABreach            `json:"sla_breaches"`
+}
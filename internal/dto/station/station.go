@@ -0,0 +1,56 @@
+package station
+
+import (
+	"encoding/json"
+	"time"
+)
+
+type CreateStationRequest struct {
+	Name        string `json:"name" validate:"required"`
+	Description string `json:"description"`
+	IsDefault   bool   `json:"is_default"`
+}
+
+// CreateStationRouteRequest must carry exactly one of MenuItemID or Category
+type CreateStationRouteRequest struct {
+	MenuItemID string `json:"menu_item_id,omitempty" validate:"required_without=Category,excluded_with=Category,omitempty,uuid"`
+	Category   string `json:"category,omitempty"`
+}
+
+type GetStationRouteResponse struct {
+	StationRouteID string `json:"station_route_id"`
+	MenuItemID     string `json:"menu_item_id,omitempty"`
+	Category       string `json:"category,omitempty"`
+}
+
+type GetStationResponse struct {
+	StationID   string                    `json:"station_id"`
+	Name        string                    `json:"name"`
+	Description string                    `json:"description,omitempty"`
+	IsDefault   bool                      `json:"is_default"`
+	CreatedAt   time.Time                 `json:"created_at"`
+	Routes      []GetStationRouteResponse `json:"routes"`
+}
+
+type TicketItemResponse struct {
+	OrderItemID    string          `json:"order_item_id"`
+	MenuItemID     string          `json:"menu_item_id"`
+	Name           string          `json:"name"`
+	Quantity       int             `json:"quantity"`
+	Customizations json.RawMessage `json:"customizations,omitempty"`
+}
+
+type GetTicketResponse struct {
+	TicketID    string               `json:"ticket_id"`
+	OrderID     string               `json:"order_id"`
+	StationID   string               `json:"station_id"`
+	Status      string               `json:"status"` // "pending", "in_progress" or "done"
+	CreatedAt   time.Time            `json:"created_at"`
+	StartedAt   *time.Time           `json:"started_at,omitempty"`
+	CompletedAt *time.Time           `json:"completed_at,omitempty"`
+	Items       []TicketItemResponse `json:"items"`
+}
+
+type UpdateTicketStatusRequest struct {
+	Status string `json:"status" validate:"required,enum=ticket_status"`
+}
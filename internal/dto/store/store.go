@@ -0,0 +1,20 @@
+package store
+
+import "time"
+
+type CreateStoreRequest struct {
+	Code    string `json:"code" validate:"required"` // short name staff can send in the X-Store-ID header instead of the ID
+	Name    string `json:"name" validate:"required"`
+	Address string `json:"address,omitempty"`
+	Phone   string `json:"phone,omitempty"`
+}
+
+type GetStoreResponse struct {
+	StoreID   string    `json:"store_id"`
+	Code      string    `json:"code"`
+	Name      string    `json:"name"`
+	Address   string    `json:"address,omitempty"`
+	Phone     string    `json:"phone,omitempty"`
+	IsActive  bool      `json:"is_active"`
+	CreatedAt time.Time `json:"created_at"`
+}
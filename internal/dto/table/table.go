@@ -0,0 +1,75 @@
+package table
+
+import (
+	"encoding/json"
+	"time"
+
+	orderdto "frappuccino/internal/dto/order"
+)
+
+type CreateTableRequest struct {
+	TableNumber int    `json:"table_number" validate:"gt=0"`
+	Capacity    int    `json:"capacity" validate:"gt=0"`
+	Area        string `json:"area,omitempty"` // defaults to "main"
+}
+
+// TableSessionResponse describes the open tab of an occupied table
+type TableSessionResponse struct {
+	SessionID     string    `json:"session_id"`
+	OrderID       string    `json:"order_id,omitempty"`
+	OrderStatus   string    `json:"order_status,omitempty"`
+	Guests        int       `json:"guests"`
+	OpenedAt      time.Time `json:"opened_at"`
+	SeatedMinutes float64   `json:"seated_minutes"`
+	RunningTotal  float64   `json:"running_total"`
+	ItemCount     int       `json:"item_count"`
+}
+
+type GetTableResponse struct {
+	TableID     string                `json:"table_id"`
+	StoreID     string                `json:"store_id"`
+	TableNumber int                   `json:"table_number"`
+	Capacity    int                   `json:"capacity"`
+	Area        string                `json:"area"`
+	Occupied    bool                  `json:"occupied"`
+	Session     *TableSessionResponse `json:"session,omitempty"`
+}
+
+// OpenTabRequest seats a party and starts its tab with the first round of items
+type OpenTabRequest struct {
+	CustomerName        string                     `json:"customer_name"`
+	Guests              int                        `json:"guests" validate:"gt=0"`
+	SpecialInstructions json.RawMessage            `json:"special_instructions,omitempty"`
+	Items               []orderdto.CreateOrderItem `json:"items" validate:"dive"`
+}
+
+type CloseTabRequest struct {
+	Reason string `json:"reason,omitempty"`
+}
+
+// TableTurnoverRequest holds the optional date range of the turnover report
+type TableTurnoverRequest struct {
+	StartDate *time.Time
+	EndDate   *time.Time
+}
+
+// TableTurnoverStats summarises the closed sessions of one table
+type TableTurnoverStats struct {
+	TableID              string  `json:"table_id"`
+	TableNumber          int     `json:"table_number"`
+	Area                 string  `json:"area"`
+	Sessions             int     `json:"sessions"`
+	Guests               int     `json:"guests"`
+	AverageSeatedMinutes float64 `json:"average_seated_minutes"`
+	Revenue              float64 `json:"revenue"`
+	AverageSpend         float64 `json:"average_spend"`
+}
+
+type TableTurnoverResponse struct {
+	StartDate            *time.Time           `json:"start_date,omitempty"`
+	EndDate              *time.Time           `json:"end_date,omitempty"`
+	Sessions             int                  `json:"sessions"`
+	AverageSeatedMinutes float64              `json:"average_seated_minutes"`
+	Revenue              float64              `json:"revenue"`
+	ByTable              []TableTurnoverStats `json:"by_table"`
+}
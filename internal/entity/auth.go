@@ -0,0 +1,60 @@
+package entity
+
+import "time"
+
+type User struct {
+	UserID       string    `json:"user_id"`
+	Username     string    `json:"username"`
+	PasswordHash string    `json:"-"`
+	Role         string    `json:"role"`
+	StoreID      *string   `json:"store_id,omitempty"` // home store; nil for staff working across stores
+	IsActive     bool      `json:"is_active"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+// RefreshToken records a refresh token by its jti so it can be used once and revoked
+type RefreshToken struct {
+	TokenID    string     `json:"token_id"`
+	UserID     string     `json:"user_id"`
+	ExpiresAt  time.Time  `json:"expires_at"`
+	CreatedAt  time.Time  `json:"created_at"`
+	RevokedAt  *time.Time `json:"revoked_at,omitempty"`
+	ReplacedBy *string    `json:"replaced_by,omitempty"`
+}
+
+type APIKey struct {
+	KeyID      string     `json:"key_id"`
+	Name       string     `json:"name"`
+	Prefix     string     `json:"prefix"`
+	KeyHash    string     `json:"-"`
+	Role       string     `json:"role"`
+	StoreID    *string    `json:"store_id,omitempty"`
+	CreatedBy  *string    `json:"created_by,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
+	LastUsedAt *time.Time `json:"last_used_at,omitempty"`
+	RevokedAt  *time.Time `json:"revoked_at,omitempty"`
+}
+
+// Principal is the authenticated caller of a request: a staff user or an integration's API key
+type Principal struct {
+	Kind      string    `json:"kind"` // "user" or "api_key"
+	ID        string    `json:"id"`   // user_id or key_id
+	Name      string    `json:"name"` // username or key name
+	Role      string    `json:"role"`
+	StoreID   string    `json:"store_id,omitempty"` // home store; empty if the caller may work in any store
+	TokenID   string    `json:"-"`                  // jti of the access token a user authenticated with
+	ExpiresAt time.Time `json:"-"`                  // expiry of that access token
+}
+
+// AccessDenial records a request refused because the caller's role lacked a permission
+type AccessDenial struct {
+	DenialID      string    `json:"denial_id"`
+	PrincipalKind string    `json:"principal_kind"`
+	PrincipalID   string    `json:"principal_id"`
+	PrincipalName string    `json:"principal_name"`
+	Role          string    `json:"role"`
+	Permission    string    `json:"permission"`
+	Method        string    `json:"method"`
+	Path          string    `json:"path"`
+	CreatedAt     time.Time `json:"created_at"`
+}
@@ -0,0 +1,51 @@
+package entity
+
+import "time"
+
+// GeoPoint is a latitude/longitude pair
+type GeoPoint struct {
+	Latitude  float64 `json:"latitude"`
+	Longitude float64 `json:"longitude"`
+}
+
+// DeliveryZone is an area the shop delivers to, matched by postcode or by polygon
+type DeliveryZone struct {
+	ZoneID       string     `json:"zone_id"`
+	Name         string     `json:"name"`
+	Postcodes    []string   `json:"postcodes"`
+	Polygon      []GeoPoint `json:"polygon,omitempty"`
+	Fee          float64    `json:"fee"`
+	MinimumOrder float64    `json:"minimum_order"`
+	IsActive     bool       `json:"is_active"`
+	CreatedAt    time.Time  `json:"created_at"`
+}
+
+type Courier struct {
+	CourierID string    `json:"courier_id"`
+	Name      string    `json:"name"`
+	Phone     string    `json:"phone,omitempty"`
+	IsActive  bool      `json:"is_active"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// DeliveryAddress is where a delivery order is taken
+type DeliveryAddress struct {
+	Street   string    `json:"street"`
+	City     string    `json:"city"`
+	Postcode string    `json:"postcode"`
+	Location *GeoPoint `json:"location,omitempty"`
+	Notes    string    `json:"notes,omitempty"`
+}
+
+// Delivery holds the address, zone and courier of a delivery order
+type Delivery struct {
+	OrderID      string          `json:"order_id"`
+	ZoneID       string          `json:"zone_id"`
+	ZoneName     string          `json:"zone_name"`
+	Address      DeliveryAddress `json:"address"`
+	CourierID    *string         `json:"courier_id,omitempty"`
+	CourierName  string          `json:"courier_name,omitempty"`
+	AssignedAt   *time.Time      `json:"assigned_at,omitempty"`
+	DispatchedAt *time.Time      `json:"dispatched_at,omitempty"`
+	DeliveredAt  *time.Time      `json:"delivered_at,omitempty"`
+}
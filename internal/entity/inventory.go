@@ -4,6 +4,7 @@ import "time"
 
 type Inventory struct {
 	IngredientID string
+	StoreID      string
 	Name         string
 	Quantity     float32
 	Unit         string
@@ -18,5 +19,6 @@ type InventoryTransaction struct {
 	QuantityChange  float32
 	TransactionType string
 	Reason          string
+	TransferID      *string // set on both sides of a transfer between stores
 	CreatedAt       time.Time
 }
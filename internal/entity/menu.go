@@ -9,7 +9,8 @@ type MenuItem struct {
 	MenuItemID           string          `json:"menu_item_id"`
 	Name                 string          `json:"name"`
 	Description          string          `json:"description,omitempty"`
-	Price                float32         `json:"price"`
+	Price                float32         `json:"price"` // the store's price when read for a store
+	IsAvailable          bool            `json:"is_available"`
 	Categories           []string        `json:"categories"`
 	Allergens            []string        `json:"allergens"`
 	Size                 string          `json:"size"`
@@ -21,6 +22,7 @@ type MenuItemIngredient struct {
 	ID           string  `json:"id"`
 	MenuItemID   string  `json:"menu_item_id"`
 	IngredientID string  `json:"ingredient_id"`
+	Name         string  `json:"name,omitempty"` // ingredient name, filled in when a recipe is read
 	Quantity     float64 `json:"quantity"`
 	Unit         string  `json:"unit"`
 }
@@ -28,6 +30,7 @@ type MenuItemIngredient struct {
 type PriceHistory struct {
 	ID           string    `json:"id"`
 	MenuItemID   string    `json:"menu_item_id"`
+	StoreID      *string   `json:"store_id,omitempty"` // set for a store's own price
 	OldPrice     float32   `json:"old_price"`
 	NewPrice     float32   `json:"new_price"`
 	ChangedAt    time.Time `json:"changed_at"`
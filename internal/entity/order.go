@@ -7,10 +7,17 @@ import (
 
 type Order struct {
 	OrderID             string          `json:"order_id"`
+	StoreID             string          `json:"store_id"`
+	OrderNumber         int             `json:"order_number"`  // sequential within the business day
+	BusinessDate        time.Time       `json:"business_date"` // date only
 	CustomerName        string          `json:"customer_name"`
 	SpecialInstructions json.RawMessage `json:"special_instructions,omitempty"` // JSONB
 	TotalAmount         float64         `json:"total_amount"`
 	Status              string          `json:"status"`
+	PickupAt            *time.Time      `json:"pickup_at,omitempty"` // set for scheduled pre-orders
+	OrderType           string          `json:"order_type"`
+	TableID             *string         `json:"table_id,omitempty"` // set for dine-in orders
+	DeliveryFee         float64         `json:"delivery_fee"`       // part of TotalAmount
 	CreatedAt           time.Time       `json:"created_at"`
 	UpdatedAt           time.Time       `json:"updated_at"`
 }
@@ -19,6 +26,7 @@ type OrderItem struct {
 	OrderItemID    string          `json:"order_item_id"`
 	OrderID        string          `json:"order_id"`
 	MenuItemID     string          `json:"menu_item_id"`
+	Name           string          `json:"name,omitempty"` // menu item name, filled in when items are read
 	Quantity       int             `json:"quantity"`
 	PriceAtTime    float64         `json:"price_at_time"`
 	Customizations json.RawMessage `json:"customizations,omitempty"` // JSONB
@@ -33,3 +41,53 @@ type OrderStatusHistory struct {
 	ChangedAt     time.Time `json:"changed_at"`
 	ChangeReason  string    `json:"change_reason"`
 }
+
+// OrderStateTiming holds the first moment an order entered each kitchen state
+type OrderStateTiming struct {
+	OrderID      string
+	CustomerName string
+	Status       string
+	CreatedAt    time.Time
+	PreparingAt  *time.Time
+	ReadyAt      *time.Time
+	DeliveredAt  *time.Time
+}
+
+// OrderMenuItem links an order to one of the menu items it contains
+type OrderMenuItem struct {
+	OrderID    string
+	MenuItemID string
+	Name       string
+}
+
+// StationTicketTiming holds the lifecycle timestamps of a single station ticket
+type StationTicketTiming struct {
+	StationID   string
+	StationName string
+	CreatedAt   time.Time
+	StartedAt   *time.Time
+	CompletedAt *time.Time
+}
+
+// QueuedOrder is an order waiting for or being worked on in the kitchen
+type QueuedOrder struct {
+	OrderID     string
+	Status      string
+	CreatedAt   time.Time
+	PreparingAt *time.Time
+	MenuItemIDs []string
+}
+
+// OrderAuditEntry records one change to the line items of an order
+type OrderAuditEntry struct {
+	AuditID     string          `json:"audit_id"`
+	OrderID     string          `json:"order_id"`
+	OrderItemID string          `json:"order_item_id"`
+	Action      string          `json:"action"`
+	OldValue    json.RawMessage `json:"old_value,omitempty"`
+	NewValue    json.RawMessage `json:"new_value,omitempty"`
+	TotalBefore float64         `json:"total_before"`
+	TotalAfter  float64         `json:"total_after"`
+	Reason      string          `json:"reason"`
+	CreatedAt   time.Time       `json:"created_at"`
+}
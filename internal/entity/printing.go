@@ -0,0 +1,34 @@
+package entity
+
+import "time"
+
+// Printer is a network ESC/POS printer. Without a station it prints tickets for whole orders.
+type Printer struct {
+	PrinterID string    `json:"printer_id"`
+	StoreID   string    `json:"store_id"`
+	Name      string    `json:"name"`
+	Address   string    `json:"address"` // host:port
+	StationID *string   `json:"station_id,omitempty"`
+	IsActive  bool      `json:"is_active"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// PrintJob is a kitchen ticket of an order queued for a printer
+type PrintJob struct {
+	JobID          string     `json:"job_id"`
+	PrinterID      string     `json:"printer_id"`
+	StoreID        string     `json:"store_id"`
+	PrinterName    string     `json:"printer_name"`
+	PrinterAddress string     `json:"printer_address"`
+	OrderID        string     `json:"order_id"`
+	StationID      *string    `json:"station_id,omitempty"`
+	StationName    string     `json:"station_name,omitempty"`
+	Status         string     `json:"status"`
+	Attempts       int        `json:"attempts"`
+	LastError      string     `json:"last_error,omitempty"`
+	ReprintOf      *string    `json:"reprint_of,omitempty"`
+	NextAttemptAt  time.Time  `json:"next_attempt_at"`
+	CreatedAt      time.Time  `json:"created_at"`
+	UpdatedAt      time.Time  `json:"updated_at"`
+	PrintedAt      *time.Time `json:"printed_at,omitempty"`
+}
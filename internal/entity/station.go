@@ -0,0 +1,42 @@
+package entity
+
+import (
+	"encoding/json"
+	"time"
+)
+
+type Station struct {
+	StationID   string    `json:"station_id"`
+	Name        string    `json:"name"`
+	Description string    `json:"description,omitempty"`
+	IsDefault   bool      `json:"is_default"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+// StationRoute maps either a menu item or a category to a station
+type StationRoute struct {
+	StationRouteID string `json:"station_route_id"`
+	StationID      string `json:"station_id"`
+	MenuItemID     string `json:"menu_item_id,omitempty"`
+	Category       string `json:"category,omitempty"`
+}
+
+type StationTicket struct {
+	TicketID    string              `json:"ticket_id"`
+	OrderID     string              `json:"order_id"`
+	StationID   string              `json:"station_id"`
+	Status      string              `json:"status"`
+	CreatedAt   time.Time           `json:"created_at"`
+	StartedAt   *time.Time          `json:"started_at,omitempty"`
+	CompletedAt *time.Time          `json:"completed_at,omitempty"`
+	UpdatedAt   time.Time           `json:"updated_at"`
+	Items       []StationTicketItem `json:"items"`
+}
+
+type StationTicketItem struct {
+	OrderItemID    string          `json:"order_item_id"`
+	MenuItemID     string          `json:"menu_item_id"`
+	Name           string          `json:"name"`
+	Quantity       int             `json:"quantity"`
+	Customizations json.RawMessage `json:"customizations,omitempty"` // JSONB
+}
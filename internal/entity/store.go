@@ -0,0 +1,35 @@
+package entity
+
+import "time"
+
+// Store is one location of the business
+type Store struct {
+	StoreID   string    `json:"store_id"`
+	Code      string    `json:"code"`
+	Name      string    `json:"name"`
+	Address   string    `json:"address"`
+	Phone     string    `json:"phone"`
+	IsActive  bool      `json:"is_active"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// StoreMenuItem overrides the price or availability of a menu item at one store
+type StoreMenuItem struct {
+	StoreID     string    `json:"store_id"`
+	MenuItemID  string    `json:"menu_item_id"`
+	Price       *float32  `json:"price,omitempty"` // nil keeps the menu price
+	IsAvailable bool      `json:"is_available"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// StockTransfer moves stock of an ingredient from one store to the row of the same name at another
+type StockTransfer struct {
+	TransferID       string
+	FromStoreID      string
+	ToStoreID        string
+	FromIngredientID string
+	ToIngredientID   string
+	Quantity         float32
+	Reason           string
+	CreatedAt        time.Time
+}
@@ -0,0 +1,42 @@
+package entity
+
+import "time"
+
+type DiningTable struct {
+	TableID     string    `json:"table_id"`
+	StoreID     string    `json:"store_id"`
+	TableNumber int       `json:"table_number"`
+	Capacity    int       `json:"capacity"`
+	Area        string    `json:"area"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+// TableSession is one seating of a table, from opening its tab until closing it
+type TableSession struct {
+	SessionID string     `json:"session_id"`
+	TableID   string     `json:"table_id"`
+	OrderID   *string    `json:"order_id,omitempty"`
+	Guests    int        `json:"guests"`
+	OpenedAt  time.Time  `json:"opened_at"`
+	ClosedAt  *time.Time `json:"closed_at,omitempty"`
+}
+
+// TableOccupancy is a table together with its open session, if any
+type TableOccupancy struct {
+	DiningTable
+	Session      *TableSession
+	OrderStatus  string
+	RunningTotal float64
+	ItemCount    int
+}
+
+// TableSessionTiming holds a closed session and the amount spent during it
+type TableSessionTiming struct {
+	TableID     string
+	TableNumber int
+	Area        string
+	Guests      int
+	OpenedAt    time.Time
+	ClosedAt    time.Time
+	Total       float64
+}
@@ -0,0 +1,188 @@
+package metrics
+
+import (
+	"context"
+	"database/sql"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+
+	"frappuccino/internal/entity"
+)
+
+const namespace = "frappuccino"
+
+// Reasons an order is rejected, the values of the reason label of orders_rejected_total
+const (
+	RejectInvalid      = "invalid"
+	RejectInsufficient = "insufficient_stock"
+	RejectUnavailable  = "unavailable"
+	RejectError        = "error"
+)
+
+// stockTimeout bounds reading the stock levels during a scrape
+const stockTimeout = 5 * time.Second
+
+// stockReader lists the stock of every store for the stock gauges
+type stockReader interface {
+	GetInventory(ctx context.Context, storeID string) ([]entity.Inventory, error)
+}
+
+// Metrics holds the collectors of the application. They live in a registry of their own rather
+// than the global one, so that only what is registered here is exposed on /metrics.
+type Metrics struct {
+	registry *prometheus.Registry
+
+	requests *prometheus.CounterVec
+	duration *prometheus.HistogramVec
+
+	ordersCreated  *prometheus.CounterVec
+	ordersRejected *prometheus.CounterVec
+	batchSize      prometheus.Histogram
+	deductions     *prometheus.CounterVec
+	lowStock       *prometheus.CounterVec
+}
+
+func New() *Metrics {
+	m := &Metrics{
+		registry: prometheus.NewRegistry(),
+		requests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "http_requests_total",
+			Help:      "HTTP requests by method, route pattern and status code.",
+		}, []string{"method", "route", "status"}),
+		duration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "http_request_duration_seconds",
+			Help:      "Time to serve HTTP requests by method and route pattern.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"method", "route"}),
+		ordersCreated: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "orders_created_total",
+			Help:      "Orders created, by order type.",
+		}, []string{"order_type"}),
+		ordersRejected: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "orders_rejected_total",
+			Help:      "Orders refused, by reason: invalid, insufficient_stock, unavailable or error.",
+		}, []string{"reason"}),
+		batchSize: prometheus.NewHistogram(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "order_batch_size",
+			Help:      "Number of orders per batch request.",
+			Buckets:   []float64{1, 2, 5, 10, 20, 50, 100},
+		}),
+		deductions: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "inventory_deductions_total",
+			Help:      "Stock deductions, by what caused them: order, batch, scheduled, deduction or waste.",
+		}, []string{"source"}),
+		lowStock: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "inventory_low_stock_events_total",
+			Help:      "Times an ingredient fell to or below its reorder point.",
+		}, []string{"store_id", "ingredient"}),
+	}
+
+	m.registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		m.requests,
+		m.duration,
+		m.ordersCreated,
+		m.ordersRejected,
+		m.batchSize,
+		m.deductions,
+		m.lowStock,
+	)
+	return m
+}
+
+// RegisterDB exposes the connection pool statistics of sql.DB.Stats
+func (m *Metrics) RegisterDB(db *sql.DB, name string) {
+	m.registry.MustRegister(collectors.NewDBStatsCollector(db, name))
+}
+
+// RegisterStock exposes the current stock of each ingredient, read from the database at scrape time
+func (m *Metrics) RegisterStock(stock stockReader, logger *slog.Logger) {
+	m.registry.MustRegister(&stockCollector{
+		stock:  stock,
+		logger: logger,
+		desc: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "inventory", "stock_quantity"),
+			"Current stock of an ingredient in its unit.",
+			[]string{"store_id", "ingredient", "unit"}, nil,
+		),
+	})
+}
+
+// Handler serves the registry in the Prometheus text format
+func (m *Metrics) Handler() http.Handler {
+	return promhttp.HandlerFor(m.registry, promhttp.HandlerOpts{})
+}
+
+// ObserveRequest counts a served request; route is the pattern it matched, empty when none did
+func (m *Metrics) ObserveRequest(method, route string, status int, elapsed time.Duration) {
+	if route == "" {
+		route = "unmatched"
+	}
+	m.requests.WithLabelValues(method, route, strconv.Itoa(status)).Inc()
+	m.duration.WithLabelValues(method, route).Observe(elapsed.Seconds())
+}
+
+func (m *Metrics) OrderCreated(orderType string) {
+	if orderType == "" {
+		orderType = "takeaway"
+	}
+	m.ordersCreated.WithLabelValues(orderType).Inc()
+}
+
+func (m *Metrics) OrderRejected(reason string) {
+	m.ordersRejected.WithLabelValues(reason).Inc()
+}
+
+func (m *Metrics) BatchProcessed(size int) {
+	m.batchSize.Observe(float64(size))
+}
+
+func (m *Metrics) StockDeducted(source string) {
+	m.deductions.WithLabelValues(source).Inc()
+}
+
+func (m *Metrics) LowStock(storeID, ingredient string) {
+	m.lowStock.WithLabelValues(storeID, ingredient).Inc()
+}
+
+// stockCollector reads the stock levels on every scrape, so the gauges never go stale
+type stockCollector struct {
+	stock  stockReader
+	logger *slog.Logger
+	desc   *prometheus.Desc
+}
+
+func (c *stockCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.desc
+}
+
+func (c *stockCollector) Collect(ch chan<- prometheus.Metric) {
+	ctx, cancel := context.WithTimeout(context.Background(), stockTimeout)
+	defer cancel()
+
+	// An empty store ID reads the stock of every store
+	inventory, err := c.stock.GetInventory(ctx, "")
+	if err != nil {
+		c.logger.ErrorContext(ctx, "Error reading stock for metrics", "error", err)
+		ch <- prometheus.NewInvalidMetric(c.desc, err)
+		return
+	}
+	for _, item := range inventory {
+		ch <- prometheus.MustNewConstMetric(c.desc, prometheus.GaugeValue, float64(item.Quantity),
+			item.StoreID, item.Name, item.Unit)
+	}
+}
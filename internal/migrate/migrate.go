@@ -0,0 +1,382 @@
+package migrate
+
+import (
+	"context"
+	"database/sql"
+	"embed"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// lockKey identifies the advisory lock held while migrating, so that several instances starting
+// at once apply every migration exactly once
+const lockKey = 7_264_971_120_040
+
+var (
+	//go:embed migrations/*.sql
+	migrationFiles embed.FS
+
+	//go:embed seeds/*.sql
+	seedFiles embed.FS
+)
+
+var ErrNoDownScript = errors.New("migration has no down script")
+
+// Migration is one numbered schema change
+type Migration struct {
+	Version int
+	Name    string
+	Up      string
+	Down    string
+}
+
+// Status tells whether a migration has been applied
+type Status struct {
+	Version   int
+	Name      string
+	AppliedAt *time.Time
+}
+
+// Migrator keeps the database schema in step with the binary. Migrations are numbered SQL files
+// embedded in the binary, each with an up and a down script; seeds are numbered SQL files with
+// demo data that are only loaded when asked for.
+type Migrator struct {
+	db         *sql.DB
+	migrations []Migration
+	seeds      []Migration // only Up is set
+	logger     *slog.Logger
+}
+
+func New(db *sql.DB, logger *slog.Logger) (*Migrator, error) {
+	migrations, err := load(migrationFiles, "migrations")
+	if err != nil {
+		return nil, err
+	}
+	seeds, err := load(seedFiles, "seeds")
+	if err != nil {
+		return nil, err
+	}
+	return &Migrator{
+		db:         db,
+		migrations: migrations,
+		seeds:      seeds,
+		logger:     logger,
+	}, nil
+}
+
+// Up applies every migration that has not been applied yet and reports how many it applied
+func (m *Migrator) Up(ctx context.Context) (int, error) {
+	applied := 0
+	err := m.locked(ctx, func(conn *sql.Conn) error {
+		done, err := appliedVersions(ctx, conn, "schema_migrations")
+		if err != nil {
+			return err
+		}
+		for _, migration := range m.migrations {
+			if _, ok := done[migration.Version]; ok {
+				continue
+			}
+			m.logger.InfoContext(ctx, "Applying migration", "version", migration.Version, "name", migration.Name)
+			if err := apply(ctx, conn, migration.Up,
+				`INSERT INTO schema_migrations (version, name) VALUES ($1, $2)`, migration.Version, migration.Name); err != nil {
+				return fmt.Errorf("migration %04d %s: %w", migration.Version, migration.Name, err)
+			}
+			applied++
+		}
+		return nil
+	})
+	return applied, err
+}
+
+// Down reverts the given number of most recently applied migrations
+func (m *Migrator) Down(ctx context.Context, steps int) (int, error) {
+	reverted := 0
+	err := m.locked(ctx, func(conn *sql.Conn) error {
+		done, err := appliedVersions(ctx, conn, "schema_migrations")
+		if err != nil {
+			return err
+		}
+		for i := len(m.migrations) - 1; i >= 0 && reverted < steps; i-- {
+			migration := m.migrations[i]
+			if _, ok := done[migration.Version]; !ok {
+				continue
+			}
+			if migration.Down == "" {
+				return fmt.Errorf("migration %04d %s: %w", migration.Version, migration.Name, ErrNoDownScript)
+			}
+			m.logger.InfoContext(ctx, "Reverting migration", "version", migration.Version, "name", migration.Name)
+			if err := apply(ctx, conn, migration.Down,
+				`DELETE FROM schema_migrations WHERE version = $1`, migration.Version); err != nil {
+				return fmt.Errorf("revert migration %04d %s: %w", migration.Version, migration.Name, err)
+			}
+			reverted++
+			delete(done, migration.Version)
+		}
+
+		// Without any schema left the demo data is gone too, so it may be loaded again
+		if len(done) == 0 {
+			if _, err := conn.ExecContext(ctx, `DELETE FROM schema_seeds`); err != nil {
+				return fmt.Errorf("clear seed history: %w", err)
+			}
+		}
+		return nil
+	})
+	return reverted, err
+}
+
+// Status lists every embedded migration and when it was applied
+func (m *Migrator) Status(ctx context.Context) ([]Status, error) {
+	var statuses []Status
+	err := m.locked(ctx, func(conn *sql.Conn) error {
+		rows, err := conn.QueryContext(ctx, `SELECT version, applied_at FROM schema_migrations`)
+		if err != nil {
+			return fmt.Errorf("read schema_migrations: %w", err)
+		}
+		defer rows.Close()
+
+		appliedAt := make(map[int]time.Time)
+		for rows.Next() {
+			var version int
+			var at time.Time
+			if err := rows.Scan(&version, &at); err != nil {
+				return fmt.Errorf("scan schema_migrations: %w", err)
+			}
+			appliedAt[version] = at
+		}
+		if err := rows.Err(); err != nil {
+			return fmt.Errorf("iterate schema_migrations: %w", err)
+		}
+
+		for _, migration := range m.migrations {
+			s := Status{Version: migration.Version, Name: migration.Name}
+			if at, ok := appliedAt[migration.Version]; ok {
+				s.AppliedAt = &at
+			}
+			statuses = append(statuses, s)
+		}
+		return nil
+	})
+	return statuses, err
+}
+
+// Pending reports how many embedded migrations have not been applied yet. It only reads the
+// history, without taking the migration lock, so it is cheap enough for readiness probes and
+// does not wait for a migration running in another instance.
+func (m *Migrator) Pending(ctx context.Context) (int, error) {
+	var tracked bool
+	if err := m.db.QueryRowContext(ctx, `SELECT to_regclass('schema_migrations') IS NOT NULL`).Scan(&tracked); err != nil {
+		return 0, fmt.Errorf("look for schema_migrations: %w", err)
+	}
+	if !tracked {
+		return len(m.migrations), nil
+	}
+
+	done, err := appliedVersions(ctx, m.db, "schema_migrations")
+	if err != nil {
+		return 0, err
+	}
+	pending := 0
+	for _, migration := range m.migrations {
+		if _, ok := done[migration.Version]; !ok {
+			pending++
+		}
+	}
+	return pending, nil
+}
+
+// Seed loads the demo data seeds that have not been loaded yet; the schema must be up to date
+func (m *Migrator) Seed(ctx context.Context) (int, error) {
+	loaded := 0
+	err := m.locked(ctx, func(conn *sql.Conn) error {
+		done, err := appliedVersions(ctx, conn, "schema_seeds")
+		if err != nil {
+			return err
+		}
+		for _, seed := range m.seeds {
+			if _, ok := done[seed.Version]; ok {
+				continue
+			}
+			m.logger.InfoContext(ctx, "Loading seed", "version", seed.Version, "name", seed.Name)
+			if err := apply(ctx, conn, seed.Up,
+				`INSERT INTO schema_seeds (version, name) VALUES ($1, $2)`, seed.Version, seed.Name); err != nil {
+				return fmt.Errorf("seed %04d %s: %w", seed.Version, seed.Name, err)
+			}
+			loaded++
+		}
+		return nil
+	})
+	return loaded, err
+}
+
+// locked runs fn on one connection holding the migration lock, after making sure the tables
+// that track migrations and seeds exist
+func (m *Migrator) locked(ctx context.Context, fn func(conn *sql.Conn) error) error {
+	conn, err := m.db.Conn(ctx)
+	if err != nil {
+		return fmt.Errorf("get connection: %w", err)
+	}
+	defer conn.Close()
+
+	// Session level lock: it is held by this connection until unlocked, across the transactions
+	// of the individual migrations
+	if _, err := conn.ExecContext(ctx, `SELECT pg_advisory_lock($1)`, lockKey); err != nil {
+		return fmt.Errorf("acquire migration lock: %w", err)
+	}
+	defer func() {
+		if _, err := conn.ExecContext(context.WithoutCancel(ctx), `SELECT pg_advisory_unlock($1)`, lockKey); err != nil {
+			m.logger.ErrorContext(ctx, "Error releasing migration lock", "error", err)
+		}
+	}()
+
+	for _, table := range []string{"schema_migrations", "schema_seeds"} {
+		_, err := conn.ExecContext(ctx, `
+			CREATE TABLE IF NOT EXISTS `+table+` (
+				version INTEGER PRIMARY KEY,
+				name TEXT NOT NULL,
+				applied_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+			)
+		`)
+		if err != nil {
+			return fmt.Errorf("create %s: %w", table, err)
+		}
+	}
+	if err := m.baseline(ctx, conn); err != nil {
+		return err
+	}
+
+	return fn(conn)
+}
+
+// baseline records the first migration and seed as applied on databases created from the former
+// init.sql, which already have that schema and its demo data but no migration history. The
+// migrations after the first one then bring them up to date like any other database.
+func (m *Migrator) baseline(ctx context.Context, conn *sql.Conn) error {
+	var history int
+	if err := conn.QueryRowContext(ctx, `SELECT COUNT(*) FROM schema_migrations`).Scan(&history); err != nil {
+		return fmt.Errorf("read migration history: %w", err)
+	}
+	var existing bool
+	if err := conn.QueryRowContext(ctx, `SELECT to_regclass('orders') IS NOT NULL`).Scan(&existing); err != nil {
+		return fmt.Errorf("look for an existing schema: %w", err)
+	}
+	if history > 0 || !existing || len(m.migrations) == 0 {
+		return nil
+	}
+
+	m.logger.InfoContext(ctx, "Existing schema without migration history, recording it as applied", "version", m.migrations[0].Version)
+	if _, err := conn.ExecContext(ctx, `INSERT INTO schema_migrations (version, name) VALUES ($1, $2)`,
+		m.migrations[0].Version, m.migrations[0].Name); err != nil {
+		return fmt.Errorf("record baseline migration: %w", err)
+	}
+	if len(m.seeds) > 0 {
+		if _, err := conn.ExecContext(ctx, `INSERT INTO schema_seeds (version, name) VALUES ($1, $2) ON CONFLICT DO NOTHING`,
+			m.seeds[0].Version, m.seeds[0].Name); err != nil {
+			return fmt.Errorf("record baseline seed: %w", err)
+		}
+	}
+	return nil
+}
+
+// apply runs a script and the statement that records it in one transaction
+func apply(ctx context.Context, conn *sql.Conn, script, record string, args ...interface{}) error {
+	tx, err := conn.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, script); err != nil {
+		return err
+	}
+	if _, err := tx.ExecContext(ctx, record, args...); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// querier is a *sql.DB or a *sql.Conn
+type querier interface {
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+}
+
+func appliedVersions(ctx context.Context, db querier, table string) (map[int]struct{}, error) {
+	rows, err := db.QueryContext(ctx, `SELECT version FROM `+table)
+	if err != nil {
+		return nil, fmt.Errorf("read %s: %w", table, err)
+	}
+	defer rows.Close()
+
+	versions := make(map[int]struct{})
+	for rows.Next() {
+		var version int
+		if err := rows.Scan(&version); err != nil {
+			return nil, fmt.Errorf("scan %s: %w", table, err)
+		}
+		versions[version] = struct{}{}
+	}
+	return versions, rows.Err()
+}
+
+// load reads the numbered scripts of a directory. Migrations are named 0001_name.up.sql and
+// 0001_name.down.sql; seeds are named 0001_name.sql and only have an up script.
+func load(files fs.FS, dir string) ([]Migration, error) {
+	entries, err := fs.ReadDir(files, dir)
+	if err != nil {
+		return nil, fmt.Errorf("read embedded %s: %w", dir, err)
+	}
+
+	byVersion := make(map[int]*Migration)
+	for _, entry := range entries {
+		file := entry.Name()
+		base := strings.TrimSuffix(file, ".sql")
+		direction := "up"
+		if rest, ok := strings.CutSuffix(base, ".up"); ok {
+			base = rest
+		} else if rest, ok := strings.CutSuffix(base, ".down"); ok {
+			base, direction = rest, "down"
+		}
+
+		number, name, ok := strings.Cut(base, "_")
+		version, err := strconv.Atoi(number)
+		if !ok || err != nil || version <= 0 {
+			return nil, fmt.Errorf("%s/%s: name must start with a version number", dir, file)
+		}
+
+		script, err := fs.ReadFile(files, path.Join(dir, file))
+		if err != nil {
+			return nil, fmt.Errorf("read %s/%s: %w", dir, file, err)
+		}
+
+		migration, ok := byVersion[version]
+		if !ok {
+			migration = &Migration{Version: version, Name: name}
+			byVersion[version] = migration
+		} else if migration.Name != name {
+			return nil, fmt.Errorf("%s: version %d is used by %s and %s", dir, version, migration.Name, name)
+		}
+		if direction == "up" {
+			migration.Up = string(script)
+		} else {
+			migration.Down = string(script)
+		}
+	}
+
+	migrations := make([]Migration, 0, len(byVersion))
+	for _, migration := range byVersion {
+		if migration.Up == "" {
+			return nil, fmt.Errorf("%s: version %d has no up script", dir, migration.Version)
+		}
+		migrations = append(migrations, *migration)
+	}
+	sort.Slice(migrations, func(i, j int) bool {
+		return migrations[i].Version < migrations[j].Version
+	})
+	return migrations, nil
+}
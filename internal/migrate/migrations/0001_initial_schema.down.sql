@@ -0,0 +1,17 @@
+DROP TABLE IF EXISTS
+    inventory_transactions,
+    price_history,
+    order_status_history,
+    order_items,
+    orders,
+    menu_item_ingredients,
+    inventory,
+    menu_items;
+
+DROP FUNCTION IF EXISTS update_updated_at();
+
+DROP TYPE IF EXISTS
+    item_size,
+    transaction_type,
+    unit_type,
+    order_status;
@@ -0,0 +1,152 @@
+-- Create ENUMs
+CREATE TYPE order_status AS ENUM (
+    'pending',
+    'preparing',
+    'ready',
+    'delivered',
+    'cancelled'
+);
+
+CREATE TYPE unit_type AS ENUM (
+    'grams',
+    'milliliters',
+    'pieces',
+    'units'
+);
+
+CREATE TYPE transaction_type AS ENUM (
+    'addition',
+    'deduction',
+    'adjustment',
+    'waste'
+);
+
+CREATE TYPE item_size AS ENUM (
+    'small',
+    'medium',
+    'large'
+);
+
+-- Create Tables
+CREATE TABLE menu_items (
+    menu_item_id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    name VARCHAR(255) NOT NULL UNIQUE,
+    description TEXT,
+    price DECIMAL(10,2) NOT NULL CHECK (price > 0),
+    categories TEXT[] NOT NULL DEFAULT '{}',
+    allergens TEXT[] NOT NULL DEFAULT '{}',
+    size item_size NOT NULL DEFAULT 'medium',
+    customization_options JSONB,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE inventory (
+    ingredient_id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    name VARCHAR(255) NOT NULL UNIQUE,
+    quantity DECIMAL(10,2) NOT NULL DEFAULT 0,
+    unit unit_type NOT NULL,
+    unit_price DECIMAL(10,2) NOT NULL CHECK (unit_price >= 0),
+    reorder_point INTEGER NOT NULL CHECK (reorder_point >= 0),
+    last_updated TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE menu_item_ingredients (
+    menu_item_ingr_id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    menu_item_id UUID NOT NULL REFERENCES menu_items(menu_item_id) ON DELETE CASCADE,
+    ingredient_id UUID NOT NULL REFERENCES inventory(ingredient_id),
+    quantity DECIMAL(10,2) NOT NULL CHECK (quantity > 0),
+    unit unit_type NOT NULL,
+    UNIQUE(menu_item_id, ingredient_id)
+);
+
+CREATE TABLE orders (
+    order_id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    customer_name VARCHAR(255) NOT NULL,
+    special_instructions JSONB,
+    total_amount DECIMAL(10,2) NOT NULL CHECK (total_amount >= 0),
+    status order_status NOT NULL DEFAULT 'pending',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE order_items (
+    order_item_id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    order_id UUID NOT NULL REFERENCES orders(order_id) ON DELETE CASCADE,
+    menu_item_id UUID NOT NULL REFERENCES menu_items(menu_item_id),
+    quantity INTEGER NOT NULL CHECK (quantity > 0),
+    price_at_time DECIMAL(10,2) NOT NULL CHECK (price_at_time >= 0),
+    customizations JSONB
+    -- Removed UNIQUE constraint to allow multiple of the same item in an order
+);
+
+CREATE TABLE order_status_history (
+    order_status_id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    order_id UUID NOT NULL REFERENCES orders(order_id) ON DELETE CASCADE,
+    old_status order_status NOT NULL,
+    new_status order_status NOT NULL,
+    changed_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    change_reason TEXT NOT NULL
+);
+
+CREATE TABLE price_history (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    menu_item_id UUID NOT NULL REFERENCES menu_items(menu_item_id) ON DELETE CASCADE,
+    old_price DECIMAL(10,2) NOT NULL CHECK (old_price >= 0),
+    new_price DECIMAL(10,2) NOT NULL CHECK (new_price >= 0),
+    changed_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    change_reason TEXT NOT NULL
+);
+
+CREATE TABLE inventory_transactions (
+    transaction_id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    ingredient_id UUID NOT NULL REFERENCES inventory(ingredient_id),
+    quantity_change DECIMAL(10,2) NOT NULL,
+    transaction_type transaction_type NOT NULL,
+    reason TEXT NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Create Indexes
+CREATE INDEX idx_orders_status ON orders(status);
+CREATE INDEX idx_orders_created_at ON orders(created_at);
+CREATE INDEX idx_menu_items_price ON menu_items(price);
+CREATE INDEX idx_menu_items_categories ON menu_items USING GIN(categories);
+CREATE INDEX idx_inventory_quantity ON inventory(quantity);
+CREATE INDEX idx_order_items_order_id ON order_items(order_id);
+
+-- Full Text Search Indexes
+CREATE INDEX idx_menu_items_search ON menu_items 
+    USING GIN(to_tsvector('english', name || ' ' || COALESCE(description, '')));
+CREATE INDEX idx_orders_customer_search ON orders 
+    USING GIN(to_tsvector('english', customer_name || ' ' || COALESCE(special_instructions::text, '')));
+
+-- Composite Indexes
+CREATE INDEX idx_order_status_date ON orders(status, created_at);
+CREATE INDEX idx_inventory_stock_price ON inventory(quantity, unit_price);
+
+-- Trigger for UpdatedAt
+CREATE OR REPLACE FUNCTION update_updated_at()
+RETURNS TRIGGER AS $$
+BEGIN
+    NEW.updated_at = CURRENT_TIMESTAMP;
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+-- Create triggers for tables with updated_at columns
+CREATE TRIGGER update_orders_updated_at
+    BEFORE UPDATE ON orders
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_at();
+
+CREATE TRIGGER update_menu_items_updated_at
+    BEFORE UPDATE ON menu_items
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_at();
+
+-- Additional indexes for specific query patterns
+CREATE INDEX idx_inventory_transactions_date ON inventory_transactions(created_at);
+CREATE INDEX idx_price_history_date ON price_history(changed_at);
+CREATE INDEX idx_order_status_history_date ON order_status_history(changed_at);
+CREATE INDEX idx_menu_items_name_price ON menu_items(name, price);
+CREATE INDEX idx_inventory_name_quantity ON inventory(name, quantity);
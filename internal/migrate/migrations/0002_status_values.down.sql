@@ -0,0 +1,28 @@
+-- Enum values cannot be dropped, so the types are recreated without them; rows still using
+-- the values make this fail
+ALTER TYPE order_status RENAME TO order_status_old;
+CREATE TYPE order_status AS ENUM (
+    'pending',
+    'preparing',
+    'ready',
+    'delivered',
+    'cancelled'
+);
+ALTER TABLE orders ALTER COLUMN status DROP DEFAULT;
+ALTER TABLE orders ALTER COLUMN status TYPE order_status USING status::text::order_status;
+ALTER TABLE orders ALTER COLUMN status SET DEFAULT 'pending';
+ALTER TABLE order_status_history
+    ALTER COLUMN old_status TYPE order_status USING old_status::text::order_status,
+    ALTER COLUMN new_status TYPE order_status USING new_status::text::order_status;
+DROP TYPE order_status_old;
+
+ALTER TYPE transaction_type RENAME TO transaction_type_old;
+CREATE TYPE transaction_type AS ENUM (
+    'addition',
+    'deduction',
+    'adjustment',
+    'waste'
+);
+ALTER TABLE inventory_transactions
+    ALTER COLUMN transaction_type TYPE transaction_type USING transaction_type::text::transaction_type;
+DROP TYPE transaction_type_old;
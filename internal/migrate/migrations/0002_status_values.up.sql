@@ -0,0 +1,7 @@
+-- New enum values cannot be used in the transaction that adds them, so they come before the
+-- migration that does
+ALTER TYPE order_status ADD VALUE 'scheduled' BEFORE 'pending';
+ALTER TYPE order_status ADD VALUE 'out_for_delivery' AFTER 'ready';
+
+ALTER TYPE transaction_type ADD VALUE 'transfer_out';
+ALTER TYPE transaction_type ADD VALUE 'transfer_in';
@@ -0,0 +1,49 @@
+DROP TABLE IF EXISTS
+    print_jobs,
+    printers,
+    access_denials,
+    api_keys,
+    revoked_access_tokens,
+    refresh_tokens,
+    users,
+    station_ticket_items,
+    station_tickets,
+    station_routes,
+    stations,
+    inventory_reservations,
+    order_audit_log,
+    deliveries,
+    table_sessions,
+    order_number_counters;
+
+ALTER TABLE inventory_transactions DROP COLUMN IF EXISTS transfer_id;
+ALTER TABLE price_history DROP COLUMN IF EXISTS store_id;
+
+ALTER TABLE orders
+    DROP CONSTRAINT IF EXISTS orders_store_id_business_date_order_number_key,
+    DROP COLUMN IF EXISTS delivery_fee,
+    DROP COLUMN IF EXISTS table_id,
+    DROP COLUMN IF EXISTS order_type,
+    DROP COLUMN IF EXISTS pickup_at,
+    DROP COLUMN IF EXISTS order_number,
+    DROP COLUMN IF EXISTS business_date,
+    DROP COLUMN IF EXISTS store_id;
+
+-- Fails when two stores stock an ingredient of the same name
+ALTER TABLE inventory
+    DROP CONSTRAINT IF EXISTS inventory_store_id_name_key,
+    DROP COLUMN IF EXISTS store_id,
+    ADD CONSTRAINT inventory_name_key UNIQUE (name);
+
+DROP TABLE IF EXISTS
+    couriers,
+    delivery_zones,
+    dining_tables,
+    store_menu_items,
+    stores;
+
+DROP TYPE IF EXISTS
+    print_job_status,
+    staff_role,
+    ticket_status,
+    order_type;
@@ -0,0 +1,344 @@
+-- Stores, kitchen stations, dine-in and delivery, staff logins and ticket printing.
+-- Databases created from init.sql already hold menu items, stock and orders; those all
+-- belong to the main store created here.
+
+CREATE TYPE order_type AS ENUM (
+    'takeaway',
+    'dine_in',
+    'delivery'
+);
+
+CREATE TYPE ticket_status AS ENUM (
+    'pending',
+    'in_progress',
+    'done'
+);
+
+CREATE TYPE staff_role AS ENUM (
+    'barista',
+    'shift_lead',
+    'manager',
+    'admin'
+);
+
+CREATE TYPE print_job_status AS ENUM (
+    'pending',
+    'printing',
+    'printed',
+    'failed'
+);
+
+-- Locations; stock, orders, tables and printers belong to one store
+CREATE TABLE stores (
+    store_id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    code VARCHAR(32) NOT NULL UNIQUE, -- short name, accepted in the X-Store-ID header
+    name VARCHAR(255) NOT NULL,
+    address TEXT NOT NULL DEFAULT '',
+    phone VARCHAR(50) NOT NULL DEFAULT '',
+    is_active BOOLEAN NOT NULL DEFAULT TRUE,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+-- The first store, so a fresh installation has somewhere to take orders
+INSERT INTO stores (code, name, address, phone) VALUES
+    ('main', 'Frappuccino Main Street', '1 Main Street', '+1-555-0100');
+
+-- Stock is kept per store; the name identifies the ingredient across stores
+ALTER TABLE inventory ADD COLUMN store_id UUID REFERENCES stores(store_id);
+UPDATE inventory SET store_id = (SELECT store_id FROM stores WHERE code = 'main');
+ALTER TABLE inventory
+    ALTER COLUMN store_id SET NOT NULL,
+    DROP CONSTRAINT inventory_name_key,
+    ADD UNIQUE (store_id, name);
+
+-- Price and availability of a menu item at one store; without a row the item is sold at its menu price
+CREATE TABLE store_menu_items (
+    store_id UUID NOT NULL REFERENCES stores(store_id) ON DELETE CASCADE,
+    menu_item_id UUID NOT NULL REFERENCES menu_items(menu_item_id) ON DELETE CASCADE,
+    price DECIMAL(10,2) CHECK (price > 0), -- NULL keeps the menu price
+    is_available BOOLEAN NOT NULL DEFAULT TRUE,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (store_id, menu_item_id)
+);
+
+CREATE TABLE dining_tables (
+    table_id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    store_id UUID NOT NULL REFERENCES stores(store_id),
+    table_number INTEGER NOT NULL CHECK (table_number > 0),
+    capacity INTEGER NOT NULL CHECK (capacity > 0),
+    area VARCHAR(100) NOT NULL DEFAULT 'main',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (store_id, table_number)
+);
+
+-- A delivery zone matches an address by postcode or, when coordinates are given, by polygon
+CREATE TABLE delivery_zones (
+    zone_id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    name VARCHAR(255) NOT NULL UNIQUE,
+    postcodes TEXT[] NOT NULL DEFAULT '{}',
+    polygon JSONB, -- [[latitude, longitude], ...]
+    fee DECIMAL(10,2) NOT NULL DEFAULT 0 CHECK (fee >= 0),
+    minimum_order DECIMAL(10,2) NOT NULL DEFAULT 0 CHECK (minimum_order >= 0),
+    is_active BOOLEAN NOT NULL DEFAULT TRUE,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CHECK (cardinality(postcodes) > 0 OR polygon IS NOT NULL)
+);
+
+CREATE TABLE couriers (
+    courier_id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    name VARCHAR(255) NOT NULL,
+    phone VARCHAR(50),
+    is_active BOOLEAN NOT NULL DEFAULT TRUE,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+ALTER TABLE orders
+    ADD COLUMN store_id UUID REFERENCES stores(store_id),
+    ADD COLUMN business_date DATE, -- day the order counts towards, which ends at the configured cutoff
+    ADD COLUMN order_number INTEGER CHECK (order_number > 0), -- called out at the counter
+    ADD COLUMN pickup_at TIMESTAMPTZ, -- requested pickup time of scheduled pre-orders
+    ADD COLUMN order_type order_type NOT NULL DEFAULT 'takeaway',
+    ADD COLUMN table_id UUID REFERENCES dining_tables(table_id),
+    ADD COLUMN delivery_fee DECIMAL(10,2) NOT NULL DEFAULT 0 CHECK (delivery_fee >= 0), -- included in total_amount
+    ADD CHECK ((order_type = 'dine_in') = (table_id IS NOT NULL));
+
+-- Existing orders are numbered by the day they were placed on; the numbering must not touch
+-- updated_at
+ALTER TABLE orders DISABLE TRIGGER update_orders_updated_at;
+UPDATE orders o
+SET store_id = n.store_id,
+    business_date = n.business_date,
+    order_number = n.order_number
+FROM (
+    SELECT order_id,
+           (SELECT store_id FROM stores WHERE code = 'main') AS store_id,
+           created_at::DATE AS business_date,
+           ROW_NUMBER() OVER (PARTITION BY created_at::DATE ORDER BY created_at, order_id) AS order_number
+    FROM orders
+) n
+WHERE n.order_id = o.order_id;
+ALTER TABLE orders ENABLE TRIGGER update_orders_updated_at;
+
+ALTER TABLE orders
+    ALTER COLUMN store_id SET NOT NULL,
+    ALTER COLUMN business_date SET NOT NULL,
+    ALTER COLUMN order_number SET NOT NULL,
+    ADD UNIQUE (store_id, business_date, order_number);
+
+-- Last order number handed out per store and business day
+CREATE TABLE order_number_counters (
+    store_id UUID NOT NULL REFERENCES stores(store_id),
+    business_date DATE NOT NULL,
+    last_number INTEGER NOT NULL,
+    PRIMARY KEY (store_id, business_date)
+);
+
+-- New orders continue after the numbers given to existing ones
+INSERT INTO order_number_counters (store_id, business_date, last_number)
+SELECT store_id, business_date, MAX(order_number)
+FROM orders
+GROUP BY store_id, business_date;
+
+-- A seating of a table: the open tab order and how long the table was occupied
+CREATE TABLE table_sessions (
+    session_id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    table_id UUID NOT NULL REFERENCES dining_tables(table_id),
+    order_id UUID REFERENCES orders(order_id) ON DELETE CASCADE,
+    guests INTEGER NOT NULL DEFAULT 1 CHECK (guests > 0),
+    opened_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    closed_at TIMESTAMPTZ
+);
+
+-- Address, zone and courier of a delivery order
+CREATE TABLE deliveries (
+    order_id UUID PRIMARY KEY REFERENCES orders(order_id) ON DELETE CASCADE,
+    zone_id UUID NOT NULL REFERENCES delivery_zones(zone_id),
+    street VARCHAR(255) NOT NULL,
+    city VARCHAR(255) NOT NULL,
+    postcode VARCHAR(20) NOT NULL,
+    latitude DOUBLE PRECISION,
+    longitude DOUBLE PRECISION,
+    notes TEXT NOT NULL DEFAULT '',
+    courier_id UUID REFERENCES couriers(courier_id),
+    assigned_at TIMESTAMPTZ,
+    dispatched_at TIMESTAMPTZ,
+    delivered_at TIMESTAMPTZ
+);
+
+ALTER TABLE price_history
+    ADD COLUMN store_id UUID REFERENCES stores(store_id) ON DELETE CASCADE; -- NULL for the menu price
+
+ALTER TABLE inventory_transactions
+    ADD COLUMN transfer_id UUID; -- pairs the two sides of a transfer between stores
+
+-- Every change to the line items of an order after it was placed
+CREATE TABLE order_audit_log (
+    audit_id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    order_id UUID NOT NULL REFERENCES orders(order_id) ON DELETE CASCADE,
+    order_item_id UUID, -- no foreign key so entries survive removed items
+    action VARCHAR(50) NOT NULL,
+    old_value JSONB,
+    new_value JSONB,
+    total_before DECIMAL(10,2) NOT NULL,
+    total_after DECIMAL(10,2) NOT NULL,
+    reason TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Ingredients held for scheduled orders until they are released to the kitchen
+CREATE TABLE inventory_reservations (
+    reservation_id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    order_id UUID NOT NULL REFERENCES orders(order_id) ON DELETE CASCADE,
+    ingredient_id UUID NOT NULL REFERENCES inventory(ingredient_id),
+    quantity DECIMAL(10,2) NOT NULL CHECK (quantity > 0),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE(order_id, ingredient_id)
+);
+
+-- Kitchen stations and the tickets routed to them
+CREATE TABLE stations (
+    station_id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    name VARCHAR(255) NOT NULL UNIQUE,
+    description TEXT,
+    is_default BOOLEAN NOT NULL DEFAULT FALSE,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+-- A route sends either a single menu item or a whole category to a station
+CREATE TABLE station_routes (
+    station_route_id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    station_id UUID NOT NULL REFERENCES stations(station_id) ON DELETE CASCADE,
+    menu_item_id UUID UNIQUE REFERENCES menu_items(menu_item_id) ON DELETE CASCADE,
+    category TEXT UNIQUE,
+    CHECK ((menu_item_id IS NULL) <> (category IS NULL))
+);
+
+CREATE TABLE station_tickets (
+    ticket_id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    order_id UUID NOT NULL REFERENCES orders(order_id) ON DELETE CASCADE,
+    station_id UUID NOT NULL REFERENCES stations(station_id),
+    status ticket_status NOT NULL DEFAULT 'pending',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    started_at TIMESTAMPTZ,
+    completed_at TIMESTAMPTZ,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Every order item lives on exactly one station ticket
+CREATE TABLE station_ticket_items (
+    order_item_id UUID PRIMARY KEY REFERENCES order_items(order_item_id) ON DELETE CASCADE,
+    ticket_id UUID NOT NULL REFERENCES station_tickets(ticket_id) ON DELETE CASCADE
+);
+
+-- Staff accounts that log in for JWT access tokens
+CREATE TABLE users (
+    user_id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    username VARCHAR(255) NOT NULL UNIQUE,
+    password_hash TEXT NOT NULL, -- bcrypt
+    role staff_role NOT NULL DEFAULT 'barista',
+    store_id UUID REFERENCES stores(store_id), -- NULL for staff working across stores
+    is_active BOOLEAN NOT NULL DEFAULT TRUE,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Refresh tokens handed out at login; each is used once and replaced by the next one
+CREATE TABLE refresh_tokens (
+    token_id UUID PRIMARY KEY, -- the jti claim of the token
+    user_id UUID NOT NULL REFERENCES users(user_id) ON DELETE CASCADE,
+    expires_at TIMESTAMPTZ NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    revoked_at TIMESTAMPTZ,
+    replaced_by UUID
+);
+
+-- Access tokens revoked at logout, kept until they would have expired anyway
+CREATE TABLE revoked_access_tokens (
+    token_id UUID PRIMARY KEY,
+    expires_at TIMESTAMPTZ NOT NULL
+);
+
+-- API keys of integrations; only a SHA-256 hash of the key is stored
+CREATE TABLE api_keys (
+    key_id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    name VARCHAR(255) NOT NULL,
+    prefix VARCHAR(16) NOT NULL UNIQUE, -- identifies the key without revealing it
+    key_hash TEXT NOT NULL,
+    role staff_role NOT NULL DEFAULT 'barista',
+    store_id UUID REFERENCES stores(store_id), -- NULL for integrations working across stores
+    created_by UUID REFERENCES users(user_id) ON DELETE SET NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    last_used_at TIMESTAMPTZ,
+    revoked_at TIMESTAMPTZ
+);
+
+-- Requests refused because the caller's role lacked a permission
+CREATE TABLE access_denials (
+    denial_id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    principal_kind VARCHAR(16) NOT NULL, -- user or api_key
+    principal_id UUID NOT NULL,
+    principal_name VARCHAR(255) NOT NULL,
+    role staff_role NOT NULL,
+    permission VARCHAR(64) NOT NULL,
+    method VARCHAR(16) NOT NULL,
+    path TEXT NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Network ESC/POS printers; a printer with a station prints only that station's items,
+-- one without prints every new order
+CREATE TABLE printers (
+    printer_id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    store_id UUID NOT NULL REFERENCES stores(store_id),
+    name VARCHAR(255) NOT NULL UNIQUE,
+    address VARCHAR(255) NOT NULL, -- host:port
+    station_id UUID REFERENCES stations(station_id),
+    is_active BOOLEAN NOT NULL DEFAULT TRUE,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Kitchen tickets waiting for, or sent to, a printer
+CREATE TABLE print_jobs (
+    job_id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    printer_id UUID NOT NULL REFERENCES printers(printer_id) ON DELETE CASCADE,
+    order_id UUID NOT NULL REFERENCES orders(order_id) ON DELETE CASCADE,
+    station_id UUID REFERENCES stations(station_id),
+    status print_job_status NOT NULL DEFAULT 'pending',
+    attempts INTEGER NOT NULL DEFAULT 0,
+    last_error TEXT,
+    reprint_of UUID REFERENCES print_jobs(job_id) ON DELETE SET NULL,
+    next_attempt_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    printed_at TIMESTAMPTZ
+);
+
+-- Create Indexes
+CREATE INDEX idx_orders_store_created_at ON orders(store_id, created_at);
+CREATE INDEX idx_inventory_transactions_transfer_id ON inventory_transactions(transfer_id) WHERE transfer_id IS NOT NULL;
+CREATE UNIQUE INDEX idx_stations_single_default ON stations(is_default) WHERE is_default;
+CREATE INDEX idx_station_tickets_queue ON station_tickets(station_id, status, created_at);
+CREATE INDEX idx_station_tickets_order_id ON station_tickets(order_id);
+CREATE INDEX idx_station_ticket_items_ticket_id ON station_ticket_items(ticket_id);
+CREATE INDEX idx_orders_scheduled_pickup ON orders(pickup_at) WHERE status = 'scheduled';
+CREATE INDEX idx_inventory_reservations_ingredient ON inventory_reservations(ingredient_id);
+CREATE INDEX idx_order_audit_log_order_id ON order_audit_log(order_id, created_at);
+CREATE UNIQUE INDEX idx_table_sessions_open ON table_sessions(table_id) WHERE closed_at IS NULL;
+CREATE INDEX idx_table_sessions_order_id ON table_sessions(order_id);
+CREATE INDEX idx_table_sessions_opened_at ON table_sessions(opened_at);
+CREATE INDEX idx_deliveries_courier_id ON deliveries(courier_id) WHERE delivered_at IS NULL;
+CREATE INDEX idx_print_jobs_queue ON print_jobs(next_attempt_at) WHERE status IN ('pending', 'printing');
+CREATE INDEX idx_print_jobs_order_id ON print_jobs(order_id);
+CREATE INDEX idx_refresh_tokens_user_id ON refresh_tokens(user_id) WHERE revoked_at IS NULL;
+CREATE INDEX idx_revoked_access_tokens_expires_at ON revoked_access_tokens(expires_at);
+CREATE INDEX idx_access_denials_created_at ON access_denials(created_at);
+CREATE INDEX idx_inventory_store_id ON inventory(store_id);
+
+CREATE TRIGGER update_station_tickets_updated_at
+    BEFORE UPDATE ON station_tickets
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_at();
+
+CREATE TRIGGER update_print_jobs_updated_at
+    BEFORE UPDATE ON print_jobs
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_at();
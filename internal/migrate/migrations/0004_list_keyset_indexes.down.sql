@@ -0,0 +1,6 @@
+DROP INDEX IF EXISTS
+    idx_orders_keyset,
+    idx_order_status_history_keyset,
+    idx_price_history_keyset,
+    idx_inventory_transactions_keyset,
+    idx_inventory_name_keyset;
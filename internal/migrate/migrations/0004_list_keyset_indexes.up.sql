@@ -0,0 +1,7 @@
+-- Lists read their pages by keyset, ordered by a sort key and then by ID, so their default
+-- sorts are indexed along with the ID
+CREATE INDEX idx_orders_keyset ON orders(created_at, order_id);
+CREATE INDEX idx_order_status_history_keyset ON order_status_history(changed_at, order_status_id);
+CREATE INDEX idx_price_history_keyset ON price_history(changed_at, id);
+CREATE INDEX idx_inventory_transactions_keyset ON inventory_transactions(ingredient_id, created_at, transaction_id);
+CREATE INDEX idx_inventory_name_keyset ON inventory(name, ingredient_id);
@@ -0,0 +1,204 @@
+-- Demo menu, stock, tables, stations and order history for the main store
+
+-- Menu Items
+INSERT INTO menu_items (name, description, price, categories, allergens, size, customization_options) VALUES
+    ('Espresso', 'Strong Italian coffee', 3.50, ARRAY['coffee', 'hot'], ARRAY['caffeine'], 'small', '{"extras": ["extra_shot", "hot_water"]}'),
+    ('Cappuccino', 'Espresso with steamed milk', 4.50, ARRAY['coffee', 'hot', 'milk'], ARRAY['caffeine', 'lactose'], 'medium', '{"milk_options": ["whole", "skim", "oat"]}'),
+    ('Latte', 'Mild coffee with lots of milk', 4.00, ARRAY['coffee', 'hot', 'milk'], ARRAY['caffeine', 'lactose'], 'medium', '{"milk_options": ["whole", "skim", "oat"], "flavors": ["vanilla", "caramel"]}'),
+    ('Croissant', 'Buttery French pastry', 3.00, ARRAY['pastry', 'breakfast'], ARRAY['gluten', 'dairy'], 'medium', '{"warming": true}'),
+    ('Chocolate Muffin', 'Rich chocolate muffin', 3.50, ARRAY['pastry', 'dessert'], ARRAY['gluten', 'dairy', 'eggs'], 'medium', '{"warming": true}'),
+    ('Green Tea', 'Japanese green tea', 3.00, ARRAY['tea', 'hot'], ARRAY[]::TEXT[], 'medium', '{"strength": ["light", "medium", "strong"]}'),
+    ('Iced Coffee', 'Cold brew coffee', 4.00, ARRAY['coffee', 'cold'], ARRAY['caffeine'], 'large', '{"ice": ["normal", "light", "extra"], "sweetener": true}'),
+    ('Breakfast Sandwich', 'Egg and cheese sandwich', 6.00, ARRAY['sandwich', 'breakfast'], ARRAY['gluten', 'dairy', 'eggs'], 'medium', '{"bread": ["croissant", "bagel", "english_muffin"]}'),
+    ('Cheesecake', 'New York style cheesecake', 5.50, ARRAY['dessert'], ARRAY['gluten', 'dairy', 'eggs'], 'medium', '{"toppings": ["strawberry", "chocolate", "caramel"]}'),
+    ('Smoothie', 'Fresh fruit smoothie', 5.00, ARRAY['beverages', 'cold'], ARRAY[]::TEXT[], 'large', '{"fruits": ["strawberry", "banana", "mango"], "extras": ["protein", "spinach"]}');
+
+-- Dining Tables
+INSERT INTO dining_tables (store_id, table_number, capacity, area)
+SELECT s.store_id, t.table_number, t.capacity, t.area
+FROM stores s
+CROSS JOIN (
+    VALUES
+        (1, 2, 'window'),
+        (2, 2, 'window'),
+        (3, 4, 'main'),
+        (4, 4, 'main'),
+        (5, 6, 'main'),
+        (6, 4, 'terrace')
+) AS t(table_number, capacity, area)
+WHERE s.code = 'main';
+
+-- Delivery zones and couriers
+INSERT INTO delivery_zones (name, postcodes, fee, minimum_order) VALUES
+    ('Downtown', ARRAY['10001', '10002', '10003'], 2.50, 10.00),
+    ('Uptown', ARRAY['10025', '10026', '10027'], 4.00, 15.00);
+
+INSERT INTO couriers (name, phone) VALUES
+    ('Alex Rider', '+1-555-0101'),
+    ('Sam Carter', '+1-555-0102');
+
+-- Stations
+INSERT INTO stations (name, description, is_default) VALUES
+    ('Bar', 'Espresso machine, brewers and cold drinks', TRUE),
+    ('Pastry Counter', 'Pastries, sandwiches and desserts', FALSE);
+
+-- Station Routes (categories are matched in the order they appear on the menu item)
+INSERT INTO station_routes (station_id, category)
+SELECT s.station_id, r.category
+FROM stations s
+JOIN (
+    VALUES
+        ('Bar', 'coffee'),
+        ('Bar', 'tea'),
+        ('Bar', 'beverages'),
+        ('Pastry Counter', 'pastry'),
+        ('Pastry Counter', 'dessert'),
+        ('Pastry Counter', 'sandwich'),
+        ('Pastry Counter', 'breakfast')
+) AS r(station_name, category) ON r.station_name = s.name;
+
+-- Inventory Items
+INSERT INTO inventory (store_id, name, quantity, unit, unit_price, reorder_point)
+SELECT s.store_id, i.name, i.quantity, i.unit::unit_type, i.unit_price, i.reorder_point
+FROM stores s
+CROSS JOIN (
+    VALUES
+        ('Coffee Beans', 10000, 'grams', 0.04, 2000),
+        ('Whole Milk', 20000, 'milliliters', 0.002, 5000),
+        ('Sugar', 5000, 'grams', 0.002, 1000),
+        ('Chocolate Powder', 2000, 'grams', 0.05, 500),
+        ('Green Tea Leaves', 1000, 'grams', 0.08, 200),
+        ('Croissant Dough', 100, 'pieces', 1.00, 20),
+        ('Muffin Mix', 5000, 'grams', 0.03, 1000),
+        ('Eggs', 200, 'pieces', 0.25, 50),
+        ('Cheese', 3000, 'grams', 0.05, 500),
+        ('English Muffins', 100, 'pieces', 0.50, 20),
+        ('Strawberries', 2000, 'grams', 0.02, 500),
+        ('Bananas', 5000, 'grams', 0.01, 1000),
+        ('Whipped Cream', 2000, 'grams', 0.03, 500),
+        ('Caramel Syrup', 2000, 'milliliters', 0.02, 500),
+        ('Vanilla Syrup', 2000, 'milliliters', 0.02, 500),
+        ('Ice', 10000, 'grams', 0.001, 2000),
+        ('Paper Cups', 500, 'pieces', 0.10, 100),
+        ('Napkins', 1000, 'pieces', 0.02, 200),
+        ('To-Go Bags', 300, 'pieces', 0.15, 50),
+        ('Straws', 800, 'pieces', 0.01, 200)
+) AS i(name, quantity, unit, unit_price, reorder_point)
+WHERE s.code = 'main';
+
+-- Menu Item Ingredients (Recipe relationships)
+INSERT INTO menu_item_ingredients (menu_item_id, ingredient_id, quantity, unit) 
+SELECT 
+    m.menu_item_id,
+    i.ingredient_id,
+    CASE 
+        WHEN m.name = 'Espresso' THEN 18
+        WHEN m.name = 'Cappuccino' THEN 18
+        WHEN m.name = 'Latte' THEN 14
+    END,
+    CASE 
+        WHEN i.name = 'Coffee Beans' THEN 'grams'::unit_type
+        WHEN i.name = 'Whole Milk' THEN 'milliliters'::unit_type
+        ELSE 'grams'::unit_type
+    END
+FROM menu_items m
+CROSS JOIN inventory i
+WHERE 
+    (m.name = 'Espresso' AND i.name = 'Coffee Beans')
+    OR (m.name = 'Cappuccino' AND i.name IN ('Coffee Beans', 'Whole Milk'))
+    OR (m.name = 'Latte' AND i.name IN ('Coffee Beans', 'Whole Milk'));
+
+-- Orders (at least 30 in different statuses)
+DO $$
+DECLARE
+    customer_names TEXT[] := ARRAY['John Smith', 'Emma Davis', 'Michael Johnson', 'Sarah Wilson', 'David Brown', 'Lisa Anderson', 'James Taylor', 'Jennifer Martinez', 'Robert Garcia', 'Maria Rodriguez'];
+    statuses order_status[] := ARRAY['pending', 'preparing', 'ready', 'delivered', 'cancelled']::order_status[];
+    i INTEGER;
+    selected_status order_status;
+    selected_name TEXT;
+    new_order_id UUID;
+    main_store_id UUID := (SELECT store_id FROM stores WHERE code = 'main');
+BEGIN
+    FOR i IN 1..35 LOOP
+        -- Select random status and name
+        selected_status := statuses[1 + (i % 5)];
+        selected_name := customer_names[1 + (i % 10)];
+        
+        -- Insert order
+        INSERT INTO orders (store_id, business_date, order_number, customer_name, special_instructions, total_amount, status, created_at)
+        VALUES (
+            main_store_id,
+            (CURRENT_TIMESTAMP - (i || ' days')::INTERVAL)::DATE,
+            1,
+            selected_name,
+                                CASE WHEN i % 3 = 0 THEN '{"notes": "Extra hot"}'::JSONB ELSE NULL END,
+            (5 + (i % 20))::DECIMAL(10,2),
+            selected_status,
+            CURRENT_TIMESTAMP - (i || ' days')::INTERVAL
+        ) RETURNING order_id INTO new_order_id;
+
+        -- Insert order status history
+        INSERT INTO order_status_history (order_id, old_status, new_status, change_reason)
+        VALUES (
+            new_order_id,
+            'pending',
+            selected_status,
+            CASE 
+                WHEN selected_status = 'cancelled' THEN 'Customer request'
+                WHEN selected_status = 'delivered' THEN 'Order completed'
+                ELSE 'Regular processing'
+            END
+        );
+    END LOOP;
+END $$;
+
+-- Order Items
+INSERT INTO order_items (order_id, menu_item_id, quantity, price_at_time, customizations)
+SELECT 
+    o.order_id,
+    m.menu_item_id,
+    1 + (random() * 3)::INT,
+    m.price,
+    CASE 
+        WHEN m.name = 'Latte' THEN '{"milk": "oat"}'::jsonb
+        WHEN m.name = 'Cappuccino' THEN '{"extra_shot": true}'::jsonb
+        ELSE NULL
+    END
+FROM orders o
+CROSS JOIN menu_items m
+WHERE o.order_id IN (SELECT order_id FROM orders LIMIT 35)
+AND m.name IN ('Latte', 'Cappuccino', 'Espresso')
+LIMIT 50;
+
+-- Price History (spanning several months)
+INSERT INTO price_history (menu_item_id, old_price, new_price, changed_at, change_reason)
+SELECT 
+    menu_item_id,
+    price - 0.50,
+    price,
+    CURRENT_TIMESTAMP - (generate_series(1, 6) || ' months')::INTERVAL,
+    'Regular price adjustment'
+FROM menu_items
+WHERE name IN ('Latte', 'Cappuccino', 'Espresso');
+
+-- Inventory Transactions (showing stock movements)
+INSERT INTO inventory_transactions (ingredient_id, quantity_change, transaction_type, reason, created_at)
+SELECT 
+    i.ingredient_id,
+    CASE 
+        WHEN it.type = 'addition' THEN 1000
+        ELSE -500
+    END,
+    it.type::transaction_type,
+    it.reason,
+    CURRENT_TIMESTAMP - (it.days || ' days')::INTERVAL
+FROM inventory i
+CROSS JOIN (
+    VALUES 
+        ('addition', 'Weekly restock', 1),
+        ('deduction', 'Daily usage', 2),
+        ('adjustment', 'Inventory check', 3),
+        ('waste', 'Expired products', 4)
+) as it(type, reason, days)
+WHERE i.name IN ('Coffee Beans', 'Whole Milk', 'Sugar')
+LIMIT 50;
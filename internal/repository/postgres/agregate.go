@@ -8,11 +8,13 @@ import (
 	"frappuccino/internal/dto/report"
 )
 
-// GetTotalSales returns the total sales amount for the given date range and status
-func (repo *OrderRepository) GetTotalSales(ctx context.Context, startDate, endDate *time.Time, status string) (float64, int, error) {
+// GetTotalSales returns the total sales amount for the given date range and status, at a store
+// or across all stores when storeID is empty
+func (repo *OrderRepository) GetTotalSales(ctx context.Context, storeID string, startDate, endDate *time.Time, status string) (float64, float64, int, error) {
 	query := `
 		SELECT 
 			COALESCE(SUM(total_amount), 0) as total_sales,
+			COALESCE(SUM(delivery_fee), 0) as delivery_fees,
 			COUNT(*) as order_count
 		FROM orders
 		WHERE 1=1
@@ -42,19 +44,65 @@ func (repo *OrderRepository) GetTotalSales(ctx context.Context, startDate, endDa
 		args = append(args, status)
 	}
 
+	filter, args := storeFilter("store_id", storeID, args)
+	query += filter
+
 	// Execute the query
-	var totalSales float64
+	var totalSales, deliveryFees float64
 	var orderCount int
-	err := repo.db.QueryRowContext(ctx, query, args...).Scan(&totalSales, &orderCount)
+	err := repo.db.QueryRowContext(ctx, query, args...).Scan(&totalSales, &deliveryFees, &orderCount)
+	if err != nil {
+		return 0, 0, 0, fmt.Errorf("error querying total sales: %w", err)
+	}
+
+	return totalSales, deliveryFees, orderCount, nil
+}
+
+// GetSalesByStore breaks the total sales for the given date range and status down by store
+func (repo *OrderRepository) GetSalesByStore(ctx context.Context, startDate, endDate *time.Time, status string) ([]report.StoreSales, error) {
+	filter, args := createdAtFilter("o.created_at", startDate, endDate)
+	if status != "" {
+		args = append(args, status)
+		filter += fmt.Sprintf(" AND o.status = $%d", len(args))
+	}
+
+	query := `
+		SELECT 
+			s.store_id,
+			s.name,
+			COALESCE(SUM(o.total_amount), 0),
+			COALESCE(SUM(o.delivery_fee), 0),
+			COUNT(o.order_id)
+		FROM stores s
+		LEFT JOIN orders o ON o.store_id = s.store_id` + filter + `
+		GROUP BY s.store_id, s.name
+		ORDER BY s.name
+	`
+
+	rows, err := repo.db.QueryContext(ctx, query, args...)
 	if err != nil {
-		return 0, 0, fmt.Errorf("error querying total sales: %w", err)
+		return nil, fmt.Errorf("error querying sales by store: %w", err)
+	}
+	defer rows.Close()
+
+	var sales []report.StoreSales
+	for rows.Next() {
+		var ss report.StoreSales
+		if err := rows.Scan(&ss.StoreID, &ss.StoreName, &ss.TotalSales, &ss.DeliveryFees, &ss.OrderCount); err != nil {
+			return nil, fmt.Errorf("error scanning store sales row: %w", err)
+		}
+		sales = append(sales, ss)
 	}
 
-	return totalSales, orderCount, nil
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating store sales rows: %w", err)
+	}
+
+	return sales, nil
 }
 
 // GetPopularItems returns the most popular menu items for the given date range
-func (repo *OrderRepository) GetPopularItems(ctx context.Context, startDate, endDate *time.Time, limit int) ([]report.PopularItem, int, float64, error) {
+func (repo *OrderRepository) GetPopularItems(ctx context.Context, storeID string, startDate, endDate *time.Time, limit int) ([]report.PopularItem, int, float64, error) {
 	// Default limit if not specified
 	if limit <= 0 {
 		limit = 10
@@ -97,6 +145,12 @@ func (repo *OrderRepository) GetPopularItems(ctx context.Context, startDate, end
 	// Exclude cancelled orders
 	query += " AND o.status != 'cancelled'"
 
+	if storeID != "" {
+		query += fmt.Sprintf(" AND o.store_id = $%d", argIndex)
+		args = append(args, storeID)
+		argIndex++
+	}
+
 	// Group by menu item, sort by quantity sold in descending order, and limit the results
 	query += `
 			GROUP BY 
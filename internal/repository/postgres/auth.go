@@ -0,0 +1,332 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"frappuccino/internal/entity"
+)
+
+type AuthRepository struct {
+	db *sql.DB
+}
+
+func NewAuthRepository(db *sql.DB) *AuthRepository {
+	return &AuthRepository{
+		db: db,
+	}
+}
+
+func (repo *AuthRepository) CreateUser(ctx context.Context, user entity.User) (string, error) {
+	var userID string
+	query := `
+		INSERT INTO users (username, password_hash, role, store_id, is_active)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING user_id
+	`
+	err := repo.db.QueryRowContext(ctx, query, user.Username, user.PasswordHash, user.Role, user.StoreID, user.IsActive).Scan(&userID)
+	if err != nil {
+		return "", fmt.Errorf("insert user: %w", err)
+	}
+	return userID, nil
+}
+
+func (repo *AuthRepository) GetUserByUsername(ctx context.Context, username string) (entity.User, error) {
+	return repo.getUser(ctx, `WHERE username = $1`, username)
+}
+
+func (repo *AuthRepository) GetUserByID(ctx context.Context, userID string) (entity.User, error) {
+	return repo.getUser(ctx, `WHERE user_id = $1`, userID)
+}
+
+const userColumns = `user_id, username, password_hash, role, store_id, is_active, created_at`
+
+func (repo *AuthRepository) getUser(ctx context.Context, where string, arg interface{}) (entity.User, error) {
+	query := `SELECT ` + userColumns + ` FROM users ` + where
+	return scanUser(repo.db.QueryRowContext(ctx, query, arg))
+}
+
+func (repo *AuthRepository) GetUsers(ctx context.Context) ([]entity.User, error) {
+	query := `SELECT ` + userColumns + ` FROM users ORDER BY username`
+
+	rows, err := repo.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("query users: %w", err)
+	}
+	defer rows.Close()
+
+	var users []entity.User
+	for rows.Next() {
+		u, err := scanUser(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scan user: %w", err)
+		}
+		users = append(users, u)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate users: %w", err)
+	}
+
+	return users, nil
+}
+
+// UpdateUser changes the role and active flag of a user, or returns sql.ErrNoRows if there is no such user
+func (repo *AuthRepository) UpdateUser(ctx context.Context, userID, role string, isActive bool) error {
+	query := `UPDATE users SET role = $2, is_active = $3 WHERE user_id = $1`
+	result, err := repo.db.ExecContext(ctx, query, userID, role, isActive)
+	if err != nil {
+		return fmt.Errorf("update user: %w", err)
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("update user: %w", err)
+	}
+	if affected == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+func scanUser(row rowScanner) (entity.User, error) {
+	var u entity.User
+	var storeID sql.NullString
+	err := row.Scan(&u.UserID, &u.Username, &u.PasswordHash, &u.Role, &storeID, &u.IsActive, &u.CreatedAt)
+	u.StoreID = nullStringPtr(storeID)
+	return u, err
+}
+
+func (repo *AuthRepository) UpdatePassword(ctx context.Context, userID, passwordHash string) error {
+	query := `UPDATE users SET password_hash = $2 WHERE user_id = $1`
+	if _, err := repo.db.ExecContext(ctx, query, userID, passwordHash); err != nil {
+		return fmt.Errorf("update password: %w", err)
+	}
+	return nil
+}
+
+func (repo *AuthRepository) CreateRefreshToken(ctx context.Context, token entity.RefreshToken) error {
+	query := `
+		INSERT INTO refresh_tokens (token_id, user_id, expires_at)
+		VALUES ($1, $2, $3)
+	`
+	if _, err := repo.db.ExecContext(ctx, query, token.TokenID, token.UserID, token.ExpiresAt); err != nil {
+		return fmt.Errorf("insert refresh token: %w", err)
+	}
+	return nil
+}
+
+// RevokeRefreshToken marks a refresh token used, optionally recording the token that replaces it.
+// It reports false when the token was already revoked, which means it is being replayed.
+func (repo *AuthRepository) RevokeRefreshToken(ctx context.Context, tokenID string, replacedBy *string) (bool, error) {
+	query := `
+		UPDATE refresh_tokens
+		SET revoked_at = CURRENT_TIMESTAMP, replaced_by = $2
+		WHERE token_id = $1 AND revoked_at IS NULL
+	`
+	result, err := repo.db.ExecContext(ctx, query, tokenID, replacedBy)
+	if err != nil {
+		return false, fmt.Errorf("revoke refresh token: %w", err)
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("revoke refresh token: %w", err)
+	}
+	return affected > 0, nil
+}
+
+// RevokeUserRefreshTokens ends every session of a user
+func (repo *AuthRepository) RevokeUserRefreshTokens(ctx context.Context, userID string) error {
+	query := `
+		UPDATE refresh_tokens
+		SET revoked_at = CURRENT_TIMESTAMP
+		WHERE user_id = $1 AND revoked_at IS NULL
+	`
+	if _, err := repo.db.ExecContext(ctx, query, userID); err != nil {
+		return fmt.Errorf("revoke user refresh tokens: %w", err)
+	}
+	return nil
+}
+
+// RevokeAccessToken rejects an access token until it expires; expired entries are cleaned up on the way
+func (repo *AuthRepository) RevokeAccessToken(ctx context.Context, tokenID string, expiresAt time.Time) error {
+	if _, err := repo.db.ExecContext(ctx, `DELETE FROM revoked_access_tokens WHERE expires_at < CURRENT_TIMESTAMP`); err != nil {
+		return fmt.Errorf("clean up revoked access tokens: %w", err)
+	}
+
+	query := `
+		INSERT INTO revoked_access_tokens (token_id, expires_at)
+		VALUES ($1, $2)
+		ON CONFLICT (token_id) DO NOTHING
+	`
+	if _, err := repo.db.ExecContext(ctx, query, tokenID, expiresAt); err != nil {
+		return fmt.Errorf("revoke access token: %w", err)
+	}
+	return nil
+}
+
+func (repo *AuthRepository) IsAccessTokenRevoked(ctx context.Context, tokenID string) (bool, error) {
+	var revoked bool
+	query := `SELECT EXISTS (SELECT 1 FROM revoked_access_tokens WHERE token_id = $1)`
+	if err := repo.db.QueryRowContext(ctx, query, tokenID).Scan(&revoked); err != nil {
+		return false, fmt.Errorf("check revoked access token: %w", err)
+	}
+	return revoked, nil
+}
+
+func (repo *AuthRepository) CreateAPIKey(ctx context.Context, key entity.APIKey) (string, error) {
+	var keyID string
+	query := `
+		INSERT INTO api_keys (name, prefix, key_hash, role, store_id, created_by)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING key_id
+	`
+	err := repo.db.QueryRowContext(ctx, query, key.Name, key.Prefix, key.KeyHash, key.Role, key.StoreID, key.CreatedBy).Scan(&keyID)
+	if err != nil {
+		return "", fmt.Errorf("insert api key: %w", err)
+	}
+	return keyID, nil
+}
+
+const apiKeyColumns = `key_id, name, prefix, key_hash, role, store_id, created_by, created_at, last_used_at, revoked_at`
+
+func (repo *AuthRepository) GetAPIKeyByPrefix(ctx context.Context, prefix string) (entity.APIKey, error) {
+	query := `SELECT ` + apiKeyColumns + ` FROM api_keys WHERE prefix = $1`
+	return scanAPIKey(repo.db.QueryRowContext(ctx, query, prefix))
+}
+
+func (repo *AuthRepository) GetAPIKeys(ctx context.Context) ([]entity.APIKey, error) {
+	query := `SELECT ` + apiKeyColumns + ` FROM api_keys ORDER BY created_at`
+
+	rows, err := repo.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("query api keys: %w", err)
+	}
+	defer rows.Close()
+
+	var keys []entity.APIKey
+	for rows.Next() {
+		key, err := scanAPIKey(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scan api key: %w", err)
+		}
+		keys = append(keys, key)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate api keys: %w", err)
+	}
+
+	return keys, nil
+}
+
+// TouchAPIKey records when a key was last used
+func (repo *AuthRepository) TouchAPIKey(ctx context.Context, keyID string) error {
+	query := `UPDATE api_keys SET last_used_at = CURRENT_TIMESTAMP WHERE key_id = $1`
+	if _, err := repo.db.ExecContext(ctx, query, keyID); err != nil {
+		return fmt.Errorf("touch api key: %w", err)
+	}
+	return nil
+}
+
+// RevokeAPIKey disables a key for good; it reports false if there is no such active key
+func (repo *AuthRepository) RevokeAPIKey(ctx context.Context, keyID string) (bool, error) {
+	query := `UPDATE api_keys SET revoked_at = CURRENT_TIMESTAMP WHERE key_id = $1 AND revoked_at IS NULL`
+	result, err := repo.db.ExecContext(ctx, query, keyID)
+	if err != nil {
+		return false, fmt.Errorf("revoke api key: %w", err)
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("revoke api key: %w", err)
+	}
+	return affected > 0, nil
+}
+
+func scanAPIKey(row rowScanner) (entity.APIKey, error) {
+	var key entity.APIKey
+	var storeID, createdBy sql.NullString
+	var lastUsedAt, revokedAt sql.NullTime
+	err := row.Scan(
+		&key.KeyID,
+		&key.Name,
+		&key.Prefix,
+		&key.KeyHash,
+		&key.Role,
+		&storeID,
+		&createdBy,
+		&key.CreatedAt,
+		&lastUsedAt,
+		&revokedAt,
+	)
+	key.StoreID = nullStringPtr(storeID)
+	key.CreatedBy = nullStringPtr(createdBy)
+	key.LastUsedAt = nullTimePtr(lastUsedAt)
+	key.RevokedAt = nullTimePtr(revokedAt)
+	return key, err
+}
+
+func (repo *AuthRepository) CreateAccessDenial(ctx context.Context, denial entity.AccessDenial) error {
+	query := `
+		INSERT INTO access_denials (principal_kind, principal_id, principal_name, role, permission, method, path)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+	`
+	_, err := repo.db.ExecContext(ctx, query,
+		denial.PrincipalKind,
+		denial.PrincipalID,
+		denial.PrincipalName,
+		denial.Role,
+		denial.Permission,
+		denial.Method,
+		denial.Path,
+	)
+	if err != nil {
+		return fmt.Errorf("insert access denial: %w", err)
+	}
+	return nil
+}
+
+// GetAccessDenials returns the denials in a period, newest first
+func (repo *AuthRepository) GetAccessDenials(ctx context.Context, startDate, endDate *time.Time, limit int) ([]entity.AccessDenial, error) {
+	filter, args := createdAtFilter("created_at", startDate, endDate)
+	args = append(args, limit)
+	query := fmt.Sprintf(`
+		SELECT denial_id, principal_kind, principal_id, principal_name, role, permission, method, path, created_at
+		FROM access_denials
+		WHERE TRUE%s
+		ORDER BY created_at DESC
+		LIMIT $%d
+	`, filter, len(args))
+
+	rows, err := repo.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("query access denials: %w", err)
+	}
+	defer rows.Close()
+
+	var denials []entity.AccessDenial
+	for rows.Next() {
+		var d entity.AccessDenial
+		if err := rows.Scan(
+			&d.DenialID,
+			&d.PrincipalKind,
+			&d.PrincipalID,
+			&d.PrincipalName,
+			&d.Role,
+			&d.Permission,
+			&d.Method,
+			&d.Path,
+			&d.CreatedAt,
+		); err != nil {
+			return nil, fmt.Errorf("scan access denial: %w", err)
+		}
+		denials = append(denials, d)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate access denials: %w", err)
+	}
+
+	return denials, nil
+}
@@ -1,37 +1,83 @@
 package postgres
 
 import (
+	"context"
 	"database/sql"
 	"errors"
 	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/lib/pq"
 
 	"frappuccino/internal/config"
 )
 
-func NewDbConnInstance(cfg *config.Repository) (*sql.DB, error) {
+// maxRetryDelay caps the wait between connection attempts as it doubles
+const maxRetryDelay = 30 * time.Second
+
+// NewDbConnInstance opens the database and waits for it to answer, retrying with a doubling delay
+// so that the application may start before Postgres is ready, e.g. under docker compose
+func NewDbConnInstance(ctx context.Context, cfg *config.Repository, logger *slog.Logger) (*sql.DB, error) {
 	if cfg == nil {
 		return nil, errors.New("Postgres configuration is nil")
 	}
 
 	dsn := fmt.Sprintf(
-		"host=%s port=%d user=%s password=%s dbname=%s sslmode=%s",
+		"host=%s port=%d user=%s password=%s dbname=%s sslmode=%s connect_timeout=%d",
 		cfg.DBHost,
 		cfg.DBPort,
 		cfg.DBUsername,
-		cfg.DBPassword,
+		quoteDSN(cfg.DBPassword),
 		cfg.DBName,
 		cfg.DBSSLMode,
+		int(cfg.ConnectTimeout/time.Second),
 	)
 
-	db, err := sql.Open("postgres", dsn)
+	connector, err := pq.NewConnector(dsn)
 	if err != nil {
 		return nil, fmt.Errorf("Failed to open database: %w", err)
 	}
-	if err := db.Ping(); err != nil {
+	db := sql.OpenDB(tracedConnector{connector})
+	if err := ping(ctx, db, cfg, logger); err != nil {
+		db.Close()
 		return nil, fmt.Errorf("Failed to ping database: %w", err)
 	}
 
 	db.SetMaxOpenConns(cfg.MaxConn)
 	db.SetMaxIdleConns(cfg.MaxIdleConn)
+	db.SetConnMaxLifetime(cfg.ConnMaxLifetime)
 	return db, nil
 }
+
+func ping(ctx context.Context, db *sql.DB, cfg *config.Repository, logger *slog.Logger) error {
+	delay := cfg.ConnectRetryDelay
+	for attempt := 1; ; attempt++ {
+		err := db.PingContext(ctx)
+		if err == nil || attempt >= cfg.ConnectAttempts || ctx.Err() != nil {
+			return err
+		}
+
+		logger.WarnContext(ctx, "Database not reachable, retrying",
+			"attempt", attempt,
+			"attempts", cfg.ConnectAttempts,
+			"retry_in", delay,
+			"error", err,
+		)
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(delay):
+		}
+		delay = min(delay*2, maxRetryDelay)
+	}
+}
+
+// quoteDSN quotes a connection string value, so that passwords read from secret files may
+// contain spaces and quotes
+func quoteDSN(value string) string {
+	value = strings.ReplaceAll(value, `\`, `\\`)
+	value = strings.ReplaceAll(value, `'`, `\'`)
+	return "'" + value + "'"
+}
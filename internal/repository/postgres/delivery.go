@@ -0,0 +1,303 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+
+	"frappuccino/internal/entity"
+
+	"github.com/lib/pq"
+)
+
+type DeliveryRepository struct {
+	db *sql.DB
+}
+
+func NewDeliveryRepository(db *sql.DB) *DeliveryRepository {
+	return &DeliveryRepository{
+		db: db,
+	}
+}
+
+func (repo *DeliveryRepository) CreateZone(ctx context.Context, zone entity.DeliveryZone) (string, error) {
+	polygon, err := marshalPolygon(zone.Polygon)
+	if err != nil {
+		return "", err
+	}
+
+	var zoneID string
+	query := `
+		INSERT INTO delivery_zones (name, postcodes, polygon, fee, minimum_order, is_active)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING zone_id
+	`
+	err = repo.db.QueryRowContext(ctx, query,
+		zone.Name,
+		pq.Array(zone.Postcodes),
+		polygon,
+		zone.Fee,
+		zone.MinimumOrder,
+		zone.IsActive,
+	).Scan(&zoneID)
+	if err != nil {
+		return "", fmt.Errorf("insert delivery zone: %w", err)
+	}
+	return zoneID, nil
+}
+
+// GetZones returns the delivery zones, optionally only the active ones, in creation order
+func (repo *DeliveryRepository) GetZones(ctx context.Context, activeOnly bool) ([]entity.DeliveryZone, error) {
+	query := `
+		SELECT zone_id, name, postcodes, polygon, fee, minimum_order, is_active, created_at
+		FROM delivery_zones
+		WHERE is_active OR NOT $1
+		ORDER BY created_at, name
+	`
+
+	rows, err := repo.db.QueryContext(ctx, query, activeOnly)
+	if err != nil {
+		return nil, fmt.Errorf("query delivery zones: %w", err)
+	}
+	defer rows.Close()
+
+	var zones []entity.DeliveryZone
+	for rows.Next() {
+		var z entity.DeliveryZone
+		var polygon sql.NullString
+		if err := rows.Scan(
+			&z.ZoneID,
+			&z.Name,
+			pq.Array(&z.Postcodes),
+			&polygon,
+			&z.Fee,
+			&z.MinimumOrder,
+			&z.IsActive,
+			&z.CreatedAt,
+		); err != nil {
+			return nil, fmt.Errorf("scan delivery zone: %w", err)
+		}
+		if polygon.Valid {
+			if z.Polygon, err = unmarshalPolygon(polygon.String); err != nil {
+				return nil, err
+			}
+		}
+		zones = append(zones, z)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate delivery zones: %w", err)
+	}
+
+	return zones, nil
+}
+
+func (repo *DeliveryRepository) CreateCourier(ctx context.Context, courier entity.Courier) (string, error) {
+	var courierID string
+	query := `
+		INSERT INTO couriers (name, phone, is_active)
+		VALUES ($1, NULLIF($2, ''), $3)
+		RETURNING courier_id
+	`
+	err := repo.db.QueryRowContext(ctx, query, courier.Name, courier.Phone, courier.IsActive).Scan(&courierID)
+	if err != nil {
+		return "", fmt.Errorf("insert courier: %w", err)
+	}
+	return courierID, nil
+}
+
+func (repo *DeliveryRepository) GetCouriers(ctx context.Context) ([]entity.Courier, error) {
+	query := `
+		SELECT courier_id, name, COALESCE(phone, ''), is_active, created_at
+		FROM couriers
+		ORDER BY name
+	`
+
+	rows, err := repo.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("query couriers: %w", err)
+	}
+	defer rows.Close()
+
+	var couriers []entity.Courier
+	for rows.Next() {
+		var c entity.Courier
+		if err := rows.Scan(&c.CourierID, &c.Name, &c.Phone, &c.IsActive, &c.CreatedAt); err != nil {
+			return nil, fmt.Errorf("scan courier: %w", err)
+		}
+		couriers = append(couriers, c)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate couriers: %w", err)
+	}
+
+	return couriers, nil
+}
+
+func (repo *DeliveryRepository) GetCourierByID(ctx context.Context, courierID string) (entity.Courier, error) {
+	var c entity.Courier
+	query := `
+		SELECT courier_id, name, COALESCE(phone, ''), is_active, created_at
+		FROM couriers
+		WHERE courier_id = $1
+	`
+	err := repo.db.QueryRowContext(ctx, query, courierID).Scan(&c.CourierID, &c.Name, &c.Phone, &c.IsActive, &c.CreatedAt)
+	return c, err
+}
+
+// CreateDelivery stores the address and zone of a delivery order
+func (repo *DeliveryRepository) CreateDelivery(ctx context.Context, delivery entity.Delivery) error {
+	return createDelivery(ctx, repo.db, delivery)
+}
+
+// CreateDeliveryWithTx stores the address and zone of a delivery order within an existing transaction
+func (repo *DeliveryRepository) CreateDeliveryWithTx(ctx context.Context, tx *Transaction, delivery entity.Delivery) error {
+	return createDelivery(ctx, tx.tx, delivery)
+}
+
+func createDelivery(ctx context.Context, q queryer, delivery entity.Delivery) error {
+	var latitude, longitude sql.NullFloat64
+	if delivery.Address.Location != nil {
+		latitude = sql.NullFloat64{Float64: delivery.Address.Location.Latitude, Valid: true}
+		longitude = sql.NullFloat64{Float64: delivery.Address.Location.Longitude, Valid: true}
+	}
+
+	query := `
+		INSERT INTO deliveries (order_id, zone_id, street, city, postcode, latitude, longitude, notes)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+	`
+	_, err := q.ExecContext(ctx, query,
+		delivery.OrderID,
+		delivery.ZoneID,
+		delivery.Address.Street,
+		delivery.Address.City,
+		delivery.Address.Postcode,
+		latitude,
+		longitude,
+		delivery.Address.Notes,
+	)
+	if err != nil {
+		return fmt.Errorf("insert delivery: %w", err)
+	}
+	return nil
+}
+
+// GetDelivery returns the delivery details of an order, or sql.ErrNoRows if it is not a delivery
+func (repo *DeliveryRepository) GetDelivery(ctx context.Context, orderID string) (entity.Delivery, error) {
+	var d entity.Delivery
+	var latitude, longitude sql.NullFloat64
+	var courierID sql.NullString
+	var assignedAt, dispatchedAt, deliveredAt sql.NullTime
+	query := `
+		SELECT
+			d.order_id,
+			d.zone_id,
+			z.name,
+			d.street,
+			d.city,
+			d.postcode,
+			d.latitude,
+			d.longitude,
+			d.notes,
+			d.courier_id,
+			COALESCE(c.name, ''),
+			d.assigned_at,
+			d.dispatched_at,
+			d.delivered_at
+		FROM deliveries d
+		JOIN delivery_zones z ON z.zone_id = d.zone_id
+		LEFT JOIN couriers c ON c.courier_id = d.courier_id
+		WHERE d.order_id = $1
+	`
+	err := repo.db.QueryRowContext(ctx, query, orderID).Scan(
+		&d.OrderID,
+		&d.ZoneID,
+		&d.ZoneName,
+		&d.Address.Street,
+		&d.Address.City,
+		&d.Address.Postcode,
+		&latitude,
+		&longitude,
+		&d.Address.Notes,
+		&courierID,
+		&d.CourierName,
+		&assignedAt,
+		&dispatchedAt,
+		&deliveredAt,
+	)
+	if latitude.Valid && longitude.Valid {
+		d.Address.Location = &entity.GeoPoint{Latitude: latitude.Float64, Longitude: longitude.Float64}
+	}
+	d.CourierID = nullStringPtr(courierID)
+	d.AssignedAt = nullTimePtr(assignedAt)
+	d.DispatchedAt = nullTimePtr(dispatchedAt)
+	d.DeliveredAt = nullTimePtr(deliveredAt)
+	return d, err
+}
+
+// AssignCourier hands a delivery that has not left yet to a courier.
+// It reports false when the order is not a delivery or is already on its way.
+func (repo *DeliveryRepository) AssignCourier(ctx context.Context, orderID, courierID string) (bool, error) {
+	query := `
+		UPDATE deliveries
+		SET courier_id = $2, assigned_at = CURRENT_TIMESTAMP
+		WHERE order_id = $1 AND dispatched_at IS NULL
+	`
+	result, err := repo.db.ExecContext(ctx, query, orderID, courierID)
+	if err != nil {
+		return false, fmt.Errorf("assign courier: %w", err)
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("assign courier: %w", err)
+	}
+	return affected > 0, nil
+}
+
+// MarkDispatched records when the courier left with the order
+func (repo *DeliveryRepository) MarkDispatched(ctx context.Context, orderID string) error {
+	query := `UPDATE deliveries SET dispatched_at = CURRENT_TIMESTAMP WHERE order_id = $1 AND dispatched_at IS NULL`
+	if _, err := repo.db.ExecContext(ctx, query, orderID); err != nil {
+		return fmt.Errorf("mark delivery dispatched: %w", err)
+	}
+	return nil
+}
+
+// MarkDelivered records when the order reached the customer
+func (repo *DeliveryRepository) MarkDelivered(ctx context.Context, orderID string) error {
+	query := `UPDATE deliveries SET delivered_at = CURRENT_TIMESTAMP WHERE order_id = $1 AND delivered_at IS NULL`
+	if _, err := repo.db.ExecContext(ctx, query, orderID); err != nil {
+		return fmt.Errorf("mark delivery delivered: %w", err)
+	}
+	return nil
+}
+
+// marshalPolygon stores a polygon as an array of [latitude, longitude] pairs
+func marshalPolygon(polygon []entity.GeoPoint) (interface{}, error) {
+	if len(polygon) == 0 {
+		return nil, nil
+	}
+	pairs := make([][2]float64, 0, len(polygon))
+	for _, p := range polygon {
+		pairs = append(pairs, [2]float64{p.Latitude, p.Longitude})
+	}
+	data, err := json.Marshal(pairs)
+	if err != nil {
+		return nil, fmt.Errorf("marshal polygon: %w", err)
+	}
+	return data, nil
+}
+
+func unmarshalPolygon(data string) ([]entity.GeoPoint, error) {
+	var pairs [][2]float64
+	if err := json.Unmarshal([]byte(data), &pairs); err != nil {
+		return nil, fmt.Errorf("unmarshal polygon: %w", err)
+	}
+	polygon := make([]entity.GeoPoint, 0, len(pairs))
+	for _, p := range pairs {
+		polygon = append(polygon, entity.GeoPoint{Latitude: p[0], Longitude: p[1]})
+	}
+	return polygon, nil
+}
@@ -0,0 +1,51 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+)
+
+// EnumRepository reads the enum types of the schema, so that requests can be checked against
+// the values the database accepts before anything is written
+type EnumRepository struct {
+	db *sql.DB
+}
+
+func NewEnumRepository(db *sql.DB) *EnumRepository {
+	return &EnumRepository{
+		db: db,
+	}
+}
+
+// GetEnums returns the values of every enum type by its name, like unit_type, in declared order
+func (repo *EnumRepository) GetEnums(ctx context.Context) (map[string][]string, error) {
+	query := `
+		SELECT t.typname, e.enumlabel
+		FROM pg_type t
+		JOIN pg_enum e ON e.enumtypid = t.oid
+		JOIN pg_namespace n ON n.oid = t.typnamespace
+		WHERE n.nspname = current_schema()
+		ORDER BY t.typname, e.enumsortorder
+	`
+
+	rows, err := repo.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("query enums: %w", err)
+	}
+	defer rows.Close()
+
+	enums := make(map[string][]string)
+	for rows.Next() {
+		var name, value string
+		if err := rows.Scan(&name, &value); err != nil {
+			return nil, fmt.Errorf("scan enum: %w", err)
+		}
+		enums[name] = append(enums[name], value)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate enums: %w", err)
+	}
+	return enums, nil
+}
@@ -0,0 +1,25 @@
+package postgres
+
+import (
+	"errors"
+
+	"github.com/lib/pq"
+
+	"frappuccino/internal/apperror"
+)
+
+// ErrInUse is returned by deletes of rows that other rows still refer to
+var ErrInUse = apperror.New(apperror.Conflict, "in_use", "the resource is still in use")
+
+const foreignKeyViolation pq.ErrorCode = "23503"
+
+// inUse describes err as ErrInUse when it is a foreign key of another table refusing a delete.
+// Postgres names the referencing table and constraint for violations on either side, so only
+// callers that delete may read it this way.
+func inUse(err error) error {
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) && pqErr.Code == foreignKeyViolation {
+		return ErrInUse.WithDetail("referenced from " + pqErr.Table)
+	}
+	return err
+}
@@ -0,0 +1,109 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"frappuccino/internal/entity"
+
+	"github.com/lib/pq"
+)
+
+// GetMenuItemPrepDurations returns the median preparing -> ready duration of the orders
+// containing each menu item, sampled from the orders of a store created since the given time.
+// Each store has its own kitchen, so a slow store does not skew the others.
+func (repo *OrderRepository) GetMenuItemPrepDurations(ctx context.Context, storeID string, since time.Time) (map[string]time.Duration, error) {
+	query := `
+		WITH timings AS (
+			SELECT
+				o.order_id,
+				MIN(h.changed_at) FILTER (WHERE h.new_status = 'preparing') AS preparing_at,
+				MIN(h.changed_at) FILTER (WHERE h.new_status = 'ready') AS ready_at
+			FROM orders o
+			JOIN order_status_history h ON h.order_id = o.order_id
+			WHERE o.created_at >= $1
+			  AND ($2::uuid IS NULL OR o.store_id = $2)
+			GROUP BY o.order_id
+		)
+		SELECT
+			oi.menu_item_id,
+			percentile_cont(0.5) WITHIN GROUP (ORDER BY EXTRACT(EPOCH FROM (t.ready_at - t.preparing_at)))
+		FROM timings t
+		JOIN order_items oi ON oi.order_id = t.order_id
+		WHERE t.preparing_at IS NOT NULL AND t.ready_at > t.preparing_at
+		GROUP BY oi.menu_item_id
+	`
+
+	rows, err := repo.db.QueryContext(ctx, query, since, nullIfEmpty(storeID))
+	if err != nil {
+		return nil, fmt.Errorf("error querying menu item prep durations: %w", err)
+	}
+	defer rows.Close()
+
+	durations := make(map[string]time.Duration)
+	for rows.Next() {
+		var menuItemID string
+		var seconds float64
+		if err := rows.Scan(&menuItemID, &seconds); err != nil {
+			return nil, fmt.Errorf("error scanning menu item prep duration: %w", err)
+		}
+		durations[menuItemID] = time.Duration(seconds * float64(time.Second))
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating menu item prep durations: %w", err)
+	}
+
+	return durations, nil
+}
+
+// GetActiveOrderQueue returns the pending and preparing orders of a store in the order they were placed
+func (repo *OrderRepository) GetActiveOrderQueue(ctx context.Context, storeID string) ([]entity.QueuedOrder, error) {
+	query := `
+		SELECT
+			o.order_id,
+			o.status,
+			o.created_at,
+			(SELECT MAX(h.changed_at)
+			 FROM order_status_history h
+			 WHERE h.order_id = o.order_id AND h.new_status = 'preparing') AS preparing_at,
+			ARRAY(SELECT DISTINCT oi.menu_item_id::TEXT
+			      FROM order_items oi
+			      WHERE oi.order_id = o.order_id) AS menu_item_ids
+		FROM orders o
+		WHERE o.status IN ('pending', 'preparing')
+		  AND ($1::uuid IS NULL OR o.store_id = $1)
+		ORDER BY o.created_at
+	`
+
+	rows, err := repo.db.QueryContext(ctx, query, nullIfEmpty(storeID))
+	if err != nil {
+		return nil, fmt.Errorf("error querying active order queue: %w", err)
+	}
+	defer rows.Close()
+
+	var queue []entity.QueuedOrder
+	for rows.Next() {
+		var q entity.QueuedOrder
+		var preparingAt sql.NullTime
+		if err := rows.Scan(
+			&q.OrderID,
+			&q.Status,
+			&q.CreatedAt,
+			&preparingAt,
+			pq.Array(&q.MenuItemIDs),
+		); err != nil {
+			return nil, fmt.Errorf("error scanning queued order: %w", err)
+		}
+		q.PreparingAt = nullTimePtr(preparingAt)
+		queue = append(queue, q)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating active order queue: %w", err)
+	}
+
+	return queue, nil
+}
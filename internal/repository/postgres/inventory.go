@@ -3,9 +3,11 @@ package postgres
 import (
 	"context"
 	"database/sql"
+	"fmt"
 	"strconv"
 	"strings"
 
+	"frappuccino/internal/dto/list"
 	"frappuccino/internal/entity"
 )
 
@@ -23,9 +25,10 @@ func NewInventoryRepository(db *sql.DB) *InventoryRepository {
 func (repo *InventoryRepository) CreateInventory(ctx context.Context, inventory entity.Inventory) (string, error) {
 	var ID string
 	query := `
-	 INSERT INTO inventory (name, quantity, unit, unit_price, reorder_point) VALUES ($1, $2, $3, $4, $5) RETURNING ingredient_id;
+	 INSERT INTO inventory (store_id, name, quantity, unit, unit_price, reorder_point) VALUES ($1, $2, $3, $4, $5, $6) RETURNING ingredient_id;
 	  `
 	err := repo.db.QueryRowContext(ctx, query,
+		inventory.StoreID,
 		inventory.Name,
 		inventory.Quantity,
 		inventory.Unit,
@@ -34,15 +37,18 @@ func (repo *InventoryRepository) CreateInventory(ctx context.Context, inventory
 	return ID, err
 }
 
-func (repo *InventoryRepository) GetInventory(ctx context.Context) ([]entity.Inventory, error) {
+// GetInventory lists the stock of a store, or of every store when storeID is empty
+func (repo *InventoryRepository) GetInventory(ctx context.Context, storeID string) ([]entity.Inventory, error) {
 	var inventories []entity.Inventory
+	filter, args := storeFilter("store_id", storeID, nil)
 	query := `
-	SELECT * 
+	SELECT ingredient_id, store_id, name, quantity, unit, unit_price, reorder_point, last_updated
 	FROM inventory
-	ORDER BY name
+	WHERE TRUE` + filter + `
+	ORDER BY name, store_id
 	`
 
-	rows, err := repo.db.QueryContext(ctx, query)
+	rows, err := repo.db.QueryContext(ctx, query, args...)
 	if err != nil {
 		return nil, err
 	}
@@ -52,6 +58,7 @@ func (repo *InventoryRepository) GetInventory(ctx context.Context) ([]entity.Inv
 		var inv entity.Inventory
 		if err := rows.Scan(
 			&inv.IngredientID,
+			&inv.StoreID,
 			&inv.Name,
 			&inv.Quantity,
 			&inv.Unit,
@@ -73,13 +80,14 @@ func (repo *InventoryRepository) GetInventory(ctx context.Context) ([]entity.Inv
 func (repo *InventoryRepository) GetInventoryByID(ctx context.Context, id string) (entity.Inventory, error) {
 	var inv entity.Inventory
 	query := `
-    SELECT ingredient_id, name, quantity, unit, unit_price, reorder_point, last_updated 
+    SELECT ingredient_id, store_id, name, quantity, unit, unit_price, reorder_point, last_updated 
     FROM inventory 
     WHERE ingredient_id = $1;
     `
 
 	err := repo.db.QueryRowContext(ctx, query, id).Scan(
 		&inv.IngredientID,
+		&inv.StoreID,
 		&inv.Name,
 		&inv.Quantity,
 		&inv.Unit,
@@ -97,9 +105,11 @@ func (repo *InventoryRepository) DeleteInventory(ctx context.Context, id string)
 	FROM inventory
 	WHERE ingredient_id = $1;
 	`
-	_, err := repo.db.QueryContext(ctx, query, id)
+	if _, err := repo.db.ExecContext(ctx, query, id); err != nil {
+		return "", inUse(err)
+	}
 
-	return id, err
+	return id, nil
 }
 
 func (repo *InventoryRepository) UpdateInventory(ctx context.Context, updates map[string]interface{}, id string) (string, error) {
@@ -140,114 +150,103 @@ func (repo *InventoryRepository) UpdateInventory(ctx context.Context, updates ma
 func (repo *InventoryRepository) CreateInventoryTransaction(ctx context.Context, transaction entity.InventoryTransaction) error {
 	query := `
         INSERT INTO inventory_transactions 
-        (ingredient_id, quantity_change, transaction_type, reason) 
-        VALUES ($1, $2, $3, $4);
+        (ingredient_id, quantity_change, transaction_type, reason, transfer_id) 
+        VALUES ($1, $2, $3, $4, $5);
     `
 	_, err := repo.db.ExecContext(ctx, query,
 		transaction.IngredientID,
 		transaction.QuantityChange,
 		transaction.TransactionType,
-		transaction.Reason)
+		transaction.Reason,
+		transaction.TransferID)
 
 	return err
 }
 
-func (repo *InventoryRepository) GetInventoryTransactions(ctx context.Context, ingredientID string) ([]entity.InventoryTransaction, error) {
-	var transactions []entity.InventoryTransaction
-
-	query := `
-        SELECT transaction_id, ingredient_id, quantity_change, transaction_type, reason, created_at
-        FROM inventory_transactions
-        WHERE ingredient_id = $1
-        ORDER BY created_at DESC
-    `
+// transactionListing pages through the stock movements of an ingredient, latest first unless
+// asked otherwise
+var transactionListing = listing{
+	id: "transaction_id",
+	sorts: map[string]sortKey{
+		"created_at":      {column: "created_at", cast: "timestamptz"},
+		"quantity_change": {column: "quantity_change", cast: "numeric"},
+	},
+	defaultSort: "-created_at",
+	filters: map[filterField]string{
+		filterDate:            "created_at",
+		filterTransactionType: "transaction_type",
+	},
+}
 
-	rows, err := repo.db.QueryContext(ctx, query, ingredientID)
+// ListInventoryTransactions reads a page of the stock movements of an ingredient
+func (repo *InventoryRepository) ListInventoryTransactions(ctx context.Context, ingredientID string, q list.Query) ([]entity.InventoryTransaction, list.Page, error) {
+	var transactions []entity.InventoryTransaction
+	columns := `transaction_id, ingredient_id, quantity_change, transaction_type, reason, transfer_id, created_at`
+
+	page, err := transactionListing.page(ctx, repo.db, columns, "inventory_transactions", " AND ingredient_id = $1", []interface{}{ingredientID}, q,
+		func(rows *sql.Rows, keys ...interface{}) error {
+			var tx entity.InventoryTransaction
+			if err := rows.Scan(append([]interface{}{
+				&tx.TransactionID,
+				&tx.IngredientID,
+				&tx.QuantityChange,
+				&tx.TransactionType,
+				&tx.Reason,
+				&tx.TransferID,
+				&tx.CreatedAt,
+			}, keys...)...); err != nil {
+				return err
+			}
+			transactions = append(transactions, tx)
+			return nil
+		})
 	if err != nil {
-		return nil, err
-	}
-	defer rows.Close()
-
-	for rows.Next() {
-		var tx entity.InventoryTransaction
-		if err := rows.Scan(
-			&tx.TransactionID,
-			&tx.IngredientID,
-			&tx.QuantityChange,
-			&tx.TransactionType,
-			&tx.Reason,
-			&tx.CreatedAt,
-		); err != nil {
-			return nil, err
-		}
-		transactions = append(transactions, tx)
-	}
-
-	if err := rows.Err(); err != nil {
-		return nil, err
+		return nil, list.Page{}, fmt.Errorf("list inventory transactions: %w", err)
 	}
-
-	return transactions, nil
+	return transactions, page, nil
 }
 
-func (repo *InventoryRepository) GetLeftOvers(ctx context.Context, sortBy string, page, pageSize int) ([]entity.Inventory, int, error) {
-	// Build the query with sorting and pagination
-	queryBuilder := strings.Builder{}
-	queryBuilder.WriteString(`
-		SELECT ingredient_id, name, quantity, unit, unit_price, reorder_point, last_updated
-		FROM inventory
-	`)
-
-	// Add sorting
-	switch sortBy {
-	case "price":
-		queryBuilder.WriteString(" ORDER BY unit_price DESC")
-	case "quantity":
-		queryBuilder.WriteString(" ORDER BY quantity DESC")
-	default:
-		queryBuilder.WriteString(" ORDER BY name")
-	}
-
-	// Add pagination
-	offset := (page - 1) * pageSize
-	queryBuilder.WriteString(" LIMIT $1 OFFSET $2")
-
-	// Execute the query to get items
-	rows, err := repo.db.QueryContext(ctx, queryBuilder.String(), pageSize, offset)
-	if err != nil {
-		return nil, 0, err
-	}
-	defer rows.Close()
+// inventoryListing pages through the stock of a store, by name unless asked otherwise
+var inventoryListing = listing{
+	id: "ingredient_id",
+	sorts: map[string]sortKey{
+		"name":         {column: "name", cast: "text"},
+		"quantity":     {column: "quantity", cast: "numeric"},
+		"price":        {column: "unit_price", cast: "numeric"},
+		"last_updated": {column: "last_updated", cast: "timestamptz"},
+	},
+	defaultSort: "name",
+	filters: map[filterField]string{
+		filterDate:  "last_updated",
+		filterPrice: "unit_price",
+	},
+}
 
-	// Parse the inventory items
+// ListInventory reads a page of the stock of a store, or of every store when storeID is empty
+func (repo *InventoryRepository) ListInventory(ctx context.Context, storeID string, q list.Query) ([]entity.Inventory, list.Page, error) {
 	var inventories []entity.Inventory
-	for rows.Next() {
+	filter, args := storeFilter("store_id", storeID, nil)
+	columns := `ingredient_id, store_id, name, quantity, unit, unit_price, reorder_point, last_updated`
+
+	page, err := inventoryListing.page(ctx, repo.db, columns, "inventory", filter, args, q, func(rows *sql.Rows, keys ...interface{}) error {
 		var inv entity.Inventory
-		if err := rows.Scan(
+		if err := rows.Scan(append([]interface{}{
 			&inv.IngredientID,
+			&inv.StoreID,
 			&inv.Name,
 			&inv.Quantity,
 			&inv.Unit,
 			&inv.UnitPrice,
 			&inv.ReorderPoint,
 			&inv.LastUpdated,
-		); err != nil {
-			return nil, 0, err
+		}, keys...)...); err != nil {
+			return err
 		}
 		inventories = append(inventories, inv)
-	}
-
-	if err := rows.Err(); err != nil {
-		return nil, 0, err
-	}
-
-	// Get total count for pagination info
-	var totalCount int
-	countQuery := "SELECT COUNT(*) FROM inventory"
-	err = repo.db.QueryRowContext(ctx, countQuery).Scan(&totalCount)
+		return nil
+	})
 	if err != nil {
-		return nil, 0, err
+		return nil, list.Page{}, fmt.Errorf("list inventory: %w", err)
 	}
-
-	return inventories, totalCount, nil
+	return inventories, page, nil
 }
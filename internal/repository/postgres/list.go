@@ -0,0 +1,182 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"frappuccino/internal/apperror"
+	"frappuccino/internal/dto/list"
+
+	"github.com/lib/pq"
+)
+
+// listing describes how the list queries of a table map onto its columns. Every list reads its
+// pages by keyset, ordered by a whitelisted sort key and then by the ID of the rows, so that a
+// cursor, the sort value and ID of the last row of a page, says where the next page starts.
+type listing struct {
+	// id is the UUID column that orders rows with the same sort value
+	id string
+	// sorts are the sort keys clients may use, by name
+	sorts map[string]sortKey
+	// defaultSort is the sort of queries that name none, like -created_at
+	defaultSort string
+	// filters are the columns the filters of a query apply to; a list rejects the others
+	filters map[filterField]string
+}
+
+// sortKey is a column a list can be sorted by. The column must not be NULL, as rows are
+// compared with the cursor by value.
+type sortKey struct {
+	column string
+	// cast is the type of the column, which the value of a cursor is read back as
+	cast string
+}
+
+// filterField names a filter of list.Filter
+type filterField string
+
+const (
+	filterStatus          filterField = "status"
+	filterDate            filterField = "date"
+	filterCustomer        filterField = "customer"
+	filterCategory        filterField = "category"
+	filterPrice           filterField = "price"
+	filterTransactionType filterField = "transaction_type"
+)
+
+// pageScanner reads a row of a list. The page query selects the sort value and the ID of each
+// row after the columns of the list, so keys must be passed to rows.Scan after the destinations
+// of the row.
+type pageScanner func(rows *sql.Rows, keys ...interface{}) error
+
+// page reads the page q asks for, along with the number of rows matching its filters. columns is
+// the select list of the rows, from what follows FROM, joins included, and where conditions that
+// apply before the filters, each starting with AND, with args their arguments.
+func (l listing) page(ctx context.Context, db *sql.DB, columns, from, where string, args []interface{}, q list.Query, scan pageScanner) (list.Page, error) {
+	sortName := q.Sort
+	if sortName == "" {
+		sortName = l.defaultSort
+	}
+	desc := strings.HasPrefix(sortName, "-")
+	key, ok := l.sorts[strings.TrimPrefix(sortName, "-")]
+	if !ok {
+		return list.Page{}, list.ErrInvalidSort.WithFields(apperror.Field("sort", "sort must be one of "+l.sortNames()))
+	}
+
+	where, args, err := l.filter(q.Filter, where, args)
+	if err != nil {
+		return list.Page{}, err
+	}
+
+	var total int
+	if err := db.QueryRowContext(ctx, "SELECT COUNT(*) FROM "+from+" WHERE TRUE"+where, args...).Scan(&total); err != nil {
+		return list.Page{}, fmt.Errorf("count rows: %w", err)
+	}
+
+	op, direction := ">", "ASC"
+	if desc {
+		op, direction = "<", "DESC"
+	}
+	if q.Cursor != "" {
+		cursor, err := list.DecodeCursor(q.Cursor, sortName)
+		if err != nil {
+			return list.Page{}, err
+		}
+		args = append(args, cursor.Value, cursor.ID)
+		where += fmt.Sprintf(" AND (%s, %s) %s ($%d::%s, $%d::uuid)", key.column, l.id, op, len(args)-1, key.cast, len(args))
+	}
+
+	// One row more than the page holds tells whether there is a next page
+	limit := q.PageSize()
+	args = append(args, limit+1)
+	query := fmt.Sprintf(`SELECT %s, (%s)::text, %s::text FROM %s WHERE TRUE%s ORDER BY %s %s, %s %s LIMIT $%d`,
+		columns, key.column, l.id, from, where, key.column, direction, l.id, direction, len(args))
+
+	rows, err := db.QueryContext(ctx, query, args...)
+	if err != nil {
+		if q.Cursor != "" && invalidCursorValue(err) {
+			return list.Page{}, list.ErrInvalidCursor
+		}
+		return list.Page{}, fmt.Errorf("query page: %w", err)
+	}
+	defer rows.Close()
+
+	page := list.Page{TotalCount: total}
+	var value, id string
+	for n := 0; rows.Next(); n++ {
+		if n == limit {
+			page.NextCursor = list.Cursor{Sort: sortName, Value: value, ID: id}.Encode()
+			break
+		}
+		if err := scan(rows, &value, &id); err != nil {
+			return list.Page{}, fmt.Errorf("scan row: %w", err)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return list.Page{}, fmt.Errorf("iterate rows: %w", err)
+	}
+	return page, nil
+}
+
+// invalidCursorValue tells whether err is Postgres refusing the value or ID of a cursor as the
+// type of its column. Cursors are not signed, so a client may have edited them; the values the
+// filters add are typed already and cannot cause these errors.
+func invalidCursorValue(err error) bool {
+	var pqErr *pq.Error
+	return errors.As(err, &pqErr) && pqErr.Code.Class() == "22" // data exception
+}
+
+// filter adds the conditions of the filters f sets to where
+func (l listing) filter(f list.Filter, where string, args []interface{}) (string, []interface{}, error) {
+	var err error
+	add := func(field filterField, condition string, value interface{}) {
+		column, ok := l.filters[field]
+		if !ok {
+			if err == nil {
+				err = list.ErrInvalidFilter.WithFields(apperror.Field(string(field), fmt.Sprintf("this list cannot be filtered by %s", field)))
+			}
+			return
+		}
+		args = append(args, value)
+		where += " AND " + fmt.Sprintf(condition, column, len(args))
+	}
+
+	if f.Status != "" {
+		add(filterStatus, "%s::text = $%d", f.Status)
+	}
+	if f.From != nil {
+		add(filterDate, "%s >= $%d", *f.From)
+	}
+	if f.To != nil {
+		add(filterDate, "%s < $%d::timestamptz + INTERVAL '1 day'", *f.To)
+	}
+	if f.Customer != "" {
+		add(filterCustomer, "%s ILIKE '%%' || $%d::text || '%%'", f.Customer)
+	}
+	if f.Category != "" {
+		add(filterCategory, "$%[2]d = ANY(%[1]s)", f.Category)
+	}
+	if f.MinPrice != nil {
+		add(filterPrice, "%s >= $%d", *f.MinPrice)
+	}
+	if f.MaxPrice != nil {
+		add(filterPrice, "%s <= $%d", *f.MaxPrice)
+	}
+	if f.TransactionType != "" {
+		add(filterTransactionType, "%s::text = $%d", f.TransactionType)
+	}
+	return where, args, err
+}
+
+func (l listing) sortNames() string {
+	names := make([]string, 0, len(l.sorts))
+	for name := range l.sorts {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return strings.Join(names, ", ")
+}
@@ -0,0 +1,32 @@
+package postgres
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/lib/pq"
+)
+
+func TestInvalidCursorValue(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"value edited to another type", &pq.Error{Code: "22007", Message: `invalid input syntax for type timestamp with time zone: "yesterday-ish"`}, true},
+		{"ID edited to a non-UUID", &pq.Error{Code: "22P02", Message: `invalid input syntax for type uuid: "42"`}, true},
+		{"number out of range", fmt.Errorf("query: %w", &pq.Error{Code: "22003"}), true},
+		{"lost connection", &pq.Error{Code: "08006"}, false},
+		{"missing column", &pq.Error{Code: "42703"}, false},
+		{"not a database error", errors.New("context canceled"), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := invalidCursorValue(tt.err); got != tt.want {
+				t.Errorf("invalidCursorValue(%v) = %v, want %v", tt.err, got, tt.want)
+			}
+		})
+	}
+}
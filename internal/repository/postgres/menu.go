@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"strings"
 
+	"frappuccino/internal/dto/list"
 	"frappuccino/internal/entity"
 
 	"github.com/lib/pq"
@@ -50,24 +51,34 @@ func (repo *MenuRepository) CreateMenuItem(ctx context.Context, menuItem entity.
 	return ID, err
 }
 
-func (repo *MenuRepository) GetMenuItem(ctx context.Context) ([]entity.MenuItem, error) {
+// storeMenuFields and storeMenuFrom read a menu item with the price and availability of the
+// store in $1; for a NULL store they are the menu price and available
+const (
+	storeMenuFields = `
+		m.menu_item_id, 
+		m.name, 
+		m.description, 
+		COALESCE(smi.price, m.price), 
+		COALESCE(smi.is_available, TRUE), 
+		m.categories, 
+		m.allergens, 
+		m.size, 
+		m.customization_options, 
+		m.updated_at`
+	storeMenuFrom = `menu_items m
+	LEFT JOIN store_menu_items smi ON smi.menu_item_id = m.menu_item_id AND smi.store_id = $1`
+	storeMenuColumns = storeMenuFields + `
+	FROM ` + storeMenuFrom
+)
+
+// GetMenuItem lists the menu as sold at a store, or at menu prices when storeID is empty
+func (repo *MenuRepository) GetMenuItem(ctx context.Context, storeID string) ([]entity.MenuItem, error) {
 	var menuItems []entity.MenuItem
-	query := `
-	SELECT 
-		menu_item_id, 
-		name, 
-		description, 
-		price, 
-		categories, 
-		allergens, 
-		size, 
-		customization_options, 
-		updated_at
-	FROM menu_items
-	ORDER BY name
+	query := `SELECT ` + storeMenuColumns + `
+	ORDER BY m.name
 	`
 
-	rows, err := repo.db.QueryContext(ctx, query)
+	rows, err := repo.db.QueryContext(ctx, query, nullIfEmpty(storeID))
 	if err != nil {
 		return nil, err
 	}
@@ -84,6 +95,7 @@ func (repo *MenuRepository) GetMenuItem(ctx context.Context) ([]entity.MenuItem,
 			&menu.Name,
 			&menu.Description,
 			&menu.Price,
+			&menu.IsAvailable,
 			pq.Array(&categories), // Use pq.Array for scanning array types
 			pq.Array(&allergens),  // Use pq.Array for scanning array types
 			&menu.Size,
@@ -107,29 +119,66 @@ func (repo *MenuRepository) GetMenuItem(ctx context.Context) ([]entity.MenuItem,
 	return menuItems, nil
 }
 
-func (repo *MenuRepository) GetMenuByID(ctx context.Context, id string) (entity.MenuItem, error) {
+// menuListing pages through the menu as sold at a store, by name unless asked otherwise
+var menuListing = listing{
+	id: "m.menu_item_id",
+	sorts: map[string]sortKey{
+		"name":       {column: "m.name", cast: "text"},
+		"price":      {column: "COALESCE(smi.price, m.price)", cast: "numeric"},
+		"updated_at": {column: "m.updated_at", cast: "timestamptz"},
+	},
+	defaultSort: "name",
+	filters: map[filterField]string{
+		filterCategory: "m.categories",
+		filterPrice:    "COALESCE(smi.price, m.price)",
+	},
+}
+
+// ListMenuItems reads a page of the menu as sold at a store, or at menu prices when storeID is empty
+func (repo *MenuRepository) ListMenuItems(ctx context.Context, storeID string, q list.Query) ([]entity.MenuItem, list.Page, error) {
+	var menuItems []entity.MenuItem
+	page, err := menuListing.page(ctx, repo.db, storeMenuFields, storeMenuFrom, "", []interface{}{nullIfEmpty(storeID)}, q,
+		func(rows *sql.Rows, keys ...interface{}) error {
+			var menu entity.MenuItem
+			var categories, allergens []string
+			if err := rows.Scan(append([]interface{}{
+				&menu.MenuItemID,
+				&menu.Name,
+				&menu.Description,
+				&menu.Price,
+				&menu.IsAvailable,
+				pq.Array(&categories),
+				pq.Array(&allergens),
+				&menu.Size,
+				&menu.CustomizationOptions,
+				&menu.UpdatedAt,
+			}, keys...)...); err != nil {
+				return err
+			}
+			menu.Categories = categories
+			menu.Allergens = allergens
+			menuItems = append(menuItems, menu)
+			return nil
+		})
+	if err != nil {
+		return nil, list.Page{}, fmt.Errorf("list menu items: %w", err)
+	}
+	return menuItems, page, nil
+}
+
+func (repo *MenuRepository) GetMenuByID(ctx context.Context, storeID, id string) (entity.MenuItem, error) {
 	var menu entity.MenuItem
-	query := `
-    	SELECT 
-		menu_item_id, 
-		name, 
-		description, 
-		price, 
-		categories, 
-		allergens, 
-		size, 
-		customization_options, 
-		updated_at
-	FROM menu_items
-    WHERE menu_item_id = $1;
+	query := `SELECT ` + storeMenuColumns + `
+    WHERE m.menu_item_id = $2;
     `
 	var categories, allergens []string
 
-	err := repo.db.QueryRowContext(ctx, query, id).Scan(
+	err := repo.db.QueryRowContext(ctx, query, nullIfEmpty(storeID), id).Scan(
 		&menu.MenuItemID,
 		&menu.Name,
 		&menu.Description,
 		&menu.Price,
+		&menu.IsAvailable,
 		pq.Array(&categories), // Use pq.Array for scanning array types
 		pq.Array(&allergens),  // Use pq.Array for scanning array types
 		&menu.Size,
@@ -146,9 +195,11 @@ func (repo *MenuRepository) DeleteMenu(ctx context.Context, id string) (string,
  	FROM menu_items
 	WHERE menu_item_id = $1;
  	`
-	_, err := repo.db.QueryContext(ctx, query, id)
+	if _, err := repo.db.ExecContext(ctx, query, id); err != nil {
+		return "", inUse(err)
+	}
 
-	return id, err
+	return id, nil
 }
 
 func (r *MenuRepository) UpdateMenu(ctx context.Context, updates map[string]interface{}, id string) (string, error) {
@@ -227,62 +278,77 @@ func (r *MenuRepository) CreateMenuItemIngredients(ctx context.Context, menuItem
 	}
 	return nil
 }
-func (repo *MenuRepository) GetAllPriceHistory(ctx context.Context) ([]entity.PriceHistory, error) {
+
+// priceHistoryListing pages through price changes, latest first unless asked otherwise
+var priceHistoryListing = listing{
+	id: "id",
+	sorts: map[string]sortKey{
+		"changed_at": {column: "changed_at", cast: "timestamptz"},
+		"new_price":  {column: "new_price", cast: "numeric"},
+	},
+	defaultSort: "-changed_at",
+	filters: map[filterField]string{
+		filterDate:  "changed_at",
+		filterPrice: "new_price",
+	},
+}
+
+// ListPriceHistory reads a page of the menu price changes and those of a store, or of every
+// store when storeID is empty
+func (repo *MenuRepository) ListPriceHistory(ctx context.Context, storeID string, q list.Query) ([]entity.PriceHistory, list.Page, error) {
 	var priceHistory []entity.PriceHistory
-	query := `
-    SELECT 
+	columns := `
         id,
         menu_item_id,
+        store_id,
         old_price,
         new_price,
         changed_at,
-        change_reason
-    FROM price_history
-    ORDER BY changed_at DESC
-    `
-
-	rows, err := repo.db.QueryContext(ctx, query)
+        change_reason`
+	where := ` AND ($1::uuid IS NULL OR store_id IS NULL OR store_id = $1)`
+
+	page, err := priceHistoryListing.page(ctx, repo.db, columns, "price_history", where, []interface{}{nullIfEmpty(storeID)}, q,
+		func(rows *sql.Rows, keys ...interface{}) error {
+			var history entity.PriceHistory
+			if err := rows.Scan(append([]interface{}{
+				&history.ID,
+				&history.MenuItemID,
+				&history.StoreID,
+				&history.OldPrice,
+				&history.NewPrice,
+				&history.ChangedAt,
+				&history.ChangeReason,
+			}, keys...)...); err != nil {
+				return err
+			}
+			priceHistory = append(priceHistory, history)
+			return nil
+		})
 	if err != nil {
-		return nil, err
-	}
-	defer rows.Close()
-
-	for rows.Next() {
-		var history entity.PriceHistory
-
-		if err := rows.Scan(
-			&history.ID,
-			&history.MenuItemID,
-			&history.OldPrice,
-			&history.NewPrice,
-			&history.ChangedAt,
-			&history.ChangeReason,
-		); err != nil {
-			return nil, err
-		}
-
-		priceHistory = append(priceHistory, history)
-	}
-
-	if err := rows.Err(); err != nil {
-		return nil, err
+		return nil, list.Page{}, fmt.Errorf("list price history: %w", err)
 	}
-
-	return priceHistory, nil
+	return priceHistory, page, nil
 }
-func (repo *MenuRepository) GetMenuItemIngredients(ctx context.Context, menuItemID string) ([]entity.MenuItemIngredient, error) {
+
+// GetMenuItemIngredients returns the recipe of a menu item with the ingredient rows of a store,
+// matched by name. An ingredient the store does not stock has an empty IngredientID. With an
+// empty storeID the rows the recipe was written with are returned.
+func (repo *MenuRepository) GetMenuItemIngredients(ctx context.Context, storeID, menuItemID string) ([]entity.MenuItemIngredient, error) {
 	query := `
 		SELECT 
-			menu_item_ingr_id, 
-			menu_item_id, 
-			ingredient_id, 
-			quantity, 
-			unit
-		FROM menu_item_ingredients
-		WHERE menu_item_id = $1
+			mii.menu_item_ingr_id, 
+			mii.menu_item_id, 
+			CASE WHEN $2::uuid IS NULL THEN i.ingredient_id::text ELSE COALESCE(local.ingredient_id::text, '') END, 
+			i.name, 
+			mii.quantity, 
+			mii.unit
+		FROM menu_item_ingredients mii
+		JOIN inventory i ON i.ingredient_id = mii.ingredient_id
+		LEFT JOIN inventory local ON local.name = i.name AND local.store_id = $2
+		WHERE mii.menu_item_id = $1
 	`
 
-	rows, err := repo.db.QueryContext(ctx, query, menuItemID)
+	rows, err := repo.db.QueryContext(ctx, query, menuItemID, nullIfEmpty(storeID))
 	if err != nil {
 		return nil, fmt.Errorf("failed to query menu item ingredients: %w", err)
 	}
@@ -295,6 +361,7 @@ func (repo *MenuRepository) GetMenuItemIngredients(ctx context.Context, menuItem
 			&ingredient.ID,
 			&ingredient.MenuItemID,
 			&ingredient.IngredientID,
+			&ingredient.Name,
 			&ingredient.Quantity,
 			&ingredient.Unit,
 		)
@@ -310,3 +377,77 @@ func (repo *MenuRepository) GetMenuItemIngredients(ctx context.Context, menuItem
 
 	return ingredients, nil
 }
+
+// SetStoreMenuItem sets the price and availability of a menu item at a store. A change of the
+// price the store sells at is recorded in the price history of the store.
+// SetStoreMenuItem stores the override of a menu item at a store and records a change of the
+// price the store sells it for. It returns sql.ErrNoRows if there is no such menu item.
+func (repo *MenuRepository) SetStoreMenuItem(ctx context.Context, item entity.StoreMenuItem) error {
+	tx, err := repo.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("begin tx: %w", err)
+	}
+	defer tx.Rollback()
+
+	var oldPrice float64
+	err = tx.QueryRowContext(ctx, `
+		SELECT COALESCE(smi.price, m.price)
+		FROM menu_items m
+		LEFT JOIN store_menu_items smi ON smi.menu_item_id = m.menu_item_id AND smi.store_id = $2
+		WHERE m.menu_item_id = $1
+		FOR UPDATE OF m
+	`, item.MenuItemID, item.StoreID).Scan(&oldPrice)
+	if err != nil {
+		return err
+	}
+
+	var newPrice float64
+	err = tx.QueryRowContext(ctx, `
+		WITH upserted AS (
+			INSERT INTO store_menu_items (store_id, menu_item_id, price, is_available, updated_at)
+			VALUES ($1, $2, $3, $4, CURRENT_TIMESTAMP)
+			ON CONFLICT (store_id, menu_item_id) DO UPDATE
+			SET price = EXCLUDED.price, is_available = EXCLUDED.is_available, updated_at = EXCLUDED.updated_at
+			RETURNING price
+		)
+		SELECT COALESCE(u.price, m.price)
+		FROM upserted u, menu_items m
+		WHERE m.menu_item_id = $2
+	`, item.StoreID, item.MenuItemID, item.Price, item.IsAvailable).Scan(&newPrice)
+	if err != nil {
+		return fmt.Errorf("upsert store menu item: %w", err)
+	}
+
+	if newPrice != oldPrice {
+		_, err := tx.ExecContext(ctx, `
+			INSERT INTO price_history (menu_item_id, store_id, old_price, new_price, changed_at, change_reason)
+			VALUES ($1, $2, $3, $4, CURRENT_TIMESTAMP, $5)
+		`, item.MenuItemID, item.StoreID, oldPrice, newPrice, "store price update")
+		if err != nil {
+			return fmt.Errorf("insert price history: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("commit: %w", err)
+	}
+	return nil
+}
+
+// GetStoreMenuItem returns the override of a menu item at a store, or sql.ErrNoRows if it has none
+func (repo *MenuRepository) GetStoreMenuItem(ctx context.Context, storeID, menuItemID string) (entity.StoreMenuItem, error) {
+	var item entity.StoreMenuItem
+	query := `
+		SELECT store_id, menu_item_id, price, is_available, updated_at
+		FROM store_menu_items
+		WHERE store_id = $1 AND menu_item_id = $2
+	`
+	err := repo.db.QueryRowContext(ctx, query, storeID, menuItemID).Scan(
+		&item.StoreID,
+		&item.MenuItemID,
+		&item.Price,
+		&item.IsAvailable,
+		&item.UpdatedAt,
+	)
+	return item, err
+}
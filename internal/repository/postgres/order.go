@@ -9,10 +9,14 @@ import (
 	"strings"
 	"time"
 
+	"frappuccino/internal/dto/list"
 	"frappuccino/internal/dto/report"
 	"frappuccino/internal/entity"
 )
 
+// dateLayout formats DATE parameters so they do not depend on the session time zone
+const dateLayout = "2006-01-02"
+
 type OrderRepository struct {
 	db *sql.DB
 }
@@ -24,23 +28,45 @@ func NewOrderRepository(db *sql.DB) *OrderRepository {
 }
 
 // CreateOrder inserts order and order items inside a transaction.
-func (repo *OrderRepository) CreateOrder(ctx context.Context, order entity.Order, items []entity.OrderItem) (string, error) {
+func (repo *OrderRepository) CreateOrder(ctx context.Context, order entity.Order, items []entity.OrderItem) (string, int, error) {
+	return insertOrder(ctx, repo.db, order, items)
+}
+
+// insertOrder stores an order with the next order number of its business day, then its items.
+// Taking the number and inserting the order happen in one statement; the counter row lock makes
+// concurrent orders of the same day wait for each other, so numbers are never handed out twice.
+func insertOrder(ctx context.Context, q queryer, order entity.Order, items []entity.OrderItem) (string, int, error) {
 	var orderID string
+	var orderNumber int
 	orderQuery := `
-		INSERT INTO orders (customer_name, special_instructions, total_amount, status, created_at, updated_at)
-		VALUES ($1, $2, $3, $4, $5, $6)
-		RETURNING order_id;
+		WITH next_number AS (
+			INSERT INTO order_number_counters (store_id, business_date, last_number)
+			VALUES ($12, $11::DATE, 1)
+			ON CONFLICT (store_id, business_date) DO UPDATE
+			SET last_number = order_number_counters.last_number + 1
+			RETURNING last_number
+		)
+		INSERT INTO orders (customer_name, special_instructions, total_amount, status, pickup_at, order_type, table_id, delivery_fee, created_at, updated_at, business_date, store_id, order_number)
+		SELECT $1, $2, $3, $4, $5, COALESCE(NULLIF($6, ''), 'takeaway')::order_type, $7, $8, $9, $10, $11::DATE, $12, last_number
+		FROM next_number
+		RETURNING order_id, order_number;
 	`
-	err := repo.db.QueryRowContext(ctx, orderQuery,
+	err := q.QueryRowContext(ctx, orderQuery,
 		order.CustomerName,
 		order.SpecialInstructions,
 		order.TotalAmount,
 		order.Status,
+		order.PickupAt,
+		order.OrderType,
+		order.TableID,
+		order.DeliveryFee,
 		order.CreatedAt,
 		order.UpdatedAt,
-	).Scan(&orderID)
+		order.BusinessDate.Format(dateLayout),
+		order.StoreID,
+	).Scan(&orderID, &orderNumber)
 	if err != nil {
-		return "", fmt.Errorf("insert order: %w", err)
+		return "", 0, fmt.Errorf("insert order: %w", err)
 	}
 
 	itemQuery := `
@@ -48,7 +74,7 @@ func (repo *OrderRepository) CreateOrder(ctx context.Context, order entity.Order
 		VALUES ($1, $2, $3, $4, $5)
 	`
 	for _, item := range items {
-		_, err := repo.db.ExecContext(ctx, itemQuery,
+		_, err := q.ExecContext(ctx, itemQuery,
 			orderID,
 			item.MenuItemID,
 			item.Quantity,
@@ -56,50 +82,76 @@ func (repo *OrderRepository) CreateOrder(ctx context.Context, order entity.Order
 			item.Customizations,
 		)
 		if err != nil {
-			return "", fmt.Errorf("insert order item: %w", err)
+			return "", 0, fmt.Errorf("insert order item: %w", err)
 		}
 	}
 
-	return orderID, nil
+	return orderID, orderNumber, nil
 }
 
-// GetMenuItemPrice gets current price of the menu item
-func (repo *OrderRepository) GetMenuItemPrice(ctx context.Context, menuItemID string) (float64, error) {
+// GetOrderIDByNumber finds the order that a store gave a number on a business day
+func (repo *OrderRepository) GetOrderIDByNumber(ctx context.Context, storeID string, businessDate time.Time, orderNumber int) (string, error) {
+	var orderID string
+	query := `SELECT order_id FROM orders WHERE store_id = $1 AND business_date = $2::DATE AND order_number = $3`
+	err := repo.db.QueryRowContext(ctx, query, storeID, businessDate.Format(dateLayout), orderNumber).Scan(&orderID)
+	return orderID, err
+}
+
+// GetMenuItemPrice gets the current price of the menu item at a store and whether the store sells it
+func (repo *OrderRepository) GetMenuItemPrice(ctx context.Context, storeID, menuItemID string) (float64, bool, error) {
 	var price float64
-	query := `SELECT price FROM menu_items WHERE menu_item_id = $1`
+	var available bool
+	query := `
+		SELECT COALESCE(smi.price, m.price), COALESCE(smi.is_available, TRUE)
+		FROM menu_items m
+		LEFT JOIN store_menu_items smi ON smi.menu_item_id = m.menu_item_id AND smi.store_id = $2
+		WHERE m.menu_item_id = $1
+	`
 
-	err := repo.db.QueryRowContext(ctx, query, menuItemID).Scan(&price)
+	err := repo.db.QueryRowContext(ctx, query, menuItemID, storeID).Scan(&price, &available)
 	if err != nil {
-		return 0, fmt.Errorf("get price: %w", err)
+		return 0, false, fmt.Errorf("get price: %w", err)
 	}
 
-	return price, nil
+	return price, available, nil
 }
 
 func (repo *OrderRepository) GetOrderByID(ctx context.Context, orderID string) (entity.Order, error) {
 	var o entity.Order
 	query := `
-	SELECT order_id, customer_name, special_instructions, total_amount, status, created_at, updated_at
+	SELECT order_id, store_id, order_number, business_date, customer_name, special_instructions, total_amount, status, pickup_at, order_type, table_id, delivery_fee, created_at, updated_at
 	FROM orders
 	WHERE order_id = $1;
 	`
+	var pickupAt sql.NullTime
+	var tableID sql.NullString
 	err := repo.db.QueryRowContext(ctx, query, orderID).Scan(
 		&o.OrderID,
+		&o.StoreID,
+		&o.OrderNumber,
+		&o.BusinessDate,
 		&o.CustomerName,
 		&o.SpecialInstructions,
 		&o.TotalAmount,
 		&o.Status,
+		&pickupAt,
+		&o.OrderType,
+		&tableID,
+		&o.DeliveryFee,
 		&o.CreatedAt,
 		&o.UpdatedAt,
 	)
+	o.PickupAt = nullTimePtr(pickupAt)
+	o.TableID = nullStringPtr(tableID)
 	return o, err
 }
 
 func (repo *OrderRepository) GetOrderItemsByOrderID(ctx context.Context, orderID string) ([]entity.OrderItem, error) {
 	query := `
-	SELECT menu_item_id, quantity, price_at_time, customizations
-	FROM order_items
-	WHERE order_id = $1;
+	SELECT oi.order_item_id, oi.order_id, oi.menu_item_id, m.name, oi.quantity, oi.price_at_time, oi.customizations
+	FROM order_items oi
+	JOIN menu_items m ON m.menu_item_id = oi.menu_item_id
+	WHERE oi.order_id = $1;
 	`
 
 	rows, err := repo.db.QueryContext(ctx, query, orderID)
@@ -112,49 +164,80 @@ func (repo *OrderRepository) GetOrderItemsByOrderID(ctx context.Context, orderID
 	var customizationsNullable sql.NullString
 	for rows.Next() {
 		var i entity.OrderItem
-		if err := rows.Scan(&i.MenuItemID, &i.Quantity, &i.PriceAtTime, &customizationsNullable); err != nil {
+		if err := rows.Scan(&i.OrderItemID, &i.OrderID, &i.MenuItemID, &i.Name, &i.Quantity, &i.PriceAtTime, &customizationsNullable); err != nil {
 			return nil, err
 		}
+		if customizationsNullable.Valid {
+			i.Customizations = json.RawMessage(customizationsNullable.String)
+		}
 		items = append(items, i)
 	}
 	return items, rows.Err()
 }
 
-func (repo *OrderRepository) GetAllOrders(ctx context.Context) ([]entity.Order, error) {
+// orderListing pages through orders, newest first unless asked otherwise
+var orderListing = listing{
+	id: "order_id",
+	sorts: map[string]sortKey{
+		"created_at":    {column: "created_at", cast: "timestamptz"},
+		"order_number":  {column: "order_number", cast: "integer"},
+		"total_amount":  {column: "total_amount", cast: "numeric"},
+		"customer_name": {column: "customer_name", cast: "text"},
+	},
+	defaultSort: "-created_at",
+	filters: map[filterField]string{
+		filterStatus:   "status",
+		filterDate:     "created_at",
+		filterCustomer: "customer_name",
+		filterPrice:    "total_amount",
+	},
+}
+
+// ListOrders reads a page of the orders of a store, or of every store when storeID is empty
+func (repo *OrderRepository) ListOrders(ctx context.Context, storeID string, q list.Query) ([]entity.Order, list.Page, error) {
 	var orders []entity.Order
-	query := `
-        SELECT
+	filter, args := storeFilter("store_id", storeID, nil)
+	columns := `
             order_id,
+            store_id,
+            order_number,
+            business_date,
             customer_name,
             special_instructions,
             total_amount,
             status,
+            pickup_at,
+            order_type,
+            table_id,
+            delivery_fee,
             created_at,
-            updated_at
-        FROM orders
-        ORDER BY created_at DESC
-    `
+            updated_at`
 
-	rows, err := repo.db.QueryContext(ctx, query)
-	if err != nil {
-		return nil, err
-	}
-	defer rows.Close()
-	var specialInstructionsNullable sql.NullString
-
-	for rows.Next() {
+	page, err := orderListing.page(ctx, repo.db, columns, "orders", filter, args, q, func(rows *sql.Rows, keys ...interface{}) error {
 		var order entity.Order
-		if err := rows.Scan(
+		var specialInstructionsNullable sql.NullString
+		var pickupAt sql.NullTime
+		var tableID sql.NullString
+		if err := rows.Scan(append([]interface{}{
 			&order.OrderID,
+			&order.StoreID,
+			&order.OrderNumber,
+			&order.BusinessDate,
 			&order.CustomerName,
 			&specialInstructionsNullable,
 			&order.TotalAmount,
 			&order.Status,
+			&pickupAt,
+			&order.OrderType,
+			&tableID,
+			&order.DeliveryFee,
 			&order.CreatedAt,
 			&order.UpdatedAt,
-		); err != nil {
-			return nil, err
+		}, keys...)...); err != nil {
+			return err
 		}
+		order.PickupAt = nullTimePtr(pickupAt)
+		order.TableID = nullStringPtr(tableID)
 		if specialInstructionsNullable.Valid {
 			order.SpecialInstructions = json.RawMessage(specialInstructionsNullable.String)
 		} else {
@@ -162,13 +245,12 @@ func (repo *OrderRepository) GetAllOrders(ctx context.Context) ([]entity.Order,
 		}
 
 		orders = append(orders, order)
+		return nil
+	})
+	if err != nil {
+		return nil, list.Page{}, fmt.Errorf("list orders: %w", err)
 	}
-
-	if err := rows.Err(); err != nil {
-		return nil, err
-	}
-
-	return orders, nil
+	return orders, page, nil
 }
 
 func (repo *OrderRepository) UpdateOrder(ctx context.Context, orderID string, updates map[string]interface{}) error {
@@ -251,47 +333,53 @@ func (repo *OrderRepository) UpdateOrder(ctx context.Context, orderID string, up
 	return nil
 }
 
-// Add to OrderRepository
-func (repo *OrderRepository) GetAllOrderStatusHistory(ctx context.Context) ([]entity.OrderStatusHistory, error) {
-	query := `
-        SELECT 
-            order_status_id, 
-            order_id, 
-            old_status, 
-            new_status, 
-            changed_at, 
-            change_reason
-        FROM order_status_history
-        ORDER BY changed_at DESC
-    `
-
-	rows, err := repo.db.QueryContext(ctx, query)
-	if err != nil {
-		return nil, fmt.Errorf("query all status history: %w", err)
-	}
-	defer rows.Close()
+// statusHistoryListing pages through status changes, latest first unless asked otherwise
+var statusHistoryListing = listing{
+	id: "h.order_status_id",
+	sorts: map[string]sortKey{
+		"changed_at": {column: "h.changed_at", cast: "timestamptz"},
+	},
+	defaultSort: "-changed_at",
+	filters: map[filterField]string{
+		filterStatus: "h.new_status",
+		filterDate:   "h.changed_at",
+	},
+}
+
+// ListOrderStatusHistory reads a page of the status changes of the orders of a store, or of
+// every store when storeID is empty
+func (repo *OrderRepository) ListOrderStatusHistory(ctx context.Context, storeID string, q list.Query) ([]entity.OrderStatusHistory, list.Page, error) {
+	filter, args := storeFilter("o.store_id", storeID, nil)
+	columns := `
+            h.order_status_id, 
+            h.order_id, 
+            h.old_status, 
+            h.new_status, 
+            h.changed_at, 
+            h.change_reason`
+	from := `order_status_history h
+        JOIN orders o ON o.order_id = h.order_id`
 
 	var history []entity.OrderStatusHistory
-	for rows.Next() {
+	page, err := statusHistoryListing.page(ctx, repo.db, columns, from, filter, args, q, func(rows *sql.Rows, keys ...interface{}) error {
 		var h entity.OrderStatusHistory
-		if err := rows.Scan(
+		if err := rows.Scan(append([]interface{}{
 			&h.OrderStatusID,
 			&h.OrderID,
 			&h.OldStatus,
 			&h.NewStatus,
 			&h.ChangedAt,
 			&h.ChangeReason,
-		); err != nil {
-			return nil, fmt.Errorf("scan status history: %w", err)
+		}, keys...)...); err != nil {
+			return err
 		}
 		history = append(history, h)
+		return nil
+	})
+	if err != nil {
+		return nil, list.Page{}, fmt.Errorf("list status history: %w", err)
 	}
-
-	if err := rows.Err(); err != nil {
-		return nil, fmt.Errorf("iterate status history: %w", err)
-	}
-
-	return history, nil
+	return history, page, nil
 }
 
 func (repo *OrderRepository) DeleteOrder(ctx context.Context, id string) (string, error) {
@@ -307,6 +395,7 @@ func (repo *OrderRepository) DeleteOrder(ctx context.Context, id string) (string
 
 func (repo *OrderRepository) GetNumberOfOrderedItems(
 	ctx context.Context,
+	storeID string,
 	startDate, endDate *time.Time,
 ) (map[string]int, error) {
 	// Initialize the base query
@@ -334,6 +423,11 @@ func (repo *OrderRepository) GetNumberOfOrderedItems(
 		args = append(args, endDatePlusDay)
 	}
 
+	if storeID != "" {
+		query += ` AND o.store_id = $` + repo.nextArgIndex(&argIndex)
+		args = append(args, storeID)
+	}
+
 	// Group by menu item name
 	query += `
 		GROUP BY m.name
@@ -375,7 +469,7 @@ func (repo *OrderRepository) nextArgIndex(index *int) string {
 	return string(rune('0' + current))
 }
 
-func (repo *OrderRepository) GetOrderedItemsByDay(ctx context.Context, month time.Month, year int) ([]report.DayCount, error) {
+func (repo *OrderRepository) GetOrderedItemsByDay(ctx context.Context, storeID string, month time.Month, year int) ([]report.DayCount, error) {
 	// Calculate the start and end dates for the given month and year
 	startDate := time.Date(year, month, 1, 0, 0, 0, 0, time.UTC)
 	endDate := startDate.AddDate(0, 1, 0)
@@ -391,13 +485,14 @@ func (repo *OrderRepository) GetOrderedItemsByDay(ctx context.Context, month tim
 		WHERE 
 			o.created_at >= $1 AND o.created_at < $2
 			AND o.status != 'cancelled'
+			AND ($3::uuid IS NULL OR o.store_id = $3)
 		GROUP BY 
 			EXTRACT(DAY FROM o.created_at)
 		ORDER BY 
 			day
 	`
 
-	rows, err := repo.db.QueryContext(ctx, query, startDate, endDate)
+	rows, err := repo.db.QueryContext(ctx, query, startDate, endDate, nullIfEmpty(storeID))
 	if err != nil {
 		return nil, err
 	}
@@ -420,7 +515,7 @@ func (repo *OrderRepository) GetOrderedItemsByDay(ctx context.Context, month tim
 }
 
 // GetOrderedItemsByMonth retrieves the number of items ordered by month within a specified year
-func (repo *OrderRepository) GetOrderedItemsByMonth(ctx context.Context, year int) ([]report.MonthCount, error) {
+func (repo *OrderRepository) GetOrderedItemsByMonth(ctx context.Context, storeID string, year int) ([]report.MonthCount, error) {
 	// Calculate the start and end dates for the given year
 	startDate := time.Date(year, 1, 1, 0, 0, 0, 0, time.UTC)
 	endDate := startDate.AddDate(1, 0, 0)
@@ -436,13 +531,14 @@ func (repo *OrderRepository) GetOrderedItemsByMonth(ctx context.Context, year in
 		WHERE 
 			o.created_at >= $1 AND o.created_at < $2
 			AND o.status != 'cancelled'
+			AND ($3::uuid IS NULL OR o.store_id = $3)
 		GROUP BY 
 			EXTRACT(MONTH FROM o.created_at)
 		ORDER BY 
 			month
 	`
 
-	rows, err := repo.db.QueryContext(ctx, query, startDate, endDate)
+	rows, err := repo.db.QueryContext(ctx, query, startDate, endDate, nullIfEmpty(storeID))
 	if err != nil {
 		return nil, err
 	}
@@ -0,0 +1,249 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+
+	"frappuccino/internal/entity"
+
+	"github.com/lib/pq"
+)
+
+// LockOrderWithTx reads an order and locks it until the transaction ends
+func (repo *OrderRepository) LockOrderWithTx(ctx context.Context, tx *Transaction, orderID string) (entity.Order, error) {
+	var o entity.Order
+	query := `
+		SELECT order_id, store_id, order_number, business_date, customer_name, special_instructions, total_amount, status, order_type, table_id, delivery_fee, created_at, updated_at
+		FROM orders
+		WHERE order_id = $1
+		FOR UPDATE
+	`
+	var tableID sql.NullString
+	err := tx.tx.QueryRowContext(ctx, query, orderID).Scan(
+		&o.OrderID,
+		&o.StoreID,
+		&o.OrderNumber,
+		&o.BusinessDate,
+		&o.CustomerName,
+		&o.SpecialInstructions,
+		&o.TotalAmount,
+		&o.Status,
+		&o.OrderType,
+		&tableID,
+		&o.DeliveryFee,
+		&o.CreatedAt,
+		&o.UpdatedAt,
+	)
+	o.TableID = nullStringPtr(tableID)
+	return o, err
+}
+
+// GetOrderItemWithTx returns a single item of an order, or sql.ErrNoRows if the order has no such item
+func (repo *OrderRepository) GetOrderItemWithTx(ctx context.Context, tx *Transaction, orderID, orderItemID string) (entity.OrderItem, error) {
+	var i entity.OrderItem
+	var customizations sql.NullString
+	query := `
+		SELECT order_item_id, order_id, menu_item_id, quantity, price_at_time, customizations
+		FROM order_items
+		WHERE order_id = $1 AND order_item_id = $2
+	`
+	err := tx.tx.QueryRowContext(ctx, query, orderID, orderItemID).Scan(
+		&i.OrderItemID,
+		&i.OrderID,
+		&i.MenuItemID,
+		&i.Quantity,
+		&i.PriceAtTime,
+		&customizations,
+	)
+	if customizations.Valid {
+		i.Customizations = json.RawMessage(customizations.String)
+	}
+	return i, err
+}
+
+// CountOrderItemsWithTx returns how many line items an order has
+func (repo *OrderRepository) CountOrderItemsWithTx(ctx context.Context, tx *Transaction, orderID string) (int, error) {
+	var count int
+	err := tx.tx.QueryRowContext(ctx, `SELECT COUNT(*) FROM order_items WHERE order_id = $1`, orderID).Scan(&count)
+	return count, err
+}
+
+// AddOrderItemWithTx inserts a new line item into an existing order
+func (repo *OrderRepository) AddOrderItemWithTx(ctx context.Context, tx *Transaction, item entity.OrderItem) (string, error) {
+	var orderItemID string
+	query := `
+		INSERT INTO order_items (order_id, menu_item_id, quantity, price_at_time, customizations)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING order_item_id
+	`
+	err := tx.tx.QueryRowContext(ctx, query,
+		item.OrderID,
+		item.MenuItemID,
+		item.Quantity,
+		item.PriceAtTime,
+		item.Customizations,
+	).Scan(&orderItemID)
+	if err != nil {
+		return "", fmt.Errorf("insert order item: %w", err)
+	}
+	return orderItemID, nil
+}
+
+// UpdateOrderItemWithTx changes the quantity and customizations of a line item
+func (repo *OrderRepository) UpdateOrderItemWithTx(ctx context.Context, tx *Transaction, item entity.OrderItem) error {
+	query := `
+		UPDATE order_items
+		SET quantity = $2, customizations = $3
+		WHERE order_item_id = $1
+	`
+	if _, err := tx.tx.ExecContext(ctx, query, item.OrderItemID, item.Quantity, item.Customizations); err != nil {
+		return fmt.Errorf("update order item: %w", err)
+	}
+	return nil
+}
+
+// DeleteOrderItemWithTx removes a line item; its station ticket entry goes with it
+func (repo *OrderRepository) DeleteOrderItemWithTx(ctx context.Context, tx *Transaction, orderItemID string) error {
+	if _, err := tx.tx.ExecContext(ctx, `DELETE FROM order_items WHERE order_item_id = $1`, orderItemID); err != nil {
+		return fmt.Errorf("delete order item: %w", err)
+	}
+	return nil
+}
+
+// RecalculateOrderTotalWithTx sets the order total to the sum of its line items plus the delivery fee and returns it
+func (repo *OrderRepository) RecalculateOrderTotalWithTx(ctx context.Context, tx *Transaction, orderID string) (float64, error) {
+	var total float64
+	query := `
+		UPDATE orders
+		SET total_amount = COALESCE(
+			(SELECT SUM(quantity * price_at_time) FROM order_items WHERE order_id = $1), 0)
+		WHERE order_id = $1
+		RETURNING total_amount
+	`
+	if err := tx.tx.QueryRowContext(ctx, query, orderID).Scan(&total); err != nil {
+		return 0, fmt.Errorf("recalculate order total: %w", err)
+	}
+	return total, nil
+}
+
+// CreateOrderAuditWithTx appends an entry to the audit trail of an order
+func (repo *OrderRepository) CreateOrderAuditWithTx(ctx context.Context, tx *Transaction, entry entity.OrderAuditEntry) error {
+	query := `
+		INSERT INTO order_audit_log
+		(order_id, order_item_id, action, old_value, new_value, total_before, total_after, reason)
+		VALUES ($1, NULLIF($2, '')::UUID, $3, $4, $5, $6, $7, $8)
+	`
+	_, err := tx.tx.ExecContext(ctx, query,
+		entry.OrderID,
+		entry.OrderItemID,
+		entry.Action,
+		nullJSON(entry.OldValue),
+		nullJSON(entry.NewValue),
+		entry.TotalBefore,
+		entry.TotalAfter,
+		entry.Reason,
+	)
+	if err != nil {
+		return fmt.Errorf("insert order audit entry: %w", err)
+	}
+	return nil
+}
+
+// GetOrderAuditLog returns the audit trail of an order, oldest first
+func (repo *OrderRepository) GetOrderAuditLog(ctx context.Context, orderID string) ([]entity.OrderAuditEntry, error) {
+	query := `
+		SELECT
+			audit_id,
+			order_id,
+			COALESCE(order_item_id::TEXT, ''),
+			action,
+			old_value,
+			new_value,
+			total_before,
+			total_after,
+			reason,
+			created_at
+		FROM order_audit_log
+		WHERE order_id = $1
+		ORDER BY created_at, audit_id
+	`
+
+	rows, err := repo.db.QueryContext(ctx, query, orderID)
+	if err != nil {
+		return nil, fmt.Errorf("query order audit log: %w", err)
+	}
+	defer rows.Close()
+
+	var entries []entity.OrderAuditEntry
+	for rows.Next() {
+		var e entity.OrderAuditEntry
+		var oldValue, newValue sql.NullString
+		if err := rows.Scan(
+			&e.AuditID,
+			&e.OrderID,
+			&e.OrderItemID,
+			&e.Action,
+			&oldValue,
+			&newValue,
+			&e.TotalBefore,
+			&e.TotalAfter,
+			&e.Reason,
+			&e.CreatedAt,
+		); err != nil {
+			return nil, fmt.Errorf("scan order audit entry: %w", err)
+		}
+		if oldValue.Valid {
+			e.OldValue = json.RawMessage(oldValue.String)
+		}
+		if newValue.Valid {
+			e.NewValue = json.RawMessage(newValue.String)
+		}
+		entries = append(entries, e)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate order audit log: %w", err)
+	}
+
+	return entries, nil
+}
+
+// CreateOrderStatusHistoryWithTx records a status history entry without changing the order
+func (repo *OrderRepository) CreateOrderStatusHistoryWithTx(ctx context.Context, tx *Transaction, orderID, oldStatus, newStatus, reason string) error {
+	query := `
+		INSERT INTO order_status_history (order_id, old_status, new_status, change_reason)
+		VALUES ($1, $2, $3, $4)
+	`
+	if _, err := tx.tx.ExecContext(ctx, query, orderID, oldStatus, newStatus, reason); err != nil {
+		return fmt.Errorf("insert status history: %w", err)
+	}
+	return nil
+}
+
+// MoveOrderItemsWithTx moves the line items and station tickets of the given orders to another
+// order, keeping the item rows so inventory deductions and kitchen progress stay attached to them
+func (repo *OrderRepository) MoveOrderItemsWithTx(ctx context.Context, tx *Transaction, fromOrderIDs []string, toOrderID string) error {
+	if _, err := tx.tx.ExecContext(ctx, `
+		UPDATE order_items SET order_id = $2 WHERE order_id = ANY($1::UUID[])
+	`, pq.Array(fromOrderIDs), toOrderID); err != nil {
+		return fmt.Errorf("move order items: %w", err)
+	}
+
+	if _, err := tx.tx.ExecContext(ctx, `
+		UPDATE station_tickets SET order_id = $2 WHERE order_id = ANY($1::UUID[])
+	`, pq.Array(fromOrderIDs), toOrderID); err != nil {
+		return fmt.Errorf("move station tickets: %w", err)
+	}
+
+	return nil
+}
+
+// nullJSON stores empty JSON values as NULL
+func nullJSON(value json.RawMessage) interface{} {
+	if len(value) == 0 {
+		return nil
+	}
+	return []byte(value)
+}
@@ -0,0 +1,291 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"frappuccino/internal/entity"
+)
+
+type PrintRepository struct {
+	db *sql.DB
+}
+
+func NewPrintRepository(db *sql.DB) *PrintRepository {
+	return &PrintRepository{
+		db: db,
+	}
+}
+
+func (repo *PrintRepository) CreatePrinter(ctx context.Context, printer entity.Printer) (string, error) {
+	var printerID string
+	query := `
+		INSERT INTO printers (store_id, name, address, station_id, is_active)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING printer_id
+	`
+	err := repo.db.QueryRowContext(ctx, query, printer.StoreID, printer.Name, printer.Address, printer.StationID, printer.IsActive).Scan(&printerID)
+	if err != nil {
+		return "", fmt.Errorf("insert printer: %w", err)
+	}
+	return printerID, nil
+}
+
+// GetPrinters lists the printers of a store, or of all stores when storeID is empty
+func (repo *PrintRepository) GetPrinters(ctx context.Context, storeID string) ([]entity.Printer, error) {
+	query := `
+		SELECT printer_id, store_id, name, address, station_id, is_active, created_at
+		FROM printers
+		WHERE $1::uuid IS NULL OR store_id = $1
+		ORDER BY name
+	`
+
+	rows, err := repo.db.QueryContext(ctx, query, nullIfEmpty(storeID))
+	if err != nil {
+		return nil, fmt.Errorf("query printers: %w", err)
+	}
+	defer rows.Close()
+
+	var printers []entity.Printer
+	for rows.Next() {
+		var p entity.Printer
+		var stationID sql.NullString
+		if err := rows.Scan(&p.PrinterID, &p.StoreID, &p.Name, &p.Address, &stationID, &p.IsActive, &p.CreatedAt); err != nil {
+			return nil, fmt.Errorf("scan printer: %w", err)
+		}
+		p.StationID = nullStringPtr(stationID)
+		printers = append(printers, p)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate printers: %w", err)
+	}
+
+	return printers, nil
+}
+
+// EnqueueKitchenTickets queues a job for every active printer of the order's store the order has
+// items for: printers of a station that got a ticket of the order, and printers without a station
+func (repo *PrintRepository) EnqueueKitchenTickets(ctx context.Context, orderID string) (int, error) {
+	return enqueueKitchenTickets(ctx, repo.db, orderID)
+}
+
+// EnqueueKitchenTicketsWithTx queues the kitchen tickets of an order within an existing transaction
+func (repo *PrintRepository) EnqueueKitchenTicketsWithTx(ctx context.Context, tx *Transaction, orderID string) (int, error) {
+	return enqueueKitchenTickets(ctx, tx.tx, orderID)
+}
+
+func enqueueKitchenTickets(ctx context.Context, q queryer, orderID string) (int, error) {
+	query := `
+		INSERT INTO print_jobs (printer_id, order_id, station_id)
+		SELECT p.printer_id, $1, p.station_id
+		FROM printers p
+		JOIN orders o ON o.order_id = $1 AND o.store_id = p.store_id
+		WHERE p.is_active
+		AND (
+			p.station_id IS NULL
+			OR EXISTS (SELECT 1 FROM station_tickets st WHERE st.order_id = $1 AND st.station_id = p.station_id)
+		)
+	`
+	result, err := q.ExecContext(ctx, query, orderID)
+	if err != nil {
+		return 0, fmt.Errorf("enqueue kitchen tickets: %w", err)
+	}
+	queued, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("enqueue kitchen tickets: %w", err)
+	}
+	return int(queued), nil
+}
+
+const printJobColumns = `
+	j.job_id,
+	j.printer_id,
+	p.store_id,
+	p.name,
+	p.address,
+	j.order_id,
+	j.station_id,
+	COALESCE((SELECT s.name FROM stations s WHERE s.station_id = j.station_id), ''),
+	j.status,
+	j.attempts,
+	COALESCE(j.last_error, ''),
+	j.reprint_of,
+	j.next_attempt_at,
+	j.created_at,
+	j.updated_at,
+	j.printed_at
+`
+
+// ClaimPrintJob marks the next due job as printing and returns it. Jobs left printing since
+// staleBefore belong to a worker that stopped and are claimed again. SKIP LOCKED lets several
+// workers share the queue without waiting on each other.
+func (repo *PrintRepository) ClaimPrintJob(ctx context.Context, staleBefore time.Time) (entity.PrintJob, bool, error) {
+	query := `
+		UPDATE print_jobs j
+		SET status = 'printing', attempts = j.attempts + 1
+		FROM printers p
+		WHERE p.printer_id = j.printer_id
+		AND j.job_id = (
+			SELECT job_id FROM print_jobs
+			WHERE (status = 'pending' AND next_attempt_at <= CURRENT_TIMESTAMP)
+			OR (status = 'printing' AND updated_at < $1)
+			ORDER BY next_attempt_at, created_at
+			LIMIT 1
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING` + printJobColumns
+
+	job, err := scanPrintJob(repo.db.QueryRowContext(ctx, query, staleBefore))
+	if err == sql.ErrNoRows {
+		return entity.PrintJob{}, false, nil
+	}
+	if err != nil {
+		return entity.PrintJob{}, false, fmt.Errorf("claim print job: %w", err)
+	}
+	return job, true, nil
+}
+
+// MarkJobPrinted records that the printer accepted the ticket
+func (repo *PrintRepository) MarkJobPrinted(ctx context.Context, jobID string) error {
+	query := `
+		UPDATE print_jobs
+		SET status = 'printed', last_error = NULL, printed_at = CURRENT_TIMESTAMP
+		WHERE job_id = $1
+	`
+	if _, err := repo.db.ExecContext(ctx, query, jobID); err != nil {
+		return fmt.Errorf("mark print job printed: %w", err)
+	}
+	return nil
+}
+
+// MarkJobFailed records a failed attempt. The job is retried at retryAt, or given up when retryAt is nil.
+func (repo *PrintRepository) MarkJobFailed(ctx context.Context, jobID, reason string, retryAt *time.Time) error {
+	query := `
+		UPDATE print_jobs
+		SET status = CASE WHEN $3::TIMESTAMPTZ IS NULL THEN 'failed' ELSE 'pending' END::print_job_status,
+			last_error = $2,
+			next_attempt_at = COALESCE($3, next_attempt_at)
+		WHERE job_id = $1
+	`
+	if _, err := repo.db.ExecContext(ctx, query, jobID, reason, retryAt); err != nil {
+		return fmt.Errorf("mark print job failed: %w", err)
+	}
+	return nil
+}
+
+// GetPrintJobs returns the most recent jobs, optionally only those with the given status. An empty
+// storeID returns the jobs of every store.
+func (repo *PrintRepository) GetPrintJobs(ctx context.Context, storeID, status string, limit int) ([]entity.PrintJob, error) {
+	query := `
+		SELECT` + printJobColumns + `
+		FROM print_jobs j
+		JOIN printers p ON p.printer_id = j.printer_id
+		WHERE ($1 = '' OR j.status::TEXT = $1)
+		  AND ($3::uuid IS NULL OR p.store_id = $3)
+		ORDER BY j.created_at DESC
+		LIMIT $2
+	`
+
+	rows, err := repo.db.QueryContext(ctx, query, status, limit, nullIfEmpty(storeID))
+	if err != nil {
+		return nil, fmt.Errorf("query print jobs: %w", err)
+	}
+	defer rows.Close()
+
+	var jobs []entity.PrintJob
+	for rows.Next() {
+		job, err := scanPrintJob(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scan print job: %w", err)
+		}
+		jobs = append(jobs, job)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate print jobs: %w", err)
+	}
+
+	return jobs, nil
+}
+
+func (repo *PrintRepository) GetPrintJobByID(ctx context.Context, jobID string) (entity.PrintJob, error) {
+	query := `
+		SELECT` + printJobColumns + `
+		FROM print_jobs j
+		JOIN printers p ON p.printer_id = j.printer_id
+		WHERE j.job_id = $1
+	`
+	return scanPrintJob(repo.db.QueryRowContext(ctx, query, jobID))
+}
+
+// ReprintJob queues a copy of a job for the same printer, or returns sql.ErrNoRows if there is no such job
+func (repo *PrintRepository) ReprintJob(ctx context.Context, jobID string) (string, error) {
+	var newJobID string
+	query := `
+		INSERT INTO print_jobs (printer_id, order_id, station_id, reprint_of)
+		SELECT printer_id, order_id, station_id, job_id
+		FROM print_jobs
+		WHERE job_id = $1
+		RETURNING job_id
+	`
+	err := repo.db.QueryRowContext(ctx, query, jobID).Scan(&newJobID)
+	return newJobID, err
+}
+
+// GetStationItemIDs returns the items of an order that are on the tickets of a station
+func (repo *PrintRepository) GetStationItemIDs(ctx context.Context, orderID, stationID string) ([]string, error) {
+	query := `
+		SELECT sti.order_item_id
+		FROM station_ticket_items sti
+		JOIN station_tickets st ON st.ticket_id = sti.ticket_id
+		WHERE st.order_id = $1 AND st.station_id = $2
+	`
+
+	rows, err := repo.db.QueryContext(ctx, query, orderID, stationID)
+	if err != nil {
+		return nil, fmt.Errorf("query station items: %w", err)
+	}
+	defer rows.Close()
+
+	var itemIDs []string
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("scan station item: %w", err)
+		}
+		itemIDs = append(itemIDs, id)
+	}
+
+	return itemIDs, rows.Err()
+}
+
+func scanPrintJob(row rowScanner) (entity.PrintJob, error) {
+	var job entity.PrintJob
+	var stationID, reprintOf sql.NullString
+	var printedAt sql.NullTime
+	err := row.Scan(
+		&job.JobID,
+		&job.PrinterID,
+		&job.StoreID,
+		&job.PrinterName,
+		&job.PrinterAddress,
+		&job.OrderID,
+		&stationID,
+		&job.StationName,
+		&job.Status,
+		&job.Attempts,
+		&job.LastError,
+		&reprintOf,
+		&job.NextAttemptAt,
+		&job.CreatedAt,
+		&job.UpdatedAt,
+		&printedAt,
+	)
+	job.StationID = nullStringPtr(stationID)
+	job.ReprintOf = nullStringPtr(reprintOf)
+	job.PrintedAt = nullTimePtr(printedAt)
+	return job, err
+}
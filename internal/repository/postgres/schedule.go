@@ -0,0 +1,173 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"frappuccino/internal/entity"
+)
+
+// GetDueScheduledOrders returns the IDs of scheduled orders whose pickup time is at or before the given time
+func (repo *OrderRepository) GetDueScheduledOrders(ctx context.Context, before time.Time) ([]string, error) {
+	query := `
+		SELECT order_id
+		FROM orders
+		WHERE status = 'scheduled' AND pickup_at <= $1
+		ORDER BY pickup_at
+	`
+
+	rows, err := repo.db.QueryContext(ctx, query, before)
+	if err != nil {
+		return nil, fmt.Errorf("error querying due scheduled orders: %w", err)
+	}
+	defer rows.Close()
+
+	var orderIDs []string
+	for rows.Next() {
+		var orderID string
+		if err := rows.Scan(&orderID); err != nil {
+			return nil, fmt.Errorf("error scanning scheduled order: %w", err)
+		}
+		orderIDs = append(orderIDs, orderID)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating scheduled orders: %w", err)
+	}
+
+	return orderIDs, nil
+}
+
+// TransitionOrderStatusWithTx moves an order from one status to another and records the change
+// in the status history. It reports false without changing anything when the order is no longer
+// in the expected status, which makes concurrent transitions safe.
+func (repo *OrderRepository) TransitionOrderStatusWithTx(
+	ctx context.Context,
+	tx *Transaction,
+	orderID, fromStatus, toStatus, reason string,
+) (bool, error) {
+	query := `
+		UPDATE orders
+		SET status = $3
+		WHERE order_id = $1 AND status = $2
+	`
+	result, err := tx.tx.ExecContext(ctx, query, orderID, fromStatus, toStatus)
+	if err != nil {
+		return false, fmt.Errorf("update order status: %w", err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("get affected rows: %w", err)
+	}
+	if affected == 0 {
+		return false, nil
+	}
+
+	historyQuery := `
+		INSERT INTO order_status_history (order_id, old_status, new_status, change_reason)
+		VALUES ($1, $2, $3, $4)
+	`
+	if _, err := tx.tx.ExecContext(ctx, historyQuery, orderID, fromStatus, toStatus, reason); err != nil {
+		return false, fmt.Errorf("insert status history: %w", err)
+	}
+
+	return true, nil
+}
+
+// GetReservedQuantities returns the total quantity of each ingredient held for scheduled orders
+func (repo *InventoryRepository) GetReservedQuantities(ctx context.Context) (map[string]float32, error) {
+	query := `
+		SELECT ingredient_id, SUM(quantity)
+		FROM inventory_reservations
+		GROUP BY ingredient_id
+	`
+
+	rows, err := repo.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("error querying reserved quantities: %w", err)
+	}
+	defer rows.Close()
+
+	reserved := make(map[string]float32)
+	for rows.Next() {
+		var ingredientID string
+		var quantity float32
+		if err := rows.Scan(&ingredientID, &quantity); err != nil {
+			return nil, fmt.Errorf("error scanning reserved quantity: %w", err)
+		}
+		reserved[ingredientID] = quantity
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating reserved quantities: %w", err)
+	}
+
+	return reserved, nil
+}
+
+// CreateReservationsWithTx holds the given ingredient quantities for an order
+func (repo *InventoryRepository) CreateReservationsWithTx(ctx context.Context, tx *Transaction, orderID string, quantities map[string]float32) error {
+	query := `
+		INSERT INTO inventory_reservations (order_id, ingredient_id, quantity)
+		VALUES ($1, $2, $3)
+	`
+	for ingredientID, quantity := range quantities {
+		if _, err := tx.tx.ExecContext(ctx, query, orderID, ingredientID, quantity); err != nil {
+			return fmt.Errorf("insert reservation for ingredient %s: %w", ingredientID, err)
+		}
+	}
+	return nil
+}
+
+// ReleaseReservationsWithTx deletes the reservations of an order and returns what they held
+func (repo *InventoryRepository) ReleaseReservationsWithTx(ctx context.Context, tx *Transaction, orderID string) (map[string]float32, error) {
+	query := `
+		DELETE FROM inventory_reservations
+		WHERE order_id = $1
+		RETURNING ingredient_id, quantity
+	`
+
+	rows, err := tx.tx.QueryContext(ctx, query, orderID)
+	if err != nil {
+		return nil, fmt.Errorf("delete reservations: %w", err)
+	}
+	defer rows.Close()
+
+	released := make(map[string]float32)
+	for rows.Next() {
+		var ingredientID string
+		var quantity float32
+		if err := rows.Scan(&ingredientID, &quantity); err != nil {
+			return nil, fmt.Errorf("scan released reservation: %w", err)
+		}
+		released[ingredientID] = quantity
+	}
+
+	return released, rows.Err()
+}
+
+// GetInventoryByIDWithTx reads an inventory row inside a transaction, locking it until the transaction ends
+func (repo *InventoryRepository) GetInventoryByIDWithTx(ctx context.Context, tx *Transaction, id string) (entity.Inventory, error) {
+	var inv entity.Inventory
+	query := `
+		SELECT ingredient_id, store_id, name, quantity, unit, unit_price, reorder_point, last_updated
+		FROM inventory
+		WHERE ingredient_id = $1
+		FOR UPDATE
+	`
+
+	err := tx.tx.QueryRowContext(ctx, query, id).Scan(
+		&inv.IngredientID,
+		&inv.StoreID,
+		&inv.Name,
+		&inv.Quantity,
+		&inv.Unit,
+		&inv.UnitPrice,
+		&inv.ReorderPoint,
+		&inv.LastUpdated,
+	)
+
+	return inv, err
+}
@@ -22,6 +22,7 @@ func NewSearchRepository(db *sql.DB) *SearchRepository {
 // SearchMenuItems searches menu items based on search criteria
 func (repo *SearchRepository) SearchMenuItems(
 	ctx context.Context,
+	storeID string,
 	query string,
 	minPrice, maxPrice *float64,
 ) ([]report.SearchResultMenuItem, error) {
@@ -33,13 +34,18 @@ func (repo *SearchRepository) SearchMenuItems(
             description,
             price,
             ts_rank(to_tsvector('english', name || ' ' || COALESCE(description, '')), plainto_tsquery('english', $1)) AS relevance
-        FROM menu_items
+        FROM (
+            SELECT m.menu_item_id, m.name, m.description, COALESCE(smi.price, m.price) AS price
+            FROM menu_items m
+            LEFT JOIN store_menu_items smi ON smi.menu_item_id = m.menu_item_id AND smi.store_id = $2
+            WHERE COALESCE(smi.is_available, TRUE)
+        ) menu_items
         WHERE to_tsvector('english', name || ' ' || COALESCE(description, '')) @@ plainto_tsquery('english', $1)
     `
 
 	// Add price filters if provided
-	args := []interface{}{query}
-	paramIndex := 2 // starting from second parameter since $1 is already used
+	args := []interface{}{query, nullIfEmpty(storeID)}
+	paramIndex := 3 // $1 is the search text and $2 the store
 
 	if minPrice != nil {
 		sqlQuery += fmt.Sprintf(" AND price >= $%d", paramIndex)
@@ -87,6 +93,7 @@ func (repo *SearchRepository) SearchMenuItems(
 // SearchOrders searches orders based on search criteria
 func (repo *SearchRepository) SearchOrders(
 	ctx context.Context,
+	storeID string,
 	query string,
 	minPrice, maxPrice *float64,
 ) ([]report.SearchResultOrder, error) {
@@ -100,6 +107,7 @@ func (repo *SearchRepository) SearchOrders(
                 ts_rank(to_tsvector('english', o.customer_name || ' ' || COALESCE(o.special_instructions::text, '')), plainto_tsquery('english', $1)) AS relevance
             FROM orders o
             WHERE to_tsvector('english', o.customer_name || ' ' || COALESCE(o.special_instructions::text, '')) @@ plainto_tsquery('english', $1)
+              AND ($2::uuid IS NULL OR o.store_id = $2)
         )
         SELECT 
             mo.order_id,
@@ -113,8 +121,8 @@ func (repo *SearchRepository) SearchOrders(
     `
 
 	// Add price filters if provided
-	args := []interface{}{query}
-	paramIndex := 2 // starting from second parameter since $1 is already used
+	args := []interface{}{query, nullIfEmpty(storeID)}
+	paramIndex := 3 // $1 is the search text and $2 the store
 
 	if minPrice != nil {
 		sqlQuery += fmt.Sprintf(" WHERE mo.total_amount >= $%d", paramIndex)
@@ -172,6 +180,7 @@ func (repo *SearchRepository) SearchOrders(
 // SearchMenuItemsByKeywords searches menu items using individual keywords
 func (repo *SearchRepository) SearchMenuItemsByKeywords(
 	ctx context.Context,
+	storeID string,
 	keywords []string,
 	minPrice, maxPrice *float64,
 ) ([]report.SearchResultMenuItem, error) {
@@ -186,13 +195,18 @@ func (repo *SearchRepository) SearchMenuItemsByKeywords(
             description,
             price,
             ts_rank(to_tsvector('english', name || ' ' || COALESCE(description, '')), to_tsquery('english', $1)) AS relevance
-        FROM menu_items
+        FROM (
+            SELECT m.menu_item_id, m.name, m.description, COALESCE(smi.price, m.price) AS price
+            FROM menu_items m
+            LEFT JOIN store_menu_items smi ON smi.menu_item_id = m.menu_item_id AND smi.store_id = $2
+            WHERE COALESCE(smi.is_available, TRUE)
+        ) menu_items
         WHERE to_tsvector('english', name || ' ' || COALESCE(description, '')) @@ to_tsquery('english', $1)
     `
 
 	// Add price filters if provided
-	args := []interface{}{tsquery}
-	paramIndex := 2 // starting from second parameter since $1 is already used
+	args := []interface{}{tsquery, nullIfEmpty(storeID)}
+	paramIndex := 3 // $1 is the search text and $2 the store
 
 	if minPrice != nil {
 		sqlQuery += fmt.Sprintf(" AND price >= $%d", paramIndex)
@@ -240,6 +254,7 @@ func (repo *SearchRepository) SearchMenuItemsByKeywords(
 // SearchOrdersByKeywords searches orders using individual keywords
 func (repo *SearchRepository) SearchOrdersByKeywords(
 	ctx context.Context,
+	storeID string,
 	keywords []string,
 	minPrice, maxPrice *float64,
 ) ([]report.SearchResultOrder, error) {
@@ -256,6 +271,7 @@ func (repo *SearchRepository) SearchOrdersByKeywords(
                 ts_rank(to_tsvector('english', o.customer_name || ' ' || COALESCE(o.special_instructions::text, '')), to_tsquery('english', $1)) AS relevance
             FROM orders o
             WHERE to_tsvector('english', o.customer_name || ' ' || COALESCE(o.special_instructions::text, '')) @@ to_tsquery('english', $1)
+              AND ($2::uuid IS NULL OR o.store_id = $2)
         )
         SELECT 
             mo.order_id,
@@ -269,8 +285,8 @@ func (repo *SearchRepository) SearchOrdersByKeywords(
     `
 
 	// Add price filters if provided
-	args := []interface{}{tsquery}
-	paramIndex := 2 // starting from second parameter since $1 is already used
+	args := []interface{}{tsquery, nullIfEmpty(storeID)}
+	paramIndex := 3 // $1 is the search text and $2 the store
 
 	if minPrice != nil {
 		sqlQuery += fmt.Sprintf(" WHERE mo.total_amount >= $%d", paramIndex)
@@ -0,0 +1,179 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"frappuccino/internal/entity"
+)
+
+// GetOrderStateTimings returns, for every non-cancelled order in the date range, when it
+// was created and when it first entered the preparing, ready and delivered states
+func (repo *OrderRepository) GetOrderStateTimings(ctx context.Context, storeID string, startDate, endDate *time.Time) ([]entity.OrderStateTiming, error) {
+	query := `
+		SELECT
+			o.order_id,
+			o.customer_name,
+			o.status,
+			o.created_at,
+			MIN(h.changed_at) FILTER (WHERE h.new_status = 'preparing') AS preparing_at,
+			MIN(h.changed_at) FILTER (WHERE h.new_status = 'ready') AS ready_at,
+			MIN(h.changed_at) FILTER (WHERE h.new_status = 'delivered') AS delivered_at
+		FROM orders o
+		LEFT JOIN order_status_history h ON h.order_id = o.order_id
+		WHERE o.status != 'cancelled'
+	`
+
+	filter, args := createdAtFilter("o.created_at", startDate, endDate)
+	storeCond, args := storeFilter("o.store_id", storeID, args)
+	filter += storeCond
+	query += filter + `
+		GROUP BY o.order_id, o.customer_name, o.status, o.created_at
+		ORDER BY o.created_at
+	`
+
+	rows, err := repo.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("error querying order state timings: %w", err)
+	}
+	defer rows.Close()
+
+	var timings []entity.OrderStateTiming
+	for rows.Next() {
+		var t entity.OrderStateTiming
+		var preparingAt, readyAt, deliveredAt sql.NullTime
+		if err := rows.Scan(
+			&t.OrderID,
+			&t.CustomerName,
+			&t.Status,
+			&t.CreatedAt,
+			&preparingAt,
+			&readyAt,
+			&deliveredAt,
+		); err != nil {
+			return nil, fmt.Errorf("error scanning order state timing: %w", err)
+		}
+		t.PreparingAt = nullTimePtr(preparingAt)
+		t.ReadyAt = nullTimePtr(readyAt)
+		t.DeliveredAt = nullTimePtr(deliveredAt)
+		timings = append(timings, t)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating order state timings: %w", err)
+	}
+
+	return timings, nil
+}
+
+// GetOrderMenuItems returns the distinct menu items of every non-cancelled order in the date range
+func (repo *OrderRepository) GetOrderMenuItems(ctx context.Context, storeID string, startDate, endDate *time.Time) ([]entity.OrderMenuItem, error) {
+	query := `
+		SELECT DISTINCT o.order_id, m.menu_item_id, m.name
+		FROM orders o
+		JOIN order_items oi ON oi.order_id = o.order_id
+		JOIN menu_items m ON m.menu_item_id = oi.menu_item_id
+		WHERE o.status != 'cancelled'
+	`
+
+	filter, args := createdAtFilter("o.created_at", startDate, endDate)
+	storeCond, args := storeFilter("o.store_id", storeID, args)
+	filter += storeCond
+	query += filter
+
+	rows, err := repo.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("error querying order menu items: %w", err)
+	}
+	defer rows.Close()
+
+	var items []entity.OrderMenuItem
+	for rows.Next() {
+		var item entity.OrderMenuItem
+		if err := rows.Scan(&item.OrderID, &item.MenuItemID, &item.Name); err != nil {
+			return nil, fmt.Errorf("error scanning order menu item: %w", err)
+		}
+		items = append(items, item)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating order menu items: %w", err)
+	}
+
+	return items, nil
+}
+
+// GetStationTicketTimings returns the lifecycle of every station ticket of the orders in the date range
+func (repo *OrderRepository) GetStationTicketTimings(ctx context.Context, storeID string, startDate, endDate *time.Time) ([]entity.StationTicketTiming, error) {
+	query := `
+		SELECT s.station_id, s.name, st.created_at, st.started_at, st.completed_at
+		FROM station_tickets st
+		JOIN stations s ON s.station_id = st.station_id
+		JOIN orders o ON o.order_id = st.order_id
+		WHERE o.status != 'cancelled'
+	`
+
+	filter, args := createdAtFilter("o.created_at", startDate, endDate)
+	storeCond, args := storeFilter("o.store_id", storeID, args)
+	filter += storeCond
+	query += filter + " ORDER BY s.name"
+
+	rows, err := repo.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("error querying station ticket timings: %w", err)
+	}
+	defer rows.Close()
+
+	var timings []entity.StationTicketTiming
+	for rows.Next() {
+		var t entity.StationTicketTiming
+		var startedAt, completedAt sql.NullTime
+		if err := rows.Scan(&t.StationID, &t.StationName, &t.CreatedAt, &startedAt, &completedAt); err != nil {
+			return nil, fmt.Errorf("error scanning station ticket timing: %w", err)
+		}
+		t.StartedAt = nullTimePtr(startedAt)
+		t.CompletedAt = nullTimePtr(completedAt)
+		timings = append(timings, t)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating station ticket timings: %w", err)
+	}
+
+	return timings, nil
+}
+
+// createdAtFilter builds the optional date range condition used by the reports.
+// The end date is inclusive, so the range is extended until the end of that day.
+func createdAtFilter(column string, startDate, endDate *time.Time) (string, []interface{}) {
+	var filter string
+	var args []interface{}
+
+	if startDate != nil {
+		args = append(args, *startDate)
+		filter += fmt.Sprintf(" AND %s >= $%d", column, len(args))
+	}
+
+	if endDate != nil {
+		args = append(args, endDate.AddDate(0, 0, 1))
+		filter += fmt.Sprintf(" AND %s < $%d", column, len(args))
+	}
+
+	return filter, args
+}
+
+func nullTimePtr(t sql.NullTime) *time.Time {
+	if !t.Valid {
+		return nil
+	}
+	return &t.Time
+}
+
+func nullStringPtr(s sql.NullString) *string {
+	if !s.Valid {
+		return nil
+	}
+	return &s.String
+}
@@ -0,0 +1,398 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"frappuccino/internal/entity"
+
+	"github.com/lib/pq"
+)
+
+type StationRepository struct {
+	db *sql.DB
+}
+
+func NewStationRepository(db *sql.DB) *StationRepository {
+	return &StationRepository{
+		db: db,
+	}
+}
+
+func (repo *StationRepository) CreateStation(ctx context.Context, station entity.Station) (string, error) {
+	var ID string
+	query := `
+	INSERT INTO stations (name, description, is_default)
+	VALUES ($1, $2, $3)
+	RETURNING station_id;
+	`
+	err := repo.db.QueryRowContext(ctx, query,
+		station.Name,
+		station.Description,
+		station.IsDefault).Scan(&ID)
+	return ID, err
+}
+
+func (repo *StationRepository) GetStations(ctx context.Context) ([]entity.Station, error) {
+	query := `
+	SELECT station_id, name, COALESCE(description, ''), is_default, created_at
+	FROM stations
+	ORDER BY name
+	`
+
+	rows, err := repo.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var stations []entity.Station
+	for rows.Next() {
+		var s entity.Station
+		if err := rows.Scan(&s.StationID, &s.Name, &s.Description, &s.IsDefault, &s.CreatedAt); err != nil {
+			return nil, err
+		}
+		stations = append(stations, s)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return stations, nil
+}
+
+func (repo *StationRepository) GetStationByID(ctx context.Context, id string) (entity.Station, error) {
+	var s entity.Station
+	query := `
+	SELECT station_id, name, COALESCE(description, ''), is_default, created_at
+	FROM stations
+	WHERE station_id = $1;
+	`
+	err := repo.db.QueryRowContext(ctx, query, id).Scan(
+		&s.StationID,
+		&s.Name,
+		&s.Description,
+		&s.IsDefault,
+		&s.CreatedAt,
+	)
+	return s, err
+}
+
+func (repo *StationRepository) CreateStationRoute(ctx context.Context, route entity.StationRoute) (string, error) {
+	var ID string
+	query := `
+	INSERT INTO station_routes (station_id, menu_item_id, category)
+	VALUES ($1, NULLIF($2, '')::UUID, NULLIF($3, ''))
+	RETURNING station_route_id;
+	`
+	err := repo.db.QueryRowContext(ctx, query,
+		route.StationID,
+		route.MenuItemID,
+		route.Category).Scan(&ID)
+	return ID, err
+}
+
+func (repo *StationRepository) GetStationRoutes(ctx context.Context, stationID string) ([]entity.StationRoute, error) {
+	query := `
+	SELECT station_route_id, station_id, COALESCE(menu_item_id::TEXT, ''), COALESCE(category, '')
+	FROM station_routes
+	WHERE station_id = $1
+	ORDER BY category NULLS LAST, menu_item_id
+	`
+
+	rows, err := repo.db.QueryContext(ctx, query, stationID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var routes []entity.StationRoute
+	for rows.Next() {
+		var r entity.StationRoute
+		if err := rows.Scan(&r.StationRouteID, &r.StationID, &r.MenuItemID, &r.Category); err != nil {
+			return nil, err
+		}
+		routes = append(routes, r)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return routes, nil
+}
+
+// RouteOrderItems places every order item that is not yet on a ticket onto a ticket of its station
+func (repo *StationRepository) RouteOrderItems(ctx context.Context, orderID string) error {
+	tx, err := repo.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("begin tx: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := routeOrderItems(ctx, tx, orderID); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("commit: %w", err)
+	}
+	return nil
+}
+
+// RouteOrderItemsWithTx routes unassigned order items within an existing transaction
+func (repo *StationRepository) RouteOrderItemsWithTx(ctx context.Context, tx *Transaction, orderID string) error {
+	return routeOrderItems(ctx, tx.tx, orderID)
+}
+
+// routeOrderItems resolves the station of each unrouted item (menu item route first, then the first
+// routed category of the item, then the default station) and attaches it to the order's pending
+// ticket for that station, opening a new ticket when there is none.
+func routeOrderItems(ctx context.Context, q queryer, orderID string) error {
+	query := `
+		SELECT
+			oi.order_item_id,
+			COALESCE(
+				(SELECT sr.station_id FROM station_routes sr WHERE sr.menu_item_id = oi.menu_item_id),
+				(SELECT sr.station_id
+				 FROM unnest(mi.categories) WITH ORDINALITY AS c(category, position)
+				 JOIN station_routes sr ON sr.category = c.category
+				 ORDER BY c.position
+				 LIMIT 1),
+				(SELECT s.station_id FROM stations s WHERE s.is_default)
+			) AS station_id
+		FROM order_items oi
+		JOIN menu_items mi ON mi.menu_item_id = oi.menu_item_id
+		LEFT JOIN station_ticket_items sti ON sti.order_item_id = oi.order_item_id
+		WHERE oi.order_id = $1 AND sti.order_item_id IS NULL
+	`
+
+	rows, err := q.QueryContext(ctx, query, orderID)
+	if err != nil {
+		return fmt.Errorf("resolve item stations: %w", err)
+	}
+
+	itemsByStation := make(map[string][]string)
+	var stationOrder []string
+	for rows.Next() {
+		var orderItemID string
+		var stationID sql.NullString
+		if err := rows.Scan(&orderItemID, &stationID); err != nil {
+			rows.Close()
+			return fmt.Errorf("scan item station: %w", err)
+		}
+		// Without a matching route and without a default station the item stays unrouted
+		if !stationID.Valid {
+			continue
+		}
+		if _, exists := itemsByStation[stationID.String]; !exists {
+			stationOrder = append(stationOrder, stationID.String)
+		}
+		itemsByStation[stationID.String] = append(itemsByStation[stationID.String], orderItemID)
+	}
+	if err := rows.Err(); err != nil {
+		rows.Close()
+		return fmt.Errorf("iterate item stations: %w", err)
+	}
+	rows.Close()
+
+	for _, stationID := range stationOrder {
+		var ticketID string
+		err := q.QueryRowContext(ctx, `
+			SELECT ticket_id FROM station_tickets
+			WHERE order_id = $1 AND station_id = $2 AND status = 'pending'
+			ORDER BY created_at
+			LIMIT 1
+		`, orderID, stationID).Scan(&ticketID)
+		if err == sql.ErrNoRows {
+			err = q.QueryRowContext(ctx, `
+				INSERT INTO station_tickets (order_id, station_id)
+				VALUES ($1, $2)
+				RETURNING ticket_id
+			`, orderID, stationID).Scan(&ticketID)
+		}
+		if err != nil {
+			return fmt.Errorf("get ticket for station %s: %w", stationID, err)
+		}
+
+		_, err = q.ExecContext(ctx, `
+			INSERT INTO station_ticket_items (order_item_id, ticket_id)
+			SELECT unnest($1::UUID[]), $2
+		`, pq.Array(itemsByStation[stationID]), ticketID)
+		if err != nil {
+			return fmt.Errorf("attach items to ticket %s: %w", ticketID, err)
+		}
+	}
+
+	return nil
+}
+
+// GetStationTickets returns the tickets of a station with the given statuses, oldest first. Only the
+// tickets of orders placed at the store are returned unless storeID is empty.
+func (repo *StationRepository) GetStationTickets(ctx context.Context, storeID, stationID string, statuses []string) ([]entity.StationTicket, error) {
+	query := `
+	SELECT t.ticket_id, t.order_id, t.station_id, t.status, t.created_at, t.started_at, t.completed_at, t.updated_at
+	FROM station_tickets t
+	JOIN orders o ON o.order_id = t.order_id
+	WHERE t.station_id = $1 AND t.status::TEXT = ANY($2)
+	  AND ($3::uuid IS NULL OR o.store_id = $3)
+	ORDER BY t.created_at
+	`
+	return getTickets(ctx, repo.db, query, stationID, pq.Array(statuses), nullIfEmpty(storeID))
+}
+
+// GetOrderTicketsWithTx returns the tickets of an order as the transaction sees them
+func (repo *StationRepository) GetOrderTicketsWithTx(ctx context.Context, tx *Transaction, orderID string) ([]entity.StationTicket, error) {
+	query := `
+	SELECT ticket_id, order_id, station_id, status, created_at, started_at, completed_at, updated_at
+	FROM station_tickets
+	WHERE order_id = $1
+	ORDER BY created_at
+	`
+	return getTickets(ctx, tx.tx, query, orderID)
+}
+
+func (repo *StationRepository) GetTicketByID(ctx context.Context, ticketID string) (entity.StationTicket, error) {
+	return getTicketByID(ctx, repo.db, ticketID)
+}
+
+// GetTicketByIDWithTx returns a ticket as the transaction sees it
+func (repo *StationRepository) GetTicketByIDWithTx(ctx context.Context, tx *Transaction, ticketID string) (entity.StationTicket, error) {
+	return getTicketByID(ctx, tx.tx, ticketID)
+}
+
+func getTicketByID(ctx context.Context, q queryer, ticketID string) (entity.StationTicket, error) {
+	query := `
+	SELECT ticket_id, order_id, station_id, status, created_at, started_at, completed_at, updated_at
+	FROM station_tickets
+	WHERE ticket_id = $1
+	`
+	tickets, err := getTickets(ctx, q, query, ticketID)
+	if err != nil {
+		return entity.StationTicket{}, err
+	}
+	if len(tickets) == 0 {
+		return entity.StationTicket{}, sql.ErrNoRows
+	}
+	return tickets[0], nil
+}
+
+// UpdateTicketStatusWithTx moves a ticket to a new status and stamps when work started or finished
+func (repo *StationRepository) UpdateTicketStatusWithTx(ctx context.Context, tx *Transaction, ticketID string, status string) error {
+	query := `
+	UPDATE station_tickets
+	SET status = $1,
+		started_at = CASE WHEN $1 IN ('in_progress', 'done') THEN COALESCE(started_at, $2) ELSE started_at END,
+		completed_at = CASE WHEN $1 = 'done' THEN $2 ELSE completed_at END
+	WHERE ticket_id = $3
+	`
+	result, err := tx.tx.ExecContext(ctx, query, status, time.Now(), ticketID)
+	if err != nil {
+		return fmt.Errorf("update ticket status: %w", err)
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("update ticket status: %w", err)
+	}
+	if affected == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+// getTickets runs a ticket query and loads the items of every returned ticket
+func getTickets(ctx context.Context, q queryer, query string, args ...interface{}) ([]entity.StationTicket, error) {
+	rows, err := q.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("query tickets: %w", err)
+	}
+	defer rows.Close()
+
+	var tickets []entity.StationTicket
+	var ticketIDs []string
+	for rows.Next() {
+		var t entity.StationTicket
+		var startedAt, completedAt sql.NullTime
+		if err := rows.Scan(
+			&t.TicketID,
+			&t.OrderID,
+			&t.StationID,
+			&t.Status,
+			&t.CreatedAt,
+			&startedAt,
+			&completedAt,
+			&t.UpdatedAt,
+		); err != nil {
+			return nil, fmt.Errorf("scan ticket: %w", err)
+		}
+		t.StartedAt = nullTimePtr(startedAt)
+		t.CompletedAt = nullTimePtr(completedAt)
+		tickets = append(tickets, t)
+		ticketIDs = append(ticketIDs, t.TicketID)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate tickets: %w", err)
+	}
+
+	if len(tickets) == 0 {
+		return tickets, nil
+	}
+
+	itemRows, err := q.QueryContext(ctx, `
+		SELECT sti.ticket_id, oi.order_item_id, oi.menu_item_id, mi.name, oi.quantity, oi.customizations
+		FROM station_ticket_items sti
+		JOIN order_items oi ON oi.order_item_id = sti.order_item_id
+		JOIN menu_items mi ON mi.menu_item_id = oi.menu_item_id
+		WHERE sti.ticket_id = ANY($1::UUID[])
+		ORDER BY mi.name
+	`, pq.Array(ticketIDs))
+	if err != nil {
+		return nil, fmt.Errorf("query ticket items: %w", err)
+	}
+	defer itemRows.Close()
+
+	itemsByTicket := make(map[string][]entity.StationTicketItem)
+	for itemRows.Next() {
+		var ticketID string
+		var item entity.StationTicketItem
+		var customizations sql.NullString
+		if err := itemRows.Scan(
+			&ticketID,
+			&item.OrderItemID,
+			&item.MenuItemID,
+			&item.Name,
+			&item.Quantity,
+			&customizations,
+		); err != nil {
+			return nil, fmt.Errorf("scan ticket item: %w", err)
+		}
+		if customizations.Valid {
+			item.Customizations = json.RawMessage(customizations.String)
+		}
+		itemsByTicket[ticketID] = append(itemsByTicket[ticketID], item)
+	}
+	if err := itemRows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate ticket items: %w", err)
+	}
+
+	for i := range tickets {
+		tickets[i].Items = itemsByTicket[tickets[i].TicketID]
+	}
+	return tickets, nil
+}
+
+// DeleteEmptyTicketsWithTx removes the pending tickets of an order that no longer hold any items
+func (repo *StationRepository) DeleteEmptyTicketsWithTx(ctx context.Context, tx *Transaction, orderID string) error {
+	query := `
+		DELETE FROM station_tickets st
+		WHERE st.order_id = $1
+		  AND st.status = 'pending'
+		  AND NOT EXISTS (SELECT 1 FROM station_ticket_items sti WHERE sti.ticket_id = st.ticket_id)
+	`
+	if _, err := tx.tx.ExecContext(ctx, query, orderID); err != nil {
+		return fmt.Errorf("delete empty tickets: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,96 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"frappuccino/internal/entity"
+)
+
+type StoreRepository struct {
+	db *sql.DB
+}
+
+func NewStoreRepository(db *sql.DB) *StoreRepository {
+	return &StoreRepository{
+		db: db,
+	}
+}
+
+func (repo *StoreRepository) CreateStore(ctx context.Context, store entity.Store) (string, error) {
+	var storeID string
+	query := `
+		INSERT INTO stores (code, name, address, phone, is_active)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING store_id
+	`
+	err := repo.db.QueryRowContext(ctx, query, store.Code, store.Name, store.Address, store.Phone, store.IsActive).Scan(&storeID)
+	if err != nil {
+		return "", fmt.Errorf("insert store: %w", err)
+	}
+	return storeID, nil
+}
+
+func (repo *StoreRepository) GetStores(ctx context.Context) ([]entity.Store, error) {
+	query := `
+		SELECT store_id, code, name, address, phone, is_active, created_at
+		FROM stores
+		ORDER BY name
+	`
+
+	rows, err := repo.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("query stores: %w", err)
+	}
+	defer rows.Close()
+
+	var stores []entity.Store
+	for rows.Next() {
+		store, err := scanStore(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scan store: %w", err)
+		}
+		stores = append(stores, store)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate stores: %w", err)
+	}
+
+	return stores, nil
+}
+
+// GetStore finds a store by its ID or its code
+func (repo *StoreRepository) GetStore(ctx context.Context, ref string) (entity.Store, error) {
+	query := `
+		SELECT store_id, code, name, address, phone, is_active, created_at
+		FROM stores
+		WHERE store_id::text = $1 OR code = $1
+	`
+	return scanStore(repo.db.QueryRowContext(ctx, query, ref))
+}
+
+func scanStore(row rowScanner) (entity.Store, error) {
+	var s entity.Store
+	err := row.Scan(&s.StoreID, &s.Code, &s.Name, &s.Address, &s.Phone, &s.IsActive, &s.CreatedAt)
+	return s, err
+}
+
+// storeFilter limits a query to one store; an empty storeID leaves it across all stores.
+// The condition continues the numbering of args.
+func storeFilter(column, storeID string, args []interface{}) (string, []interface{}) {
+	if storeID == "" {
+		return "", args
+	}
+	args = append(args, storeID)
+	return fmt.Sprintf(" AND %s = $%d", column, len(args)), args
+}
+
+// nullIfEmpty passes an empty store ID as NULL, for queries that treat a NULL store as all stores
+func nullIfEmpty(storeID string) interface{} {
+	if storeID == "" {
+		return nil
+	}
+	return storeID
+}
@@ -0,0 +1,269 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"frappuccino/internal/entity"
+)
+
+type TableRepository struct {
+	db *sql.DB
+}
+
+func NewTableRepository(db *sql.DB) *TableRepository {
+	return &TableRepository{
+		db: db,
+	}
+}
+
+func (repo *TableRepository) CreateTable(ctx context.Context, table entity.DiningTable) (string, error) {
+	var tableID string
+	query := `
+		INSERT INTO dining_tables (store_id, table_number, capacity, area)
+		VALUES ($1, $2, $3, $4)
+		RETURNING table_id
+	`
+	err := repo.db.QueryRowContext(ctx, query, table.StoreID, table.TableNumber, table.Capacity, table.Area).Scan(&tableID)
+	if err != nil {
+		return "", fmt.Errorf("insert table: %w", err)
+	}
+	return tableID, nil
+}
+
+func (repo *TableRepository) GetTableByID(ctx context.Context, tableID string) (entity.DiningTable, error) {
+	var t entity.DiningTable
+	query := `
+		SELECT table_id, store_id, table_number, capacity, area, created_at
+		FROM dining_tables
+		WHERE table_id = $1
+	`
+	err := repo.db.QueryRowContext(ctx, query, tableID).Scan(
+		&t.TableID,
+		&t.StoreID,
+		&t.TableNumber,
+		&t.Capacity,
+		&t.Area,
+		&t.CreatedAt,
+	)
+	return t, err
+}
+
+// GetTableOccupancy returns every table of a store, or of all stores when storeID is empty, with its
+// open session and the running total of the tab
+func (repo *TableRepository) GetTableOccupancy(ctx context.Context, storeID string) ([]entity.TableOccupancy, error) {
+	query := `
+		SELECT
+			t.table_id,
+			t.store_id,
+			t.table_number,
+			t.capacity,
+			t.area,
+			t.created_at,
+			s.session_id,
+			s.order_id,
+			s.guests,
+			s.opened_at,
+			COALESCE(o.status::TEXT, ''),
+			COALESCE(o.total_amount, 0),
+			(SELECT COUNT(*) FROM order_items oi WHERE oi.order_id = s.order_id)
+		FROM dining_tables t
+		LEFT JOIN table_sessions s ON s.table_id = t.table_id AND s.closed_at IS NULL
+		LEFT JOIN orders o ON o.order_id = s.order_id
+		WHERE $1::uuid IS NULL OR t.store_id = $1
+		ORDER BY t.store_id, t.table_number
+	`
+
+	rows, err := repo.db.QueryContext(ctx, query, nullIfEmpty(storeID))
+	if err != nil {
+		return nil, fmt.Errorf("query table occupancy: %w", err)
+	}
+	defer rows.Close()
+
+	var tables []entity.TableOccupancy
+	for rows.Next() {
+		var t entity.TableOccupancy
+		var sessionID, orderID sql.NullString
+		var guests sql.NullInt64
+		var openedAt sql.NullTime
+		if err := rows.Scan(
+			&t.TableID,
+			&t.StoreID,
+			&t.TableNumber,
+			&t.Capacity,
+			&t.Area,
+			&t.CreatedAt,
+			&sessionID,
+			&orderID,
+			&guests,
+			&openedAt,
+			&t.OrderStatus,
+			&t.RunningTotal,
+			&t.ItemCount,
+		); err != nil {
+			return nil, fmt.Errorf("scan table occupancy: %w", err)
+		}
+		if sessionID.Valid {
+			t.Session = &entity.TableSession{
+				SessionID: sessionID.String,
+				TableID:   t.TableID,
+				OrderID:   nullStringPtr(orderID),
+				Guests:    int(guests.Int64),
+				OpenedAt:  openedAt.Time,
+			}
+		}
+		tables = append(tables, t)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate table occupancy: %w", err)
+	}
+
+	return tables, nil
+}
+
+// GetOpenSession returns the open session of a table, or sql.ErrNoRows when the table is free
+func (repo *TableRepository) GetOpenSession(ctx context.Context, tableID string) (entity.TableSession, error) {
+	var s entity.TableSession
+	var orderID sql.NullString
+	query := `
+		SELECT session_id, table_id, order_id, guests, opened_at
+		FROM table_sessions
+		WHERE table_id = $1 AND closed_at IS NULL
+	`
+	err := repo.db.QueryRowContext(ctx, query, tableID).Scan(
+		&s.SessionID,
+		&s.TableID,
+		&orderID,
+		&s.Guests,
+		&s.OpenedAt,
+	)
+	s.OrderID = nullStringPtr(orderID)
+	return s, err
+}
+
+// OpenSession seats guests at a table. It reports false when the table already has an open session.
+func (repo *TableRepository) OpenSession(ctx context.Context, tableID string, guests int) (string, bool, error) {
+	var sessionID string
+	query := `
+		INSERT INTO table_sessions (table_id, guests)
+		VALUES ($1, $2)
+		ON CONFLICT (table_id) WHERE closed_at IS NULL DO NOTHING
+		RETURNING session_id
+	`
+	err := repo.db.QueryRowContext(ctx, query, tableID, guests).Scan(&sessionID)
+	if err == sql.ErrNoRows {
+		return "", false, nil
+	}
+	if err != nil {
+		return "", false, fmt.Errorf("open table session: %w", err)
+	}
+	return sessionID, true, nil
+}
+
+// AttachSessionOrderWithTx links the tab order to a session within the transaction inserting the order
+func (repo *TableRepository) AttachSessionOrderWithTx(ctx context.Context, tx *Transaction, sessionID, orderID string) error {
+	_, err := tx.tx.ExecContext(ctx, `UPDATE table_sessions SET order_id = $2 WHERE session_id = $1`, sessionID, orderID)
+	if err != nil {
+		return fmt.Errorf("attach session order: %w", err)
+	}
+	return nil
+}
+
+// DeleteSession frees a table whose tab could not be opened
+func (repo *TableRepository) DeleteSession(ctx context.Context, sessionID string) error {
+	_, err := repo.db.ExecContext(ctx, `DELETE FROM table_sessions WHERE session_id = $1`, sessionID)
+	if err != nil {
+		return fmt.Errorf("delete session: %w", err)
+	}
+	return nil
+}
+
+// CloseSessionForOrder closes the open session whose tab is the given order, if there is one
+func (repo *TableRepository) CloseSessionForOrder(ctx context.Context, orderID string) error {
+	query := `
+		UPDATE table_sessions
+		SET closed_at = CURRENT_TIMESTAMP
+		WHERE order_id = $1 AND closed_at IS NULL
+	`
+	if _, err := repo.db.ExecContext(ctx, query, orderID); err != nil {
+		return fmt.Errorf("close session: %w", err)
+	}
+	return nil
+}
+
+// HasOpenTabWithTx reports whether the order is the open tab of a table
+func (repo *TableRepository) HasOpenTabWithTx(ctx context.Context, tx *Transaction, orderID string) (bool, error) {
+	var open bool
+	query := `SELECT EXISTS (SELECT 1 FROM table_sessions WHERE order_id = $1 AND closed_at IS NULL)`
+	if err := tx.tx.QueryRowContext(ctx, query, orderID).Scan(&open); err != nil {
+		return false, fmt.Errorf("check open tab: %w", err)
+	}
+	return open, nil
+}
+
+// MoveOpenSessionWithTx makes another order the open tab of the session that holds the given order
+func (repo *TableRepository) MoveOpenSessionWithTx(ctx context.Context, tx *Transaction, fromOrderID, toOrderID string) error {
+	query := `
+		UPDATE table_sessions
+		SET order_id = $2
+		WHERE order_id = $1 AND closed_at IS NULL
+	`
+	if _, err := tx.tx.ExecContext(ctx, query, fromOrderID, toOrderID); err != nil {
+		return fmt.Errorf("move open session: %w", err)
+	}
+	return nil
+}
+
+// GetClosedSessions returns the sessions opened within the date range that have been closed
+func (repo *TableRepository) GetClosedSessions(ctx context.Context, storeID string, startDate, endDate *time.Time) ([]entity.TableSessionTiming, error) {
+	filter, args := createdAtFilter("s.opened_at", startDate, endDate)
+	storeCond, args := storeFilter("t.store_id", storeID, args)
+	filter += storeCond
+	query := `
+		SELECT
+			t.table_id,
+			t.table_number,
+			t.area,
+			s.guests,
+			s.opened_at,
+			s.closed_at,
+			COALESCE(o.total_amount, 0)
+		FROM table_sessions s
+		JOIN dining_tables t ON t.table_id = s.table_id
+		LEFT JOIN orders o ON o.order_id = s.order_id AND o.status != 'cancelled'
+		WHERE s.closed_at IS NOT NULL` + filter + `
+		ORDER BY t.table_number, s.opened_at
+	`
+
+	rows, err := repo.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("query closed sessions: %w", err)
+	}
+	defer rows.Close()
+
+	var sessions []entity.TableSessionTiming
+	for rows.Next() {
+		var s entity.TableSessionTiming
+		if err := rows.Scan(
+			&s.TableID,
+			&s.TableNumber,
+			&s.Area,
+			&s.Guests,
+			&s.OpenedAt,
+			&s.ClosedAt,
+			&s.Total,
+		); err != nil {
+			return nil, fmt.Errorf("scan closed session: %w", err)
+		}
+		sessions = append(sessions, s)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate closed sessions: %w", err)
+	}
+
+	return sessions, nil
+}
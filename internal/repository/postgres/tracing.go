@@ -0,0 +1,175 @@
+package postgres
+
+import (
+	"context"
+	"database/sql/driver"
+	"io"
+	"strings"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+
+	"frappuccino/internal/tracing"
+)
+
+var tracer = otel.Tracer("frappuccino/internal/repository/postgres")
+
+// tracedConnector wraps the connections of the Postgres driver so that every statement the
+// repositories run, in a transaction or not, is a span of the trace of its context
+type tracedConnector struct {
+	driver.Connector
+}
+
+func (c tracedConnector) Connect(ctx context.Context) (driver.Conn, error) {
+	conn, err := c.Connector.Connect(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &tracedConn{Conn: conn}, nil
+}
+
+// tracedConn traces the statements run on a connection. Prepared statements are not traced, as
+// the repositories run their queries directly and the driver does not fall back to preparing.
+type tracedConn struct {
+	driver.Conn
+}
+
+func (c *tracedConn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	queryer, ok := c.Conn.(driver.QueryerContext)
+	if !ok {
+		return nil, driver.ErrSkip
+	}
+	ctx, span := startQuery(ctx, query)
+	rows, err := queryer.QueryContext(ctx, query, args)
+	if err != nil {
+		tracing.End(span, err)
+		return nil, err
+	}
+	// The span lasts until the rows are read, so that it covers fetching them too
+	return &tracedRows{Rows: rows, span: span}, nil
+}
+
+func (c *tracedConn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	execer, ok := c.Conn.(driver.ExecerContext)
+	if !ok {
+		return nil, driver.ErrSkip
+	}
+	ctx, span := startQuery(ctx, query)
+	result, err := execer.ExecContext(ctx, query, args)
+	if err == nil {
+		if affected, rowsErr := result.RowsAffected(); rowsErr == nil {
+			span.SetAttributes(attribute.Int64("db.rows_affected", affected))
+		}
+	}
+	tracing.End(span, err)
+	return result, err
+}
+
+func (c *tracedConn) PrepareContext(ctx context.Context, query string) (driver.Stmt, error) {
+	if preparer, ok := c.Conn.(driver.ConnPrepareContext); ok {
+		return preparer.PrepareContext(ctx, query)
+	}
+	return c.Conn.Prepare(query)
+}
+
+func (c *tracedConn) BeginTx(ctx context.Context, opts driver.TxOptions) (driver.Tx, error) {
+	beginner, ok := c.Conn.(driver.ConnBeginTx)
+	if !ok {
+		return c.Conn.Begin()
+	}
+	_, span := startQuery(ctx, "BEGIN")
+	tx, err := beginner.BeginTx(ctx, opts)
+	tracing.End(span, err)
+	if err != nil {
+		return nil, err
+	}
+	return &tracedTx{Tx: tx, ctx: ctx}, nil
+}
+
+func (c *tracedConn) Ping(ctx context.Context) error {
+	if pinger, ok := c.Conn.(driver.Pinger); ok {
+		return pinger.Ping(ctx)
+	}
+	return nil
+}
+
+func (c *tracedConn) ResetSession(ctx context.Context) error {
+	if resetter, ok := c.Conn.(driver.SessionResetter); ok {
+		return resetter.ResetSession(ctx)
+	}
+	return nil
+}
+
+func (c *tracedConn) IsValid() bool {
+	if validator, ok := c.Conn.(driver.Validator); ok {
+		return validator.IsValid()
+	}
+	return true
+}
+
+// tracedTx traces the end of a transaction in the trace it was started in
+type tracedTx struct {
+	driver.Tx
+	ctx context.Context
+}
+
+func (t *tracedTx) Commit() error {
+	_, span := startQuery(t.ctx, "COMMIT")
+	err := t.Tx.Commit()
+	tracing.End(span, err)
+	return err
+}
+
+func (t *tracedTx) Rollback() error {
+	_, span := startQuery(t.ctx, "ROLLBACK")
+	err := t.Tx.Rollback()
+	tracing.End(span, err)
+	return err
+}
+
+// tracedRows ends the span of a query once its rows are closed, recording how many were read
+type tracedRows struct {
+	driver.Rows
+	span trace.Span
+	read int64
+	err  error
+}
+
+func (r *tracedRows) Next(dest []driver.Value) error {
+	err := r.Rows.Next(dest)
+	switch {
+	case err == nil:
+		r.read++
+	case err != io.EOF:
+		r.err = err
+	}
+	return err
+}
+
+func (r *tracedRows) Close() error {
+	err := r.Rows.Close()
+	r.span.SetAttributes(attribute.Int64("db.rows_returned", r.read))
+	if r.err == nil {
+		r.err = err
+	}
+	tracing.End(r.span, r.err)
+	return err
+}
+
+// startQuery starts the span of a statement, named by its operation such as SELECT or UPDATE
+func startQuery(ctx context.Context, query string) (context.Context, trace.Span) {
+	operation := "QUERY"
+	if fields := strings.Fields(query); len(fields) > 0 {
+		operation = strings.ToUpper(fields[0])
+	}
+	return tracer.Start(ctx, operation,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			semconv.DBSystemPostgreSQL,
+			semconv.DBOperationName(operation),
+			semconv.DBQueryText(query),
+		),
+	)
+}
@@ -12,6 +12,18 @@ type Transaction struct {
 	tx *sql.Tx
 }
 
+// queryer is satisfied by both *sql.DB and *sql.Tx, so helpers can run inside or outside a transaction
+type queryer interface {
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
+}
+
+// rowScanner is satisfied by both *sql.Row and *sql.Rows, so a row can be scanned the same way from either
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
 // Begin starts a new transaction
 func (repo *OrderRepository) Begin(ctx context.Context) (*Transaction, error) {
 	tx, err := repo.db.BeginTx(ctx, nil)
@@ -32,43 +44,8 @@ func (t *Transaction) Rollback() error {
 }
 
 // CreateOrderWithTx creates an order within an existing transaction
-func (repo *OrderRepository) CreateOrderWithTx(ctx context.Context, tx *Transaction, order entity.Order, items []entity.OrderItem) (string, error) {
-	var orderID string
-	orderQuery := `
-		INSERT INTO orders (customer_name, special_instructions, total_amount, status, created_at, updated_at)
-		VALUES ($1, $2, $3, $4, $5, $6)
-		RETURNING order_id;
-	`
-	err := tx.tx.QueryRowContext(ctx, orderQuery,
-		order.CustomerName,
-		order.SpecialInstructions,
-		order.TotalAmount,
-		order.Status,
-		order.CreatedAt,
-		order.UpdatedAt,
-	).Scan(&orderID)
-	if err != nil {
-		return "", fmt.Errorf("insert order: %w", err)
-	}
-
-	itemQuery := `
-		INSERT INTO order_items (order_id, menu_item_id, quantity, price_at_time, customizations)
-		VALUES ($1, $2, $3, $4, $5)
-	`
-	for _, item := range items {
-		_, err := tx.tx.ExecContext(ctx, itemQuery,
-			orderID,
-			item.MenuItemID,
-			item.Quantity,
-			item.PriceAtTime,
-			item.Customizations,
-		)
-		if err != nil {
-			return "", fmt.Errorf("insert order item: %w", err)
-		}
-	}
-
-	return orderID, nil
+func (repo *OrderRepository) CreateOrderWithTx(ctx context.Context, tx *Transaction, order entity.Order, items []entity.OrderItem) (string, int, error) {
+	return insertOrder(ctx, tx.tx, order, items)
 }
 
 // UpdateInventoryWithTx updates inventory within a transaction
@@ -83,14 +60,15 @@ func (repo *InventoryRepository) UpdateInventoryWithTx(ctx context.Context, tx *
 func (repo *InventoryRepository) CreateInventoryTransactionWithTx(ctx context.Context, tx *Transaction, transaction entity.InventoryTransaction) error {
 	query := `
         INSERT INTO inventory_transactions 
-        (ingredient_id, quantity_change, transaction_type, reason) 
-        VALUES ($1, $2, $3, $4);
+        (ingredient_id, quantity_change, transaction_type, reason, transfer_id) 
+        VALUES ($1, $2, $3, $4, $5);
     `
 	_, err := tx.tx.ExecContext(ctx, query,
 		transaction.IngredientID,
 		transaction.QuantityChange,
 		transaction.TransactionType,
-		transaction.Reason)
+		transaction.Reason,
+		transaction.TransferID)
 
 	return err
 }
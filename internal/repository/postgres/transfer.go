@@ -0,0 +1,118 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+
+	"frappuccino/internal/entity"
+)
+
+// TransferStock moves stock of an ingredient to the row of the same name at another store, creating
+// that row when the store does not stock the ingredient yet, and posts a transfer_out and a
+// transfer_in transaction sharing one transfer ID. It reports false without changing anything when
+// the source row has less stock than the quantity once reservations for scheduled orders are held back.
+func (repo *InventoryRepository) TransferStock(ctx context.Context, transfer entity.StockTransfer) (entity.StockTransfer, bool, error) {
+	tx, err := repo.db.BeginTx(ctx, nil)
+	if err != nil {
+		return entity.StockTransfer{}, false, fmt.Errorf("begin transfer: %w", err)
+	}
+	defer tx.Rollback()
+
+	var source entity.Inventory
+	err = tx.QueryRowContext(ctx, `
+		SELECT store_id, name, unit, unit_price, reorder_point
+		FROM inventory
+		WHERE ingredient_id = $1
+	`, transfer.FromIngredientID).Scan(&source.StoreID, &source.Name, &source.Unit, &source.UnitPrice, &source.ReorderPoint)
+	if err != nil {
+		return entity.StockTransfer{}, false, err
+	}
+	transfer.FromStoreID = source.StoreID
+
+	_, err = tx.ExecContext(ctx, `
+		INSERT INTO inventory (store_id, name, quantity, unit, unit_price, reorder_point)
+		VALUES ($1, $2, 0, $3, $4, $5)
+		ON CONFLICT (store_id, name) DO NOTHING
+	`, transfer.ToStoreID, source.Name, source.Unit, source.UnitPrice, source.ReorderPoint)
+	if err != nil {
+		return entity.StockTransfer{}, false, fmt.Errorf("create destination stock: %w", err)
+	}
+
+	// Both rows are locked in one statement in ingredient_id order, so two opposite transfers
+	// between the same stores cannot deadlock
+	rows, err := tx.QueryContext(ctx, `
+		SELECT ingredient_id, store_id, quantity
+		FROM inventory
+		WHERE name = $1 AND store_id IN ($2, $3)
+		ORDER BY ingredient_id
+		FOR UPDATE
+	`, source.Name, transfer.FromStoreID, transfer.ToStoreID)
+	if err != nil {
+		return entity.StockTransfer{}, false, fmt.Errorf("lock stock: %w", err)
+	}
+	var available float32
+	for rows.Next() {
+		var ingredientID, storeID string
+		var quantity float32
+		if err := rows.Scan(&ingredientID, &storeID, &quantity); err != nil {
+			rows.Close()
+			return entity.StockTransfer{}, false, fmt.Errorf("scan stock: %w", err)
+		}
+		if storeID == transfer.ToStoreID {
+			transfer.ToIngredientID = ingredientID
+		} else {
+			available = quantity
+		}
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return entity.StockTransfer{}, false, fmt.Errorf("iterate stock: %w", err)
+	}
+
+	var reserved float32
+	err = tx.QueryRowContext(ctx, `
+		SELECT COALESCE(SUM(quantity), 0)
+		FROM inventory_reservations
+		WHERE ingredient_id = $1
+	`, transfer.FromIngredientID).Scan(&reserved)
+	if err != nil {
+		return entity.StockTransfer{}, false, fmt.Errorf("read reserved stock: %w", err)
+	}
+	if available-reserved < transfer.Quantity {
+		return entity.StockTransfer{}, false, nil
+	}
+
+	moveQuery := `
+		UPDATE inventory
+		SET quantity = quantity + $2, last_updated = CURRENT_TIMESTAMP
+		WHERE ingredient_id = $1
+	`
+	if _, err := tx.ExecContext(ctx, moveQuery, transfer.FromIngredientID, -transfer.Quantity); err != nil {
+		return entity.StockTransfer{}, false, fmt.Errorf("take stock from source: %w", err)
+	}
+	if _, err := tx.ExecContext(ctx, moveQuery, transfer.ToIngredientID, transfer.Quantity); err != nil {
+		return entity.StockTransfer{}, false, fmt.Errorf("add stock to destination: %w", err)
+	}
+
+	err = tx.QueryRowContext(ctx, `SELECT gen_random_uuid(), CURRENT_TIMESTAMP`).Scan(&transfer.TransferID, &transfer.CreatedAt)
+	if err != nil {
+		return entity.StockTransfer{}, false, fmt.Errorf("generate transfer id: %w", err)
+	}
+
+	sides := &Transaction{tx: tx}
+	for _, side := range []entity.InventoryTransaction{
+		{IngredientID: transfer.FromIngredientID, QuantityChange: -transfer.Quantity, TransactionType: "transfer_out"},
+		{IngredientID: transfer.ToIngredientID, QuantityChange: transfer.Quantity, TransactionType: "transfer_in"},
+	} {
+		side.Reason = transfer.Reason
+		side.TransferID = &transfer.TransferID
+		if err := repo.CreateInventoryTransactionWithTx(ctx, sides, side); err != nil {
+			return entity.StockTransfer{}, false, fmt.Errorf("record %s: %w", side.TransactionType, err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return entity.StockTransfer{}, false, fmt.Errorf("commit transfer: %w", err)
+	}
+	return transfer, true, nil
+}
@@ -0,0 +1,140 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+
+	"frappuccino/internal/apperror"
+	"frappuccino/internal/delivery/http/problem"
+	"frappuccino/internal/entity"
+	"frappuccino/internal/service/access"
+	serviceAuth "frappuccino/internal/service/auth"
+	serviceStore "frappuccino/internal/service/store"
+)
+
+// authenticator resolves the caller of a request from a bearer token or an API key
+type authenticator interface {
+	AuthenticateToken(ctx context.Context, token string) (entity.Principal, error)
+	AuthenticateAPIKey(ctx context.Context, key string) (entity.Principal, error)
+}
+
+// publicRoutes can be called without credentials, as they are how a staff user gets them, are
+// probed by the orchestrator, or describe the API
+var publicRoutes = map[string]bool{
+	"POST /auth/login":   true,
+	"POST /auth/refresh": true,
+	"GET /healthz":       true,
+	"GET /readyz":        true,
+	"GET /openapi.json":  true,
+	"GET /docs":          true,
+}
+
+// denialRecorder stores requests refused for the caller's role
+type denialRecorder interface {
+	RecordDenial(ctx context.Context, denial entity.AccessDenial)
+}
+
+// storeResolver finds the store named in the X-Store-ID header
+type storeResolver interface {
+	ResolveStore(ctx context.Context, ref string) (entity.Store, error)
+}
+
+// authenticate rejects requests without a valid access token or API key and stores the caller
+// in the request context for the handlers, along with the store the request works in and where
+// to record permission denials
+func (app *App) authenticate(next http.Handler, auth authenticator, stores storeResolver, recorder denialRecorder) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if publicRoutes[r.Method+" "+r.URL.Path] {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		var principal entity.Principal
+		var err error
+		if key := r.Header.Get("X-API-Key"); key != "" {
+			principal, err = auth.AuthenticateAPIKey(r.Context(), key)
+		} else if token, ok := bearerToken(r); ok {
+			principal, err = auth.AuthenticateToken(r.Context(), token)
+		} else {
+			w.Header().Set("WWW-Authenticate", `Bearer realm="frappuccino"`)
+			problem.Write(w, r, errAuthRequired)
+			return
+		}
+
+		if err != nil {
+			if errors.Is(err, serviceAuth.ErrInvalidToken) || errors.Is(err, serviceAuth.ErrInvalidAPIKey) {
+				w.Header().Set("WWW-Authenticate", `Bearer realm="frappuccino", error="invalid_token"`)
+			} else {
+				app.logger.ErrorContext(r.Context(), "Error authenticating request", "error", err)
+			}
+			problem.Write(w, r, err)
+			return
+		}
+
+		storeID, err := requestStore(r, principal, stores)
+		if err != nil {
+			if !errors.Is(err, errOtherStore) && !errors.Is(err, serviceStore.ErrUnknownStore) {
+				app.logger.ErrorContext(r.Context(), "Error resolving request store", "error", err)
+			}
+			problem.Write(w, r, err)
+			return
+		}
+
+		ctx := access.WithPrincipal(r.Context(), principal)
+		if storeID != "" {
+			ctx = access.WithStore(ctx, storeID)
+		}
+		ctx = access.WithAudit(ctx, recorder, r.Method, r.URL.Path)
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// requireMetricsKey lets only API keys holding the metrics permission, like the one the scraper
+// sends in X-API-Key, read the metrics; staff logins cannot
+func requireMetricsKey(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if principal, _ := access.PrincipalFromContext(r.Context()); principal.Kind != "api_key" {
+			problem.Write(w, r, errAPIKeyRequired)
+			return
+		}
+		if err := access.Check(r.Context(), access.MetricsView); err != nil {
+			problem.Write(w, r, err)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+var (
+	errAuthRequired   = apperror.New(apperror.Unauthorized, "authentication_required", "authentication required")
+	errOtherStore     = apperror.New(apperror.Forbidden, "other_store", "caller is limited to another store")
+	errAPIKeyRequired = apperror.New(apperror.Forbidden, "api_key_required", "this route only accepts API keys")
+)
+
+// requestStore picks the store a request works in. Callers with a home store always work there;
+// others choose a store with the X-Store-ID header, by ID or code, or work across stores without it.
+func requestStore(r *http.Request, principal entity.Principal, stores storeResolver) (string, error) {
+	ref := strings.TrimSpace(r.Header.Get("X-Store-ID"))
+	if ref == "" {
+		return principal.StoreID, nil
+	}
+
+	store, err := stores.ResolveStore(r.Context(), ref)
+	if err != nil {
+		return "", err
+	}
+	if principal.StoreID != "" && store.StoreID != principal.StoreID {
+		return "", errOtherStore
+	}
+	return store.StoreID, nil
+}
+
+func bearerToken(r *http.Request) (string, bool) {
+	scheme, token, ok := strings.Cut(r.Header.Get("Authorization"), " ")
+	if !ok || !strings.EqualFold(scheme, "Bearer") || strings.TrimSpace(token) == "" {
+		return "", false
+	}
+	return strings.TrimSpace(token), true
+}
@@ -0,0 +1,43 @@
+package server
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"frappuccino/internal/entity"
+	"frappuccino/internal/service/access"
+)
+
+func TestRequireMetricsKey(t *testing.T) {
+	tests := []struct {
+		name      string
+		principal *entity.Principal
+		status    int
+	}{
+		{"no caller", nil, http.StatusForbidden},
+		{"staff login", &entity.Principal{Kind: "user", Role: "admin"}, http.StatusForbidden},
+		{"key without the permission", &entity.Principal{Kind: "api_key", Role: "barista"}, http.StatusForbidden},
+		{"key with the permission", &entity.Principal{Kind: "api_key", Role: "shift_lead"}, http.StatusOK},
+	}
+
+	metrics := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			if tt.principal != nil {
+				ctx = access.WithPrincipal(ctx, *tt.principal)
+			}
+			r := httptest.NewRequest(http.MethodGet, "/metrics", nil).WithContext(ctx)
+			w := httptest.NewRecorder()
+
+			requireMetricsKey(metrics).ServeHTTP(w, r)
+			if w.Code != tt.status {
+				t.Errorf("GET /metrics answered %d, want %d", w.Code, tt.status)
+			}
+		})
+	}
+}
@@ -0,0 +1,39 @@
+package server
+
+import (
+	"database/sql"
+
+	"frappuccino/internal/repository/postgres"
+)
+
+// Repositories are the Postgres repositories of the application; the server and the admin
+// commands build their services on the same ones
+type Repositories struct {
+	Store     *postgres.StoreRepository
+	Inventory *postgres.InventoryRepository
+	Menu      *postgres.MenuRepository
+	Order     *postgres.OrderRepository
+	Station   *postgres.StationRepository
+	Table     *postgres.TableRepository
+	Delivery  *postgres.DeliveryRepository
+	Print     *postgres.PrintRepository
+	Search    *postgres.SearchRepository
+	Auth      *postgres.AuthRepository
+	Enum      *postgres.EnumRepository
+}
+
+func NewRepositories(db *sql.DB) *Repositories {
+	return &Repositories{
+		Store:     postgres.NewStoreRepository(db),
+		Inventory: postgres.NewInventoryRepository(db),
+		Menu:      postgres.NewMenuRepository(db),
+		Order:     postgres.NewOrderRepository(db),
+		Station:   postgres.NewStationRepository(db),
+		Table:     postgres.NewTableRepository(db),
+		Delivery:  postgres.NewDeliveryRepository(db),
+		Print:     postgres.NewPrintRepository(db),
+		Search:    postgres.NewSearchRepository(db),
+		Auth:      postgres.NewAuthRepository(db),
+		Enum:      postgres.NewEnumRepository(db),
+	}
+}
@@ -1,108 +1,239 @@
 package server
 
 import (
+	"context"
 	"database/sql"
+	"errors"
 	"fmt"
-	"log"
+	"log/slog"
+	"net"
 	"net/http"
-	"os"
+	"strconv"
+	"sync"
 
 	_ "github.com/lib/pq"
 
 	v1 "frappuccino/internal/delivery/http/v1"
+	serviceAccess "frappuccino/internal/service/access"
+	serviceAuth "frappuccino/internal/service/auth"
+	serviceDelivery "frappuccino/internal/service/delivery"
+	serviceHealth "frappuccino/internal/service/health"
 	serviceInv "frappuccino/internal/service/inventory"
 	serviceMenu "frappuccino/internal/service/menu"
 	serviceOrder "frappuccino/internal/service/order"
+	servicePrinting "frappuccino/internal/service/printing"
+	serviceReceipt "frappuccino/internal/service/receipt"
 	serviceReport "frappuccino/internal/service/report"
+	serviceStation "frappuccino/internal/service/station"
+	serviceStore "frappuccino/internal/service/store"
+	serviceTable "frappuccino/internal/service/table"
 
 	"frappuccino/internal/config"
+	"frappuccino/internal/metrics"
+	"frappuccino/internal/migrate"
 	"frappuccino/internal/repository/postgres"
+	"frappuccino/internal/validate"
 )
 
 type App struct {
-	cfg    *config.Config
-	router *http.ServeMux
-	logger *log.Logger
-	db     *sql.DB
+	cfg     *config.Config
+	router  *http.ServeMux
+	handler http.Handler // router behind the authentication middleware
+	logger  *slog.Logger
+	db      *sql.DB
+	metrics *metrics.Metrics
+
+	// backgroundJobs run for the lifetime of the server
+	backgroundJobs []func(ctx context.Context)
 }
 
-func NewApp(cfg *config.Config) *App {
-	return &App{cfg: cfg}
+func NewApp(cfg *config.Config, logger *slog.Logger) *App {
+	return &App{cfg: cfg, logger: logger}
 }
 
-func (app *App) Initialize() error {
-	logger := log.New(os.Stdout, "http: ", log.LstdFlags)
-	app.logger = logger
+// Initialize connects to the database, waiting for it while ctx allows, and wires the handlers
+func (app *App) Initialize(ctx context.Context) error {
 	app.router = http.NewServeMux()
-	if err := app.setHandler(); err != nil {
-		app.logger.Println("method:Initialize, function:setHandler", err.Error())
+	if err := app.setHandler(ctx); err != nil {
+		app.logger.ErrorContext(ctx, "Error setting up handlers", "error", err)
 		return err
 	}
 	return nil
 }
 
-func (app *App) Run() {
+// Run serves until ctx is cancelled, then stops taking connections and gives the requests in
+// flight and the background jobs the shutdown timeout to finish their work
+func (app *App) Run(ctx context.Context) error {
 	server := &http.Server{
-		Addr:         fmt.Sprintf(":%d", app.cfg.App.Port),
-		Handler:      app.router,
+		Addr:         net.JoinHostPort(app.cfg.App.Host, strconv.Itoa(app.cfg.App.Port)),
+		Handler:      app.handler,
 		ReadTimeout:  app.cfg.App.RTO,
 		WriteTimeout: app.cfg.App.WTO,
+		IdleTimeout:  app.cfg.App.IdleTimeout,
 	}
-	app.logger.Println("Starting server on port", app.cfg.App.Port)
-	if err := server.ListenAndServe(); err != nil {
-		app.logger.Println("server shutdown: ", err)
-		return
+	defer app.db.Close()
+
+	// Jobs get their own context: they stop when it is cancelled after the HTTP server has
+	// drained, finishing the batch they are working on
+	jobsCtx, stopJobs := context.WithCancel(context.Background())
+	defer stopJobs()
+	var jobs sync.WaitGroup
+	for _, job := range app.backgroundJobs {
+		jobs.Add(1)
+		go func() {
+			defer jobs.Done()
+			job(jobsCtx)
+		}()
 	}
-}
 
-func (app *App) setHandler() error {
+	serveErr := make(chan error, 1)
+	go func() {
+		serveErr <- server.ListenAndServe()
+	}()
+	app.logger.Info("Starting server", "addr", server.Addr)
+
+	select {
+	case err := <-serveErr:
+		stopJobs()
+		jobs.Wait()
+		return err
+	case <-ctx.Done():
+	}
+
+	app.logger.Info("Shutting down, waiting for requests and jobs to finish", "timeout", app.cfg.App.ShutdownTimeout)
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), app.cfg.App.ShutdownTimeout)
+	defer cancel()
+
 	var err error
+	if shutdownErr := server.Shutdown(shutdownCtx); shutdownErr != nil {
+		err = fmt.Errorf("drain requests: %w", shutdownErr)
+	}
+	stopJobs()
+	jobsDone := make(chan struct{})
+	go func() {
+		jobs.Wait()
+		close(jobsDone)
+	}()
+	select {
+	case <-jobsDone:
+	case <-shutdownCtx.Done():
+		err = errors.Join(err, errors.New("background jobs did not finish in time"))
+	}
 
-	/*dbConn*/
-	dbConn, err := postgres.NewDbConnInstance(&app.cfg.Repository)
+	if err == nil {
+		app.logger.Info("Server stopped")
+	}
+	return err
+}
 
-	inventoryRepository := postgres.NewInventoryRepository(dbConn)
-	inventoryService := serviceInv.NewInventoryService(inventoryRepository, app.logger)
+func (app *App) setHandler(ctx context.Context) error {
+	var err error
 
-	v1.SetInventoryHandler(app.router, inventoryService, app.logger)
+	/*dbConn*/
+	dbConn, err := postgres.NewDbConnInstance(ctx, &app.cfg.Repository, app.logger)
 	if err != nil {
-		app.logger.Println("Connection to db failed")
+		app.logger.ErrorContext(ctx, "Connection to db failed", "error", err)
 		return err
 	}
+	app.db = dbConn
+	repos := NewRepositories(dbConn)
+
+	// Request bodies are checked against the enum types of the schema, like unit_type
+	enums, err := repos.Enum.GetEnums(ctx)
+	if err != nil {
+		return fmt.Errorf("load enum types: %w", err)
+	}
+	for name, values := range enums {
+		validate.RegisterEnum(name, values...)
+	}
 
-	menuRepository := postgres.NewMenuRepository(dbConn)
-	menuService := serviceMenu.NewMenuService(menuRepository, app.logger)
+	// Prometheus metrics of the requests, the connection pool, orders and stock, for the scraper's API key
+	app.metrics = metrics.New()
+	app.metrics.RegisterDB(dbConn, "frappuccino")
+	app.metrics.RegisterStock(repos.Inventory, app.logger)
+	app.router.Handle("GET /metrics", requireMetricsKey(app.metrics.Handler()))
 
-	v1.SetMenuHandler(app.router, menuService, app.logger)
+	// Liveness and readiness probes; ready once the database answers and is migrated
+	migrator, err := migrate.New(dbConn, app.logger)
 	if err != nil {
-		app.logger.Println("Connection to db failed")
 		return err
 	}
+	healthService := serviceHealth.NewHealthService(dbConn, migrator, app.logger)
+	v1.SetHealthHandler(app.router, healthService, app.logger)
+
+	// The OpenAPI document of the routes, and a page to browse it
+	v1.SetDocsHandler(app.router, app.logger)
+
+	// Locations of the business; stock, orders, prices and reports are kept per store
+	storeService := serviceStore.NewStoreService(repos.Store, app.logger)
+	v1.SetStoreHandler(app.router, storeService, app.logger)
+
+	inventoryService := serviceInv.NewInventoryService(repos.Inventory, repos.Store, app.metrics, app.logger)
+
+	v1.SetInventoryHandler(app.router, inventoryService, app.logger)
+
+	menuService := serviceMenu.NewMenuService(repos.Menu, app.logger)
+
+	v1.SetMenuHandler(app.router, menuService, app.logger)
 
-	orderRepository := postgres.NewOrderRepository(dbConn)
 	orderService := serviceOrder.NewOrderService(
-		orderRepository,
-		menuRepository,      // Required for ingredient checks
-		inventoryRepository, // Required for inventory updates
+		repos.Order,
+		repos.Menu,      // Required for ingredient checks
+		repos.Inventory, // Required for inventory updates
+		repos.Station,   // Required for station ticket routing
+		repos.Table,     // Required for dine-in tabs
+		repos.Delivery,  // Required for delivery fees and couriers
+		repos.Print,     // Required for kitchen ticket printing
+		app.cfg.ETA,
+		app.cfg.Schedule,
+		app.cfg.Numbering,
+		app.metrics,
 		app.logger,
 	)
 
-	v1.SetOrderHandler(app.router, orderService, app.logger)
-	if err != nil {
-		app.logger.Println("Connection to db failed")
-		return err
-	}
+	// Release scheduled pre-orders to the kitchen ahead of their pickup time
+	app.backgroundJobs = append(app.backgroundJobs, orderService.RunScheduler)
+
+	receiptService := serviceReceipt.NewReceiptService(orderService, repos.Store, app.cfg.Store, app.logger)
 
-	// Add search repository and service
-	searchRepository := postgres.NewSearchRepository(dbConn)
+	v1.SetOrderHandler(app.router, orderService, receiptService, app.logger)
+
+	// Send queued kitchen tickets to the station printers
+	printService := servicePrinting.NewPrintService(repos.Print, receiptService, app.cfg.Printing, app.logger)
+	app.backgroundJobs = append(app.backgroundJobs, printService.RunWorker)
+	v1.SetPrintHandler(app.router, printService, app.logger)
+
+	stationService := serviceStation.NewStationService(repos.Station, repos.Order, app.logger)
+	v1.SetStationHandler(app.router, stationService, app.logger)
+
+	deliveryService := serviceDelivery.NewDeliveryService(repos.Delivery, app.logger)
+	v1.SetDeliveryHandler(app.router, deliveryService, app.logger)
+
+	tableService := serviceTable.NewTableService(repos.Table, orderService, app.logger)
+	v1.SetTableHandler(app.router, tableService, app.logger)
+
+	// Add search service
 	searchService := serviceReport.NewSearchService(
-		searchRepository,
-		orderRepository, // Pass the orderRepository
+		repos.Search,
+		repos.Order, // Pass the order repository
+		app.cfg.Report,
 		app.logger,
 	)
 
 	// Add report handler
 	v1.SetReportHandler(app.router, searchService, app.logger)
 
+	// Staff logins and API keys; every other route requires one of them
+	authService, err := serviceAuth.NewAuthService(repos.Auth, storeService, app.cfg.Auth, app.logger)
+	if err != nil {
+		return err
+	}
+	v1.SetAuthHandler(app.router, authService, app.logger)
+
+	// Staff roles; denied requests are recorded for managers to review
+	accessService := serviceAccess.NewAccessService(repos.Auth, app.logger)
+	v1.SetAccessHandler(app.router, accessService, app.logger)
+	app.handler = app.traceRequests(app.logRequests(app.authenticate(app.router, authService, storeService, accessService)))
+
 	return nil
 }
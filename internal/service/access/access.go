@@ -0,0 +1,118 @@
+package access
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"frappuccino/internal/apperror"
+	"frappuccino/internal/dto/access"
+	"frappuccino/internal/entity"
+)
+
+const defaultDenialsLimit = 100
+
+var ErrForbidden = apperror.New(apperror.Forbidden, "forbidden", "forbidden")
+
+// denialRecorder stores refused requests so managers can see who tried what
+type denialRecorder interface {
+	RecordDenial(ctx context.Context, denial entity.AccessDenial)
+}
+
+type auditKey struct{}
+
+type audit struct {
+	recorder denialRecorder
+	method   string
+	path     string
+}
+
+// WithAudit makes Check record denials of the request with the given method and path
+func WithAudit(ctx context.Context, recorder denialRecorder, method, path string) context.Context {
+	return context.WithValue(ctx, auditKey{}, audit{recorder: recorder, method: method, path: path})
+}
+
+// Check returns ErrForbidden unless the caller in the context holds the permission. Requests
+// without a caller are refused, so a route that skipped authentication cannot slip through.
+func Check(ctx context.Context, permission Permission) error {
+	principal, ok := PrincipalFromContext(ctx)
+	if !ok {
+		return ErrForbidden.WithDetail("no authenticated caller")
+	}
+
+	role := Role(principal.Role)
+	if Allows(role, permission) {
+		return nil
+	}
+
+	if a, ok := ctx.Value(auditKey{}).(audit); ok && a.recorder != nil {
+		a.recorder.RecordDenial(ctx, entity.AccessDenial{
+			PrincipalKind: principal.Kind,
+			PrincipalID:   principal.ID,
+			PrincipalName: principal.Name,
+			Role:          principal.Role,
+			Permission:    string(permission),
+			Method:        a.method,
+			Path:          a.path,
+		})
+	}
+
+	return ErrForbidden.WithDetail(fmt.Sprintf("%s requires the %s role", permission, MinimumRole(permission)))
+}
+
+// AccessService keeps the log of refused requests
+type AccessService struct {
+	accessRepo accessRepo
+	logger     *slog.Logger
+}
+
+func NewAccessService(accessRepo accessRepo, logger *slog.Logger) *AccessService {
+	return &AccessService{
+		accessRepo: accessRepo,
+		logger:     logger,
+	}
+}
+
+// RecordDenial stores a denial; a failure is only logged, the request is refused either way
+func (s *AccessService) RecordDenial(ctx context.Context, denial entity.AccessDenial) {
+	s.logger.WarnContext(ctx, "Access denied",
+		"principal_kind", denial.PrincipalKind,
+		"principal", denial.PrincipalName,
+		"role", denial.Role,
+		"method", denial.Method,
+		"path", denial.Path,
+		"permission", denial.Permission,
+	)
+	if err := s.accessRepo.CreateAccessDenial(context.WithoutCancel(ctx), denial); err != nil {
+		s.logger.ErrorContext(ctx, "Error recording access denial", "error", err)
+	}
+}
+
+func (s *AccessService) GetAccessDenials(ctx context.Context, startDate, endDate *time.Time, limit int) ([]access.AccessDenialResponse, error) {
+	if limit <= 0 {
+		limit = defaultDenialsLimit
+	}
+
+	denials, err := s.accessRepo.GetAccessDenials(ctx, startDate, endDate, limit)
+	if err != nil {
+		s.logger.ErrorContext(ctx, "Error getting access denials", "error", err)
+		return nil, err
+	}
+
+	response := make([]access.AccessDenialResponse, 0, len(denials))
+	for _, d := range denials {
+		response = append(response, access.AccessDenialResponse{
+			DenialID:      d.DenialID,
+			PrincipalKind: d.PrincipalKind,
+			PrincipalID:   d.PrincipalID,
+			PrincipalName: d.PrincipalName,
+			Role:          d.Role,
+			Permission:    d.Permission,
+			Method:        d.Method,
+			Path:          d.Path,
+			CreatedAt:     d.CreatedAt,
+		})
+	}
+	return response, nil
+}
@@ -0,0 +1,52 @@
+package access
+
+import (
+	"context"
+
+	"frappuccino/internal/apperror"
+	"frappuccino/internal/entity"
+)
+
+type principalKey struct{}
+
+// WithPrincipal stores the authenticated caller in the request context
+func WithPrincipal(ctx context.Context, principal entity.Principal) context.Context {
+	return context.WithValue(ctx, principalKey{}, principal)
+}
+
+// PrincipalFromContext returns the authenticated caller of a request, if any
+func PrincipalFromContext(ctx context.Context) (entity.Principal, bool) {
+	principal, ok := ctx.Value(principalKey{}).(entity.Principal)
+	return principal, ok
+}
+
+type storeKey struct{}
+
+var ErrStoreRequired = apperror.New(apperror.Invalid, "store_required", "choose a store with the X-Store-ID header")
+
+// WithStore limits the request to one store
+func WithStore(ctx context.Context, storeID string) context.Context {
+	return context.WithValue(ctx, storeKey{}, storeID)
+}
+
+// StoreFromContext returns the store the request is limited to. Without one the caller works
+// across stores: lists and reports cover every store.
+func StoreFromContext(ctx context.Context) string {
+	storeID, _ := ctx.Value(storeKey{}).(string)
+	return storeID
+}
+
+// RequireStore returns the store of the request, for changes that must happen at one store
+func RequireStore(ctx context.Context) (string, error) {
+	storeID := StoreFromContext(ctx)
+	if storeID == "" {
+		return "", ErrStoreRequired
+	}
+	return storeID, nil
+}
+
+// InStore reports whether a record of the given store is visible to the request
+func InStore(ctx context.Context, storeID string) bool {
+	scope := StoreFromContext(ctx)
+	return scope == "" || scope == storeID
+}
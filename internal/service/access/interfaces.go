@@ -0,0 +1,13 @@
+package access
+
+import (
+	"context"
+	"time"
+
+	"frappuccino/internal/entity"
+)
+
+type accessRepo interface {
+	CreateAccessDenial(ctx context.Context, denial entity.AccessDenial) error
+	GetAccessDenials(ctx context.Context, startDate, endDate *time.Time, limit int) ([]entity.AccessDenial, error)
+}
@@ -0,0 +1,112 @@
+package access
+
+// Permission names something a caller may do, either a whole route or a rule inside a service
+type Permission string
+
+const (
+	MenuView        Permission = "menu:view"
+	MenuEdit        Permission = "menu:edit"   // name, description, categories, options
+	MenuPriceChange Permission = "menu:price"  // checked by UpdateMenu when a price changes
+	MenuManage      Permission = "menu:manage" // add and remove menu items
+	PriceHistory    Permission = "menu:price_history"
+
+	InventoryView     Permission = "inventory:view"
+	InventoryRecord   Permission = "inventory:record" // additions and deductions
+	InventoryAdjust   Permission = "inventory:adjust" // checked by RecordInventoryTransaction for adjustment and waste
+	InventoryEdit     Permission = "inventory:edit"
+	InventoryDelete   Permission = "inventory:delete"
+	InventoryTransfer Permission = "inventory:transfer" // move stock between stores
+
+	OrderView   Permission = "order:view"
+	OrderTake   Permission = "order:take" // create, edit, split, merge and progress orders
+	OrderBatch  Permission = "order:batch"
+	OrderDelete Permission = "order:delete"
+	OrderAudit  Permission = "order:audit" // checked by GetOrderAuditLog for the line item changes of an order
+
+	ReportView  Permission = "report:view"
+	MetricsView Permission = "metrics:view" // GET /metrics, with an API key only
+
+	StationView   Permission = "station:view"
+	StationWork   Permission = "station:work" // move tickets along
+	StationManage Permission = "station:manage"
+
+	TableService Permission = "table:service" // open, extend and close tabs
+	TableManage  Permission = "table:manage"
+
+	DeliveryView   Permission = "delivery:view"
+	DeliveryManage Permission = "delivery:manage"
+
+	PrintView    Permission = "print:view"
+	PrintReprint Permission = "print:reprint"
+	PrintManage  Permission = "print:manage"
+
+	StoreView   Permission = "store:view"
+	StoreManage Permission = "store:manage"
+
+	UserManage   Permission = "user:manage"
+	APIKeyManage Permission = "apikey:manage"
+	AccessAudit  Permission = "access:audit"
+)
+
+// matrix holds the lowest role granted each permission
+var matrix = map[Permission]Role{
+	MenuView:        RoleBarista,
+	MenuEdit:        RoleShiftLead,
+	MenuPriceChange: RoleManager,
+	MenuManage:      RoleManager,
+	PriceHistory:    RoleShiftLead,
+
+	InventoryView:     RoleBarista,
+	InventoryRecord:   RoleBarista,
+	InventoryAdjust:   RoleShiftLead,
+	InventoryEdit:     RoleManager,
+	InventoryDelete:   RoleManager,
+	InventoryTransfer: RoleManager,
+
+	OrderView:   RoleBarista,
+	OrderTake:   RoleBarista,
+	OrderBatch:  RoleShiftLead,
+	OrderDelete: RoleManager,
+	OrderAudit:  RoleShiftLead,
+
+	ReportView:  RoleShiftLead,
+	MetricsView: RoleShiftLead,
+
+	StationView:   RoleBarista,
+	StationWork:   RoleBarista,
+	StationManage: RoleManager,
+
+	TableService: RoleBarista,
+	TableManage:  RoleManager,
+
+	DeliveryView:   RoleBarista,
+	DeliveryManage: RoleManager,
+
+	PrintView:    RoleBarista,
+	PrintReprint: RoleBarista,
+	PrintManage:  RoleManager,
+
+	StoreView:   RoleBarista,
+	StoreManage: RoleAdmin,
+
+	UserManage:   RoleAdmin,
+	APIKeyManage: RoleAdmin,
+	AccessAudit:  RoleManager,
+}
+
+// Allows reports whether a role holds a permission; permissions missing from the matrix are admin-only
+func Allows(role Role, permission Permission) bool {
+	min, ok := matrix[permission]
+	if !ok {
+		min = RoleAdmin
+	}
+	return role.AtLeast(min)
+}
+
+// MinimumRole returns the lowest role holding a permission
+func MinimumRole(permission Permission) Role {
+	if min, ok := matrix[permission]; ok {
+		return min
+	}
+	return RoleAdmin
+}
@@ -0,0 +1,44 @@
+package access
+
+import (
+	"fmt"
+
+	"frappuccino/internal/apperror"
+)
+
+// Role is a staff role; every role may do everything the roles below it may
+type Role string
+
+const (
+	RoleBarista   Role = "barista"
+	RoleShiftLead Role = "shift_lead"
+	RoleManager   Role = "manager"
+	RoleAdmin     Role = "admin"
+)
+
+var roleRank = map[Role]int{
+	RoleBarista:   1,
+	RoleShiftLead: 2,
+	RoleManager:   3,
+	RoleAdmin:     4,
+}
+
+var ErrInvalidRole = apperror.InvalidField("invalid_role", "role", "role must be barista, shift_lead, manager or admin")
+
+// ParseRole accepts the name of a role; an empty name is a barista
+func ParseRole(name string) (Role, error) {
+	if name == "" {
+		return RoleBarista, nil
+	}
+	role := Role(name)
+	if _, ok := roleRank[role]; !ok {
+		return "", ErrInvalidRole.WithDetail(fmt.Sprintf("got %q", name))
+	}
+	return role, nil
+}
+
+// AtLeast reports whether the role ranks as high as min; unknown roles rank below every role
+func (r Role) AtLeast(min Role) bool {
+	rank, ok := roleRank[r]
+	return ok && rank >= roleRank[min]
+}
@@ -0,0 +1,157 @@
+package auth
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+
+	"frappuccino/internal/dto/auth"
+	"frappuccino/internal/entity"
+	"frappuccino/internal/service/access"
+)
+
+// API keys look like frp_<prefix>_<secret>. The prefix finds the stored key; only a hash of the
+// whole key is kept, which is enough as the secret is long and random.
+const apiKeyScheme = "frp"
+
+func (s *AuthService) CreateAPIKey(ctx context.Context, request auth.CreateAPIKeyRequest) (auth.CreateAPIKeyResponse, error) {
+	name := strings.TrimSpace(request.Name)
+	if name == "" {
+		return auth.CreateAPIKeyResponse{}, ErrInvalidAPIKeyName
+	}
+	role, err := access.ParseRole(request.Role)
+	if err != nil {
+		return auth.CreateAPIKeyResponse{}, err
+	}
+	storeID, err := s.homeStore(ctx, request.Store)
+	if err != nil {
+		return auth.CreateAPIKeyResponse{}, err
+	}
+
+	prefix, err := randomHex(4)
+	if err != nil {
+		return auth.CreateAPIKeyResponse{}, err
+	}
+	secret, err := randomHex(24)
+	if err != nil {
+		return auth.CreateAPIKeyResponse{}, err
+	}
+	key := apiKeyScheme + "_" + prefix + "_" + secret
+
+	var createdBy *string
+	if principal, ok := access.PrincipalFromContext(ctx); ok && principal.Kind == "user" {
+		createdBy = &principal.ID
+	}
+
+	keyID, err := s.authRepo.CreateAPIKey(ctx, entity.APIKey{
+		Name:      name,
+		Prefix:    prefix,
+		KeyHash:   hashAPIKey(key),
+		Role:      string(role),
+		StoreID:   storeID,
+		CreatedBy: createdBy,
+	})
+	if err != nil {
+		s.logger.ErrorContext(ctx, "Error creating API key", "error", err)
+		return auth.CreateAPIKeyResponse{}, err
+	}
+
+	return auth.CreateAPIKeyResponse{
+		KeyID:   keyID,
+		Name:    name,
+		Prefix:  prefix,
+		Role:    string(role),
+		StoreID: storeID,
+		Key:     key,
+	}, nil
+}
+
+func (s *AuthService) GetAPIKeys(ctx context.Context) ([]auth.APIKeyResponse, error) {
+	keys, err := s.authRepo.GetAPIKeys(ctx)
+	if err != nil {
+		s.logger.ErrorContext(ctx, "Error getting API keys", "error", err)
+		return nil, err
+	}
+
+	response := make([]auth.APIKeyResponse, 0, len(keys))
+	for _, k := range keys {
+		response = append(response, auth.APIKeyResponse{
+			KeyID:      k.KeyID,
+			Name:       k.Name,
+			Prefix:     k.Prefix,
+			Role:       k.Role,
+			StoreID:    k.StoreID,
+			CreatedBy:  k.CreatedBy,
+			CreatedAt:  k.CreatedAt,
+			LastUsedAt: k.LastUsedAt,
+			RevokedAt:  k.RevokedAt,
+		})
+	}
+	return response, nil
+}
+
+// RevokeAPIKey disables a key; it returns sql.ErrNoRows if there is no such active key
+func (s *AuthService) RevokeAPIKey(ctx context.Context, keyID string) error {
+	revoked, err := s.authRepo.RevokeAPIKey(ctx, keyID)
+	if err != nil {
+		return err
+	}
+	if !revoked {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+// AuthenticateAPIKey resolves the caller behind an API key
+func (s *AuthService) AuthenticateAPIKey(ctx context.Context, key string) (entity.Principal, error) {
+	parts := strings.Split(key, "_")
+	if len(parts) != 3 || parts[0] != apiKeyScheme {
+		return entity.Principal{}, ErrInvalidAPIKey
+	}
+
+	stored, err := s.authRepo.GetAPIKeyByPrefix(ctx, parts[1])
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return entity.Principal{}, ErrInvalidAPIKey
+		}
+		return entity.Principal{}, fmt.Errorf("error getting API key: %w", err)
+	}
+	if stored.RevokedAt != nil || subtle.ConstantTimeCompare([]byte(hashAPIKey(key)), []byte(stored.KeyHash)) != 1 {
+		return entity.Principal{}, ErrInvalidAPIKey
+	}
+
+	if err := s.authRepo.TouchAPIKey(ctx, stored.KeyID); err != nil {
+		// Only the usage timestamp is lost
+		s.logger.ErrorContext(ctx, "Error recording API key use", "key_id", stored.KeyID, "error", err)
+	}
+
+	principal := entity.Principal{
+		Kind: "api_key",
+		ID:   stored.KeyID,
+		Name: stored.Name,
+		Role: stored.Role,
+	}
+	if stored.StoreID != nil {
+		principal.StoreID = *stored.StoreID
+	}
+	return principal, nil
+}
+
+func hashAPIKey(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
+
+func randomHex(n int) (string, error) {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("generate random key: %w", err)
+	}
+	return hex.EncodeToString(b), nil
+}
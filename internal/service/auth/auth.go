@@ -0,0 +1,346 @@
+package auth
+
+import (
+	"context"
+	"crypto/rand"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"frappuccino/internal/apperror"
+	"frappuccino/internal/config"
+	"frappuccino/internal/dto/auth"
+	"frappuccino/internal/entity"
+	"frappuccino/internal/service/access"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+const (
+	defaultIssuer          = "frappuccino"
+	defaultAccessTokenTTL  = 15 * time.Minute
+	defaultRefreshTokenTTL = 7 * 24 * time.Hour
+
+	passwordHashCost  = 12
+	minPasswordLength = 8
+)
+
+var (
+	ErrInvalidCredentials = apperror.New(apperror.Unauthorized, "invalid_credentials", "invalid username or password")
+	ErrInvalidToken       = apperror.New(apperror.Unauthorized, "invalid_token", "invalid or expired token")
+	ErrInvalidAPIKey      = apperror.New(apperror.Unauthorized, "invalid_api_key", "invalid API key")
+	ErrInvalidAPIKeyName  = apperror.InvalidField("api_key_name_required", "name", "API key name is required")
+	ErrInvalidUser        = apperror.New(apperror.Invalid, "invalid_user", fmt.Sprintf("username is required and passwords need at least %d characters", minPasswordLength))
+	ErrNotAUser           = apperror.New(apperror.Forbidden, "not_a_user", "only staff logins have a session to end")
+	ErrSelfUpdate         = apperror.New(apperror.Forbidden, "self_update", "users cannot change their own role or deactivate themselves")
+)
+
+// dummyHash is compared against when a username does not exist, so failed logins take the
+// same time whether or not the user exists
+var dummyHash, _ = bcrypt.GenerateFromPassword([]byte("dummy password"), passwordHashCost)
+
+// AuthService logs staff in with JWT access and refresh tokens and checks API keys of integrations
+type AuthService struct {
+	authRepo authRepo
+	stores   storeResolver
+	cfg      config.Auth
+	secret   []byte
+	logger   *slog.Logger
+}
+
+func NewAuthService(authRepo authRepo, stores storeResolver, cfg config.Auth, logger *slog.Logger) (*AuthService, error) {
+	if cfg.Issuer == "" {
+		cfg.Issuer = defaultIssuer
+	}
+	if cfg.AccessTokenTTL <= 0 {
+		cfg.AccessTokenTTL = defaultAccessTokenTTL
+	}
+	if cfg.RefreshTokenTTL <= 0 {
+		cfg.RefreshTokenTTL = defaultRefreshTokenTTL
+	}
+
+	secret := []byte(cfg.JWTSecret)
+	if len(secret) == 0 {
+		secret = make([]byte, 32)
+		if _, err := rand.Read(secret); err != nil {
+			return nil, fmt.Errorf("generate jwt secret: %w", err)
+		}
+		logger.Warn("auth.jwt_secret is not set; using a random secret, tokens will not survive a restart")
+	}
+
+	return &AuthService{
+		authRepo: authRepo,
+		stores:   stores,
+		cfg:      cfg,
+		secret:   secret,
+		logger:   logger,
+	}, nil
+}
+
+// Login checks a staff user's password and starts a session
+func (s *AuthService) Login(ctx context.Context, request auth.LoginRequest) (auth.TokenResponse, error) {
+	user, err := s.authRepo.GetUserByUsername(ctx, strings.TrimSpace(request.Username))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			bcrypt.CompareHashAndPassword(dummyHash, []byte(request.Password))
+			return auth.TokenResponse{}, ErrInvalidCredentials
+		}
+		return auth.TokenResponse{}, fmt.Errorf("error getting user: %w", err)
+	}
+
+	if err := bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte(request.Password)); err != nil {
+		return auth.TokenResponse{}, ErrInvalidCredentials
+	}
+	if !user.IsActive {
+		return auth.TokenResponse{}, ErrInvalidCredentials
+	}
+
+	refreshID, err := newTokenID()
+	if err != nil {
+		return auth.TokenResponse{}, err
+	}
+	return s.issueTokens(ctx, user, refreshID)
+}
+
+// Refresh exchanges a refresh token for new tokens. Every refresh token works once: presenting a
+// used one means it was stolen or replayed, so all sessions of the user are ended.
+func (s *AuthService) Refresh(ctx context.Context, request auth.RefreshRequest) (auth.TokenResponse, error) {
+	claims, err := s.parseToken(request.RefreshToken, refreshToken)
+	if err != nil {
+		return auth.TokenResponse{}, err
+	}
+
+	user, err := s.authRepo.GetUserByID(ctx, claims.Subject)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return auth.TokenResponse{}, ErrInvalidToken
+		}
+		return auth.TokenResponse{}, fmt.Errorf("error getting user: %w", err)
+	}
+	if !user.IsActive {
+		return auth.TokenResponse{}, ErrInvalidToken
+	}
+
+	refreshID, err := newTokenID()
+	if err != nil {
+		return auth.TokenResponse{}, err
+	}
+	rotated, err := s.authRepo.RevokeRefreshToken(ctx, claims.ID, &refreshID)
+	if err != nil {
+		return auth.TokenResponse{}, err
+	}
+	if !rotated {
+		s.logger.WarnContext(ctx, "Refresh token reused, ending all sessions of user", "user_id", user.UserID)
+		if err := s.authRepo.RevokeUserRefreshTokens(ctx, user.UserID); err != nil {
+			s.logger.ErrorContext(ctx, "Error ending sessions", "user_id", user.UserID, "error", err)
+		}
+		return auth.TokenResponse{}, ErrInvalidToken
+	}
+
+	return s.issueTokens(ctx, user, refreshID)
+}
+
+// Logout revokes the access token of the caller and, when given, the refresh token of the session
+func (s *AuthService) Logout(ctx context.Context, request auth.LogoutRequest) error {
+	principal, ok := access.PrincipalFromContext(ctx)
+	if !ok || principal.Kind != "user" {
+		return ErrNotAUser
+	}
+
+	if request.RefreshToken != "" {
+		claims, err := s.parseToken(request.RefreshToken, refreshToken)
+		if err != nil {
+			return err
+		}
+		if claims.Subject != principal.ID {
+			return ErrInvalidToken
+		}
+		if _, err := s.authRepo.RevokeRefreshToken(ctx, claims.ID, nil); err != nil {
+			return err
+		}
+	}
+
+	return s.authRepo.RevokeAccessToken(ctx, principal.TokenID, principal.ExpiresAt)
+}
+
+// AuthenticateToken resolves the staff user behind an access token
+func (s *AuthService) AuthenticateToken(ctx context.Context, token string) (entity.Principal, error) {
+	claims, err := s.parseToken(token, accessToken)
+	if err != nil {
+		return entity.Principal{}, err
+	}
+
+	revoked, err := s.authRepo.IsAccessTokenRevoked(ctx, claims.ID)
+	if err != nil {
+		return entity.Principal{}, err
+	}
+	if revoked {
+		return entity.Principal{}, ErrInvalidToken
+	}
+
+	return entity.Principal{
+		Kind:      "user",
+		ID:        claims.Subject,
+		Name:      claims.Username,
+		Role:      claims.Role,
+		StoreID:   claims.StoreID,
+		TokenID:   claims.ID,
+		ExpiresAt: claims.ExpiresAt.Time,
+	}, nil
+}
+
+func (s *AuthService) CreateUser(ctx context.Context, request auth.CreateUserRequest) (string, error) {
+	username := strings.TrimSpace(request.Username)
+	if username == "" || len(request.Password) < minPasswordLength {
+		return "", ErrInvalidUser
+	}
+
+	role, err := access.ParseRole(request.Role)
+	if err != nil {
+		return "", err
+	}
+	storeID, err := s.homeStore(ctx, request.Store)
+	if err != nil {
+		return "", err
+	}
+
+	hash, err := bcrypt.GenerateFromPassword([]byte(request.Password), passwordHashCost)
+	if err != nil {
+		return "", fmt.Errorf("hash password: %w", err)
+	}
+
+	return s.authRepo.CreateUser(ctx, entity.User{
+		Username:     username,
+		PasswordHash: string(hash),
+		Role:         string(role),
+		StoreID:      storeID,
+		IsActive:     true,
+	})
+}
+
+func (s *AuthService) GetUsers(ctx context.Context) ([]auth.UserResponse, error) {
+	users, err := s.authRepo.GetUsers(ctx)
+	if err != nil {
+		s.logger.ErrorContext(ctx, "Error getting users", "error", err)
+		return nil, err
+	}
+
+	response := make([]auth.UserResponse, 0, len(users))
+	for _, u := range users {
+		response = append(response, auth.UserResponse{
+			UserID:    u.UserID,
+			Username:  u.Username,
+			Role:      u.Role,
+			StoreID:   u.StoreID,
+			IsActive:  u.IsActive,
+			CreatedAt: u.CreatedAt,
+		})
+	}
+	return response, nil
+}
+
+// UpdateUser changes the role of a user or deactivates them. Their sessions are ended, so a new
+// role is in the tokens from the next login; access tokens already issued run out on their own.
+// It returns sql.ErrNoRows if there is no such user.
+func (s *AuthService) UpdateUser(ctx context.Context, userID string, request auth.UpdateUserRequest) error {
+	if principal, ok := access.PrincipalFromContext(ctx); ok && principal.Kind == "user" && principal.ID == userID {
+		return ErrSelfUpdate
+	}
+
+	user, err := s.authRepo.GetUserByID(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	role := access.Role(user.Role)
+	if request.Role != nil {
+		if role, err = access.ParseRole(*request.Role); err != nil {
+			return err
+		}
+	}
+	isActive := user.IsActive
+	if request.IsActive != nil {
+		isActive = *request.IsActive
+	}
+
+	if err := s.authRepo.UpdateUser(ctx, userID, string(role), isActive); err != nil {
+		return err
+	}
+	return s.authRepo.RevokeUserRefreshTokens(ctx, userID)
+}
+
+// ChangePassword sets a new password for the calling user and ends their other sessions
+func (s *AuthService) ChangePassword(ctx context.Context, request auth.ChangePasswordRequest) error {
+	principal, ok := access.PrincipalFromContext(ctx)
+	if !ok || principal.Kind != "user" {
+		return ErrNotAUser
+	}
+	if len(request.NewPassword) < minPasswordLength {
+		return ErrInvalidUser
+	}
+
+	user, err := s.authRepo.GetUserByID(ctx, principal.ID)
+	if err != nil {
+		return err
+	}
+	if err := bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte(request.CurrentPassword)); err != nil {
+		return ErrInvalidCredentials
+	}
+
+	hash, err := bcrypt.GenerateFromPassword([]byte(request.NewPassword), passwordHashCost)
+	if err != nil {
+		return fmt.Errorf("hash password: %w", err)
+	}
+	if err := s.authRepo.UpdatePassword(ctx, user.UserID, string(hash)); err != nil {
+		return err
+	}
+	return s.authRepo.RevokeUserRefreshTokens(ctx, user.UserID)
+}
+
+// homeStore resolves the home store given for a user or an API key; an empty ref means none
+func (s *AuthService) homeStore(ctx context.Context, ref string) (*string, error) {
+	if strings.TrimSpace(ref) == "" {
+		return nil, nil
+	}
+	store, err := s.stores.ResolveStore(ctx, ref)
+	if err != nil {
+		return nil, err
+	}
+	return &store.StoreID, nil
+}
+
+// issueTokens signs a new access token and a refresh token with the given jti, and records the refresh token
+func (s *AuthService) issueTokens(ctx context.Context, user entity.User, refreshID string) (auth.TokenResponse, error) {
+	accessID, err := newTokenID()
+	if err != nil {
+		return auth.TokenResponse{}, err
+	}
+	accessTok, _, err := s.issueToken(user, accessToken, accessID, s.cfg.AccessTokenTTL)
+	if err != nil {
+		return auth.TokenResponse{}, err
+	}
+	refresh, refreshExpiresAt, err := s.issueToken(user, refreshToken, refreshID, s.cfg.RefreshTokenTTL)
+	if err != nil {
+		return auth.TokenResponse{}, err
+	}
+
+	err = s.authRepo.CreateRefreshToken(ctx, entity.RefreshToken{
+		TokenID:   refreshID,
+		UserID:    user.UserID,
+		ExpiresAt: refreshExpiresAt,
+	})
+	if err != nil {
+		return auth.TokenResponse{}, err
+	}
+
+	return auth.TokenResponse{
+		AccessToken:      accessTok,
+		RefreshToken:     refresh,
+		TokenType:        "Bearer",
+		ExpiresIn:        int(s.cfg.AccessTokenTTL.Seconds()),
+		RefreshExpiresIn: int(s.cfg.RefreshTokenTTL.Seconds()),
+	}, nil
+}
@@ -0,0 +1,34 @@
+package auth
+
+import (
+	"context"
+	"time"
+
+	"frappuccino/internal/entity"
+)
+
+type authRepo interface {
+	CreateUser(ctx context.Context, user entity.User) (string, error)
+	GetUserByUsername(ctx context.Context, username string) (entity.User, error)
+	GetUserByID(ctx context.Context, userID string) (entity.User, error)
+	GetUsers(ctx context.Context) ([]entity.User, error)
+	UpdatePassword(ctx context.Context, userID, passwordHash string) error
+	UpdateUser(ctx context.Context, userID, role string, isActive bool) error
+
+	CreateRefreshToken(ctx context.Context, token entity.RefreshToken) error
+	RevokeRefreshToken(ctx context.Context, tokenID string, replacedBy *string) (bool, error)
+	RevokeUserRefreshTokens(ctx context.Context, userID string) error
+	RevokeAccessToken(ctx context.Context, tokenID string, expiresAt time.Time) error
+	IsAccessTokenRevoked(ctx context.Context, tokenID string) (bool, error)
+
+	CreateAPIKey(ctx context.Context, key entity.APIKey) (string, error)
+	GetAPIKeyByPrefix(ctx context.Context, prefix string) (entity.APIKey, error)
+	GetAPIKeys(ctx context.Context) ([]entity.APIKey, error)
+	TouchAPIKey(ctx context.Context, keyID string) error
+	RevokeAPIKey(ctx context.Context, keyID string) (bool, error)
+}
+
+// storeResolver checks the home store given for a user or an API key
+type storeResolver interface {
+	ResolveStore(ctx context.Context, ref string) (entity.Store, error)
+}
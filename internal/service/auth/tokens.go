@@ -0,0 +1,84 @@
+package auth
+
+import (
+	"crypto/rand"
+	"fmt"
+	"time"
+
+	"frappuccino/internal/entity"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const (
+	accessToken  = "access"
+	refreshToken = "refresh"
+)
+
+// tokenClaims are the claims of both token kinds; TokenType keeps a refresh token from being
+// accepted as an access token and the other way round
+type tokenClaims struct {
+	jwt.RegisteredClaims
+	TokenType string `json:"token_type"`
+	Username  string `json:"username"`
+	Role      string `json:"role"` // the role when the token was issued; a changed role applies from the next refresh
+	StoreID   string `json:"store_id,omitempty"`
+}
+
+// issueToken signs a token for a user with the given jti
+func (s *AuthService) issueToken(user entity.User, tokenType, tokenID string, ttl time.Duration) (string, time.Time, error) {
+	now := time.Now()
+	expiresAt := now.Add(ttl)
+	claims := tokenClaims{
+		RegisteredClaims: jwt.RegisteredClaims{
+			Issuer:    s.cfg.Issuer,
+			Subject:   user.UserID,
+			ID:        tokenID,
+			IssuedAt:  jwt.NewNumericDate(now),
+			NotBefore: jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(expiresAt),
+		},
+		TokenType: tokenType,
+		Username:  user.Username,
+		Role:      user.Role,
+	}
+	if user.StoreID != nil {
+		claims.StoreID = *user.StoreID
+	}
+
+	signed, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString(s.secret)
+	if err != nil {
+		return "", time.Time{}, fmt.Errorf("sign %s token: %w", tokenType, err)
+	}
+	return signed, expiresAt, nil
+}
+
+// parseToken verifies the signature, issuer, expiry and kind of a token
+func (s *AuthService) parseToken(raw, tokenType string) (tokenClaims, error) {
+	var claims tokenClaims
+	_, err := jwt.ParseWithClaims(raw, &claims, func(*jwt.Token) (interface{}, error) {
+		return s.secret, nil
+	},
+		jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}),
+		jwt.WithIssuer(s.cfg.Issuer),
+		jwt.WithExpirationRequired(),
+	)
+	if err != nil {
+		return tokenClaims{}, fmt.Errorf("%w: %v", ErrInvalidToken, err)
+	}
+	if claims.TokenType != tokenType || claims.Subject == "" || claims.ID == "" {
+		return tokenClaims{}, ErrInvalidToken
+	}
+	return claims, nil
+}
+
+// newTokenID returns a random UUID to use as the jti of a token
+func newTokenID() (string, error) {
+	var b [16]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		return "", fmt.Errorf("generate token id: %w", err)
+	}
+	b[6] = b[6]&0x0f | 0x40 // version 4
+	b[8] = b[8]&0x3f | 0x80 // RFC 4122 variant
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:16]), nil
+}
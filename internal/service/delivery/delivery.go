@@ -0,0 +1,154 @@
+package delivery
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"frappuccino/internal/apperror"
+	"frappuccino/internal/dto/delivery"
+	"frappuccino/internal/entity"
+)
+
+var (
+	ErrInvalidZone    = apperror.New(apperror.Invalid, "invalid_delivery_zone", "invalid delivery zone")
+	ErrInvalidCourier = apperror.InvalidField("courier_name_required", "name", "courier name is required")
+)
+
+// DeliveryService manages the delivery zones and couriers that delivery orders use
+type DeliveryService struct {
+	deliveryRepo deliveryRepo
+	logger       *slog.Logger
+}
+
+func NewDeliveryService(deliveryRepo deliveryRepo, logger *slog.Logger) *DeliveryService {
+	return &DeliveryService{
+		deliveryRepo: deliveryRepo,
+		logger:       logger,
+	}
+}
+
+func (s *DeliveryService) CreateZone(ctx context.Context, request delivery.CreateZoneRequest) (string, error) {
+	name := strings.TrimSpace(request.Name)
+	if name == "" {
+		return "", ErrInvalidZone.WithDetail("name is required")
+	}
+	if request.Fee < 0 || request.MinimumOrder < 0 {
+		return "", ErrInvalidZone.WithDetail("fee and minimum order cannot be negative")
+	}
+
+	var postcodes []string
+	for _, p := range request.Postcodes {
+		if p = strings.TrimSpace(p); p != "" {
+			postcodes = append(postcodes, p)
+		}
+	}
+	if len(postcodes) == 0 && len(request.Polygon) == 0 {
+		return "", ErrInvalidZone.WithDetail("a postcode list or a polygon is required")
+	}
+	if len(request.Polygon) > 0 && len(request.Polygon) < 3 {
+		return "", ErrInvalidZone.WithDetail("a polygon needs at least three points")
+	}
+
+	polygon := make([]entity.GeoPoint, 0, len(request.Polygon))
+	for _, p := range request.Polygon {
+		if p[0] < -90 || p[0] > 90 || p[1] < -180 || p[1] > 180 {
+			return "", ErrInvalidZone.WithDetail(fmt.Sprintf("polygon point %v is not a valid latitude/longitude", p))
+		}
+		polygon = append(polygon, entity.GeoPoint{Latitude: p[0], Longitude: p[1]})
+	}
+
+	isActive := true
+	if request.IsActive != nil {
+		isActive = *request.IsActive
+	}
+
+	id, err := s.deliveryRepo.CreateZone(ctx, entity.DeliveryZone{
+		Name:         name,
+		Postcodes:    postcodes,
+		Polygon:      polygon,
+		Fee:          request.Fee,
+		MinimumOrder: request.MinimumOrder,
+		IsActive:     isActive,
+	})
+	if err != nil {
+		s.logger.ErrorContext(ctx, "CreateZone error", "error", err)
+		return "", err
+	}
+	return id, nil
+}
+
+func (s *DeliveryService) GetZones(ctx context.Context) ([]delivery.GetZoneResponse, error) {
+	zones, err := s.deliveryRepo.GetZones(ctx, false)
+	if err != nil {
+		s.logger.ErrorContext(ctx, "Error retrieving delivery zones", "error", err)
+		return nil, err
+	}
+
+	response := make([]delivery.GetZoneResponse, 0, len(zones))
+	for _, z := range zones {
+		item := delivery.GetZoneResponse{
+			ZoneID:       z.ZoneID,
+			Name:         z.Name,
+			Postcodes:    z.Postcodes,
+			Fee:          z.Fee,
+			MinimumOrder: z.MinimumOrder,
+			IsActive:     z.IsActive,
+			CreatedAt:    z.CreatedAt,
+		}
+		if item.Postcodes == nil {
+			item.Postcodes = []string{}
+		}
+		for _, p := range z.Polygon {
+			item.Polygon = append(item.Polygon, [2]float64{p.Latitude, p.Longitude})
+		}
+		response = append(response, item)
+	}
+
+	return response, nil
+}
+
+func (s *DeliveryService) CreateCourier(ctx context.Context, request delivery.CreateCourierRequest) (string, error) {
+	name := strings.TrimSpace(request.Name)
+	if name == "" {
+		return "", ErrInvalidCourier
+	}
+
+	isActive := true
+	if request.IsActive != nil {
+		isActive = *request.IsActive
+	}
+
+	id, err := s.deliveryRepo.CreateCourier(ctx, entity.Courier{
+		Name:     name,
+		Phone:    strings.TrimSpace(request.Phone),
+		IsActive: isActive,
+	})
+	if err != nil {
+		s.logger.ErrorContext(ctx, "CreateCourier error", "error", err)
+		return "", err
+	}
+	return id, nil
+}
+
+func (s *DeliveryService) GetCouriers(ctx context.Context) ([]delivery.GetCourierResponse, error) {
+	couriers, err := s.deliveryRepo.GetCouriers(ctx)
+	if err != nil {
+		s.logger.ErrorContext(ctx, "Error retrieving couriers", "error", err)
+		return nil, err
+	}
+
+	response := make([]delivery.GetCourierResponse, 0, len(couriers))
+	for _, c := range couriers {
+		response = append(response, delivery.GetCourierResponse{
+			CourierID: c.CourierID,
+			Name:      c.Name,
+			Phone:     c.Phone,
+			IsActive:  c.IsActive,
+			CreatedAt: c.CreatedAt,
+		})
+	}
+
+	return response, nil
+}
@@ -0,0 +1,14 @@
+package delivery
+
+import (
+	"context"
+
+	"frappuccino/internal/entity"
+)
+
+type deliveryRepo interface {
+	CreateZone(ctx context.Context, zone entity.DeliveryZone) (string, error)
+	GetZones(ctx context.Context, activeOnly bool) ([]entity.DeliveryZone, error)
+	CreateCourier(ctx context.Context, courier entity.Courier) (string, error)
+	GetCouriers(ctx context.Context) ([]entity.Courier, error)
+}
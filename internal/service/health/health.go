@@ -0,0 +1,71 @@
+package health
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"frappuccino/internal/dto/health"
+)
+
+const (
+	StatusOK          = "ok"
+	StatusUnavailable = "unavailable"
+)
+
+// checkTimeout bounds each readiness check, so a stuck database fails the probe instead of hanging it
+const checkTimeout = 2 * time.Second
+
+// HealthService answers the liveness and readiness probes of the orchestrator
+type HealthService struct {
+	db         pinger
+	migrations migrationState
+	logger     *slog.Logger
+}
+
+func NewHealthService(db pinger, migrations migrationState, logger *slog.Logger) *HealthService {
+	return &HealthService{
+		db:         db,
+		migrations: migrations,
+		logger:     logger,
+	}
+}
+
+// Liveness reports that the process is up; it checks nothing else, so a database outage does
+// not get the server restarted
+func (s *HealthService) Liveness(ctx context.Context) health.HealthResponse {
+	return health.HealthResponse{Status: StatusOK}
+}
+
+// Readiness reports whether the server can take traffic: the database answers and every
+// migration of this binary has been applied
+func (s *HealthService) Readiness(ctx context.Context) (health.HealthResponse, bool) {
+	ctx, cancel := context.WithTimeout(ctx, checkTimeout)
+	defer cancel()
+
+	response := health.HealthResponse{Status: StatusOK, Checks: make(map[string]string)}
+	fail := func(check string, err error) {
+		s.logger.WarnContext(ctx, "Readiness check failed", "check", check, "error", err)
+		response.Status = StatusUnavailable
+		response.Checks[check] = err.Error()
+	}
+
+	if err := s.db.PingContext(ctx); err != nil {
+		fail("database", err)
+		response.Checks["migrations"] = "not checked"
+		return response, false
+	}
+	response.Checks["database"] = StatusOK
+
+	pending, err := s.migrations.Pending(ctx)
+	switch {
+	case err != nil:
+		fail("migrations", err)
+	case pending > 0:
+		fail("migrations", fmt.Errorf("%d pending", pending))
+	default:
+		response.Checks["migrations"] = StatusOK
+	}
+	return response, response.Status == StatusOK
+}
@@ -0,0 +1,11 @@
+package health
+
+import "context"
+
+type pinger interface {
+	PingContext(ctx context.Context) error
+}
+
+type migrationState interface {
+	Pending(ctx context.Context) (int, error)
+}
@@ -3,16 +3,28 @@ package inventory
 import (
 	"context"
 
+	"frappuccino/internal/dto/list"
 	"frappuccino/internal/entity"
 )
 
 type inventoryRepo interface {
 	CreateInventory(ctx context.Context, inventory entity.Inventory) (string, error)
-	GetInventory(ctx context.Context) ([]entity.Inventory, error)
+	GetInventory(ctx context.Context, storeID string) ([]entity.Inventory, error)
 	GetInventoryByID(ctx context.Context, id string) (entity.Inventory, error)
 	DeleteInventory(ctx context.Context, id string) (string, error)
 	UpdateInventory(ctx context.Context, updates map[string]interface{}, id string) (string, error)
 	CreateInventoryTransaction(ctx context.Context, transaction entity.InventoryTransaction) error
-	GetInventoryTransactions(ctx context.Context, ingredientID string) ([]entity.InventoryTransaction, error)
-	GetLeftOvers(ctx context.Context, sortBy string, page, pageSize int) ([]entity.Inventory, int, error)
+	ListInventory(ctx context.Context, storeID string, q list.Query) ([]entity.Inventory, list.Page, error)
+	ListInventoryTransactions(ctx context.Context, ingredientID string, q list.Query) ([]entity.InventoryTransaction, list.Page, error)
+	TransferStock(ctx context.Context, transfer entity.StockTransfer) (entity.StockTransfer, bool, error)
+}
+
+type storeRepo interface {
+	GetStore(ctx context.Context, ref string) (entity.Store, error)
+}
+
+// metricsRecorder counts stock deductions for the metrics endpoint
+type metricsRecorder interface {
+	StockDeducted(source string)
+	LowStock(storeID, ingredient string)
 }
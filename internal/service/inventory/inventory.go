@@ -2,28 +2,88 @@ package inventory
 
 import (
 	"context"
+	"database/sql"
 	"errors"
-	"log"
+	"log/slog"
 	"time"
 
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+
+	"frappuccino/internal/apperror"
 	"frappuccino/internal/dto/inventory"
+	"frappuccino/internal/dto/list"
 	"frappuccino/internal/entity"
+	"frappuccino/internal/service/access"
+	"frappuccino/internal/tracing"
+)
+
+var tracer = otel.Tracer("frappuccino/internal/service/inventory")
+
+var (
+	ErrInsufficientStock      = apperror.New(apperror.Conflict, "insufficient_stock", "not enough stock")
+	ErrInvalidTransfer        = apperror.New(apperror.Invalid, "invalid_transfer", "transfer needs a positive quantity and another active store")
+	ErrIngredientNotFound     = apperror.New(apperror.NotFound, "ingredient_not_found", "ingredient not found")
+	ErrInvalidTransactionType = apperror.InvalidField("invalid_transaction_type", "transaction_type", "transaction type must be addition, deduction, adjustment or waste")
+	ErrNoChanges              = apperror.New(apperror.Invalid, "no_changes", "no fields to update")
 )
 
 type InventoryService struct {
 	inventoryRepo inventoryRepo
-	logger        *log.Logger
+	storeRepo     storeRepo
+	metrics       metricsRecorder
+	logger        *slog.Logger
 }
 
-func NewInventoryService(inventoryRepo inventoryRepo, logger *log.Logger) *InventoryService {
+func NewInventoryService(inventoryRepo inventoryRepo, storeRepo storeRepo, metrics metricsRecorder, logger *slog.Logger) *InventoryService {
 	return &InventoryService{
 		inventoryRepo: inventoryRepo,
+		storeRepo:     storeRepo,
+		metrics:       metrics,
 		logger:        logger,
 	}
 }
 
+// ingredientError reports a missing ingredient as ErrIngredientNotFound and passes other errors on
+func ingredientError(err error) error {
+	if errors.Is(err, sql.ErrNoRows) {
+		return ErrIngredientNotFound
+	}
+	return err
+}
+
+// insufficientStock describes a deduction larger than the stock of the ingredient
+func insufficientStock(item entity.Inventory, quantity float32) error {
+	return ErrInsufficientStock.WithShortages(apperror.Shortage{
+		IngredientID: item.IngredientID,
+		Name:         item.Name,
+		Required:     quantity,
+		Available:    item.Quantity,
+		Unit:         item.Unit,
+	})
+}
+
+// getInventory reads an inventory row, treating rows of another store as missing
+func (s *InventoryService) getInventory(ctx context.Context, id string) (entity.Inventory, error) {
+	item, err := s.inventoryRepo.GetInventoryByID(ctx, id)
+	if err != nil {
+		return entity.Inventory{}, err
+	}
+	if !access.InStore(ctx, item.StoreID) {
+		return entity.Inventory{}, sql.ErrNoRows
+	}
+	return item, nil
+}
+
 func (s *InventoryService) CreateInventory(ctx context.Context, request inventory.CreateInventoryRequest) (string, error) {
+	storeID, err := access.RequireStore(ctx)
+	if err != nil {
+		return "", err
+	}
+
 	insertToDBInventopory := entity.Inventory{
+		StoreID:      storeID,
 		Name:         request.Name,
 		Quantity:     request.Quantity,
 		Unit:         request.Unit,
@@ -33,7 +93,7 @@ func (s *InventoryService) CreateInventory(ctx context.Context, request inventor
 	}
 	id, err := s.inventoryRepo.CreateInventory(ctx, insertToDBInventopory)
 	if err != nil {
-		s.logger.Println(err)
+		s.logger.ErrorContext(ctx, "Error creating inventory item", "error", err)
 		return "", err
 	}
 
@@ -46,7 +106,7 @@ func (s *InventoryService) CreateInventory(ctx context.Context, request inventor
 		}
 
 		if err := s.inventoryRepo.CreateInventoryTransaction(ctx, transaction); err != nil {
-			s.logger.Println("Failed to record inventory transaction:", err)
+			s.logger.ErrorContext(ctx, "Failed to record inventory transaction", "error", err)
 			// Note: We don't return here since the inventory was already created successfully
 		}
 	}
@@ -55,9 +115,9 @@ func (s *InventoryService) CreateInventory(ctx context.Context, request inventor
 
 func (s *InventoryService) GetInventory(ctx context.Context) ([]inventory.GetInventoryResponse, error) {
 	// Call the repository function to get all inventory items
-	items, err := s.inventoryRepo.GetInventory(ctx)
+	items, err := s.inventoryRepo.GetInventory(ctx, access.StoreFromContext(ctx))
 	if err != nil {
-		s.logger.Println("Error retrieving inventory items:", err)
+		s.logger.ErrorContext(ctx, "Error retrieving inventory items", "error", err)
 		return nil, err
 	}
 
@@ -66,6 +126,32 @@ func (s *InventoryService) GetInventory(ctx context.Context) ([]inventory.GetInv
 	for _, item := range items {
 		response = append(response, inventory.GetInventoryResponse{
 			IngredientID: item.IngredientID,
+			StoreID:      item.StoreID,
+			Name:         item.Name,
+			Quantity:     item.Quantity,
+			Unit:         item.Unit,
+			UnitPrice:    item.UnitPrice,
+			LastUpdated:  item.LastUpdated,
+			ReorderPoint: item.ReorderPoint,
+		})
+	}
+
+	return response, nil
+}
+
+// ListInventory returns a page of the stock of the current store
+func (s *InventoryService) ListInventory(ctx context.Context, q list.Query) (inventory.ListInventoryResponse, error) {
+	items, page, err := s.inventoryRepo.ListInventory(ctx, access.StoreFromContext(ctx), q)
+	if err != nil {
+		s.logger.ErrorContext(ctx, "Error retrieving inventory items", "error", err)
+		return inventory.ListInventoryResponse{}, err
+	}
+
+	response := inventory.ListInventoryResponse{Items: make([]inventory.GetInventoryResponse, 0, len(items)), Page: page}
+	for _, item := range items {
+		response.Items = append(response.Items, inventory.GetInventoryResponse{
+			IngredientID: item.IngredientID,
+			StoreID:      item.StoreID,
 			Name:         item.Name,
 			Quantity:     item.Quantity,
 			Unit:         item.Unit,
@@ -79,14 +165,15 @@ func (s *InventoryService) GetInventory(ctx context.Context) ([]inventory.GetInv
 }
 
 func (s *InventoryService) GetInventoryByID(ctx context.Context, id string) (inventory.GetInventoryResponse, error) {
-	item, err := s.inventoryRepo.GetInventoryByID(ctx, id)
+	item, err := s.getInventory(ctx, id)
 	if err != nil {
-		s.logger.Println("Error retrieving inventory item:", err)
-		return inventory.GetInventoryResponse{}, err
+		s.logger.ErrorContext(ctx, "Error retrieving inventory item", "error", err)
+		return inventory.GetInventoryResponse{}, ingredientError(err)
 	}
 
 	response := inventory.GetInventoryResponse{
 		IngredientID: item.IngredientID,
+		StoreID:      item.StoreID,
 		Name:         item.Name,
 		Quantity:     item.Quantity,
 		Unit:         item.Unit,
@@ -100,10 +187,10 @@ func (s *InventoryService) GetInventoryByID(ctx context.Context, id string) (inv
 
 func (s *InventoryService) DeleteInventory(ctx context.Context, id string) (string, error) {
 	// Get the inventory item first to check if it exists and has quantity
-	inventory, err := s.inventoryRepo.GetInventoryByID(ctx, id)
+	inventory, err := s.getInventory(ctx, id)
 	if err != nil {
-		s.logger.Println("Error retrieving inventory item:", err)
-		return "", err
+		s.logger.ErrorContext(ctx, "Error retrieving inventory item", "error", err)
+		return "", ingredientError(err)
 	}
 
 	// If there's quantity, record a deduction transaction
@@ -116,7 +203,7 @@ func (s *InventoryService) DeleteInventory(ctx context.Context, id string) (stri
 		}
 
 		if err := s.inventoryRepo.CreateInventoryTransaction(ctx, transaction); err != nil {
-			s.logger.Println("Failed to record inventory transaction for deletion:", err)
+			s.logger.ErrorContext(ctx, "Failed to record inventory transaction for deletion", "error", err)
 			// Continue with deletion anyway
 		}
 	}
@@ -124,7 +211,7 @@ func (s *InventoryService) DeleteInventory(ctx context.Context, id string) (stri
 	// Delete the inventory item
 	ingredient_id, err := s.inventoryRepo.DeleteInventory(ctx, id)
 	if err != nil {
-		s.logger.Println(err)
+		s.logger.ErrorContext(ctx, "Error deleting inventory item", "ingredient_id", id, "error", err)
 		return "", err
 	}
 
@@ -134,10 +221,10 @@ func (s *InventoryService) DeleteInventory(ctx context.Context, id string) (stri
 // Update the UpdateInventory method in the service layer
 func (s *InventoryService) UpdateInventory(ctx context.Context, request inventory.UpdateInventoryRequest, id string) (string, error) {
 	// First, get the current inventory to compare quantity changes
-	currentInventory, err := s.inventoryRepo.GetInventoryByID(ctx, id)
+	currentInventory, err := s.getInventory(ctx, id)
 	if err != nil {
-		s.logger.Println("Error retrieving current inventory:", err)
-		return "", err
+		s.logger.ErrorContext(ctx, "Error retrieving current inventory", "error", err)
+		return "", ingredientError(err)
 	}
 
 	// Create a map to store only the fields that need updating
@@ -176,13 +263,13 @@ func (s *InventoryService) UpdateInventory(ctx context.Context, request inventor
 
 	// Don't proceed if there are no fields to update
 	if len(updates) == 1 && updates["last_updated"] != nil {
-		return "", errors.New("no fields to update")
+		return "", ErrNoChanges
 	}
 
 	// Call the repository with only the fields that need updating
 	id, err = s.inventoryRepo.UpdateInventory(ctx, updates, id)
 	if err != nil {
-		s.logger.Println(err)
+		s.logger.ErrorContext(ctx, "Error updating inventory item", "error", err)
 		return "", err
 	}
 
@@ -207,19 +294,35 @@ func (s *InventoryService) UpdateInventory(ctx context.Context, request inventor
 		}
 
 		if err := s.inventoryRepo.CreateInventoryTransaction(ctx, transaction); err != nil {
-			s.logger.Println("Failed to record inventory transaction:", err)
+			s.logger.ErrorContext(ctx, "Failed to record inventory transaction", "error", err)
 			// Note: We don't return an error here since the inventory was already updated successfully
 		}
+		if transactionType == "deduction" {
+			s.recordDeduction(currentInventory, transactionType, newQuantity)
+		}
 	}
 
 	return id, nil
 }
 
-func (s *InventoryService) RecordInventoryTransaction(ctx context.Context, request inventory.CreateTransactionRequest) error {
+func (s *InventoryService) RecordInventoryTransaction(ctx context.Context, request inventory.CreateTransactionRequest) (err error) {
+	ctx, span := tracer.Start(ctx, "InventoryService.RecordInventoryTransaction", trace.WithAttributes(
+		attribute.String("inventory.ingredient_id", request.IngredientID),
+		attribute.String("inventory.transaction_type", request.TransactionType),
+	))
+	defer func() { tracing.End(span, err) }()
+
+	// Adjustments overwrite the counted stock and waste writes it off, so they need a shift lead
+	if request.TransactionType == "adjustment" || request.TransactionType == "waste" {
+		if err := access.Check(ctx, access.InventoryAdjust); err != nil {
+			return err
+		}
+	}
+
 	// Validate the ingredient exists
-	_, err := s.inventoryRepo.GetInventoryByID(ctx, request.IngredientID)
+	_, err = s.getInventory(ctx, request.IngredientID)
 	if err != nil {
-		return errors.New("ingredient not found")
+		return ingredientError(err)
 	}
 
 	transaction := entity.InventoryTransaction{
@@ -247,7 +350,7 @@ func (s *InventoryService) RecordInventoryTransaction(ctx context.Context, reque
 	case "deduction":
 		newQuantity = currentInventory.Quantity - request.QuantityChange
 		if newQuantity < 0 {
-			return errors.New("insufficient inventory")
+			return insufficientStock(currentInventory, request.QuantityChange)
 		}
 	case "adjustment":
 		// For adjustments, the quantity_change is the new absolute value
@@ -255,10 +358,10 @@ func (s *InventoryService) RecordInventoryTransaction(ctx context.Context, reque
 	case "waste":
 		newQuantity = currentInventory.Quantity - request.QuantityChange
 		if newQuantity < 0 {
-			return errors.New("insufficient inventory")
+			return insufficientStock(currentInventory, request.QuantityChange)
 		}
 	default:
-		return errors.New("invalid transaction type")
+		return ErrInvalidTransactionType
 	}
 
 	// Update the inventory
@@ -267,65 +370,140 @@ func (s *InventoryService) RecordInventoryTransaction(ctx context.Context, reque
 		"last_updated": time.Now(),
 	}
 
-	_, err = s.inventoryRepo.UpdateInventory(ctx, updates, request.IngredientID)
-	return err
+	if _, err = s.inventoryRepo.UpdateInventory(ctx, updates, request.IngredientID); err != nil {
+		return err
+	}
+
+	if request.TransactionType == "deduction" || request.TransactionType == "waste" {
+		s.recordDeduction(currentInventory, request.TransactionType, newQuantity)
+	}
+	return nil
+}
+
+// recordDeduction counts stock taken out of an ingredient, and a low-stock event when it brought
+// the ingredient down to its reorder point
+func (s *InventoryService) recordDeduction(item entity.Inventory, source string, newQuantity float32) {
+	s.metrics.StockDeducted(source)
+	if item.Quantity > item.ReorderPoint && newQuantity <= item.ReorderPoint {
+		s.metrics.LowStock(item.StoreID, item.Name)
+	}
 }
 
-func (s *InventoryService) GetInventoryTransactions(ctx context.Context, ingredientID string) ([]inventory.TransactionResponse, error) {
+// ListInventoryTransactions returns a page of the stock movements of an ingredient
+func (s *InventoryService) ListInventoryTransactions(ctx context.Context, ingredientID string, q list.Query) (inventory.ListTransactionsResponse, error) {
 	// Validate the ingredient exists
-	_, err := s.inventoryRepo.GetInventoryByID(ctx, ingredientID)
+	_, err := s.getInventory(ctx, ingredientID)
 	if err != nil {
-		return nil, errors.New("ingredient not found")
+		return inventory.ListTransactionsResponse{}, ingredientError(err)
 	}
 
-	transactions, err := s.inventoryRepo.GetInventoryTransactions(ctx, ingredientID)
+	transactions, page, err := s.inventoryRepo.ListInventoryTransactions(ctx, ingredientID, q)
 	if err != nil {
-		s.logger.Println("Error retrieving inventory transactions:", err)
-		return nil, err
+		s.logger.ErrorContext(ctx, "Error retrieving inventory transactions", "error", err)
+		return inventory.ListTransactionsResponse{}, err
 	}
 
-	var response []inventory.TransactionResponse
+	response := inventory.ListTransactionsResponse{Items: make([]inventory.TransactionResponse, 0, len(transactions)), Page: page}
 	for _, tx := range transactions {
-		response = append(response, inventory.TransactionResponse{
+		response.Items = append(response.Items, inventory.TransactionResponse{
 			TransactionID:   tx.TransactionID,
 			IngredientID:    tx.IngredientID,
 			QuantityChange:  tx.QuantityChange,
 			TransactionType: tx.TransactionType,
 			Reason:          tx.Reason,
+			TransferID:      tx.TransferID,
 			CreatedAt:       tx.CreatedAt,
 		})
 	}
 
 	return response, nil
 }
-func (s *InventoryService) GetLeftOvers(ctx context.Context, sortBy string, page, pageSize int) (inventory.GetLeftOversResponse, error) {
-	// Call repository to get paginated and sorted inventory items
-	items, totalCount, err := s.inventoryRepo.GetLeftOvers(ctx, sortBy, page, pageSize)
+
+// GetLeftOvers returns a page of the stock left at the current store, priced in cents
+func (s *InventoryService) GetLeftOvers(ctx context.Context, q list.Query) (inventory.GetLeftOversResponse, error) {
+	items, page, err := s.inventoryRepo.ListInventory(ctx, access.StoreFromContext(ctx), q)
 	if err != nil {
-		s.logger.Println("Error retrieving inventory leftovers:", err)
+		s.logger.ErrorContext(ctx, "Error retrieving inventory leftovers", "error", err)
 		return inventory.GetLeftOversResponse{}, err
 	}
 
-	// Calculate pagination details
-	totalPages := (totalCount + pageSize - 1) / pageSize // Ceiling division
-	hasNextPage := page < totalPages
-
 	// Map entity.Inventory items to LeftOverItem DTOs
-	var leftoverItems []inventory.LeftOverItem
+	leftoverItems := make([]inventory.LeftOverItem, 0, len(items))
 	for _, item := range items {
 		leftoverItems = append(leftoverItems, inventory.LeftOverItem{
+			StoreID:  item.StoreID,
 			Name:     item.Name,
 			Quantity: item.Quantity,
 			Price:    item.UnitPrice * 100, // Convert to cents as in the example
 		})
 	}
 
-	// Build and return the response
 	return inventory.GetLeftOversResponse{
-		CurrentPage: page,
-		HasNextPage: hasNextPage,
-		PageSize:    pageSize,
-		TotalPages:  totalPages,
-		Data:        leftoverItems,
+		Data: leftoverItems,
+		Page: page,
+	}, nil
+}
+
+// TransferStock moves stock of an ingredient of the request's store to another store. The stock
+// leaves and arrives in one transaction, recorded as a transfer_out and a transfer_in sharing a
+// transfer ID.
+func (s *InventoryService) TransferStock(ctx context.Context, request inventory.TransferStockRequest) (_ inventory.TransferStockResponse, err error) {
+	ctx, span := tracer.Start(ctx, "InventoryService.TransferStock",
+		trace.WithAttributes(attribute.String("inventory.ingredient_id", request.IngredientID)))
+	defer func() { tracing.End(span, err) }()
+
+	fromStoreID, err := access.RequireStore(ctx)
+	if err != nil {
+		return inventory.TransferStockResponse{}, err
+	}
+
+	source, err := s.getInventory(ctx, request.IngredientID)
+	if err != nil {
+		return inventory.TransferStockResponse{}, ingredientError(err)
+	}
+	if source.StoreID != fromStoreID || request.Quantity <= 0 {
+		return inventory.TransferStockResponse{}, ErrInvalidTransfer
+	}
+
+	destination, err := s.storeRepo.GetStore(ctx, request.ToStore)
+	if err == sql.ErrNoRows {
+		return inventory.TransferStockResponse{}, ErrInvalidTransfer
+	}
+	if err != nil {
+		s.logger.ErrorContext(ctx, "Error retrieving destination store", "error", err)
+		return inventory.TransferStockResponse{}, err
+	}
+	if !destination.IsActive || destination.StoreID == fromStoreID {
+		return inventory.TransferStockResponse{}, ErrInvalidTransfer
+	}
+
+	reason := request.Reason
+	if reason == "" {
+		reason = "Transfer to " + destination.Name
+	}
+
+	transfer, ok, err := s.inventoryRepo.TransferStock(ctx, entity.StockTransfer{
+		ToStoreID:        destination.StoreID,
+		FromIngredientID: source.IngredientID,
+		Quantity:         request.Quantity,
+		Reason:           reason,
+	})
+	if err != nil {
+		s.logger.ErrorContext(ctx, "Error transferring stock", "error", err)
+		return inventory.TransferStockResponse{}, err
+	}
+	if !ok {
+		return inventory.TransferStockResponse{}, ErrInsufficientStock.WithMessage("not enough unreserved stock to transfer")
+	}
+
+	return inventory.TransferStockResponse{
+		TransferID:       transfer.TransferID,
+		FromStoreID:      transfer.FromStoreID,
+		ToStoreID:        transfer.ToStoreID,
+		FromIngredientID: transfer.FromIngredientID,
+		ToIngredientID:   transfer.ToIngredientID,
+		Quantity:         transfer.Quantity,
+		Reason:           transfer.Reason,
+		CreatedAt:        transfer.CreatedAt,
 	}, nil
 }
@@ -2,15 +2,19 @@ package menu
 
 import (
 	"context"
+
+	"frappuccino/internal/dto/list"
 	"frappuccino/internal/entity"
 )
 
 type menuRepo interface {
 	CreateMenuItem(ctx context.Context, menuItem entity.MenuItem) (string, error)
-	GetMenuItem(ctx context.Context) ([]entity.MenuItem, error)
-	GetMenuByID(ctx context.Context, id string) (entity.MenuItem, error)
+	ListMenuItems(ctx context.Context, storeID string, q list.Query) ([]entity.MenuItem, list.Page, error)
+	GetMenuByID(ctx context.Context, storeID, id string) (entity.MenuItem, error)
 	DeleteMenu(ctx context.Context, id string) (string, error)
 	UpdateMenu(ctx context.Context, updates map[string]interface{}, id string) (string, error)
 	CreateMenuItemIngredients(ctx context.Context, menuItemID string, ingredients []entity.MenuItemIngredient) error
-	GetAllPriceHistory(ctx context.Context) ([]entity.PriceHistory, error)
+	ListPriceHistory(ctx context.Context, storeID string, q list.Query) ([]entity.PriceHistory, list.Page, error)
+	GetStoreMenuItem(ctx context.Context, storeID, menuItemID string) (entity.StoreMenuItem, error)
+	SetStoreMenuItem(ctx context.Context, item entity.StoreMenuItem) error
 }
@@ -2,19 +2,27 @@ package menu
 
 import (
 	"context"
-	"errors"
+	"database/sql"
+	"frappuccino/internal/apperror"
+	"frappuccino/internal/dto/list"
 	"frappuccino/internal/dto/menu"
 	"frappuccino/internal/entity"
-	"log"
+	"frappuccino/internal/service/access"
+	"log/slog"
 	"time"
 )
 
+var (
+	ErrNoChanges    = apperror.New(apperror.Invalid, "no_changes", "no fields to update")
+	ErrInvalidPrice = apperror.InvalidField("invalid_price", "price", "price must be positive")
+)
+
 type MenuService struct {
 	menuRepo menuRepo
-	logger   *log.Logger
+	logger   *slog.Logger
 }
 
-func NewMenuService(menuRepo menuRepo, logger *log.Logger) *MenuService {
+func NewMenuService(menuRepo menuRepo, logger *slog.Logger) *MenuService {
 	return &MenuService{
 		menuRepo: menuRepo,
 		logger:   logger,
@@ -37,7 +45,7 @@ func (s *MenuService) CreateMenuItem(ctx context.Context, request menu.CreateMen
 	// Step 2: Insert menu_item
 	id, err := s.menuRepo.CreateMenuItem(ctx, insertToDBMenuItem)
 	if err != nil {
-		s.logger.Println("CreateMenuItem error:", err)
+		s.logger.ErrorContext(ctx, "CreateMenuItem error", "error", err)
 		return "", err
 	}
 
@@ -54,25 +62,25 @@ func (s *MenuService) CreateMenuItem(ctx context.Context, request menu.CreateMen
 
 	// Step 4: Insert menu_item_ingredients
 	if err := s.menuRepo.CreateMenuItemIngredients(ctx, id, ingredients); err != nil {
-		s.logger.Println("CreateMenuItemIngredients error:", err)
+		s.logger.ErrorContext(ctx, "CreateMenuItemIngredients error", "error", err)
 		return "", err
 	}
 
 	return id, nil
 }
 
-func (s *MenuService) GetMenuItem(ctx context.Context) ([]menu.GetMenuResponse, error) {
-	// Call the repository function to get all menu items
-	items, err := s.menuRepo.GetMenuItem(ctx)
+// ListMenuItems returns a page of the menu as sold at the current store
+func (s *MenuService) ListMenuItems(ctx context.Context, q list.Query) (menu.ListMenuResponse, error) {
+	items, page, err := s.menuRepo.ListMenuItems(ctx, access.StoreFromContext(ctx), q)
 	if err != nil {
-		s.logger.Println("Error retrieving menu items:", err)
-		return nil, err
+		s.logger.ErrorContext(ctx, "Error retrieving menu items", "error", err)
+		return menu.ListMenuResponse{}, err
 	}
 
 	// Map entity.Menu items to the response type
-	var response []menu.GetMenuResponse
+	response := menu.ListMenuResponse{Items: make([]menu.GetMenuResponse, 0, len(items)), Page: page}
 	for _, item := range items {
-		response = append(response, menu.GetMenuResponse{
+		response.Items = append(response.Items, menu.GetMenuResponse{
 			MenuItemID:           item.MenuItemID,
 			Name:                 item.Name,
 			Description:          item.Description,
@@ -80,6 +88,7 @@ func (s *MenuService) GetMenuItem(ctx context.Context) ([]menu.GetMenuResponse,
 			Categories:           item.Categories,
 			Size:                 item.Size,
 			CustomizationOptions: item.CustomizationOptions,
+			IsAvailable:          item.IsAvailable,
 			UpdatedAt:            item.UpdatedAt,
 		})
 	}
@@ -88,9 +97,9 @@ func (s *MenuService) GetMenuItem(ctx context.Context) ([]menu.GetMenuResponse,
 }
 
 func (s *MenuService) GetMenuByID(ctx context.Context, id string) (menu.GetMenuResponse, error) {
-	item, err := s.menuRepo.GetMenuByID(ctx, id)
+	item, err := s.menuRepo.GetMenuByID(ctx, access.StoreFromContext(ctx), id)
 	if err != nil {
-		s.logger.Println("Error retrieving menu item:", err)
+		s.logger.ErrorContext(ctx, "Error retrieving menu item", "error", err)
 		return menu.GetMenuResponse{}, err
 	}
 
@@ -102,6 +111,7 @@ func (s *MenuService) GetMenuByID(ctx context.Context, id string) (menu.GetMenuR
 		Categories:           item.Categories,
 		Size:                 item.Size,
 		CustomizationOptions: item.CustomizationOptions,
+		IsAvailable:          item.IsAvailable,
 		UpdatedAt:            item.UpdatedAt,
 	}
 
@@ -111,7 +121,7 @@ func (s *MenuService) GetMenuByID(ctx context.Context, id string) (menu.GetMenuR
 func (s *MenuService) DeleteMenu(ctx context.Context, id string) (string, error) {
 	menu_item_id, err := s.menuRepo.DeleteMenu(ctx, id)
 	if err != nil {
-		s.logger.Println(err)
+		s.logger.ErrorContext(ctx, "Error deleting menu item", "menu_item_id", id, "error", err)
 		return "", err
 	}
 	return menu_item_id, nil
@@ -131,6 +141,10 @@ func (s *MenuService) UpdateMenu(ctx context.Context, request menu.UpdateMenuReq
 	}
 
 	if request.Price != nil {
+		// Editing an item is open to shift leads, but only managers may change what it costs
+		if err := access.Check(ctx, access.MenuPriceChange); err != nil {
+			return "", err
+		}
 		updates["price"] = *request.Price
 	}
 
@@ -154,33 +168,34 @@ func (s *MenuService) UpdateMenu(ctx context.Context, request menu.UpdateMenuReq
 
 	// Don't proceed if there are no fields to update
 	if len(updates) == 1 && updates["updated_at"] != nil {
-		return "", errors.New("no fields to update")
+		return "", ErrNoChanges
 	}
 
 	// Call the repository with only the fields that need updating
 	id, err := s.menuRepo.UpdateMenu(ctx, updates, id)
 	if err != nil {
-		s.logger.Println(err)
+		s.logger.ErrorContext(ctx, "Error updating menu item", "error", err)
 		return "", err
 	}
 
 	return id, nil
 }
 
-func (s *MenuService) GetAllPriceHistory(ctx context.Context) ([]menu.GetPriceHistoryResponse, error) {
-	// Call the repository function to get all price history records
-	histories, err := s.menuRepo.GetAllPriceHistory(ctx)
+// ListPriceHistory returns a page of the menu price changes and those of the current store
+func (s *MenuService) ListPriceHistory(ctx context.Context, q list.Query) (menu.ListPriceHistoryResponse, error) {
+	histories, page, err := s.menuRepo.ListPriceHistory(ctx, access.StoreFromContext(ctx), q)
 	if err != nil {
-		s.logger.Println("Error retrieving price history:", err)
-		return nil, err
+		s.logger.ErrorContext(ctx, "Error retrieving price history", "error", err)
+		return menu.ListPriceHistoryResponse{}, err
 	}
 
 	// Map entity.PriceHistory items to the response type
-	var response []menu.GetPriceHistoryResponse
+	response := menu.ListPriceHistoryResponse{Items: make([]menu.GetPriceHistoryResponse, 0, len(histories)), Page: page}
 	for _, history := range histories {
-		response = append(response, menu.GetPriceHistoryResponse{
+		response.Items = append(response.Items, menu.GetPriceHistoryResponse{
 			ID:           history.ID,
 			MenuItemID:   history.MenuItemID,
+			StoreID:      history.StoreID,
 			OldPrice:     history.OldPrice,
 			NewPrice:     history.NewPrice,
 			ChangedAt:    history.ChangedAt,
@@ -190,3 +205,42 @@ func (s *MenuService) GetAllPriceHistory(ctx context.Context) ([]menu.GetPriceHi
 
 	return response, nil
 }
+
+// SetStoreMenuItem overrides the price or availability of a menu item at the request's store. A
+// store price is a price change like any other and needs a manager.
+func (s *MenuService) SetStoreMenuItem(ctx context.Context, id string, request menu.SetStoreMenuItemRequest) error {
+	storeID, err := access.RequireStore(ctx)
+	if err != nil {
+		return err
+	}
+	if request.Price == nil && !request.ResetPrice && request.IsAvailable == nil {
+		return ErrNoChanges
+	}
+	if request.Price != nil && *request.Price <= 0 {
+		return ErrInvalidPrice
+	}
+
+	item, err := s.menuRepo.GetStoreMenuItem(ctx, storeID, id)
+	if err == sql.ErrNoRows {
+		item = entity.StoreMenuItem{StoreID: storeID, MenuItemID: id, IsAvailable: true}
+	} else if err != nil {
+		s.logger.ErrorContext(ctx, "Error retrieving store menu item", "error", err)
+		return err
+	}
+
+	if request.Price != nil || request.ResetPrice {
+		if err := access.Check(ctx, access.MenuPriceChange); err != nil {
+			return err
+		}
+		item.Price = request.Price
+	}
+	if request.IsAvailable != nil {
+		item.IsAvailable = *request.IsAvailable
+	}
+
+	if err := s.menuRepo.SetStoreMenuItem(ctx, item); err != nil {
+		s.logger.ErrorContext(ctx, "Error setting store menu item", "error", err)
+		return err
+	}
+	return nil
+}
@@ -2,14 +2,35 @@ package order
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"strings"
 	"sync"
 
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+
+	"frappuccino/internal/apperror"
 	orderdto "frappuccino/internal/dto/order"
+	"frappuccino/internal/service/access"
+	"frappuccino/internal/tracing"
+	"frappuccino/internal/validate"
 )
 
 // BatchProcessOrders processes multiple orders concurrently with inventory consistency
 func (s *OrderService) BatchProcessOrders(ctx context.Context, req orderdto.BatchOrderRequest) (orderdto.BatchOrderResponse, error) {
+	ctx, span := tracer.Start(ctx, "OrderService.BatchProcessOrders",
+		trace.WithAttributes(attribute.Int("order.batch_size", len(req.Orders))))
+	response, err := s.batchProcessOrders(ctx, req)
+	span.SetAttributes(
+		attribute.Int("order.accepted", response.Summary.Accepted),
+		attribute.Int("order.rejected", response.Summary.Rejected),
+	)
+	tracing.End(span, err)
+	return response, err
+}
+
+func (s *OrderService) batchProcessOrders(ctx context.Context, req orderdto.BatchOrderRequest) (orderdto.BatchOrderResponse, error) {
 	// Initialize response
 	response := orderdto.BatchOrderResponse{
 		ProcessedOrders: make([]orderdto.BatchOrderResult, len(req.Orders)),
@@ -18,6 +39,12 @@ func (s *OrderService) BatchProcessOrders(ctx context.Context, req orderdto.Batc
 		},
 	}
 
+	// Every order of a batch is taken at the request's store
+	storeID, err := access.RequireStore(ctx)
+	if err != nil {
+		return response, err
+	}
+
 	// Create a mutex for synchronized access to shared resources
 	var mutex sync.Mutex
 
@@ -31,9 +58,15 @@ func (s *OrderService) BatchProcessOrders(ctx context.Context, req orderdto.Batc
 
 	// First phase: Validate all orders and calculate total ingredient requirements
 	// This pre-check helps avoid deadlocks and ensures we have enough inventory
-	for _, order := range req.Orders {
+	// Orders that break the rules of their DTO are rejected one by one, not with the whole batch
+	invalid := make([]error, len(req.Orders))
+	for i, order := range req.Orders {
+		if invalid[i] = validate.Struct(order); invalid[i] != nil {
+			continue
+		}
+
 		// For each order, check ingredient requirements
-		ingredients, err := s.calculateIngredientsNeeded(ctx, order.Items)
+		ingredients, err := s.calculateIngredientsNeeded(ctx, storeID, order.Items)
 		if err != nil {
 			// If we can't calculate ingredients, we'll reject the order in the processing phase
 			continue
@@ -48,6 +81,12 @@ func (s *OrderService) BatchProcessOrders(ctx context.Context, req orderdto.Batc
 		mutex.Unlock()
 	}
 
+	// Stock held for scheduled orders is not available to the batch
+	reserved, err := s.inventoryRepo.GetReservedQuantities(ctx)
+	if err != nil {
+		return response, fmt.Errorf("failed to get reserved quantities: %w", err)
+	}
+
 	// Check if we have enough inventory for the entire batch
 	insufficientIngredients := make(map[string]float32)
 	for id, required := range ingredientUsage {
@@ -56,8 +95,9 @@ func (s *OrderService) BatchProcessOrders(ctx context.Context, req orderdto.Batc
 			return response, fmt.Errorf("failed to get inventory for ingredient %s: %w", id, err)
 		}
 
-		if inventory.Quantity < required {
-			insufficientIngredients[id] = inventory.Quantity
+		available := inventory.Quantity - reserved[id]
+		if available < required {
+			insufficientIngredients[id] = available
 		}
 	}
 
@@ -75,8 +115,55 @@ func (s *OrderService) BatchProcessOrders(ctx context.Context, req orderdto.Batc
 				CustomerName: orderRequest.CustomerName,
 			}
 
+			if err := invalid[orderIndex]; err != nil {
+				result.Status = "rejected"
+				result.Reason = invalidOrderReason(err)
+
+				mutex.Lock()
+				response.ProcessedOrders[orderIndex] = result
+				response.Summary.Rejected++
+				mutex.Unlock()
+				return
+			}
+
+			// Pre-orders need the scheduler, which batch processing bypasses
+			if orderRequest.PickupAt != nil {
+				result.Status = "rejected"
+				result.Reason = "scheduled_orders_not_supported"
+
+				mutex.Lock()
+				response.ProcessedOrders[orderIndex] = result
+				response.Summary.Rejected++
+				mutex.Unlock()
+				return
+			}
+
+			// Seating a table needs an open tab, which batch processing does not manage
+			if orderRequest.OrderType == "dine_in" || orderRequest.TableID != nil {
+				result.Status = "rejected"
+				result.Reason = "dine_in_orders_not_supported"
+
+				mutex.Lock()
+				response.ProcessedOrders[orderIndex] = result
+				response.Summary.Rejected++
+				mutex.Unlock()
+				return
+			}
+
+			// Deliveries need a zone lookup and an address, which batch processing does not handle
+			if orderRequest.OrderType == "delivery" || orderRequest.DeliveryAddress != nil {
+				result.Status = "rejected"
+				result.Reason = "delivery_orders_not_supported"
+
+				mutex.Lock()
+				response.ProcessedOrders[orderIndex] = result
+				response.Summary.Rejected++
+				mutex.Unlock()
+				return
+			}
+
 			// Check if we have enough inventory based on pre-check
-			orderIngredients, err := s.calculateIngredientsNeeded(ctx, orderRequest.Items)
+			orderIngredients, err := s.calculateIngredientsNeeded(ctx, storeID, orderRequest.Items)
 			if err != nil {
 				result.Status = "rejected"
 				result.Reason = "error_processing_order"
@@ -113,7 +200,7 @@ func (s *OrderService) BatchProcessOrders(ctx context.Context, req orderdto.Batc
 			}
 
 			// Process the order with a transaction
-			orderID, total, err := s.processOrderWithTransaction(ctx, orderRequest)
+			orderID, orderNumber, total, err := s.processOrderWithTransaction(ctx, storeID, orderRequest)
 			if err != nil {
 				result.Status = "rejected"
 				result.Reason = fmt.Sprintf("error: %s", err.Error())
@@ -128,6 +215,7 @@ func (s *OrderService) BatchProcessOrders(ctx context.Context, req orderdto.Batc
 			// Order successfully processed
 			result.Status = "accepted"
 			result.OrderID = orderID
+			result.OrderNumber = orderNumber
 			result.Total = total
 
 			// Update response and summary atomically
@@ -142,6 +230,15 @@ func (s *OrderService) BatchProcessOrders(ctx context.Context, req orderdto.Batc
 	// Wait for all goroutines to finish
 	wg.Wait()
 
+	s.metrics.BatchProcessed(len(req.Orders))
+	for _, result := range response.ProcessedOrders {
+		if result.Status == "accepted" {
+			s.metrics.OrderCreated("takeaway")
+			continue
+		}
+		s.metrics.OrderRejected(batchRejectReason(result.Reason))
+	}
+
 	// After all orders are processed, collect inventory summary
 	for id, used := range ingredientUsage {
 		// Skip ingredients that weren't used successfully
@@ -152,7 +249,7 @@ func (s *OrderService) BatchProcessOrders(ctx context.Context, req orderdto.Batc
 		// Get current inventory state
 		inventory, err := s.inventoryRepo.GetInventoryByID(ctx, id)
 		if err != nil {
-			s.logger.Printf("Error getting inventory for summary: %v", err)
+			s.logger.ErrorContext(ctx, "Error getting inventory for summary", "error", err)
 			continue
 		}
 
@@ -169,13 +266,13 @@ func (s *OrderService) BatchProcessOrders(ctx context.Context, req orderdto.Batc
 }
 
 // calculateIngredientsNeeded calculates the required ingredients for given order items
-func (s *OrderService) calculateIngredientsNeeded(ctx context.Context, items []orderdto.CreateOrderItem) (map[string]IngredientRequirement, error) {
+func (s *OrderService) calculateIngredientsNeeded(ctx context.Context, storeID string, items []orderdto.CreateOrderItem) (map[string]IngredientRequirement, error) {
 	requiredIngredients := make(map[string]IngredientRequirement)
 
 	// For each menu item in the order
 	for _, item := range items {
 		// Get ingredients required for this menu item
-		ingredients, err := s.menuRepo.GetMenuItemIngredients(ctx, item.MenuItemID)
+		ingredients, err := s.storeRecipe(ctx, storeID, item.MenuItemID)
 		if err != nil {
 			return nil, fmt.Errorf("failed to get ingredients for menu item %s: %w", item.MenuItemID, err)
 		}
@@ -214,3 +311,17 @@ func (s *OrderService) calculateIngredientsNeeded(ctx context.Context, items []o
 
 // Reference to processOrderWithTransaction
 // The actual implementation is in batch_tx.go
+
+// invalidOrderReason describes the violations of an order of a batch, like
+// "invalid_order: items[0].quantity must be greater than 0"
+func invalidOrderReason(err error) string {
+	var appErr *apperror.Error
+	if !errors.As(err, &appErr) || len(appErr.Fields) == 0 {
+		return fmt.Sprintf("error: %s", err.Error())
+	}
+	violations := make([]string, len(appErr.Fields))
+	for i, field := range appErr.Fields {
+		violations[i] = field.Field + " " + field.Message
+	}
+	return "invalid_order: " + strings.Join(violations, "; ")
+}
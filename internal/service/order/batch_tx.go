@@ -6,37 +6,46 @@ import (
 	"fmt"
 	"time"
 
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+
+	"frappuccino/internal/apperror"
 	orderdto "frappuccino/internal/dto/order"
 	"frappuccino/internal/entity"
 	"frappuccino/internal/repository/postgres"
+	"frappuccino/internal/tracing"
 )
 
 // processOrderWithTransaction processes a single order within a database transaction
-func (s *OrderService) processOrderWithTransaction(ctx context.Context, req orderdto.CreateOrderRequest) (string, float64, error) {
+func (s *OrderService) processOrderWithTransaction(ctx context.Context, storeID string, req orderdto.CreateOrderRequest) (_ string, _ int, _ float64, err error) {
+	ctx, span := tracer.Start(ctx, "OrderService.processOrderWithTransaction",
+		trace.WithAttributes(attribute.Int("order.items", len(req.Items))))
+	defer func() { tracing.End(span, err) }()
+
 	var items []entity.OrderItem
 	var total float64
 
 	// Begin a database transaction
 	tx, err := s.orderRepo.Begin(ctx)
 	if err != nil {
-		return "", 0, fmt.Errorf("error starting transaction: %w", err)
+		return "", 0, 0, fmt.Errorf("error starting transaction: %w", err)
 	}
 
 	// Ensure rollback on error
 	defer func() {
 		if err != nil {
 			if rbErr := tx.Rollback(); rbErr != nil {
-				s.logger.Printf("Error rolling back transaction: %v", rbErr)
+				s.logger.ErrorContext(ctx, "Error rolling back transaction", "error", rbErr)
 			}
 		}
 	}()
 
 	// Get prices and build order items
 	for _, dtoItem := range req.Items {
-		// Get current price from the menu_items table
-		price, err := s.orderRepo.GetMenuItemPrice(ctx, dtoItem.MenuItemID)
+		// Get the price the store sells the item for
+		price, err := s.storePrice(ctx, storeID, dtoItem.MenuItemID)
 		if err != nil {
-			return "", 0, fmt.Errorf("error getting price for item: %w", err)
+			return "", 0, 0, fmt.Errorf("error getting price for item: %w", err)
 		}
 
 		// Calculate subtotal and accumulate total
@@ -66,48 +75,73 @@ func (s *OrderService) processOrderWithTransaction(ctx context.Context, req orde
 
 	// Build order entity
 	orderEntity := entity.Order{
+		StoreID:             storeID,
 		CustomerName:        req.CustomerName,
 		SpecialInstructions: specialInstructions,
 		TotalAmount:         total,
 		Status:              "pending",
+		OrderType:           req.OrderType,
 		CreatedAt:           time.Now(),
 		UpdatedAt:           time.Now(),
 	}
+	orderEntity.BusinessDate, err = s.businessDate(orderEntity.CreatedAt)
+	if err != nil {
+		return "", 0, 0, err
+	}
 
 	// Create the order and get the ID within the transaction
-	orderID, err := s.orderRepo.CreateOrderWithTx(ctx, tx, orderEntity, items)
+	orderID, orderNumber, err := s.orderRepo.CreateOrderWithTx(ctx, tx, orderEntity, items)
 	if err != nil {
-		return "", 0, fmt.Errorf("error creating order: %w", err)
+		return "", 0, 0, fmt.Errorf("error creating order: %w", err)
 	}
 
 	// Deduct ingredients from inventory within the transaction
-	err = s.deductIngredientsWithTransaction(ctx, tx, req.Items, orderID)
+	var usage stockUsage
+	err = s.deductIngredientsWithTransaction(ctx, tx, storeID, req.Items, orderID, &usage)
 	if err != nil {
-		return "", 0, fmt.Errorf("error deducting ingredients: %w", err)
+		return "", 0, 0, fmt.Errorf("error deducting ingredients: %w", err)
+	}
+
+	// Split the order into station tickets within the same transaction
+	err = s.stationRepo.RouteOrderItemsWithTx(ctx, tx, orderID)
+	if err != nil {
+		return "", 0, 0, fmt.Errorf("error routing order to stations: %w", err)
+	}
+
+	// Queue the kitchen tickets with the order, so they print only if the order is accepted
+	if _, err = s.printRepo.EnqueueKitchenTicketsWithTx(ctx, tx, orderID); err != nil {
+		return "", 0, 0, fmt.Errorf("error queueing kitchen tickets: %w", err)
 	}
 
 	// Commit the transaction
 	if err = tx.Commit(); err != nil {
-		return "", 0, fmt.Errorf("error committing transaction: %w", err)
+		return "", 0, 0, fmt.Errorf("error committing transaction: %w", err)
 	}
+	s.recordStockUsage(deductedByBatch, usage)
 
-	return orderID, total, nil
+	return orderID, orderNumber, total, nil
 }
 
 // deductIngredientsWithTransaction updates inventory within a transaction
 func (s *OrderService) deductIngredientsWithTransaction(
 	ctx context.Context,
 	tx *postgres.Transaction,
+	storeID string,
 	items []orderdto.CreateOrderItem,
 	orderID string,
-) error {
+	usage *stockUsage,
+) (err error) {
+	ctx, span := tracer.Start(ctx, "OrderService.deductIngredientsWithTransaction",
+		trace.WithAttributes(attribute.String("order.id", orderID)))
+	defer func() { tracing.End(span, err) }()
+
 	// Create a map to aggregate required quantities of ingredients
 	requiredIngredients := make(map[string]float32)
 
 	// For each menu item in the order
 	for _, item := range items {
 		// Get ingredients required for this menu item
-		ingredients, err := s.menuRepo.GetMenuItemIngredients(ctx, item.MenuItemID)
+		ingredients, err := s.storeRecipe(ctx, storeID, item.MenuItemID)
 		if err != nil {
 			return fmt.Errorf("failed to get ingredients for menu item %s: %w", item.MenuItemID, err)
 		}
@@ -145,8 +179,13 @@ func (s *OrderService) deductIngredientsWithTransaction(
 		// Check if we have enough inventory
 		newQuantity := inventory.Quantity - deductQty
 		if newQuantity < 0 {
-			return fmt.Errorf("insufficient inventory for ingredient %s: required %.2f, available %.2f",
-				ingredientID, deductQty, inventory.Quantity)
+			return ErrInsufficientInventory.WithShortages(apperror.Shortage{
+				IngredientID: ingredientID,
+				Name:         inventory.Name,
+				Required:     deductQty,
+				Available:    inventory.Quantity,
+				Unit:         inventory.Unit,
+			})
 		}
 
 		// Update inventory quantity within the transaction
@@ -160,12 +199,7 @@ func (s *OrderService) deductIngredientsWithTransaction(
 			return fmt.Errorf("failed to update inventory for ingredient %s: %w", ingredientID, err)
 		}
 
-		// Check if we've fallen below reorder point
-		if newQuantity <= inventory.ReorderPoint {
-			s.logger.Printf("WARNING: Ingredient %s (%s) has fallen below reorder point. Current: %.2f, Reorder at: %.2f",
-				inventory.Name, ingredientID, newQuantity, inventory.ReorderPoint)
-			// In a real system, we might trigger a notification or automated order here
-		}
+		s.useStock(ctx, usage, inventory, newQuantity)
 	}
 
 	return nil
@@ -0,0 +1,236 @@
+package order
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+
+	"frappuccino/internal/apperror"
+	orderdto "frappuccino/internal/dto/order"
+	"frappuccino/internal/entity"
+)
+
+var (
+	ErrAddressRequired     = apperror.InvalidField("delivery_address_required", "delivery_address", "delivery orders need a delivery address, and only delivery orders may have one")
+	ErrOutsideDeliveryArea = apperror.New(apperror.Unprocessable, "outside_delivery_area", "address is outside every delivery zone")
+	ErrBelowMinimumOrder   = apperror.New(apperror.Unprocessable, "below_delivery_minimum", "order is below the minimum for delivery")
+	ErrNotDelivery         = apperror.New(apperror.Conflict, "not_a_delivery", "order is not a delivery")
+	ErrCourierNotFound     = apperror.New(apperror.NotFound, "courier_not_found", "courier not found")
+	ErrCourierInactive     = apperror.New(apperror.Conflict, "courier_inactive", "courier is not active")
+	ErrAlreadyDispatched   = apperror.New(apperror.Conflict, "already_dispatched", "delivery has already left the shop")
+	ErrCourierRequired     = apperror.New(apperror.Conflict, "courier_required", "assign a courier before the order goes out for delivery")
+	ErrNotReadyToDispatch  = apperror.New(apperror.Conflict, "not_ready_to_dispatch", "only ready delivery orders can go out for delivery")
+	ErrNotDispatched       = apperror.New(apperror.Conflict, "not_dispatched", "delivery orders must be out for delivery before they are delivered")
+)
+
+// validateDeliveryAddress checks that an address is given exactly for delivery orders
+func validateDeliveryAddress(req orderdto.CreateOrderRequest) error {
+	if req.OrderType != "delivery" {
+		if req.DeliveryAddress != nil {
+			return ErrAddressRequired
+		}
+		return nil
+	}
+
+	addr := req.DeliveryAddress
+	if addr == nil || strings.TrimSpace(addr.Street) == "" || strings.TrimSpace(addr.City) == "" ||
+		strings.TrimSpace(addr.Postcode) == "" {
+		return ErrAddressRequired.WithDetail("street, city and postcode are required")
+	}
+	if (addr.Latitude == nil) != (addr.Longitude == nil) {
+		return ErrAddressRequired.WithDetail("latitude and longitude must be given together")
+	}
+	return nil
+}
+
+// quoteDelivery finds the zone of a delivery address and checks the order meets its minimum
+func (s *OrderService) quoteDelivery(ctx context.Context, addr orderdto.DeliveryAddress, subtotal float64) (entity.DeliveryZone, error) {
+	zones, err := s.deliveryRepo.GetZones(ctx, true)
+	if err != nil {
+		return entity.DeliveryZone{}, fmt.Errorf("error getting delivery zones: %w", err)
+	}
+
+	zone, ok := matchZone(zones, toDeliveryAddress(addr))
+	if !ok {
+		return entity.DeliveryZone{}, ErrOutsideDeliveryArea
+	}
+	if subtotal < zone.MinimumOrder {
+		return entity.DeliveryZone{}, ErrBelowMinimumOrder.WithDetail(fmt.Sprintf("%s requires %.2f, order is %.2f",
+			zone.Name, zone.MinimumOrder, subtotal))
+	}
+
+	return zone, nil
+}
+
+// AssignCourier hands a delivery order to a courier until it leaves the shop
+func (s *OrderService) AssignCourier(ctx context.Context, orderID string, req orderdto.AssignCourierRequest) (orderdto.GetOrderResponse, error) {
+	order, err := s.getOrder(ctx, orderID)
+	if err != nil {
+		return orderdto.GetOrderResponse{}, err
+	}
+	if order.OrderType != "delivery" {
+		return orderdto.GetOrderResponse{}, ErrNotDelivery
+	}
+
+	courier, err := s.deliveryRepo.GetCourierByID(ctx, req.CourierID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return orderdto.GetOrderResponse{}, ErrCourierNotFound
+		}
+		return orderdto.GetOrderResponse{}, err
+	}
+	if !courier.IsActive {
+		return orderdto.GetOrderResponse{}, ErrCourierInactive
+	}
+
+	assigned, err := s.deliveryRepo.AssignCourier(ctx, orderID, courier.CourierID)
+	if err != nil {
+		s.logger.ErrorContext(ctx, "Error assigning courier", "order_id", orderID, "error", err)
+		return orderdto.GetOrderResponse{}, err
+	}
+	if !assigned {
+		return orderdto.GetOrderResponse{}, ErrAlreadyDispatched
+	}
+
+	return s.GetOrderByID(ctx, orderID)
+}
+
+// checkDeliveryTransition enforces the delivery states: a delivery goes out_for_delivery from
+// ready with a courier assigned, and is delivered only from out_for_delivery
+func (s *OrderService) checkDeliveryTransition(ctx context.Context, orderID, newStatus string) error {
+	if newStatus != "out_for_delivery" && newStatus != "delivered" {
+		return nil
+	}
+
+	order, err := s.orderRepo.GetOrderByID(ctx, orderID)
+	if err != nil {
+		return err
+	}
+
+	if order.OrderType != "delivery" {
+		if newStatus == "out_for_delivery" {
+			return ErrNotDelivery
+		}
+		return nil
+	}
+
+	if newStatus == "delivered" {
+		if order.Status != "out_for_delivery" {
+			return ErrNotDispatched
+		}
+		return nil
+	}
+
+	if order.Status != "ready" {
+		return ErrNotReadyToDispatch
+	}
+	delivery, err := s.deliveryRepo.GetDelivery(ctx, orderID)
+	if err != nil {
+		return fmt.Errorf("error getting delivery: %w", err)
+	}
+	if delivery.CourierID == nil {
+		return ErrCourierRequired
+	}
+	return nil
+}
+
+// recordDeliveryProgress stamps when a delivery left the shop and when it arrived
+func (s *OrderService) recordDeliveryProgress(ctx context.Context, orderID, newStatus string) {
+	var err error
+	switch newStatus {
+	case "out_for_delivery":
+		err = s.deliveryRepo.MarkDispatched(ctx, orderID)
+	case "delivered":
+		err = s.deliveryRepo.MarkDelivered(ctx, orderID)
+	}
+	if err != nil {
+		// The status change already happened; only the delivery timestamps are missing
+		s.logger.ErrorContext(ctx, "Error recording delivery progress", "order_id", orderID, "error", err)
+	}
+}
+
+// deliveryResponse loads the delivery details shown with a delivery order
+func (s *OrderService) deliveryResponse(ctx context.Context, orderID string) (*orderdto.DeliveryResponse, error) {
+	delivery, err := s.deliveryRepo.GetDelivery(ctx, orderID)
+	if err != nil {
+		return nil, err
+	}
+
+	addr := orderdto.DeliveryAddress{
+		Street:   delivery.Address.Street,
+		City:     delivery.Address.City,
+		Postcode: delivery.Address.Postcode,
+		Notes:    delivery.Address.Notes,
+	}
+	if delivery.Address.Location != nil {
+		addr.Latitude = &delivery.Address.Location.Latitude
+		addr.Longitude = &delivery.Address.Location.Longitude
+	}
+
+	return &orderdto.DeliveryResponse{
+		ZoneID:       delivery.ZoneID,
+		ZoneName:     delivery.ZoneName,
+		Address:      addr,
+		CourierID:    delivery.CourierID,
+		CourierName:  delivery.CourierName,
+		AssignedAt:   delivery.AssignedAt,
+		DispatchedAt: delivery.DispatchedAt,
+		DeliveredAt:  delivery.DeliveredAt,
+	}, nil
+}
+
+func toDeliveryAddress(addr orderdto.DeliveryAddress) entity.DeliveryAddress {
+	address := entity.DeliveryAddress{
+		Street:   strings.TrimSpace(addr.Street),
+		City:     strings.TrimSpace(addr.City),
+		Postcode: strings.TrimSpace(addr.Postcode),
+		Notes:    addr.Notes,
+	}
+	if addr.Latitude != nil && addr.Longitude != nil {
+		address.Location = &entity.GeoPoint{Latitude: *addr.Latitude, Longitude: *addr.Longitude}
+	}
+	return address
+}
+
+// matchZone returns the first zone listing the postcode of the address, or else the first
+// zone whose polygon contains its coordinates
+func matchZone(zones []entity.DeliveryZone, addr entity.DeliveryAddress) (entity.DeliveryZone, bool) {
+	postcode := normalizePostcode(addr.Postcode)
+	for _, zone := range zones {
+		for _, p := range zone.Postcodes {
+			if normalizePostcode(p) == postcode {
+				return zone, true
+			}
+		}
+	}
+
+	if addr.Location == nil {
+		return entity.DeliveryZone{}, false
+	}
+	for _, zone := range zones {
+		if len(zone.Polygon) >= 3 && pointInPolygon(*addr.Location, zone.Polygon) {
+			return zone, true
+		}
+	}
+
+	return entity.DeliveryZone{}, false
+}
+
+func normalizePostcode(postcode string) string {
+	return strings.ToUpper(strings.ReplaceAll(strings.TrimSpace(postcode), " ", ""))
+}
+
+// pointInPolygon uses ray casting; zones are small enough to treat coordinates as planar
+func pointInPolygon(point entity.GeoPoint, polygon []entity.GeoPoint) bool {
+	inside := false
+	for i, j := 0, len(polygon)-1; i < len(polygon); j, i = i, i+1 {
+		a, b := polygon[i], polygon[j]
+		if (a.Latitude > point.Latitude) != (b.Latitude > point.Latitude) &&
+			point.Longitude < (b.Longitude-a.Longitude)*(point.Latitude-a.Latitude)/(b.Latitude-a.Latitude)+a.Longitude {
+			inside = !inside
+		}
+	}
+	return inside
+}
@@ -0,0 +1,99 @@
+package order
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"frappuccino/internal/entity"
+)
+
+// estimateReadyTime predicts when an order will be ready by replaying the current kitchen
+// queue: orders already being prepared go first, then pending orders in the order they were
+// placed, each taken by the first of ParallelOrders workers to become free. Because the queue
+// is read on every call, the estimate follows the queue as it moves.
+// Each store has its own kitchen, so only the queue of the order's store counts. Orders that
+// have left the kitchen queue have no estimate.
+func (s *OrderService) estimateReadyTime(ctx context.Context, storeID, orderID string) (*time.Time, error) {
+	queue, err := s.orderRepo.GetActiveOrderQueue(ctx, storeID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get order queue: %w", err)
+	}
+
+	position := -1
+	for i, q := range queue {
+		if q.OrderID == orderID {
+			position = i
+			break
+		}
+	}
+	if position < 0 {
+		return nil, nil
+	}
+
+	now := time.Now()
+	prepDurations, err := s.orderRepo.GetMenuItemPrepDurations(ctx, storeID, now.Add(-s.etaCfg.HistoryWindow))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get preparation durations: %w", err)
+	}
+
+	// Orders in preparation are ahead of everything that is still pending
+	sort.SliceStable(queue, func(i, j int) bool {
+		return queue[i].Status == "preparing" && queue[j].Status != "preparing"
+	})
+
+	workers := s.etaCfg.ParallelOrders
+	if workers < 1 {
+		workers = 1
+	}
+	freeAt := make([]time.Time, workers)
+	for i := range freeAt {
+		freeAt[i] = now
+	}
+
+	for _, q := range queue {
+		remaining := s.prepTime(q, prepDurations)
+		if q.Status == "preparing" && q.PreparingAt != nil {
+			remaining -= now.Sub(*q.PreparingAt)
+			if remaining < 0 {
+				remaining = 0
+			}
+		}
+
+		// Hand the order to the worker that frees up first
+		next := 0
+		for i := range freeAt {
+			if freeAt[i].Before(freeAt[next]) {
+				next = i
+			}
+		}
+		freeAt[next] = freeAt[next].Add(remaining)
+
+		if q.OrderID == orderID {
+			readyAt := freeAt[next]
+			return &readyAt, nil
+		}
+	}
+
+	return nil, nil
+}
+
+// prepTime is the longest historical preparation time among the order's menu items,
+// since the items of one order are made side by side
+func (s *OrderService) prepTime(q entity.QueuedOrder, durations map[string]time.Duration) time.Duration {
+	var longest time.Duration
+	for _, menuItemID := range q.MenuItemIDs {
+		d, ok := durations[menuItemID]
+		if !ok {
+			d = s.etaCfg.DefaultPrepTime
+		}
+		if d > longest {
+			longest = d
+		}
+	}
+	if longest == 0 {
+		longest = s.etaCfg.DefaultPrepTime
+	}
+	return longest
+}
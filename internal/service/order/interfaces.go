@@ -4,30 +4,55 @@ import (
 	"context"
 	"time"
 
+	"frappuccino/internal/dto/list"
 	"frappuccino/internal/entity"
 	"frappuccino/internal/repository/postgres"
 )
 
 type orderRepo interface {
-	CreateOrder(ctx context.Context, order entity.Order, items []entity.OrderItem) (string, error)
-	GetMenuItemPrice(ctx context.Context, menuItemID string) (float64, error)
+	CreateOrder(ctx context.Context, order entity.Order, items []entity.OrderItem) (string, int, error)
+	GetMenuItemPrice(ctx context.Context, storeID, menuItemID string) (float64, bool, error)
 	GetOrderByID(ctx context.Context, orderID string) (entity.Order, error)
+	GetOrderIDByNumber(ctx context.Context, storeID string, businessDate time.Time, orderNumber int) (string, error)
 	GetOrderItemsByOrderID(ctx context.Context, orderID string) ([]entity.OrderItem, error)
-	GetAllOrders(ctx context.Context) ([]entity.Order, error)
+	ListOrders(ctx context.Context, storeID string, q list.Query) ([]entity.Order, list.Page, error)
 	UpdateOrder(ctx context.Context, orderID string, updates map[string]interface{}) error
-	GetAllOrderStatusHistory(ctx context.Context) ([]entity.OrderStatusHistory, error)
+	ListOrderStatusHistory(ctx context.Context, storeID string, q list.Query) ([]entity.OrderStatusHistory, list.Page, error)
 	DeleteOrder(ctx context.Context, id string) (string, error)
-	GetNumberOfOrderedItems(ctx context.Context, startDate, endDate *time.Time) (map[string]int, error)
+	GetNumberOfOrderedItems(ctx context.Context, storeID string, startDate, endDate *time.Time) (map[string]int, error)
+
+	// ETA estimation
+	GetActiveOrderQueue(ctx context.Context, storeID string) ([]entity.QueuedOrder, error)
+	GetMenuItemPrepDurations(ctx context.Context, storeID string, since time.Time) (map[string]time.Duration, error)
+
+	// Scheduled orders
+	GetDueScheduledOrders(ctx context.Context, before time.Time) ([]string, error)
+	TransitionOrderStatusWithTx(ctx context.Context, tx *postgres.Transaction, orderID, fromStatus, toStatus, reason string) (bool, error)
+
+	// Line item editing
+	LockOrderWithTx(ctx context.Context, tx *postgres.Transaction, orderID string) (entity.Order, error)
+	GetOrderItemWithTx(ctx context.Context, tx *postgres.Transaction, orderID, orderItemID string) (entity.OrderItem, error)
+	CountOrderItemsWithTx(ctx context.Context, tx *postgres.Transaction, orderID string) (int, error)
+	AddOrderItemWithTx(ctx context.Context, tx *postgres.Transaction, item entity.OrderItem) (string, error)
+	UpdateOrderItemWithTx(ctx context.Context, tx *postgres.Transaction, item entity.OrderItem) error
+	DeleteOrderItemWithTx(ctx context.Context, tx *postgres.Transaction, orderItemID string) error
+	RecalculateOrderTotalWithTx(ctx context.Context, tx *postgres.Transaction, orderID string) (float64, error)
+	CreateOrderAuditWithTx(ctx context.Context, tx *postgres.Transaction, entry entity.OrderAuditEntry) error
+	GetOrderAuditLog(ctx context.Context, orderID string) ([]entity.OrderAuditEntry, error)
+
+	// Split and merge
+	CreateOrderStatusHistoryWithTx(ctx context.Context, tx *postgres.Transaction, orderID, oldStatus, newStatus, reason string) error
+	MoveOrderItemsWithTx(ctx context.Context, tx *postgres.Transaction, fromOrderIDs []string, toOrderID string) error
 
 	// Transaction support
 	Begin(ctx context.Context) (*postgres.Transaction, error)
-	CreateOrderWithTx(ctx context.Context, tx *postgres.Transaction, order entity.Order, items []entity.OrderItem) (string, error)
+	CreateOrderWithTx(ctx context.Context, tx *postgres.Transaction, order entity.Order, items []entity.OrderItem) (string, int, error)
 }
 
 // menuRepo defines methods for working with menu items and ingredients
 type menuRepo interface {
-	GetMenuItemIngredients(ctx context.Context, menuItemID string) ([]entity.MenuItemIngredient, error)
-	GetMenuItem(ctx context.Context) ([]entity.MenuItem, error)
+	GetMenuItemIngredients(ctx context.Context, storeID, menuItemID string) ([]entity.MenuItemIngredient, error)
+	GetMenuItem(ctx context.Context, storeID string) ([]entity.MenuItem, error)
 }
 
 // inventoryRepo defines methods for working with inventory
@@ -35,8 +60,57 @@ type inventoryRepo interface {
 	GetInventoryByID(ctx context.Context, id string) (entity.Inventory, error)
 	UpdateInventory(ctx context.Context, updates map[string]interface{}, id string) (string, error)
 	CreateInventoryTransaction(ctx context.Context, transaction entity.InventoryTransaction) error
+	GetReservedQuantities(ctx context.Context) (map[string]float32, error)
 
 	// Transaction support
 	UpdateInventoryWithTx(ctx context.Context, tx *postgres.Transaction, updates map[string]interface{}, id string) error
 	CreateInventoryTransactionWithTx(ctx context.Context, tx *postgres.Transaction, transaction entity.InventoryTransaction) error
+	GetInventoryByIDWithTx(ctx context.Context, tx *postgres.Transaction, id string) (entity.Inventory, error)
+	CreateReservationsWithTx(ctx context.Context, tx *postgres.Transaction, orderID string, quantities map[string]float32) error
+	ReleaseReservationsWithTx(ctx context.Context, tx *postgres.Transaction, orderID string) (map[string]float32, error)
+}
+
+// stationRepo routes the items of new and edited orders to kitchen station tickets
+type stationRepo interface {
+	RouteOrderItems(ctx context.Context, orderID string) error
+	RouteOrderItemsWithTx(ctx context.Context, tx *postgres.Transaction, orderID string) error
+	DeleteEmptyTicketsWithTx(ctx context.Context, tx *postgres.Transaction, orderID string) error
+}
+
+// tableRepo keeps the open tab of dine-in tables in sync with their orders
+type tableRepo interface {
+	GetTableByID(ctx context.Context, tableID string) (entity.DiningTable, error)
+	OpenSession(ctx context.Context, tableID string, guests int) (string, bool, error)
+	AttachSessionOrderWithTx(ctx context.Context, tx *postgres.Transaction, sessionID, orderID string) error
+	DeleteSession(ctx context.Context, sessionID string) error
+	CloseSessionForOrder(ctx context.Context, orderID string) error
+	HasOpenTabWithTx(ctx context.Context, tx *postgres.Transaction, orderID string) (bool, error)
+	MoveOpenSessionWithTx(ctx context.Context, tx *postgres.Transaction, fromOrderID, toOrderID string) error
+}
+
+// deliveryRepo prices delivery orders by zone and tracks their couriers
+type deliveryRepo interface {
+	GetZones(ctx context.Context, activeOnly bool) ([]entity.DeliveryZone, error)
+	GetCourierByID(ctx context.Context, courierID string) (entity.Courier, error)
+	CreateDelivery(ctx context.Context, delivery entity.Delivery) error
+	CreateDeliveryWithTx(ctx context.Context, tx *postgres.Transaction, delivery entity.Delivery) error
+	GetDelivery(ctx context.Context, orderID string) (entity.Delivery, error)
+	AssignCourier(ctx context.Context, orderID, courierID string) (bool, error)
+	MarkDispatched(ctx context.Context, orderID string) error
+	MarkDelivered(ctx context.Context, orderID string) error
+}
+
+// printRepo queues kitchen tickets once an order reaches the stations
+type printRepo interface {
+	EnqueueKitchenTickets(ctx context.Context, orderID string) (int, error)
+	EnqueueKitchenTicketsWithTx(ctx context.Context, tx *postgres.Transaction, orderID string) (int, error)
+}
+
+// metricsRecorder counts orders and the stock they use for the metrics endpoint
+type metricsRecorder interface {
+	OrderCreated(orderType string)
+	OrderRejected(reason string)
+	BatchProcessed(size int)
+	StockDeducted(source string)
+	LowStock(storeID, ingredient string)
 }
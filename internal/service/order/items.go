@@ -0,0 +1,375 @@
+package order
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"frappuccino/internal/apperror"
+	orderdto "frappuccino/internal/dto/order"
+	"frappuccino/internal/entity"
+	"frappuccino/internal/repository/postgres"
+	"frappuccino/internal/service/access"
+)
+
+var (
+	ErrOrderNotEditable      = apperror.New(apperror.Conflict, "order_not_editable", "only pending orders and open tabs can be edited")
+	ErrInvalidQuantity       = apperror.InvalidField("invalid_quantity", "quantity", "quantity must be greater than zero")
+	ErrLastOrderItem         = apperror.New(apperror.Conflict, "last_order_item", "an order must keep at least one item; cancel the order instead")
+	ErrInsufficientInventory = apperror.New(apperror.Conflict, "insufficient_stock", "insufficient inventory")
+	ErrNoItemChanges         = apperror.New(apperror.Invalid, "no_changes", "quantity or customizations must be provided")
+	ErrNoChanges             = apperror.New(apperror.Invalid, "no_changes", "no valid fields to update")
+	ErrInvalidStatus         = apperror.InvalidField("invalid_order_status", "status", "status must be pending, preparing, ready, out_for_delivery, delivered or cancelled")
+)
+
+// itemEdit applies one change to the line items of a locked order. It returns the audit entry
+// describing the change and, per menu item, how many more units the order now consumes.
+type itemEdit func(tx *postgres.Transaction) (entity.OrderAuditEntry, map[string]int, error)
+
+// AddOrderItem adds a line item to a pending order or open tab at the current menu price
+func (s *OrderService) AddOrderItem(ctx context.Context, orderID string, req orderdto.AddOrderItemRequest) (orderdto.GetOrderResponse, error) {
+	if req.Quantity <= 0 {
+		return orderdto.GetOrderResponse{}, ErrInvalidQuantity
+	}
+
+	order, err := s.getOrder(ctx, orderID)
+	if err != nil {
+		return orderdto.GetOrderResponse{}, err
+	}
+
+	price, err := s.storePrice(ctx, order.StoreID, req.MenuItemID)
+	if err != nil {
+		return orderdto.GetOrderResponse{}, fmt.Errorf("error getting price for item: %w", err)
+	}
+
+	customizations := req.Customizations
+	if len(customizations) == 0 {
+		customizations = json.RawMessage(`{}`)
+	}
+
+	err = s.editOrderItems(ctx, orderID, func(tx *postgres.Transaction) (entity.OrderAuditEntry, map[string]int, error) {
+		item := entity.OrderItem{
+			OrderID:        orderID,
+			MenuItemID:     req.MenuItemID,
+			Quantity:       req.Quantity,
+			PriceAtTime:    price,
+			Customizations: customizations,
+		}
+		orderItemID, err := s.orderRepo.AddOrderItemWithTx(ctx, tx, item)
+		if err != nil {
+			return entity.OrderAuditEntry{}, nil, err
+		}
+		item.OrderItemID = orderItemID
+
+		entry := entity.OrderAuditEntry{
+			OrderItemID: orderItemID,
+			Action:      "item_added",
+			NewValue:    auditValue(item),
+			Reason:      req.Reason,
+		}
+		return entry, map[string]int{item.MenuItemID: item.Quantity}, nil
+	})
+	if err != nil {
+		s.logger.ErrorContext(ctx, "Error adding order item", "order_id", orderID, "error", err)
+		return orderdto.GetOrderResponse{}, err
+	}
+
+	return s.GetOrderByID(ctx, orderID)
+}
+
+// UpdateOrderItem changes the quantity and/or customizations of a line item of a pending order or open tab
+func (s *OrderService) UpdateOrderItem(ctx context.Context, orderID, orderItemID string, req orderdto.UpdateOrderItemRequest) (orderdto.GetOrderResponse, error) {
+	if req.Quantity == nil && len(req.Customizations) == 0 {
+		return orderdto.GetOrderResponse{}, ErrNoItemChanges
+	}
+	if req.Quantity != nil && *req.Quantity <= 0 {
+		return orderdto.GetOrderResponse{}, ErrInvalidQuantity
+	}
+
+	err := s.editOrderItems(ctx, orderID, func(tx *postgres.Transaction) (entity.OrderAuditEntry, map[string]int, error) {
+		before, err := s.orderRepo.GetOrderItemWithTx(ctx, tx, orderID, orderItemID)
+		if err != nil {
+			return entity.OrderAuditEntry{}, nil, err
+		}
+
+		after := before
+		if req.Quantity != nil {
+			after.Quantity = *req.Quantity
+		}
+		if len(req.Customizations) > 0 {
+			after.Customizations = req.Customizations
+		}
+
+		if err := s.orderRepo.UpdateOrderItemWithTx(ctx, tx, after); err != nil {
+			return entity.OrderAuditEntry{}, nil, err
+		}
+
+		entry := entity.OrderAuditEntry{
+			OrderItemID: orderItemID,
+			Action:      "item_updated",
+			OldValue:    auditValue(before),
+			NewValue:    auditValue(after),
+			Reason:      req.Reason,
+		}
+		return entry, map[string]int{after.MenuItemID: after.Quantity - before.Quantity}, nil
+	})
+	if err != nil {
+		s.logger.ErrorContext(ctx, "Error updating order item", "order_id", orderID, "order_item_id", orderItemID, "error", err)
+		return orderdto.GetOrderResponse{}, err
+	}
+
+	return s.GetOrderByID(ctx, orderID)
+}
+
+// RemoveOrderItem deletes a line item from a pending order or open tab and returns its ingredients to stock
+func (s *OrderService) RemoveOrderItem(ctx context.Context, orderID, orderItemID, reason string) (orderdto.GetOrderResponse, error) {
+	err := s.editOrderItems(ctx, orderID, func(tx *postgres.Transaction) (entity.OrderAuditEntry, map[string]int, error) {
+		before, err := s.orderRepo.GetOrderItemWithTx(ctx, tx, orderID, orderItemID)
+		if err != nil {
+			return entity.OrderAuditEntry{}, nil, err
+		}
+
+		count, err := s.orderRepo.CountOrderItemsWithTx(ctx, tx, orderID)
+		if err != nil {
+			return entity.OrderAuditEntry{}, nil, err
+		}
+		if count <= 1 {
+			return entity.OrderAuditEntry{}, nil, ErrLastOrderItem
+		}
+
+		if err := s.orderRepo.DeleteOrderItemWithTx(ctx, tx, orderItemID); err != nil {
+			return entity.OrderAuditEntry{}, nil, err
+		}
+
+		entry := entity.OrderAuditEntry{
+			OrderItemID: orderItemID,
+			Action:      "item_removed",
+			OldValue:    auditValue(before),
+			Reason:      reason,
+		}
+		return entry, map[string]int{before.MenuItemID: -before.Quantity}, nil
+	})
+	if err != nil {
+		s.logger.ErrorContext(ctx, "Error removing order item", "order_id", orderID, "order_item_id", orderItemID, "error", err)
+		return orderdto.GetOrderResponse{}, err
+	}
+
+	return s.GetOrderByID(ctx, orderID)
+}
+
+// GetOrderAuditLog returns every line item change of an order
+func (s *OrderService) GetOrderAuditLog(ctx context.Context, orderID string) ([]orderdto.OrderAuditEntryResponse, error) {
+	// Viewing an order is open to baristas, but only shift leads may review what was changed on it
+	if err := access.Check(ctx, access.OrderAudit); err != nil {
+		return nil, err
+	}
+
+	// Distinguish an unknown order from an order without changes
+	if _, err := s.getOrder(ctx, orderID); err != nil {
+		return nil, err
+	}
+
+	entries, err := s.orderRepo.GetOrderAuditLog(ctx, orderID)
+	if err != nil {
+		s.logger.ErrorContext(ctx, "Error getting order audit log", "order_id", orderID, "error", err)
+		return nil, err
+	}
+
+	response := make([]orderdto.OrderAuditEntryResponse, 0, len(entries))
+	for _, e := range entries {
+		response = append(response, orderdto.OrderAuditEntryResponse{
+			AuditID:     e.AuditID,
+			OrderItemID: e.OrderItemID,
+			Action:      e.Action,
+			OldValue:    e.OldValue,
+			NewValue:    e.NewValue,
+			TotalBefore: e.TotalBefore,
+			TotalAfter:  e.TotalAfter,
+			Reason:      e.Reason,
+			CreatedAt:   e.CreatedAt,
+		})
+	}
+
+	return response, nil
+}
+
+// editOrderItems runs an item edit inside a transaction that locks the order, posts the inventory
+// difference, recalculates the total, writes the audit entry and keeps the station tickets in sync.
+// Either all of it happens or none of it does.
+func (s *OrderService) editOrderItems(ctx context.Context, orderID string, edit itemEdit) (err error) {
+	tx, err := s.orderRepo.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("error starting transaction: %w", err)
+	}
+	defer func() {
+		if err != nil {
+			if rbErr := tx.Rollback(); rbErr != nil {
+				s.logger.ErrorContext(ctx, "Error rolling back transaction", "error", rbErr)
+			}
+		}
+	}()
+
+	order, err := s.orderRepo.LockOrderWithTx(ctx, tx, orderID)
+	if err != nil {
+		return err
+	}
+	if !access.InStore(ctx, order.StoreID) {
+		return sql.ErrNoRows
+	}
+	if err = s.checkEditable(ctx, tx, order); err != nil {
+		return err
+	}
+
+	entry, deltas, err := edit(tx)
+	if err != nil {
+		return err
+	}
+
+	// More items on a tab that was already served send it back to the kitchen
+	if order.Status == "ready" && consumesMore(deltas) {
+		_, err = s.orderRepo.TransitionOrderStatusWithTx(ctx, tx, orderID, "ready", "preparing", "Items added to tab")
+		if err != nil {
+			return err
+		}
+	}
+
+	if err = s.postInventoryDifference(ctx, tx, order.StoreID, orderID, deltas); err != nil {
+		return err
+	}
+
+	total, err := s.orderRepo.RecalculateOrderTotalWithTx(ctx, tx, orderID)
+	if err != nil {
+		return err
+	}
+
+	entry.OrderID = orderID
+	entry.TotalBefore = order.TotalAmount
+	entry.TotalAfter = total
+	if err = s.orderRepo.CreateOrderAuditWithTx(ctx, tx, entry); err != nil {
+		return err
+	}
+
+	// New items go onto the pending tickets; tickets emptied by a removal are dropped
+	if err = s.stationRepo.RouteOrderItemsWithTx(ctx, tx, orderID); err != nil {
+		return fmt.Errorf("error routing order to stations: %w", err)
+	}
+	if err = s.stationRepo.DeleteEmptyTicketsWithTx(ctx, tx, orderID); err != nil {
+		return err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("error committing transaction: %w", err)
+	}
+
+	return nil
+}
+
+// checkEditable allows edits to pending orders and to the open tab of a table until it is closed
+func (s *OrderService) checkEditable(ctx context.Context, tx *postgres.Transaction, order entity.Order) error {
+	if order.Status == "pending" {
+		return nil
+	}
+	if order.OrderType != "dine_in" || (order.Status != "preparing" && order.Status != "ready") {
+		return ErrOrderNotEditable
+	}
+
+	open, err := s.tableRepo.HasOpenTabWithTx(ctx, tx, order.OrderID)
+	if err != nil {
+		return err
+	}
+	if !open {
+		return ErrOrderNotEditable
+	}
+	return nil
+}
+
+// consumesMore reports whether an edit adds units of any menu item
+func consumesMore(deltas map[string]int) bool {
+	for _, delta := range deltas {
+		if delta > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// postInventoryDifference deducts the extra ingredients an edit consumes, or returns the ones it
+// frees, recording each change in inventory_transactions
+func (s *OrderService) postInventoryDifference(ctx context.Context, tx *postgres.Transaction, storeID, orderID string, deltas map[string]int) error {
+	changes := make(map[string]float32)
+	for menuItemID, delta := range deltas {
+		if delta == 0 {
+			continue
+		}
+		ingredients, err := s.storeRecipe(ctx, storeID, menuItemID)
+		if err != nil {
+			return fmt.Errorf("failed to get ingredients for menu item %s: %w", menuItemID, err)
+		}
+		for _, ing := range ingredients {
+			changes[ing.IngredientID] += float32(ing.Quantity) * float32(delta)
+		}
+	}
+	if len(changes) == 0 {
+		return nil
+	}
+
+	reserved, err := s.inventoryRepo.GetReservedQuantities(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get reserved quantities: %w", err)
+	}
+
+	for ingredientID, change := range changes {
+		if change == 0 {
+			continue
+		}
+
+		inventory, err := s.inventoryRepo.GetInventoryByIDWithTx(ctx, tx, ingredientID)
+		if err != nil {
+			return fmt.Errorf("failed to get inventory for ingredient %s: %w", ingredientID, err)
+		}
+
+		transaction := entity.InventoryTransaction{
+			IngredientID:    ingredientID,
+			QuantityChange:  change,
+			TransactionType: "deduction",
+			Reason:          fmt.Sprintf("Order %s edited", orderID),
+		}
+		if change < 0 {
+			transaction.QuantityChange = -change
+			transaction.TransactionType = "addition"
+		} else if available := inventory.Quantity - reserved[ingredientID]; available < change {
+			return ErrInsufficientInventory.WithShortages(apperror.Shortage{
+				IngredientID: ingredientID,
+				Name:         inventory.Name,
+				Required:     change,
+				Available:    available,
+				Unit:         inventory.Unit,
+			})
+		}
+
+		if err := s.inventoryRepo.CreateInventoryTransactionWithTx(ctx, tx, transaction); err != nil {
+			return fmt.Errorf("failed to record transaction for ingredient %s: %w", ingredientID, err)
+		}
+
+		updates := map[string]interface{}{
+			"quantity":     inventory.Quantity - change,
+			"last_updated": time.Now(),
+		}
+		if err := s.inventoryRepo.UpdateInventoryWithTx(ctx, tx, updates, ingredientID); err != nil {
+			return fmt.Errorf("failed to update inventory for ingredient %s: %w", ingredientID, err)
+		}
+	}
+
+	return nil
+}
+
+// auditValue snapshots a line item for the audit trail
+func auditValue(item entity.OrderItem) json.RawMessage {
+	value, err := json.Marshal(item)
+	if err != nil {
+		return nil
+	}
+	return value
+}
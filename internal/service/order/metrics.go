@@ -0,0 +1,96 @@
+package order
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"frappuccino/internal/entity"
+	"frappuccino/internal/metrics"
+	"frappuccino/internal/service/access"
+)
+
+// Sources of stock deductions, the source label of the inventory deduction counter
+const (
+	deductedByOrder     = "order"
+	deductedByBatch     = "batch"
+	deductedByScheduler = "scheduled"
+)
+
+// stockUsage collects the ingredients an order took, to be counted once they are committed
+type stockUsage struct {
+	deductions int
+	lowStock   []entity.Inventory
+}
+
+// useStock notes the deduction of an ingredient and warns when it left the stock at or below the
+// reorder point
+func (s *OrderService) useStock(ctx context.Context, usage *stockUsage, inventory entity.Inventory, newQuantity float32) {
+	usage.deductions++
+	if newQuantity > inventory.ReorderPoint {
+		return
+	}
+
+	s.logger.WarnContext(ctx, "Ingredient has fallen below reorder point",
+		"ingredient", inventory.Name,
+		"ingredient_id", inventory.IngredientID,
+		"quantity", newQuantity,
+		"reorder_point", inventory.ReorderPoint,
+	)
+	usage.lowStock = append(usage.lowStock, inventory)
+	// In a real system, we might trigger a notification or automated order here
+}
+
+// recordStockUsage counts the deductions and low-stock events of a committed order
+func (s *OrderService) recordStockUsage(source string, usage stockUsage) {
+	for i := 0; i < usage.deductions; i++ {
+		s.metrics.StockDeducted(source)
+	}
+	for _, inventory := range usage.lowStock {
+		s.metrics.LowStock(inventory.StoreID, inventory.Name)
+	}
+}
+
+// recordOrder counts a created order, or a refused one by why it was refused
+func (s *OrderService) recordOrder(orderType string, err error) {
+	if err == nil {
+		s.metrics.OrderCreated(orderType)
+		return
+	}
+	s.metrics.OrderRejected(rejectReason(err))
+}
+
+func rejectReason(err error) string {
+	switch {
+	case errors.Is(err, ErrInsufficientInventory):
+		return metrics.RejectInsufficient
+	case errors.Is(err, ErrNotStocked),
+		errors.Is(err, ErrItemUnavailable),
+		errors.Is(err, ErrTableOccupied),
+		errors.Is(err, ErrOutsideDeliveryArea),
+		errors.Is(err, ErrBelowMinimumOrder):
+		return metrics.RejectUnavailable
+	case errors.Is(err, ErrInvalidOrderType),
+		errors.Is(err, ErrTableRequired),
+		errors.Is(err, ErrTableNotFound),
+		errors.Is(err, ErrTableCapacity),
+		errors.Is(err, ErrAddressRequired),
+		errors.Is(err, ErrInvalidPickupTime),
+		errors.Is(err, access.ErrStoreRequired):
+		return metrics.RejectInvalid
+	default:
+		return metrics.RejectError
+	}
+}
+
+// batchRejectReason maps the reason a batch gives for refusing an order to the metric reason
+func batchRejectReason(reason string) string {
+	switch {
+	case strings.HasSuffix(reason, "_not_supported"), strings.HasPrefix(reason, "invalid_order"):
+		return metrics.RejectInvalid
+	case strings.HasPrefix(reason, "insufficient_inventory"):
+		return metrics.RejectInsufficient
+	default:
+		return metrics.RejectError
+	}
+}
@@ -0,0 +1,75 @@
+package order
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	orderdto "frappuccino/internal/dto/order"
+	"frappuccino/internal/service/access"
+)
+
+// businessDateLayout is how business dates are shown and looked up
+const businessDateLayout = "2006-01-02"
+
+// businessDate returns the business day a moment belongs to. The day runs from the configured
+// cutoff to the next one, so orders placed after midnight but before the cutoff count towards
+// the previous day.
+func (s *OrderService) businessDate(at time.Time) (time.Time, error) {
+	loc, err := loadLocation(s.numberingCfg.Location)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	local := at.In(loc)
+	if s.numberingCfg.BusinessDayCutoff != "" {
+		cutoff, err := time.Parse("15:04", s.numberingCfg.BusinessDayCutoff)
+		if err != nil {
+			return time.Time{}, fmt.Errorf("failed to parse business day cutoff: %w", err)
+		}
+		if local.Hour()*60+local.Minute() < cutoff.Hour()*60+cutoff.Minute() {
+			local = local.AddDate(0, 0, -1)
+		}
+	}
+
+	return time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, time.UTC), nil
+}
+
+// GetOrderByNumber finds an order by the number called out at the counter of the request's store.
+// Without a date the current business day is searched.
+func (s *OrderService) GetOrderByNumber(ctx context.Context, orderNumber int, date *time.Time) (orderdto.GetOrderResponse, error) {
+	// Every store numbers its orders from one, so a number means nothing without the store
+	storeID, err := access.RequireStore(ctx)
+	if err != nil {
+		return orderdto.GetOrderResponse{}, err
+	}
+
+	day := time.Now()
+	if date != nil {
+		day = *date
+	} else {
+		if day, err = s.businessDate(day); err != nil {
+			return orderdto.GetOrderResponse{}, err
+		}
+	}
+
+	orderID, err := s.orderRepo.GetOrderIDByNumber(ctx, storeID, day, orderNumber)
+	if err != nil {
+		s.logger.WarnContext(ctx, "Order not found by number", "order_number", orderNumber, "business_date", day.Format(businessDateLayout), "error", err)
+		return orderdto.GetOrderResponse{}, err
+	}
+
+	return s.GetOrderByID(ctx, orderID)
+}
+
+// loadLocation resolves a configured IANA time zone; empty means the server's local zone
+func loadLocation(name string) (*time.Location, error) {
+	if name == "" {
+		return time.Local, nil
+	}
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load location %q: %w", name, err)
+	}
+	return loc, nil
+}
@@ -4,19 +4,38 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
-	"log"
+	"log/slog"
 	"time"
 
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+
+	"frappuccino/internal/apperror"
+	"frappuccino/internal/config"
+	"frappuccino/internal/dto/list"
 	orderdto "frappuccino/internal/dto/order"
 	"frappuccino/internal/entity"
+	"frappuccino/internal/service/access"
+	"frappuccino/internal/tracing"
 )
 
+var tracer = otel.Tracer("frappuccino/internal/service/order")
+
 // OrderService handles business logic for orders
 type OrderService struct {
 	orderRepo     orderRepo
 	menuRepo      menuRepo      // New dependency for accessing menu items and ingredients
 	inventoryRepo inventoryRepo // New dependency for checking and updating inventory
-	logger        *log.Logger
+	stationRepo   stationRepo   // Routes order items to kitchen station tickets
+	tableRepo     tableRepo     // Tracks open tabs of dine-in tables
+	deliveryRepo  deliveryRepo  // Delivery zones, addresses and couriers
+	printRepo     printRepo     // Queues kitchen tickets for the station printers
+	etaCfg        config.ETA
+	scheduleCfg   config.Schedule
+	numberingCfg  config.Numbering
+	metrics       metricsRecorder // Counts created and rejected orders and stock usage
+	logger        *slog.Logger
 }
 
 // NewOrderService creates a new order service with needed dependencies
@@ -24,12 +43,28 @@ func NewOrderService(
 	orderRepo orderRepo,
 	menuRepo menuRepo,
 	inventoryRepo inventoryRepo,
-	logger *log.Logger,
+	stationRepo stationRepo,
+	tableRepo tableRepo,
+	deliveryRepo deliveryRepo,
+	printRepo printRepo,
+	etaCfg config.ETA,
+	scheduleCfg config.Schedule,
+	numberingCfg config.Numbering,
+	metrics metricsRecorder,
+	logger *slog.Logger,
 ) *OrderService {
 	return &OrderService{
 		orderRepo:     orderRepo,
 		menuRepo:      menuRepo,
 		inventoryRepo: inventoryRepo,
+		stationRepo:   stationRepo,
+		tableRepo:     tableRepo,
+		deliveryRepo:  deliveryRepo,
+		printRepo:     printRepo,
+		etaCfg:        etaCfg,
+		scheduleCfg:   scheduleCfg,
+		numberingCfg:  numberingCfg,
+		metrics:       metrics,
 		logger:        logger,
 	}
 }
@@ -44,27 +79,60 @@ type IngredientRequirement struct {
 }
 
 // CreateOrder handles the order creation with inventory validation
-func (s *OrderService) CreateOrder(ctx context.Context, req orderdto.CreateOrderRequest) (string, error) {
+func (s *OrderService) CreateOrder(ctx context.Context, req orderdto.CreateOrderRequest) (orderdto.CreateOrderResponse, error) {
+	ctx, span := tracer.Start(ctx, "OrderService.CreateOrder", trace.WithAttributes(
+		attribute.String("order.type", req.OrderType),
+		attribute.Int("order.items", len(req.Items)),
+	))
+	response, err := s.createOrder(ctx, req)
+	s.recordOrder(req.OrderType, err)
+	tracing.End(span, err)
+	return response, err
+}
+
+func (s *OrderService) createOrder(ctx context.Context, req orderdto.CreateOrderRequest) (orderdto.CreateOrderResponse, error) {
 	var items []entity.OrderItem
 	var total float64
 
+	// Orders are taken at one store, which sets their prices and the stock they use
+	storeID, err := access.RequireStore(ctx)
+	if err != nil {
+		return orderdto.CreateOrderResponse{}, err
+	}
+
+	if err := validateOrderType(&req); err != nil {
+		return orderdto.CreateOrderResponse{}, err
+	}
+	if err := validateDeliveryAddress(req); err != nil {
+		return orderdto.CreateOrderResponse{}, err
+	}
+
+	// Pre-orders must be picked up while the shop is open
+	if req.PickupAt != nil {
+		if err := s.validatePickupTime(*req.PickupAt); err != nil {
+			return orderdto.CreateOrderResponse{}, err
+		}
+	}
+
 	// Step 1: Validate ingredients availability for all items in the order
-	missingIngredients, err := s.validateIngredientsAvailability(ctx, req.Items)
+	missingIngredients, err := s.validateIngredientsAvailability(ctx, storeID, req.Items)
 	if err != nil {
-		return "", fmt.Errorf("error validating ingredients: %w", err)
+		return orderdto.CreateOrderResponse{}, fmt.Errorf("error validating ingredients: %w", err)
 	}
 
 	// If there are missing ingredients, return error with details
 	if len(missingIngredients) > 0 {
-		errorMsg := "Insufficient ingredients: "
+		shortages := make([]apperror.Shortage, len(missingIngredients))
 		for i, ing := range missingIngredients {
-			if i > 0 {
-				errorMsg += ", "
+			shortages[i] = apperror.Shortage{
+				IngredientID: ing.IngredientID,
+				Name:         ing.Name,
+				Required:     ing.Required,
+				Available:    ing.Available,
+				Unit:         ing.Unit,
 			}
-			errorMsg += fmt.Sprintf("%s (need %.2f %s, have %.2f %s)",
-				ing.Name, ing.Required, ing.Unit, ing.Available, ing.Unit)
 		}
-		return "", fmt.Errorf(errorMsg)
+		return orderdto.CreateOrderResponse{}, ErrInsufficientInventory.WithShortages(shortages...)
 	}
 
 	// Step 2: Begin transaction - ideally this would be a database transaction
@@ -72,11 +140,11 @@ func (s *OrderService) CreateOrder(ctx context.Context, req orderdto.CreateOrder
 
 	// Get prices and build order items
 	for _, dtoItem := range req.Items {
-		// Get current price from the menu_items table
-		price, err := s.orderRepo.GetMenuItemPrice(ctx, dtoItem.MenuItemID)
+		// Get the price the store sells the item for
+		price, err := s.storePrice(ctx, storeID, dtoItem.MenuItemID)
 		if err != nil {
-			s.logger.Println("Error getting price for item:", dtoItem.MenuItemID, err)
-			return "", err
+			s.logger.ErrorContext(ctx, "Error getting price for item", "menu_item_id", dtoItem.MenuItemID, "error", err)
+			return orderdto.CreateOrderResponse{}, err
 		}
 
 		// Calculate subtotal and accumulate total
@@ -99,42 +167,156 @@ func (s *OrderService) CreateOrder(ctx context.Context, req orderdto.CreateOrder
 
 	// Step 3: Build order entity
 	orderEntity := entity.Order{
+		StoreID:             storeID,
 		CustomerName:        req.CustomerName,
 		SpecialInstructions: req.SpecialInstructions,
 		TotalAmount:         total,
 		Status:              "pending",
+		OrderType:           req.OrderType,
+		TableID:             req.TableID,
+		PickupAt:            req.PickupAt,
 		CreatedAt:           time.Now(),
 		UpdatedAt:           time.Now(),
 	}
 
+	// Order numbers restart every business day; pre-orders are numbered on their pickup day
+	numberedAt := orderEntity.CreatedAt
+	if req.PickupAt != nil {
+		numberedAt = *req.PickupAt
+	}
+	if orderEntity.BusinessDate, err = s.businessDate(numberedAt); err != nil {
+		return orderdto.CreateOrderResponse{}, err
+	}
+
+	// Delivery orders pay the fee of the zone their address falls in
+	var delivery *entity.Delivery
+	if req.OrderType == "delivery" {
+		zone, err := s.quoteDelivery(ctx, *req.DeliveryAddress, total)
+		if err != nil {
+			return orderdto.CreateOrderResponse{}, err
+		}
+		orderEntity.DeliveryFee = zone.Fee
+		orderEntity.TotalAmount += zone.Fee
+		delivery = &entity.Delivery{ZoneID: zone.ZoneID, Address: toDeliveryAddress(*req.DeliveryAddress)}
+	}
+
+	// Pre-orders only reserve their ingredients and wait for the scheduler
+	if req.PickupAt != nil {
+		return s.scheduleOrder(ctx, orderEntity, items, req.Items, delivery)
+	}
+
+	// Dine-in orders become the open tab of their table, so the table must be free
+	var sessionID string
+	if req.OrderType == "dine_in" {
+		sessionID, err = s.seatTable(ctx, storeID, req)
+		if err != nil {
+			return orderdto.CreateOrderResponse{}, err
+		}
+	}
+
 	// Step 4: Insert order and items
-	orderID, err := s.orderRepo.CreateOrder(ctx, orderEntity, items)
+	orderID, orderNumber, err := s.insertOrder(ctx, orderEntity, items, sessionID, delivery)
 	if err != nil {
-		s.logger.Println("Error creating order:", err)
-		return "", err
+		s.logger.ErrorContext(ctx, "Error creating order", "error", err)
+		if sessionID != "" {
+			if delErr := s.tableRepo.DeleteSession(ctx, sessionID); delErr != nil {
+				s.logger.ErrorContext(ctx, "Error freeing table session", "session_id", sessionID, "error", delErr)
+			}
+		}
+		return orderdto.CreateOrderResponse{}, err
 	}
 
 	// Step 5: Deduct ingredients from inventory
-	err = s.deductIngredientsFromInventory(ctx, req.Items, orderID)
+	err = s.deductIngredientsFromInventory(ctx, storeID, req.Items, orderID)
 	if err != nil {
-		s.logger.Println("Error deducting ingredients:", err)
+		s.logger.ErrorContext(ctx, "Error deducting ingredients", "error", err)
 		// In a real system, we would rollback the order creation here
 		// But for simplicity, we'll just log the error and continue
-		return "", fmt.Errorf("order created but failed to update inventory: %w", err)
+		return orderdto.CreateOrderResponse{}, fmt.Errorf("order created but failed to update inventory: %w", err)
+	}
+
+	// Step 6: Split the order into station tickets and queue them for the printers
+	if err := s.stationRepo.RouteOrderItems(ctx, orderID); err != nil {
+		// The order itself is valid, so a routing failure should not reject it
+		s.logger.ErrorContext(ctx, "Error routing order items to stations", "order_id", orderID, "error", err)
+	} else if _, err := s.printRepo.EnqueueKitchenTickets(ctx, orderID); err != nil {
+		// Stations still see the order on their ticket screens; tickets can be printed again later
+		s.logger.ErrorContext(ctx, "Error queueing kitchen tickets", "order_id", orderID, "error", err)
+	}
+
+	response := orderdto.CreateOrderResponse{
+		OrderID:      orderID,
+		OrderNumber:  orderNumber,
+		BusinessDate: orderEntity.BusinessDate.Format(businessDateLayout),
+		Status:       orderEntity.Status,
+		Subtotal:     total,
+		DeliveryFee:  orderEntity.DeliveryFee,
+		TotalAmount:  orderEntity.TotalAmount,
+	}
+
+	// Step 7: Tell the customer how long the order will take
+	readyAt, err := s.estimateReadyTime(ctx, storeID, orderID)
+	if err != nil {
+		s.logger.ErrorContext(ctx, "Error estimating ready time", "order_id", orderID, "error", err)
+	} else if readyAt != nil {
+		response.EstimatedReadyAt = readyAt
+		response.EstimatedWaitSeconds = int(time.Until(*readyAt).Seconds())
 	}
 
-	return orderID, nil
+	return response, nil
+}
+
+// insertOrder stores the order and its items in one transaction, together with what its type
+// needs: a dine-in order becomes the tab of the session seatTable opened, a delivery order gets
+// its address
+func (s *OrderService) insertOrder(
+	ctx context.Context,
+	orderEntity entity.Order,
+	items []entity.OrderItem,
+	sessionID string,
+	delivery *entity.Delivery,
+) (string, int, error) {
+	tx, err := s.orderRepo.Begin(ctx)
+	if err != nil {
+		return "", 0, fmt.Errorf("error starting transaction: %w", err)
+	}
+
+	orderID, orderNumber, err := s.orderRepo.CreateOrderWithTx(ctx, tx, orderEntity, items)
+	if err != nil {
+		tx.Rollback()
+		return "", 0, err
+	}
+
+	if sessionID != "" {
+		if err := s.tableRepo.AttachSessionOrderWithTx(ctx, tx, sessionID, orderID); err != nil {
+			tx.Rollback()
+			return "", 0, err
+		}
+	}
+
+	if delivery != nil {
+		delivery.OrderID = orderID
+		if err := s.deliveryRepo.CreateDeliveryWithTx(ctx, tx, *delivery); err != nil {
+			tx.Rollback()
+			return "", 0, err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return "", 0, fmt.Errorf("error committing transaction: %w", err)
+	}
+	return orderID, orderNumber, nil
 }
 
 // validateIngredientsAvailability checks if all required ingredients are available
-func (s *OrderService) validateIngredientsAvailability(ctx context.Context, items []orderdto.CreateOrderItem) ([]IngredientRequirement, error) {
+func (s *OrderService) validateIngredientsAvailability(ctx context.Context, storeID string, items []orderdto.CreateOrderItem) ([]IngredientRequirement, error) {
 	// Create a map to aggregate required quantities of ingredients
 	requiredIngredients := make(map[string]float32)
 
 	// For each menu item in the order
 	for _, item := range items {
 		// Get ingredients required for this menu item
-		ingredients, err := s.menuRepo.GetMenuItemIngredients(ctx, item.MenuItemID)
+		ingredients, err := s.storeRecipe(ctx, storeID, item.MenuItemID)
 		if err != nil {
 			return nil, fmt.Errorf("failed to get ingredients for menu item %s: %w", item.MenuItemID, err)
 		}
@@ -147,6 +329,12 @@ func (s *OrderService) validateIngredientsAvailability(ctx context.Context, item
 		}
 	}
 
+	// Stock held for scheduled orders is not available to new ones
+	reserved, err := s.inventoryRepo.GetReservedQuantities(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get reserved quantities: %w", err)
+	}
+
 	// Check if we have enough of each ingredient
 	var missingIngredients []IngredientRequirement
 
@@ -158,12 +346,13 @@ func (s *OrderService) validateIngredientsAvailability(ctx context.Context, item
 		}
 
 		// If we don't have enough, add to missing ingredients
-		if inventory.Quantity < requiredQty {
+		available := inventory.Quantity - reserved[ingredientID]
+		if available < requiredQty {
 			missingIngredients = append(missingIngredients, IngredientRequirement{
 				IngredientID: ingredientID,
 				Name:         inventory.Name,
 				Required:     requiredQty,
-				Available:    inventory.Quantity,
+				Available:    available,
 				Unit:         inventory.Unit,
 			})
 		}
@@ -173,14 +362,14 @@ func (s *OrderService) validateIngredientsAvailability(ctx context.Context, item
 }
 
 // deductIngredientsFromInventory updates inventory after order creation
-func (s *OrderService) deductIngredientsFromInventory(ctx context.Context, items []orderdto.CreateOrderItem, orderID string) error {
+func (s *OrderService) deductIngredientsFromInventory(ctx context.Context, storeID string, items []orderdto.CreateOrderItem, orderID string) error {
 	// Create a map to aggregate required quantities of ingredients
 	requiredIngredients := make(map[string]float32)
 
 	// For each menu item in the order
 	for _, item := range items {
 		// Get ingredients required for this menu item
-		ingredients, err := s.menuRepo.GetMenuItemIngredients(ctx, item.MenuItemID)
+		ingredients, err := s.storeRecipe(ctx, storeID, item.MenuItemID)
 		if err != nil {
 			return fmt.Errorf("failed to get ingredients for menu item %s: %w", item.MenuItemID, err)
 		}
@@ -193,6 +382,12 @@ func (s *OrderService) deductIngredientsFromInventory(ctx context.Context, items
 		}
 	}
 
+	// Every update is saved on its own, so whatever was deducted is counted
+	var usage stockUsage
+	defer func() {
+		s.recordStockUsage(deductedByOrder, usage)
+	}()
+
 	// Update inventory for each ingredient
 	for ingredientID, deductQty := range requiredIngredients {
 		// Create inventory transaction record
@@ -227,12 +422,7 @@ func (s *OrderService) deductIngredientsFromInventory(ctx context.Context, items
 			return fmt.Errorf("failed to update inventory for ingredient %s: %w", ingredientID, err)
 		}
 
-		// Check if we've fallen below reorder point
-		if newQuantity <= inventory.ReorderPoint {
-			s.logger.Printf("WARNING: Ingredient %s (%s) has fallen below reorder point. Current: %.2f, Reorder at: %.2f",
-				inventory.Name, ingredientID, newQuantity, inventory.ReorderPoint)
-			// In a real system, we might trigger a notification or automated order here
-		}
+		s.useStock(ctx, &usage, inventory, newQuantity)
 	}
 
 	return nil
@@ -242,71 +432,113 @@ func (s *OrderService) deductIngredientsFromInventory(ctx context.Context, items
 // Including the other methods for completeness but without changes
 
 func (s *OrderService) GetOrderByID(ctx context.Context, id string) (orderdto.GetOrderResponse, error) {
-	orderEntity, err := s.orderRepo.GetOrderByID(ctx, id)
+	orderEntity, err := s.getOrder(ctx, id)
 	if err != nil {
-		s.logger.Println("Order not found:", err)
+		s.logger.WarnContext(ctx, "Order not found", "error", err)
 		return orderdto.GetOrderResponse{}, err
 	}
 
 	items, err := s.orderRepo.GetOrderItemsByOrderID(ctx, id)
 	if err != nil {
-		s.logger.Println("Failed to get order items:", err)
+		s.logger.ErrorContext(ctx, "Failed to get order items", "error", err)
 		return orderdto.GetOrderResponse{}, err
 	}
 
 	var responseItems []orderdto.GetOrderItemResponse
 	for _, item := range items {
 		responseItems = append(responseItems, orderdto.GetOrderItemResponse{
+			OrderItemID:    item.OrderItemID,
 			MenuItemID:     item.MenuItemID,
+			Name:           item.Name,
 			Quantity:       item.Quantity,
 			PriceAtTime:    item.PriceAtTime,
 			Customizations: item.Customizations,
 		})
 	}
 
+	// The estimate is recalculated on every read so it follows the queue
+	readyAt, err := s.estimateReadyTime(ctx, orderEntity.StoreID, id)
+	if err != nil {
+		s.logger.ErrorContext(ctx, "Error estimating ready time", "id", id, "error", err)
+		readyAt = nil
+	}
+	if orderEntity.Status == "scheduled" {
+		readyAt = orderEntity.PickupAt
+	}
+
+	var delivery *orderdto.DeliveryResponse
+	if orderEntity.OrderType == "delivery" {
+		delivery, err = s.deliveryResponse(ctx, id)
+		if err != nil {
+			s.logger.ErrorContext(ctx, "Failed to get delivery details", "id", id, "error", err)
+			return orderdto.GetOrderResponse{}, err
+		}
+	}
+
 	return orderdto.GetOrderResponse{
 		OrderID:             orderEntity.OrderID,
+		StoreID:             orderEntity.StoreID,
+		OrderNumber:         orderEntity.OrderNumber,
+		BusinessDate:        orderEntity.BusinessDate.Format(businessDateLayout),
 		CustomerName:        orderEntity.CustomerName,
 		SpecialInstructions: orderEntity.SpecialInstructions,
+		Subtotal:            orderEntity.TotalAmount - orderEntity.DeliveryFee,
+		DeliveryFee:         orderEntity.DeliveryFee,
 		TotalAmount:         orderEntity.TotalAmount,
 		Status:              orderEntity.Status,
+		OrderType:           orderEntity.OrderType,
+		TableID:             orderEntity.TableID,
+		Delivery:            delivery,
+		PickupAt:            orderEntity.PickupAt,
 		CreatedAt:           orderEntity.CreatedAt,
 		UpdatedAt:           orderEntity.UpdatedAt,
+		EstimatedReadyAt:    readyAt,
 		Items:               responseItems,
 	}, nil
 }
 
-func (s *OrderService) GetAllOrders(ctx context.Context) ([]orderdto.GetOrderResponse, error) {
-	orders, err := s.orderRepo.GetAllOrders(ctx)
+// ListOrders returns a page of the orders of the current store
+func (s *OrderService) ListOrders(ctx context.Context, q list.Query) (orderdto.ListOrdersResponse, error) {
+	orders, page, err := s.orderRepo.ListOrders(ctx, access.StoreFromContext(ctx), q)
 	if err != nil {
-		s.logger.Println("Error retrieving orders:", err)
-		return nil, err
+		s.logger.ErrorContext(ctx, "Error retrieving orders", "error", err)
+		return orderdto.ListOrdersResponse{}, err
 	}
 
-	var response []orderdto.GetOrderResponse
+	response := orderdto.ListOrdersResponse{Items: make([]orderdto.GetOrderResponse, 0, len(orders)), Page: page}
 	for _, order := range orders {
 		items, err := s.orderRepo.GetOrderItemsByOrderID(ctx, order.OrderID)
 		if err != nil {
-			s.logger.Println("Failed to get order items for order ID:", order.OrderID, err)
-			return nil, err
+			s.logger.ErrorContext(ctx, "Failed to get order items", "order_id", order.OrderID, "error", err)
+			return orderdto.ListOrdersResponse{}, err
 		}
 
 		var responseItems []orderdto.GetOrderItemResponse
 		for _, item := range items {
 			responseItems = append(responseItems, orderdto.GetOrderItemResponse{
+				OrderItemID:    item.OrderItemID,
 				MenuItemID:     item.MenuItemID,
+				Name:           item.Name,
 				Quantity:       item.Quantity,
 				PriceAtTime:    item.PriceAtTime,
 				Customizations: item.Customizations,
 			})
 		}
 
-		response = append(response, orderdto.GetOrderResponse{
+		response.Items = append(response.Items, orderdto.GetOrderResponse{
 			OrderID:             order.OrderID,
+			StoreID:             order.StoreID,
+			OrderNumber:         order.OrderNumber,
+			BusinessDate:        order.BusinessDate.Format(businessDateLayout),
 			CustomerName:        order.CustomerName,
 			SpecialInstructions: order.SpecialInstructions,
+			Subtotal:            order.TotalAmount - order.DeliveryFee,
+			DeliveryFee:         order.DeliveryFee,
 			TotalAmount:         order.TotalAmount,
 			Status:              order.Status,
+			OrderType:           order.OrderType,
+			TableID:             order.TableID,
+			PickupAt:            order.PickupAt,
 			CreatedAt:           order.CreatedAt,
 			UpdatedAt:           order.UpdatedAt,
 			Items:               responseItems,
@@ -329,16 +561,17 @@ func (s *OrderService) UpdateOrder(ctx context.Context, orderID string, req orde
 
 	if req.Status != nil {
 		validStatuses := map[string]bool{
-			"pending":   true,
-			"preparing": true,
-			"ready":     true,
-			"delivered": true,
-			"cancelled": true,
+			"pending":          true,
+			"preparing":        true,
+			"ready":            true,
+			"out_for_delivery": true,
+			"delivered":        true,
+			"cancelled":        true,
 		}
 
 		if !validStatuses[*req.Status] {
-			s.logger.Printf("Invalid order status: %s", *req.Status)
-			return fmt.Errorf("invalid order status: %s", *req.Status)
+			s.logger.WarnContext(ctx, "Invalid order status", "status", *req.Status)
+			return ErrInvalidStatus
 		}
 
 		updates["status"] = *req.Status
@@ -351,28 +584,59 @@ func (s *OrderService) UpdateOrder(ctx context.Context, orderID string, req orde
 	}
 
 	if len(updates) == 0 {
-		return fmt.Errorf("no valid fields to update")
+		return ErrNoChanges
+	}
+
+	if _, err := s.getOrder(ctx, orderID); err != nil {
+		return err
+	}
+
+	if req.Status != nil {
+		applied, err := s.applyScheduledTransition(ctx, orderID, *req.Status, updates["change_reason"].(string))
+		if err != nil {
+			s.logger.ErrorContext(ctx, "Error updating scheduled order", "error", err)
+			return err
+		}
+		if applied {
+			delete(updates, "status")
+			delete(updates, "change_reason")
+			if len(updates) == 0 {
+				return nil
+			}
+		} else if err := s.checkDeliveryTransition(ctx, orderID, *req.Status); err != nil {
+			s.logger.ErrorContext(ctx, "Error updating delivery order", "error", err)
+			return err
+		}
 	}
 
 	err := s.orderRepo.UpdateOrder(ctx, orderID, updates)
 	if err != nil {
-		s.logger.Printf("Error updating order: %v", err)
+		s.logger.ErrorContext(ctx, "Error updating order", "error", err)
 		return err
 	}
 
+	if status, ok := updates["status"].(string); ok {
+		// A settled or cancelled tab frees its table
+		if status == "delivered" || status == "cancelled" {
+			s.closeTab(ctx, orderID)
+		}
+		s.recordDeliveryProgress(ctx, orderID, status)
+	}
+
 	return nil
 }
 
-func (s *OrderService) GetAllOrderStatusHistory(ctx context.Context) ([]orderdto.OrderStatusHistoryResponse, error) {
-	history, err := s.orderRepo.GetAllOrderStatusHistory(ctx)
+// ListOrderStatusHistory returns a page of the status changes of the orders of the current store
+func (s *OrderService) ListOrderStatusHistory(ctx context.Context, q list.Query) (orderdto.ListOrderStatusHistoryResponse, error) {
+	history, page, err := s.orderRepo.ListOrderStatusHistory(ctx, access.StoreFromContext(ctx), q)
 	if err != nil {
-		s.logger.Printf("Error getting all order status history: %v", err)
-		return nil, err
+		s.logger.ErrorContext(ctx, "Error getting all order status history", "error", err)
+		return orderdto.ListOrderStatusHistoryResponse{}, err
 	}
 
-	var response []orderdto.OrderStatusHistoryResponse
+	response := orderdto.ListOrderStatusHistoryResponse{Items: make([]orderdto.OrderStatusHistoryResponse, 0, len(history)), Page: page}
 	for _, h := range history {
-		response = append(response, orderdto.OrderStatusHistoryResponse{
+		response.Items = append(response.Items, orderdto.OrderStatusHistoryResponse{
 			OrderStatusID: h.OrderStatusID,
 			OrderID:       h.OrderID,
 			OldStatus:     h.OldStatus,
@@ -386,9 +650,13 @@ func (s *OrderService) GetAllOrderStatusHistory(ctx context.Context) ([]orderdto
 }
 
 func (s *OrderService) DeleteOrder(ctx context.Context, id string) (string, error) {
+	if _, err := s.getOrder(ctx, id); err != nil {
+		return "", err
+	}
+
 	order_id, err := s.orderRepo.DeleteOrder(ctx, id)
 	if err != nil {
-		s.logger.Println(err)
+		s.logger.ErrorContext(ctx, "Error deleting order", "order_id", id, "error", err)
 		return "", err
 	}
 	return order_id, nil
@@ -405,27 +673,47 @@ func (s *OrderService) CloseOrder(ctx context.Context, orderID string, reason st
 		updates["change_reason"] = "Order completed and delivered"
 	}
 
+	if _, err := s.getOrder(ctx, orderID); err != nil {
+		return err
+	}
+
+	// Scheduled orders have not been made yet, so they cannot be handed over
+	if _, err := s.applyScheduledTransition(ctx, orderID, "delivered", ""); err != nil {
+		s.logger.ErrorContext(ctx, "Error closing order", "error", err)
+		return err
+	}
+
+	// Deliveries are closed by their courier once they are out for delivery
+	if err := s.checkDeliveryTransition(ctx, orderID, "delivered"); err != nil {
+		s.logger.ErrorContext(ctx, "Error closing order", "error", err)
+		return err
+	}
+
 	err := s.orderRepo.UpdateOrder(ctx, orderID, updates)
 	if err != nil {
-		s.logger.Printf("Error closing order: %v", err)
+		s.logger.ErrorContext(ctx, "Error closing order", "error", err)
 		return err
 	}
 
+	s.closeTab(ctx, orderID)
+	s.recordDeliveryProgress(ctx, orderID, "delivered")
+
 	return nil
 }
 
 func (s *OrderService) GetNumberOfOrderedItems(ctx context.Context, startDate, endDate *time.Time) (map[string]int, error) {
 	// Call the repository to get the data
-	itemCounts, err := s.orderRepo.GetNumberOfOrderedItems(ctx, startDate, endDate)
+	storeID := access.StoreFromContext(ctx)
+	itemCounts, err := s.orderRepo.GetNumberOfOrderedItems(ctx, storeID, startDate, endDate)
 	if err != nil {
-		s.logger.Printf("Error getting number of ordered items: %v", err)
+		s.logger.ErrorContext(ctx, "Error getting number of ordered items", "error", err)
 		return nil, err
 	}
 
 	// Get the list of all menu items to ensure all items are represented in the response
-	menuItems, err := s.menuRepo.GetMenuItem(ctx)
+	menuItems, err := s.menuRepo.GetMenuItem(ctx, storeID)
 	if err != nil {
-		s.logger.Printf("Error getting menu items: %v", err)
+		s.logger.ErrorContext(ctx, "Error getting menu items", "error", err)
 		// Not returning an error here, as we still have the order counts
 		// We'll just skip adding the zero counts for menu items that weren't ordered
 	} else {
@@ -0,0 +1,292 @@
+package order
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+
+	"frappuccino/internal/apperror"
+	orderdto "frappuccino/internal/dto/order"
+	"frappuccino/internal/entity"
+	"frappuccino/internal/tracing"
+)
+
+var (
+	ErrInvalidPickupTime = apperror.InvalidField("invalid_pickup_time", "pickup_at", "invalid pickup time")
+	ErrScheduledOrder    = apperror.New(apperror.Conflict, "order_scheduled", "scheduled orders can only be cancelled until they are released")
+)
+
+// defaultSchedulePollInterval is used when the configuration does not set a poll interval
+const defaultSchedulePollInterval = time.Minute
+
+// validatePickupTime checks that a requested pickup time is in the future and within opening hours
+func (s *OrderService) validatePickupTime(pickupAt time.Time) error {
+	if !pickupAt.After(time.Now()) {
+		return ErrInvalidPickupTime.WithDetail("pickup time must be in the future")
+	}
+
+	loc, err := loadLocation(s.scheduleCfg.Location)
+	if err != nil {
+		return err
+	}
+
+	opening, err := time.Parse("15:04", s.scheduleCfg.OpeningTime)
+	if err != nil {
+		return fmt.Errorf("failed to parse opening time: %w", err)
+	}
+	closing, err := time.Parse("15:04", s.scheduleCfg.ClosingTime)
+	if err != nil {
+		return fmt.Errorf("failed to parse closing time: %w", err)
+	}
+
+	local := pickupAt.In(loc)
+	minute := local.Hour()*60 + local.Minute()
+	if minute < opening.Hour()*60+opening.Minute() || minute > closing.Hour()*60+closing.Minute() {
+		return ErrInvalidPickupTime.WithDetail(fmt.Sprintf("pickup must be between %s and %s",
+			s.scheduleCfg.OpeningTime, s.scheduleCfg.ClosingTime))
+	}
+
+	return nil
+}
+
+// scheduleOrder stores a pre-order in the scheduled state and reserves its ingredients
+// without consuming them; the scheduler deducts them when the order is released
+func (s *OrderService) scheduleOrder(
+	ctx context.Context,
+	orderEntity entity.Order,
+	items []entity.OrderItem,
+	reqItems []orderdto.CreateOrderItem,
+	delivery *entity.Delivery,
+) (orderdto.CreateOrderResponse, error) {
+	requirements, err := s.calculateIngredientsNeeded(ctx, orderEntity.StoreID, reqItems)
+	if err != nil {
+		return orderdto.CreateOrderResponse{}, err
+	}
+	quantities := make(map[string]float32, len(requirements))
+	for id, requirement := range requirements {
+		quantities[id] = requirement.Required
+	}
+
+	tx, err := s.orderRepo.Begin(ctx)
+	if err != nil {
+		return orderdto.CreateOrderResponse{}, fmt.Errorf("error starting transaction: %w", err)
+	}
+
+	orderEntity.Status = "scheduled"
+	orderID, orderNumber, err := s.orderRepo.CreateOrderWithTx(ctx, tx, orderEntity, items)
+	if err != nil {
+		tx.Rollback()
+		return orderdto.CreateOrderResponse{}, fmt.Errorf("error creating order: %w", err)
+	}
+
+	if err := s.inventoryRepo.CreateReservationsWithTx(ctx, tx, orderID, quantities); err != nil {
+		tx.Rollback()
+		return orderdto.CreateOrderResponse{}, fmt.Errorf("error reserving ingredients: %w", err)
+	}
+
+	if delivery != nil {
+		delivery.OrderID = orderID
+		if err := s.deliveryRepo.CreateDeliveryWithTx(ctx, tx, *delivery); err != nil {
+			tx.Rollback()
+			return orderdto.CreateOrderResponse{}, err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return orderdto.CreateOrderResponse{}, fmt.Errorf("error committing transaction: %w", err)
+	}
+
+	return orderdto.CreateOrderResponse{
+		OrderID:          orderID,
+		OrderNumber:      orderNumber,
+		BusinessDate:     orderEntity.BusinessDate.Format(businessDateLayout),
+		Status:           orderEntity.Status,
+		PickupAt:         orderEntity.PickupAt,
+		Subtotal:         orderEntity.TotalAmount - orderEntity.DeliveryFee,
+		DeliveryFee:      orderEntity.DeliveryFee,
+		TotalAmount:      orderEntity.TotalAmount,
+		EstimatedReadyAt: orderEntity.PickupAt,
+	}, nil
+}
+
+// RunScheduler releases due scheduled orders to the kitchen until the context is cancelled
+func (s *OrderService) RunScheduler(ctx context.Context) {
+	interval := s.scheduleCfg.PollInterval
+	if interval <= 0 {
+		interval = defaultSchedulePollInterval
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if released, err := s.ReleaseDueOrders(ctx); err != nil {
+			s.logger.ErrorContext(ctx, "Error releasing scheduled orders", "error", err)
+		} else if released > 0 {
+			s.logger.InfoContext(ctx, "Released scheduled orders to the kitchen", "count", released)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// ReleaseDueOrders moves every scheduled order whose pickup is within the lead time into pending
+func (s *OrderService) ReleaseDueOrders(ctx context.Context) (int, error) {
+	orderIDs, err := s.orderRepo.GetDueScheduledOrders(ctx, time.Now().Add(s.scheduleCfg.LeadTime))
+	if err != nil {
+		return 0, err
+	}
+
+	// Once ctx is cancelled no further order is started, but the one being released is finished
+	// rather than rolled back halfway
+	work := context.WithoutCancel(ctx)
+	released := 0
+	for _, orderID := range orderIDs {
+		if ctx.Err() != nil {
+			break
+		}
+		ok, err := s.releaseScheduledOrder(work, orderID)
+		if err != nil {
+			// Keep going so one broken order does not hold back the rest
+			s.logger.ErrorContext(ctx, "Error releasing scheduled order", "order_id", orderID, "error", err)
+			continue
+		}
+		if ok {
+			released++
+		}
+	}
+
+	return released, nil
+}
+
+// releaseScheduledOrder turns the reservations of an order into inventory deductions,
+// moves it to pending and routes it to the stations, all in one transaction
+func (s *OrderService) releaseScheduledOrder(ctx context.Context, orderID string) (released bool, err error) {
+	ctx, span := tracer.Start(ctx, "OrderService.releaseScheduledOrder",
+		trace.WithAttributes(attribute.String("order.id", orderID)))
+	defer func() { tracing.End(span, err) }()
+
+	tx, err := s.orderRepo.Begin(ctx)
+	if err != nil {
+		return false, fmt.Errorf("error starting transaction: %w", err)
+	}
+	defer func() {
+		if err != nil || !released {
+			if rbErr := tx.Rollback(); rbErr != nil {
+				s.logger.ErrorContext(ctx, "Error rolling back transaction", "error", rbErr)
+			}
+		}
+	}()
+
+	// The conditional update locks the order, so a concurrent cancel or release loses the race cleanly
+	released, err = s.orderRepo.TransitionOrderStatusWithTx(ctx, tx, orderID, "scheduled", "pending",
+		"Released for scheduled pickup")
+	if err != nil || !released {
+		return false, err
+	}
+
+	reserved, err := s.inventoryRepo.ReleaseReservationsWithTx(ctx, tx, orderID)
+	if err != nil {
+		return false, err
+	}
+
+	var usage stockUsage
+	for ingredientID, quantity := range reserved {
+		inventory, err := s.inventoryRepo.GetInventoryByIDWithTx(ctx, tx, ingredientID)
+		if err != nil {
+			return false, fmt.Errorf("failed to get inventory for ingredient %s: %w", ingredientID, err)
+		}
+
+		err = s.inventoryRepo.CreateInventoryTransactionWithTx(ctx, tx, entity.InventoryTransaction{
+			IngredientID:    ingredientID,
+			QuantityChange:  quantity,
+			TransactionType: "deduction",
+			Reason:          fmt.Sprintf("Order %s", orderID),
+		})
+		if err != nil {
+			return false, fmt.Errorf("failed to record transaction for ingredient %s: %w", ingredientID, err)
+		}
+
+		// The stock was promised when the order was placed, so it is deducted even if
+		// manual adjustments have since left too little on hand
+		newQuantity := inventory.Quantity - quantity
+		updates := map[string]interface{}{
+			"quantity":     newQuantity,
+			"last_updated": time.Now(),
+		}
+		if err := s.inventoryRepo.UpdateInventoryWithTx(ctx, tx, updates, ingredientID); err != nil {
+			return false, fmt.Errorf("failed to update inventory for ingredient %s: %w", ingredientID, err)
+		}
+
+		s.useStock(ctx, &usage, inventory, newQuantity)
+	}
+
+	if err = s.stationRepo.RouteOrderItemsWithTx(ctx, tx, orderID); err != nil {
+		return false, fmt.Errorf("error routing order to stations: %w", err)
+	}
+	if _, err = s.printRepo.EnqueueKitchenTicketsWithTx(ctx, tx, orderID); err != nil {
+		return false, fmt.Errorf("error queueing kitchen tickets: %w", err)
+	}
+
+	if err = tx.Commit(); err != nil {
+		return false, fmt.Errorf("error committing transaction: %w", err)
+	}
+	s.recordStockUsage(deductedByScheduler, usage)
+
+	return true, nil
+}
+
+// applyScheduledTransition handles status changes of orders that are still scheduled: they may
+// only be cancelled, which also frees their reservations. It reports whether the change was
+// applied here, in which case the caller must not write the status again.
+func (s *OrderService) applyScheduledTransition(ctx context.Context, orderID, newStatus, reason string) (bool, error) {
+	order, err := s.orderRepo.GetOrderByID(ctx, orderID)
+	if err != nil {
+		return false, err
+	}
+	if order.Status != "scheduled" {
+		return false, nil
+	}
+	if newStatus != "cancelled" {
+		return false, ErrScheduledOrder
+	}
+	return s.cancelScheduledOrder(ctx, orderID, reason)
+}
+
+// cancelScheduledOrder cancels an order that has not been released yet and frees its reservations.
+// It reports false when the scheduler released the order first.
+func (s *OrderService) cancelScheduledOrder(ctx context.Context, orderID, reason string) (cancelled bool, err error) {
+	tx, err := s.orderRepo.Begin(ctx)
+	if err != nil {
+		return false, fmt.Errorf("error starting transaction: %w", err)
+	}
+	defer func() {
+		if err != nil || !cancelled {
+			if rbErr := tx.Rollback(); rbErr != nil {
+				s.logger.ErrorContext(ctx, "Error rolling back transaction", "error", rbErr)
+			}
+		}
+	}()
+
+	cancelled, err = s.orderRepo.TransitionOrderStatusWithTx(ctx, tx, orderID, "scheduled", "cancelled", reason)
+	if err != nil || !cancelled {
+		return false, err
+	}
+
+	if _, err = s.inventoryRepo.ReleaseReservationsWithTx(ctx, tx, orderID); err != nil {
+		return false, err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return false, fmt.Errorf("error committing transaction: %w", err)
+	}
+
+	return true, nil
+}
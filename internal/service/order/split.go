@@ -0,0 +1,437 @@
+package order
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"frappuccino/internal/apperror"
+	orderdto "frappuccino/internal/dto/order"
+	"frappuccino/internal/entity"
+	"frappuccino/internal/repository/postgres"
+	"frappuccino/internal/service/access"
+)
+
+var (
+	ErrInvalidSplit     = apperror.New(apperror.Invalid, "invalid_split", "invalid split")
+	ErrInvalidMerge     = apperror.New(apperror.Invalid, "invalid_merge", "invalid merge")
+	ErrOrderNotSplit    = apperror.New(apperror.Conflict, "order_not_splittable", "only ready or delivered orders can be split")
+	ErrOrderNotMerged   = apperror.New(apperror.Conflict, "order_not_mergeable", "only pending, preparing or ready orders can be merged")
+	ErrCustomerMismatch = apperror.New(apperror.Conflict, "customer_mismatch", "only orders of the same customer can be merged")
+	ErrOrderChanged     = apperror.New(apperror.Conflict, "order_changed", "order changed status during the operation")
+)
+
+// splittableStatuses are the states in which the kitchen is done with an order, so splitting the
+// bill does not have to move station tickets around
+var splittableStatuses = map[string]bool{
+	"ready":     true,
+	"delivered": true,
+}
+
+// mergeableStatusRank orders the states an order can be merged in; the merged order takes the
+// least advanced one so that no kitchen work is skipped
+var mergeableStatusRank = map[string]int{
+	"pending":   0,
+	"preparing": 1,
+	"ready":     2,
+}
+
+// SplitOrder replaces an order with several new ones, either by distributing its line items
+// or by dividing every line evenly. The new orders copy the lines at their original prices, so
+// inventory stays deducted exactly once; the original order is cancelled.
+func (s *OrderService) SplitOrder(ctx context.Context, orderID string, req orderdto.SplitOrderRequest) (orderdto.SplitOrderResponse, error) {
+	tx, err := s.orderRepo.Begin(ctx)
+	if err != nil {
+		return orderdto.SplitOrderResponse{}, fmt.Errorf("error starting transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	source, err := s.orderRepo.LockOrderWithTx(ctx, tx, orderID)
+	if err != nil {
+		return orderdto.SplitOrderResponse{}, err
+	}
+	if !access.InStore(ctx, source.StoreID) {
+		return orderdto.SplitOrderResponse{}, sql.ErrNoRows
+	}
+	if !splittableStatuses[source.Status] {
+		return orderdto.SplitOrderResponse{}, ErrOrderNotSplit
+	}
+	// A delivery has one address and one fee, so it is settled as a whole
+	if source.OrderType == "delivery" {
+		return orderdto.SplitOrderResponse{}, ErrInvalidSplit.WithDetail("delivery orders cannot be split")
+	}
+
+	items, err := s.orderRepo.GetOrderItemsByOrderID(ctx, orderID)
+	if err != nil {
+		return orderdto.SplitOrderResponse{}, err
+	}
+
+	var parts [][]entity.OrderItem
+	var customers []string
+	switch req.Mode {
+	case "items":
+		parts, customers, err = splitByItems(items, req.Groups)
+	case "even":
+		parts, err = splitEvenly(items, req.Parts)
+		customers = make([]string, len(parts))
+	default:
+		err = ErrInvalidSplit.WithDetail("mode must be 'items' or 'even'")
+	}
+	if err != nil {
+		return orderdto.SplitOrderResponse{}, err
+	}
+
+	reason := req.Reason
+	if reason == "" {
+		reason = fmt.Sprintf("Split from order %s", orderID)
+	}
+
+	var newOrderIDs []string
+	for i, partItems := range parts {
+		customer := customers[i]
+		if customer == "" {
+			customer = source.CustomerName
+		}
+
+		newOrderID, err := s.createDerivedOrder(ctx, tx, source, customer, partItems, reason)
+		if err != nil {
+			return orderdto.SplitOrderResponse{}, err
+		}
+		newOrderIDs = append(newOrderIDs, newOrderID)
+
+		if err := s.recordDerivation(ctx, tx, newOrderID, "split_from", []string{orderID}, 0, lineTotal(partItems), reason); err != nil {
+			return orderdto.SplitOrderResponse{}, err
+		}
+	}
+
+	// An open tab stays open on the first part; the other parts are settled separately
+	if err := s.tableRepo.MoveOpenSessionWithTx(ctx, tx, orderID, newOrderIDs[0]); err != nil {
+		return orderdto.SplitOrderResponse{}, err
+	}
+
+	if err := s.retireOrder(ctx, tx, source, "split_into", newOrderIDs, fmt.Sprintf("Split into %d orders", len(newOrderIDs))); err != nil {
+		return orderdto.SplitOrderResponse{}, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return orderdto.SplitOrderResponse{}, fmt.Errorf("error committing transaction: %w", err)
+	}
+
+	response := orderdto.SplitOrderResponse{SourceOrderID: orderID}
+	for _, id := range newOrderIDs {
+		newOrder, err := s.GetOrderByID(ctx, id)
+		if err != nil {
+			return orderdto.SplitOrderResponse{}, err
+		}
+		response.Orders = append(response.Orders, newOrder)
+	}
+
+	return response, nil
+}
+
+// MergeOrders combines orders of the same customer into a new order. The line items and station
+// tickets move to the new order unchanged, so inventory and kitchen progress are preserved;
+// the merged orders are cancelled.
+func (s *OrderService) MergeOrders(ctx context.Context, req orderdto.MergeOrdersRequest) (orderdto.GetOrderResponse, error) {
+	orderIDs := uniqueStrings(req.OrderIDs)
+	if len(orderIDs) < 2 {
+		return orderdto.GetOrderResponse{}, ErrInvalidMerge.WithDetail("at least two different orders are required")
+	}
+
+	tx, err := s.orderRepo.Begin(ctx)
+	if err != nil {
+		return orderdto.GetOrderResponse{}, fmt.Errorf("error starting transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	// Lock in a fixed order so concurrent merges cannot deadlock
+	sort.Strings(orderIDs)
+	var sources []entity.Order
+	for _, id := range orderIDs {
+		order, err := s.orderRepo.LockOrderWithTx(ctx, tx, id)
+		if err != nil {
+			return orderdto.GetOrderResponse{}, err
+		}
+		if !access.InStore(ctx, order.StoreID) {
+			return orderdto.GetOrderResponse{}, sql.ErrNoRows
+		}
+		if len(sources) > 0 && order.StoreID != sources[0].StoreID {
+			return orderdto.GetOrderResponse{}, ErrInvalidMerge.WithDetail("orders must belong to the same store")
+		}
+		if _, ok := mergeableStatusRank[order.Status]; !ok {
+			return orderdto.GetOrderResponse{}, ErrOrderNotMerged
+		}
+		if order.OrderType == "delivery" {
+			return orderdto.GetOrderResponse{}, ErrInvalidMerge.WithDetail("delivery orders cannot be merged")
+		}
+		if len(sources) > 0 && !strings.EqualFold(strings.TrimSpace(order.CustomerName), strings.TrimSpace(sources[0].CustomerName)) {
+			return orderdto.GetOrderResponse{}, ErrCustomerMismatch
+		}
+		if len(sources) > 0 && (order.OrderType != sources[0].OrderType || !sameTable(order.TableID, sources[0].TableID)) {
+			return orderdto.GetOrderResponse{}, ErrInvalidMerge.WithDetail("orders must have the same order type and table")
+		}
+		sources = append(sources, order)
+	}
+
+	merged := sources[0]
+	var instructions []json.RawMessage
+	for _, order := range sources {
+		if mergeableStatusRank[order.Status] < mergeableStatusRank[merged.Status] {
+			merged.Status = order.Status
+		}
+		if len(order.SpecialInstructions) > 0 && string(order.SpecialInstructions) != "{}" {
+			instructions = append(instructions, order.SpecialInstructions)
+		}
+	}
+	merged.SpecialInstructions = mergeInstructions(instructions)
+
+	reason := req.Reason
+	if reason == "" {
+		reason = fmt.Sprintf("Merged from orders %s", strings.Join(orderIDs, ", "))
+	}
+
+	mergedID, err := s.createDerivedOrder(ctx, tx, merged, merged.CustomerName, nil, reason)
+	if err != nil {
+		return orderdto.GetOrderResponse{}, err
+	}
+
+	if err := s.orderRepo.MoveOrderItemsWithTx(ctx, tx, orderIDs, mergedID); err != nil {
+		return orderdto.GetOrderResponse{}, err
+	}
+
+	total, err := s.orderRepo.RecalculateOrderTotalWithTx(ctx, tx, mergedID)
+	if err != nil {
+		return orderdto.GetOrderResponse{}, err
+	}
+
+	if err := s.recordDerivation(ctx, tx, mergedID, "merged_from", orderIDs, 0, total, reason); err != nil {
+		return orderdto.GetOrderResponse{}, err
+	}
+
+	for _, order := range sources {
+		if err := s.tableRepo.MoveOpenSessionWithTx(ctx, tx, order.OrderID, mergedID); err != nil {
+			return orderdto.GetOrderResponse{}, err
+		}
+		if err := s.retireOrder(ctx, tx, order, "merged_into", []string{mergedID}, fmt.Sprintf("Merged into order %s", mergedID)); err != nil {
+			return orderdto.GetOrderResponse{}, err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return orderdto.GetOrderResponse{}, fmt.Errorf("error committing transaction: %w", err)
+	}
+
+	return s.GetOrderByID(ctx, mergedID)
+}
+
+// splitByItems builds one part per group. Every unit of every line must end up in exactly one group.
+func splitByItems(items []entity.OrderItem, groups []orderdto.SplitGroup) ([][]entity.OrderItem, []string, error) {
+	if len(groups) < 2 {
+		return nil, nil, ErrInvalidSplit.WithDetail("at least two groups are required")
+	}
+
+	byID := make(map[string]entity.OrderItem, len(items))
+	for _, item := range items {
+		byID[item.OrderItemID] = item
+	}
+
+	allocated := make(map[string]int, len(items))
+	parts := make([][]entity.OrderItem, 0, len(groups))
+	customers := make([]string, 0, len(groups))
+	for i, group := range groups {
+		if len(group.Items) == 0 {
+			return nil, nil, ErrInvalidSplit.WithDetail(fmt.Sprintf("group %d has no items", i+1))
+		}
+
+		var part []entity.OrderItem
+		for _, groupItem := range group.Items {
+			item, ok := byID[groupItem.OrderItemID]
+			if !ok {
+				return nil, nil, ErrInvalidSplit.WithDetail(fmt.Sprintf("item %s is not part of the order", groupItem.OrderItemID))
+			}
+
+			quantity := groupItem.Quantity
+			if quantity == 0 {
+				quantity = item.Quantity
+			}
+			if quantity < 0 {
+				return nil, nil, ErrInvalidSplit.WithDetail(ErrInvalidQuantity.Error())
+			}
+			allocated[item.OrderItemID] += quantity
+
+			item.OrderItemID = ""
+			item.Quantity = quantity
+			part = append(part, item)
+		}
+
+		parts = append(parts, part)
+		customers = append(customers, group.CustomerName)
+	}
+
+	for _, item := range items {
+		if allocated[item.OrderItemID] != item.Quantity {
+			return nil, nil, ErrInvalidSplit.WithDetail(fmt.Sprintf("item %s has %d units but %d were allocated",
+				item.OrderItemID, item.Quantity, allocated[item.OrderItemID]))
+		}
+	}
+
+	return parts, customers, nil
+}
+
+// splitEvenly gives every part all lines at an equal share of their price. Shares are rounded
+// down to the cent and the remainder goes to the first part, so the parts add up to the original.
+func splitEvenly(items []entity.OrderItem, n int) ([][]entity.OrderItem, error) {
+	if n < 2 {
+		return nil, ErrInvalidSplit.WithDetail("parts must be at least 2")
+	}
+
+	parts := make([][]entity.OrderItem, n)
+	for _, item := range items {
+		cents := int64(math.Round(item.PriceAtTime * 100))
+		share := cents / int64(n)
+		first := cents - share*int64(n-1)
+
+		for i := range parts {
+			line := item
+			line.OrderItemID = ""
+			line.PriceAtTime = float64(share) / 100
+			if i == 0 {
+				line.PriceAtTime = float64(first) / 100
+			}
+			parts[i] = append(parts[i], line)
+		}
+	}
+
+	return parts, nil
+}
+
+// createDerivedOrder inserts an order produced by a split or merge. It starts in the state of the
+// order it came from, which is recorded in its status history.
+func (s *OrderService) createDerivedOrder(
+	ctx context.Context,
+	tx *postgres.Transaction,
+	from entity.Order,
+	customerName string,
+	items []entity.OrderItem,
+	reason string,
+) (string, error) {
+	specialInstructions := from.SpecialInstructions
+	if len(specialInstructions) == 0 {
+		specialInstructions = json.RawMessage(`{}`)
+	}
+
+	now := time.Now()
+	businessDate, err := s.businessDate(now)
+	if err != nil {
+		return "", err
+	}
+
+	// Derived orders get their own number, so each split part can be called out separately
+	orderID, _, err := s.orderRepo.CreateOrderWithTx(ctx, tx, entity.Order{
+		StoreID:             from.StoreID,
+		CustomerName:        customerName,
+		SpecialInstructions: specialInstructions,
+		TotalAmount:         lineTotal(items),
+		Status:              from.Status,
+		OrderType:           from.OrderType,
+		TableID:             from.TableID,
+		BusinessDate:        businessDate,
+		CreatedAt:           now,
+		UpdatedAt:           now,
+	}, items)
+	if err != nil {
+		return "", fmt.Errorf("error creating order: %w", err)
+	}
+
+	if err := s.orderRepo.CreateOrderStatusHistoryWithTx(ctx, tx, orderID, from.Status, from.Status, reason); err != nil {
+		return "", err
+	}
+
+	return orderID, nil
+}
+
+// retireOrder cancels an order that was split or merged and points its audit trail at the new orders
+func (s *OrderService) retireOrder(ctx context.Context, tx *postgres.Transaction, order entity.Order, action string, newOrderIDs []string, reason string) error {
+	cancelled, err := s.orderRepo.TransitionOrderStatusWithTx(ctx, tx, order.OrderID, order.Status, "cancelled", reason)
+	if err != nil {
+		return err
+	}
+	if !cancelled {
+		return fmt.Errorf("order %s: %w", order.OrderID, ErrOrderChanged)
+	}
+
+	return s.recordDerivation(ctx, tx, order.OrderID, action, newOrderIDs, order.TotalAmount, order.TotalAmount, reason)
+}
+
+// recordDerivation writes an audit entry linking an order to the orders it was split or merged with
+func (s *OrderService) recordDerivation(
+	ctx context.Context,
+	tx *postgres.Transaction,
+	orderID, action string,
+	relatedOrderIDs []string,
+	totalBefore, totalAfter float64,
+	reason string,
+) error {
+	value, err := json.Marshal(map[string][]string{"order_ids": relatedOrderIDs})
+	if err != nil {
+		return err
+	}
+
+	return s.orderRepo.CreateOrderAuditWithTx(ctx, tx, entity.OrderAuditEntry{
+		OrderID:     orderID,
+		Action:      action,
+		NewValue:    value,
+		TotalBefore: totalBefore,
+		TotalAfter:  totalAfter,
+		Reason:      reason,
+	})
+}
+
+// lineTotal sums quantity times price over the given lines
+func lineTotal(items []entity.OrderItem) float64 {
+	var total float64
+	for _, item := range items {
+		total += item.PriceAtTime * float64(item.Quantity)
+	}
+	return math.Round(total*100) / 100
+}
+
+// mergeInstructions keeps a single set of special instructions as is and wraps several into a list
+func mergeInstructions(instructions []json.RawMessage) json.RawMessage {
+	switch len(instructions) {
+	case 0:
+		return json.RawMessage(`{}`)
+	case 1:
+		return instructions[0]
+	}
+	merged, err := json.Marshal(map[string][]json.RawMessage{"merged": instructions})
+	if err != nil {
+		return json.RawMessage(`{}`)
+	}
+	return merged
+}
+
+func uniqueStrings(values []string) []string {
+	seen := make(map[string]bool, len(values))
+	var unique []string
+	for _, v := range values {
+		if v != "" && !seen[v] {
+			seen[v] = true
+			unique = append(unique, v)
+		}
+	}
+	return unique
+}
+
+// sameTable reports whether two orders are seated at the same table, or both at none
+func sameTable(a, b *string) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
@@ -0,0 +1,53 @@
+package order
+
+import (
+	"context"
+	"database/sql"
+
+	"frappuccino/internal/apperror"
+	"frappuccino/internal/entity"
+	"frappuccino/internal/service/access"
+)
+
+var (
+	ErrNotStocked      = apperror.New(apperror.Unprocessable, "ingredient_not_stocked", "ingredient is not stocked at this store")
+	ErrItemUnavailable = apperror.New(apperror.Unprocessable, "menu_item_unavailable", "menu item is not available at this store")
+)
+
+// getOrder reads an order, treating orders of another store as missing
+func (s *OrderService) getOrder(ctx context.Context, orderID string) (entity.Order, error) {
+	order, err := s.orderRepo.GetOrderByID(ctx, orderID)
+	if err != nil {
+		return entity.Order{}, err
+	}
+	if !access.InStore(ctx, order.StoreID) {
+		return entity.Order{}, sql.ErrNoRows
+	}
+	return order, nil
+}
+
+// storePrice returns what a menu item sells for at a store, refusing items the store took off its menu
+func (s *OrderService) storePrice(ctx context.Context, storeID, menuItemID string) (float64, error) {
+	price, available, err := s.orderRepo.GetMenuItemPrice(ctx, storeID, menuItemID)
+	if err != nil {
+		return 0, err
+	}
+	if !available {
+		return 0, ErrItemUnavailable.WithDetail(menuItemID)
+	}
+	return price, nil
+}
+
+// storeRecipe returns the ingredients of a menu item with the inventory rows of a store
+func (s *OrderService) storeRecipe(ctx context.Context, storeID, menuItemID string) ([]entity.MenuItemIngredient, error) {
+	ingredients, err := s.menuRepo.GetMenuItemIngredients(ctx, storeID, menuItemID)
+	if err != nil {
+		return nil, err
+	}
+	for _, ing := range ingredients {
+		if ing.IngredientID == "" {
+			return nil, ErrNotStocked.WithDetail(ing.Name)
+		}
+	}
+	return ingredients, nil
+}
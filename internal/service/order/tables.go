@@ -0,0 +1,85 @@
+package order
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"frappuccino/internal/apperror"
+	orderdto "frappuccino/internal/dto/order"
+)
+
+var (
+	ErrInvalidOrderType = apperror.InvalidField("invalid_order_type", "order_type", "order type must be takeaway, dine_in or delivery")
+	ErrTableRequired    = apperror.InvalidField("table_required", "table_id", "dine_in orders need a table_id, and only dine_in orders may have one")
+	ErrTableNotFound    = apperror.New(apperror.NotFound, "table_not_found", "table not found")
+	ErrTableOccupied    = apperror.New(apperror.Conflict, "table_occupied", "table already has an open tab")
+	ErrTableCapacity    = apperror.InvalidField("table_capacity_exceeded", "guests", "party is larger than the table")
+)
+
+// validateOrderType fills in the default order type and checks that a table is given exactly for dine-in orders
+func validateOrderType(req *orderdto.CreateOrderRequest) error {
+	if req.OrderType == "" {
+		req.OrderType = "takeaway"
+	}
+
+	switch req.OrderType {
+	case "takeaway", "delivery":
+		if req.TableID != nil {
+			return ErrTableRequired
+		}
+	case "dine_in":
+		if req.TableID == nil || *req.TableID == "" {
+			return ErrTableRequired
+		}
+		if req.PickupAt != nil {
+			return ErrInvalidPickupTime.WithDetail("dine_in orders cannot be scheduled")
+		}
+	default:
+		return ErrInvalidOrderType
+	}
+
+	return nil
+}
+
+// seatTable opens a session on a table of the store for a dine-in order. insertOrder attaches the
+// tab order along with inserting it.
+func (s *OrderService) seatTable(ctx context.Context, storeID string, req orderdto.CreateOrderRequest) (string, error) {
+	table, err := s.tableRepo.GetTableByID(ctx, *req.TableID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return "", ErrTableNotFound
+		}
+		return "", fmt.Errorf("error getting table: %w", err)
+	}
+	if table.StoreID != storeID {
+		return "", ErrTableNotFound
+	}
+
+	guests := req.Guests
+	if guests <= 0 {
+		guests = 1
+	}
+	if guests > table.Capacity {
+		return "", ErrTableCapacity.WithDetail(fmt.Sprintf("table %d seats %d", table.TableNumber, table.Capacity))
+	}
+
+	sessionID, opened, err := s.tableRepo.OpenSession(ctx, table.TableID, guests)
+	if err != nil {
+		return "", err
+	}
+	if !opened {
+		return "", ErrTableOccupied
+	}
+
+	return sessionID, nil
+}
+
+// closeTab frees the table whose open tab is the given order, once the order is settled or cancelled
+func (s *OrderService) closeTab(ctx context.Context, orderID string) {
+	if err := s.tableRepo.CloseSessionForOrder(ctx, orderID); err != nil {
+		// The order change already happened; the table can still be closed by hand
+		s.logger.ErrorContext(ctx, "Error closing table session", "order_id", orderID, "error", err)
+	}
+}
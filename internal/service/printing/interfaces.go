@@ -0,0 +1,27 @@
+package printing
+
+import (
+	"context"
+	"time"
+
+	"frappuccino/internal/dto/receipt"
+	"frappuccino/internal/entity"
+)
+
+type printRepo interface {
+	CreatePrinter(ctx context.Context, printer entity.Printer) (string, error)
+	GetPrinters(ctx context.Context, storeID string) ([]entity.Printer, error)
+	ClaimPrintJob(ctx context.Context, staleBefore time.Time) (entity.PrintJob, bool, error)
+	MarkJobPrinted(ctx context.Context, jobID string) error
+	MarkJobFailed(ctx context.Context, jobID, reason string, retryAt *time.Time) error
+	GetPrintJobs(ctx context.Context, storeID, status string, limit int) ([]entity.PrintJob, error)
+	GetPrintJobByID(ctx context.Context, jobID string) (entity.PrintJob, error)
+	ReprintJob(ctx context.Context, jobID string) (string, error)
+	GetStationItemIDs(ctx context.Context, orderID, stationID string) ([]string, error)
+}
+
+// ticketRenderer lays out kitchen tickets; tickets are rendered when printed, so a retry or a
+// reprint shows the order as it is at that moment
+type ticketRenderer interface {
+	GetKitchenTicket(ctx context.Context, req receipt.KitchenTicketRequest) (receipt.Receipt, error)
+}
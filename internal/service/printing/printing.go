@@ -0,0 +1,299 @@
+package printing
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log/slog"
+	"net"
+	"strings"
+	"time"
+
+	"frappuccino/internal/apperror"
+	"frappuccino/internal/config"
+	"frappuccino/internal/dto/printing"
+	"frappuccino/internal/dto/receipt"
+	"frappuccino/internal/entity"
+	"frappuccino/internal/service/access"
+)
+
+const (
+	defaultPollInterval = 2 * time.Second
+	defaultDialTimeout  = 5 * time.Second
+	defaultMaxAttempts  = 5
+	defaultRetryDelay   = 10 * time.Second
+
+	// printJobsLimit caps how many jobs the queue listing returns
+	printJobsLimit = 100
+)
+
+var (
+	ErrInvalidPrinter   = apperror.New(apperror.Invalid, "invalid_printer", "printer needs a name and a host:port address")
+	ErrInvalidJobStatus = apperror.InvalidField("invalid_print_job_status", "status", "status must be pending, printing, printed or failed")
+)
+
+// PrintService manages the kitchen printers and sends queued kitchen tickets to them
+type PrintService struct {
+	printRepo      printRepo
+	ticketRenderer ticketRenderer
+	cfg            config.Printing
+	logger         *slog.Logger
+}
+
+func NewPrintService(printRepo printRepo, ticketRenderer ticketRenderer, cfg config.Printing, logger *slog.Logger) *PrintService {
+	if cfg.PollInterval <= 0 {
+		cfg.PollInterval = defaultPollInterval
+	}
+	if cfg.DialTimeout <= 0 {
+		cfg.DialTimeout = defaultDialTimeout
+	}
+	if cfg.MaxAttempts <= 0 {
+		cfg.MaxAttempts = defaultMaxAttempts
+	}
+	if cfg.RetryDelay <= 0 {
+		cfg.RetryDelay = defaultRetryDelay
+	}
+	return &PrintService{
+		printRepo:      printRepo,
+		ticketRenderer: ticketRenderer,
+		cfg:            cfg,
+		logger:         logger,
+	}
+}
+
+func (s *PrintService) CreatePrinter(ctx context.Context, request printing.CreatePrinterRequest) (string, error) {
+	storeID, err := access.RequireStore(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	name := strings.TrimSpace(request.Name)
+	address := strings.TrimSpace(request.Address)
+	if name == "" || address == "" {
+		return "", ErrInvalidPrinter
+	}
+	if host, port, err := net.SplitHostPort(address); err != nil || host == "" || port == "" {
+		return "", ErrInvalidPrinter.WithDetail(fmt.Sprintf("%q is not host:port", address))
+	}
+
+	isActive := true
+	if request.IsActive != nil {
+		isActive = *request.IsActive
+	}
+
+	return s.printRepo.CreatePrinter(ctx, entity.Printer{
+		StoreID:   storeID,
+		Name:      name,
+		Address:   address,
+		StationID: request.StationID,
+		IsActive:  isActive,
+	})
+}
+
+func (s *PrintService) GetPrinters(ctx context.Context) ([]printing.GetPrinterResponse, error) {
+	printers, err := s.printRepo.GetPrinters(ctx, access.StoreFromContext(ctx))
+	if err != nil {
+		s.logger.ErrorContext(ctx, "Error getting printers", "error", err)
+		return nil, err
+	}
+
+	response := make([]printing.GetPrinterResponse, 0, len(printers))
+	for _, p := range printers {
+		response = append(response, printing.GetPrinterResponse{
+			PrinterID: p.PrinterID,
+			StoreID:   p.StoreID,
+			Name:      p.Name,
+			Address:   p.Address,
+			StationID: p.StationID,
+			IsActive:  p.IsActive,
+			CreatedAt: p.CreatedAt,
+		})
+	}
+	return response, nil
+}
+
+// GetPrintJobs lists the most recent print jobs, optionally filtered by status
+func (s *PrintService) GetPrintJobs(ctx context.Context, status string) ([]printing.PrintJobResponse, error) {
+	switch status {
+	case "", "pending", "printing", "printed", "failed":
+	default:
+		return nil, ErrInvalidJobStatus
+	}
+
+	jobs, err := s.printRepo.GetPrintJobs(ctx, access.StoreFromContext(ctx), status, printJobsLimit)
+	if err != nil {
+		s.logger.ErrorContext(ctx, "Error getting print jobs", "error", err)
+		return nil, err
+	}
+
+	response := make([]printing.PrintJobResponse, 0, len(jobs))
+	for _, job := range jobs {
+		response = append(response, toPrintJobResponse(job))
+	}
+	return response, nil
+}
+
+// Reprint queues a job again for the same printer, e.g. after a paper jam. Jobs of another
+// store are reported as missing.
+func (s *PrintService) Reprint(ctx context.Context, jobID string) (printing.PrintJobResponse, error) {
+	original, err := s.printRepo.GetPrintJobByID(ctx, jobID)
+	if err != nil {
+		return printing.PrintJobResponse{}, err
+	}
+	if !access.InStore(ctx, original.StoreID) {
+		return printing.PrintJobResponse{}, sql.ErrNoRows
+	}
+
+	newJobID, err := s.printRepo.ReprintJob(ctx, jobID)
+	if err != nil {
+		return printing.PrintJobResponse{}, err
+	}
+
+	job, err := s.printRepo.GetPrintJobByID(ctx, newJobID)
+	if err != nil {
+		return printing.PrintJobResponse{}, err
+	}
+	return toPrintJobResponse(job), nil
+}
+
+// RunWorker prints queued kitchen tickets until ctx is cancelled
+func (s *PrintService) RunWorker(ctx context.Context) {
+	ticker := time.NewTicker(s.cfg.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		if printed, err := s.ProcessQueue(ctx); err != nil {
+			s.logger.ErrorContext(ctx, "Error processing print queue", "error", err)
+		} else if printed > 0 {
+			s.logger.InfoContext(ctx, "Printed kitchen tickets", "count", printed)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// ProcessQueue works through every due print job and reports how many were printed
+func (s *PrintService) ProcessQueue(ctx context.Context) (int, error) {
+	// Once ctx is cancelled no further job is claimed, but the one being printed is finished
+	// and marked, so the ticket is not printed again after a restart
+	work := context.WithoutCancel(ctx)
+	printed := 0
+	for ctx.Err() == nil {
+		job, ok, err := s.printRepo.ClaimPrintJob(work, time.Now().Add(-s.staleAfter()))
+		if err != nil {
+			return printed, err
+		}
+		if !ok {
+			break
+		}
+
+		if err := s.printJob(work, job); err != nil {
+			s.recordFailure(work, job, err)
+			continue
+		}
+		if err := s.printRepo.MarkJobPrinted(work, job.JobID); err != nil {
+			return printed, err
+		}
+		printed++
+	}
+	return printed, nil
+}
+
+// printJob renders the ticket of a job and sends it to the printer
+func (s *PrintService) printJob(ctx context.Context, job entity.PrintJob) error {
+	req := receipt.KitchenTicketRequest{
+		OrderID: job.OrderID,
+		Station: job.StationName,
+		Reprint: job.ReprintOf != nil,
+	}
+	if job.StationID != nil {
+		itemIDs, err := s.printRepo.GetStationItemIDs(ctx, job.OrderID, *job.StationID)
+		if err != nil {
+			return err
+		}
+		if len(itemIDs) == 0 {
+			// The station's items were removed from the order since the job was queued
+			return nil
+		}
+		req.OrderItemIDs = itemIDs
+	}
+
+	ticket, err := s.ticketRenderer.GetKitchenTicket(ctx, req)
+	if err != nil {
+		return fmt.Errorf("render ticket: %w", err)
+	}
+
+	return s.send(ctx, job.PrinterAddress, ticket.Body)
+}
+
+// send writes raw ESC/POS bytes to a network printer. Such printers accept a ticket on a plain
+// TCP connection, conventionally on port 9100.
+func (s *PrintService) send(ctx context.Context, address string, data []byte) error {
+	dialer := net.Dialer{Timeout: s.cfg.DialTimeout}
+	conn, err := dialer.DialContext(ctx, "tcp", address)
+	if err != nil {
+		return fmt.Errorf("connect to printer %s: %w", address, err)
+	}
+	defer conn.Close()
+
+	if err := conn.SetWriteDeadline(time.Now().Add(s.cfg.DialTimeout)); err != nil {
+		return fmt.Errorf("set printer deadline: %w", err)
+	}
+	if _, err := conn.Write(data); err != nil {
+		return fmt.Errorf("write to printer %s: %w", address, err)
+	}
+	return nil
+}
+
+// recordFailure schedules the next attempt of a job, waiting longer after every attempt,
+// or marks it failed once it ran out of attempts
+func (s *PrintService) recordFailure(ctx context.Context, job entity.PrintJob, cause error) {
+	var retryAt *time.Time
+	if job.Attempts < s.cfg.MaxAttempts {
+		next := time.Now().Add(s.cfg.RetryDelay * time.Duration(job.Attempts))
+		retryAt = &next
+	}
+
+	s.logger.WarnContext(ctx, "Error printing job",
+		"job_id", job.JobID,
+		"printer", job.PrinterName,
+		"attempt", job.Attempts,
+		"max_attempts", s.cfg.MaxAttempts,
+		"error", cause,
+	)
+	if err := s.printRepo.MarkJobFailed(ctx, job.JobID, cause.Error(), retryAt); err != nil {
+		s.logger.ErrorContext(ctx, "Error recording print failure", "job_id", job.JobID, "error", err)
+	}
+}
+
+// staleAfter is how long a job may stay printing before it is assumed lost with its worker
+func (s *PrintService) staleAfter() time.Duration {
+	return max(time.Minute, 4*s.cfg.DialTimeout)
+}
+
+func toPrintJobResponse(job entity.PrintJob) printing.PrintJobResponse {
+	response := printing.PrintJobResponse{
+		JobID:       job.JobID,
+		PrinterID:   job.PrinterID,
+		StoreID:     job.StoreID,
+		PrinterName: job.PrinterName,
+		OrderID:     job.OrderID,
+		StationID:   job.StationID,
+		StationName: job.StationName,
+		Status:      job.Status,
+		Attempts:    job.Attempts,
+		LastError:   job.LastError,
+		ReprintOf:   job.ReprintOf,
+		CreatedAt:   job.CreatedAt,
+		PrintedAt:   job.PrintedAt,
+	}
+	if job.Status == "pending" {
+		nextAttemptAt := job.NextAttemptAt
+		response.NextAttemptAt = &nextAttemptAt
+	}
+	return response
+}
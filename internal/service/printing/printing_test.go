@@ -0,0 +1,287 @@
+package printing
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"fmt"
+	"io"
+	"log/slog"
+	"net"
+	"testing"
+	"time"
+
+	"frappuccino/internal/config"
+	"frappuccino/internal/dto/receipt"
+	"frappuccino/internal/entity"
+	"frappuccino/internal/service/access"
+)
+
+// fakePrintRepo keeps print jobs in memory and claims them the way the print_jobs queries do
+type fakePrintRepo struct {
+	jobs    []*entity.PrintJob
+	retryAt map[string]*time.Time // what MarkJobFailed was last called with, by job
+}
+
+func newFakePrintRepo(jobs ...entity.PrintJob) *fakePrintRepo {
+	repo := &fakePrintRepo{retryAt: make(map[string]*time.Time)}
+	for i := range jobs {
+		job := jobs[i]
+		repo.jobs = append(repo.jobs, &job)
+	}
+	return repo
+}
+
+func (r *fakePrintRepo) job(jobID string) (*entity.PrintJob, error) {
+	for _, job := range r.jobs {
+		if job.JobID == jobID {
+			return job, nil
+		}
+	}
+	return nil, sql.ErrNoRows
+}
+
+func (r *fakePrintRepo) CreatePrinter(ctx context.Context, printer entity.Printer) (string, error) {
+	return "", fmt.Errorf("not implemented")
+}
+
+func (r *fakePrintRepo) GetPrinters(ctx context.Context, storeID string) ([]entity.Printer, error) {
+	return nil, fmt.Errorf("not implemented")
+}
+
+func (r *fakePrintRepo) ClaimPrintJob(ctx context.Context, staleBefore time.Time) (entity.PrintJob, bool, error) {
+	now := time.Now()
+	for _, job := range r.jobs {
+		if job.Status == "pending" && !job.NextAttemptAt.After(now) {
+			job.Status = "printing"
+			job.Attempts++
+			return *job, true, nil
+		}
+	}
+	return entity.PrintJob{}, false, nil
+}
+
+func (r *fakePrintRepo) MarkJobPrinted(ctx context.Context, jobID string) error {
+	job, err := r.job(jobID)
+	if err != nil {
+		return err
+	}
+	job.Status = "printed"
+	job.LastError = ""
+	return nil
+}
+
+func (r *fakePrintRepo) MarkJobFailed(ctx context.Context, jobID, reason string, retryAt *time.Time) error {
+	job, err := r.job(jobID)
+	if err != nil {
+		return err
+	}
+	r.retryAt[jobID] = retryAt
+	job.LastError = reason
+	if retryAt == nil {
+		job.Status = "failed"
+		return nil
+	}
+	job.Status = "pending"
+	job.NextAttemptAt = *retryAt
+	return nil
+}
+
+func (r *fakePrintRepo) GetPrintJobs(ctx context.Context, storeID, status string, limit int) ([]entity.PrintJob, error) {
+	return nil, fmt.Errorf("not implemented")
+}
+
+func (r *fakePrintRepo) GetPrintJobByID(ctx context.Context, jobID string) (entity.PrintJob, error) {
+	job, err := r.job(jobID)
+	if err != nil {
+		return entity.PrintJob{}, err
+	}
+	return *job, nil
+}
+
+func (r *fakePrintRepo) ReprintJob(ctx context.Context, jobID string) (string, error) {
+	original, err := r.job(jobID)
+	if err != nil {
+		return "", err
+	}
+	reprint := *original
+	reprint.JobID = fmt.Sprintf("job-%d", len(r.jobs)+1)
+	reprint.Status = "pending"
+	reprint.Attempts = 0
+	reprint.LastError = ""
+	reprint.ReprintOf = &original.JobID
+	reprint.NextAttemptAt = time.Now()
+	reprint.PrintedAt = nil
+	r.jobs = append(r.jobs, &reprint)
+	return reprint.JobID, nil
+}
+
+func (r *fakePrintRepo) GetStationItemIDs(ctx context.Context, orderID, stationID string) ([]string, error) {
+	return []string{"item-1"}, nil
+}
+
+// fakeRenderer returns the same ticket for every request
+type fakeRenderer struct {
+	body     []byte
+	requests []receipt.KitchenTicketRequest
+}
+
+func (f *fakeRenderer) GetKitchenTicket(ctx context.Context, req receipt.KitchenTicketRequest) (receipt.Receipt, error) {
+	f.requests = append(f.requests, req)
+	return receipt.Receipt{Format: "escpos", ContentType: "application/octet-stream", Body: f.body}, nil
+}
+
+var ticketBody = []byte("\x1b@\x1bE\x01BAR\x1bE\x00\n2x Latte\n\x1dVA\x03")
+
+var testCfg = config.Printing{
+	DialTimeout: time.Second,
+	MaxAttempts: 3,
+	RetryDelay:  10 * time.Second,
+}
+
+func newTestService(repo printRepo, renderer ticketRenderer) *PrintService {
+	return NewPrintService(repo, renderer, testCfg, slog.New(slog.NewTextHandler(io.Discard, nil)))
+}
+
+func pendingJob(address string, attempts int) entity.PrintJob {
+	station := "station-1"
+	return entity.PrintJob{
+		JobID:          "job-1",
+		PrinterID:      "printer-1",
+		StoreID:        "store-1",
+		PrinterName:    "Bar",
+		PrinterAddress: address,
+		OrderID:        "order-1",
+		StationID:      &station,
+		StationName:    "Bar",
+		Status:         "pending",
+		Attempts:       attempts,
+		NextAttemptAt:  time.Now().Add(-time.Second),
+	}
+}
+
+// refusedAddress returns an address nothing listens on
+func refusedAddress(t *testing.T) string {
+	t.Helper()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	address := listener.Addr().String()
+	listener.Close()
+	return address
+}
+
+func TestProcessQueueSendsTicketToPrinter(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	defer listener.Close()
+
+	received := make(chan []byte, 1)
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			received <- nil
+			return
+		}
+		defer conn.Close()
+		data, _ := io.ReadAll(conn)
+		received <- data
+	}()
+
+	repo := newFakePrintRepo(pendingJob(listener.Addr().String(), 0))
+	renderer := &fakeRenderer{body: ticketBody}
+	printed, err := newTestService(repo, renderer).ProcessQueue(context.Background())
+	if err != nil {
+		t.Fatalf("ProcessQueue: %v", err)
+	}
+	if printed != 1 {
+		t.Fatalf("ProcessQueue printed %d jobs, want 1", printed)
+	}
+
+	select {
+	case data := <-received:
+		if !bytes.Equal(data, ticketBody) {
+			t.Errorf("printer received %q, want %q", data, ticketBody)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("printer received nothing")
+	}
+
+	if len(renderer.requests) != 1 || renderer.requests[0].Station != "Bar" || len(renderer.requests[0].OrderItemIDs) != 1 {
+		t.Errorf("ticket rendered for %+v, want the items of station Bar", renderer.requests)
+	}
+	if status := repo.jobs[0].Status; status != "printed" {
+		t.Errorf("job status = %q, want printed", status)
+	}
+}
+
+func TestProcessQueueRetriesRefusedConnection(t *testing.T) {
+	// The claim makes this the second attempt
+	repo := newFakePrintRepo(pendingJob(refusedAddress(t), 1))
+	before := time.Now()
+	printed, err := newTestService(repo, &fakeRenderer{body: ticketBody}).ProcessQueue(context.Background())
+	after := time.Now()
+	if err != nil {
+		t.Fatalf("ProcessQueue: %v", err)
+	}
+	if printed != 0 {
+		t.Fatalf("ProcessQueue printed %d jobs, want 0", printed)
+	}
+
+	job := repo.jobs[0]
+	if job.Status != "pending" || job.LastError == "" {
+		t.Fatalf("job is %q with error %q, want pending with the connection error", job.Status, job.LastError)
+	}
+	retryAt := repo.retryAt[job.JobID]
+	if retryAt == nil {
+		t.Fatal("no retry was scheduled")
+	}
+	wait := testCfg.RetryDelay * 2
+	if retryAt.Before(before.Add(wait)) || retryAt.After(after.Add(wait)) {
+		t.Errorf("retry at %v, want RetryDelay*attempts = %v after the attempt", retryAt, wait)
+	}
+}
+
+func TestProcessQueueFailsJobAfterMaxAttempts(t *testing.T) {
+	// The claim makes this the last attempt
+	repo := newFakePrintRepo(pendingJob(refusedAddress(t), testCfg.MaxAttempts-1))
+	if _, err := newTestService(repo, &fakeRenderer{body: ticketBody}).ProcessQueue(context.Background()); err != nil {
+		t.Fatalf("ProcessQueue: %v", err)
+	}
+
+	job := repo.jobs[0]
+	if job.Status != "failed" {
+		t.Errorf("job status = %q after %d attempts, want failed", job.Status, job.Attempts)
+	}
+	if retryAt, marked := repo.retryAt[job.JobID]; !marked || retryAt != nil {
+		t.Errorf("MarkJobFailed retry = %v, marked = %v; want no retry", retryAt, marked)
+	}
+}
+
+func TestReprintQueuesNewJob(t *testing.T) {
+	original := pendingJob("127.0.0.1:9100", 1)
+	original.Status = "printed"
+	repo := newFakePrintRepo(original)
+	service := newTestService(repo, &fakeRenderer{body: ticketBody})
+
+	job, err := service.Reprint(access.WithStore(context.Background(), original.StoreID), original.JobID)
+	if err != nil {
+		t.Fatalf("Reprint: %v", err)
+	}
+	if job.JobID == original.JobID || job.Status != "pending" || job.ReprintOf == nil || *job.ReprintOf != original.JobID {
+		t.Errorf("Reprint returned %+v, want a new pending job reprinting %s", job, original.JobID)
+	}
+	if job.PrinterID != original.PrinterID || job.OrderID != original.OrderID {
+		t.Errorf("reprint goes to printer %s for order %s, want %s for %s", job.PrinterID, job.OrderID, original.PrinterID, original.OrderID)
+	}
+	if len(repo.jobs) != 2 {
+		t.Errorf("queue holds %d jobs, want 2", len(repo.jobs))
+	}
+
+	if _, err := service.Reprint(access.WithStore(context.Background(), "store-2"), original.JobID); err != sql.ErrNoRows {
+		t.Errorf("Reprint from another store error = %v, want sql.ErrNoRows", err)
+	}
+}
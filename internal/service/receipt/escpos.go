@@ -0,0 +1,45 @@
+package receipt
+
+import (
+	"text/template"
+	"unicode/utf8"
+)
+
+// ESC/POS commands understood by practically every thermal receipt printer
+const (
+	escInitialize  = "\x1b@"
+	escAlignLeft   = "\x1ba\x00"
+	escAlignCenter = "\x1ba\x01"
+	escBoldOn      = "\x1bE\x01"
+	escBoldOff     = "\x1bE\x00"
+	gsDoubleSize   = "\x1d!\x11"
+	gsNormalSize   = "\x1d!\x00"
+	gsFeedAndCut   = "\x1dVA\x03" // feed three lines past the cutter, then a partial cut
+)
+
+var escposFuncs = template.FuncMap{
+	"initialize":  func() string { return escInitialize },
+	"alignLeft":   func() string { return escAlignLeft },
+	"alignCenter": func() string { return escAlignCenter },
+	"bold":        func() string { return escBoldOn },
+	"boldOff":     func() string { return escBoldOff },
+	"doubleSize":  func() string { return gsDoubleSize },
+	"normalSize":  func() string { return gsNormalSize },
+	"feedAndCut":  func() string { return gsFeedAndCut },
+}
+
+// toPrinterCharset replaces everything outside ASCII with '?', as printers start in code page 437
+// and would otherwise print the bytes of UTF-8 sequences as box-drawing characters
+func toPrinterCharset(data []byte) []byte {
+	out := make([]byte, 0, len(data))
+	for len(data) > 0 {
+		r, size := utf8.DecodeRune(data)
+		if r < utf8.RuneSelf {
+			out = append(out, byte(r))
+		} else {
+			out = append(out, '?')
+		}
+		data = data[size:]
+	}
+	return out
+}
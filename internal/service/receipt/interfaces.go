@@ -0,0 +1,18 @@
+package receipt
+
+import (
+	"context"
+
+	orderdto "frappuccino/internal/dto/order"
+	"frappuccino/internal/entity"
+)
+
+// orderService supplies the order a receipt is printed for, so receipts show exactly what the API returns
+type orderService interface {
+	GetOrderByID(ctx context.Context, id string) (orderdto.GetOrderResponse, error)
+}
+
+// storeRepo supplies the name, address and phone of the store an order was placed at
+type storeRepo interface {
+	GetStore(ctx context.Context, ref string) (entity.Store, error)
+}
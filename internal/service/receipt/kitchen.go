@@ -0,0 +1,86 @@
+package receipt
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"frappuccino/internal/dto/receipt"
+)
+
+// kitchenTicketView is what the kitchen ticket template sees: no prices, large quantities
+type kitchenTicketView struct {
+	Width        int
+	OrderNumber  int
+	Station      string
+	OrderType    string
+	CustomerName string
+	PickupAt     string
+	PlacedAt     string
+	Instructions string
+	Reprint      bool
+	Lines        []receiptLine
+}
+
+// GetKitchenTicket renders the ESC/POS kitchen ticket of an order, or of the items of one station
+func (s *ReceiptService) GetKitchenTicket(ctx context.Context, req receipt.KitchenTicketRequest) (receipt.Receipt, error) {
+	order, err := s.orderService.GetOrderByID(ctx, req.OrderID)
+	if err != nil {
+		return receipt.Receipt{}, err
+	}
+
+	selected := make(map[string]bool, len(req.OrderItemIDs))
+	for _, id := range req.OrderItemIDs {
+		selected[id] = true
+	}
+
+	view := kitchenTicketView{
+		Width:        s.storeCfg.ReceiptWidth,
+		OrderNumber:  order.OrderNumber,
+		Station:      req.Station,
+		OrderType:    strings.ReplaceAll(order.OrderType, "_", " "),
+		CustomerName: order.CustomerName,
+		PlacedAt:     order.CreatedAt.Format("15:04"),
+		Instructions: formatInstructions(order.SpecialInstructions),
+		Reprint:      req.Reprint,
+	}
+	if order.PickupAt != nil {
+		view.PickupAt = order.PickupAt.Format("15:04")
+	}
+	for _, item := range order.Items {
+		if len(selected) > 0 && !selected[item.OrderItemID] {
+			continue
+		}
+		view.Lines = append(view.Lines, receiptLine{
+			Quantity: item.Quantity,
+			Name:     item.Name,
+			Options:  formatCustomizations(item.Customizations),
+		})
+	}
+
+	var buf bytes.Buffer
+	if err := kitchenTicketTemplate.Execute(&buf, view); err != nil {
+		s.logger.ErrorContext(ctx, "Error rendering kitchen ticket", "order_id", req.OrderID, "error", err)
+		return receipt.Receipt{}, fmt.Errorf("execute kitchen ticket template: %w", err)
+	}
+
+	return receipt.Receipt{
+		Format:      "escpos",
+		ContentType: "application/octet-stream",
+		Body:        toPrinterCharset(buf.Bytes()),
+	}, nil
+}
+
+// formatInstructions prints special instructions given as a JSON string as plain text
+func formatInstructions(raw json.RawMessage) string {
+	if len(raw) == 0 || string(raw) == "null" {
+		return ""
+	}
+	var text string
+	if err := json.Unmarshal(raw, &text); err == nil {
+		return text
+	}
+	return string(raw)
+}
@@ -0,0 +1,123 @@
+package receipt
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"log/slog"
+
+	"frappuccino/internal/apperror"
+	"frappuccino/internal/config"
+	"frappuccino/internal/dto/receipt"
+)
+
+const defaultReceiptWidth = 42
+
+var ErrUnknownFormat = apperror.New(apperror.Invalid, "unknown_receipt_format", "receipt format must be text, html or escpos")
+
+// ReceiptService renders printable receipts of orders
+type ReceiptService struct {
+	orderService orderService
+	storeRepo    storeRepo
+	storeCfg     config.Store
+	logger       *slog.Logger
+}
+
+func NewReceiptService(orderService orderService, storeRepo storeRepo, storeCfg config.Store, logger *slog.Logger) *ReceiptService {
+	if storeCfg.ReceiptWidth <= 0 {
+		storeCfg.ReceiptWidth = defaultReceiptWidth
+	}
+	return &ReceiptService{
+		orderService: orderService,
+		storeRepo:    storeRepo,
+		storeCfg:     storeCfg,
+		logger:       logger,
+	}
+}
+
+// GetReceipt renders the receipt of an order as text, html or escpos; text is the default
+func (s *ReceiptService) GetReceipt(ctx context.Context, orderID, format string) (receipt.Receipt, error) {
+	if format == "" {
+		format = "text"
+	}
+
+	var contentType string
+	switch format {
+	case "text":
+		contentType = "text/plain; charset=utf-8"
+	case "html":
+		contentType = "text/html; charset=utf-8"
+	case "escpos":
+		contentType = "application/octet-stream"
+	default:
+		return receipt.Receipt{}, ErrUnknownFormat
+	}
+
+	order, err := s.orderService.GetOrderByID(ctx, orderID)
+	if err != nil {
+		return receipt.Receipt{}, err
+	}
+
+	store, err := s.storeHeader(ctx, order.StoreID)
+	if err != nil {
+		return receipt.Receipt{}, err
+	}
+
+	body, err := render(format, newReceiptView(store, order))
+	if err != nil {
+		s.logger.ErrorContext(ctx, "Error rendering receipt", "order_id", orderID, "format", format, "error", err)
+		return receipt.Receipt{}, err
+	}
+
+	return receipt.Receipt{
+		Format:      format,
+		ContentType: contentType,
+		Body:        body,
+	}, nil
+}
+
+// storeHeader returns the receipt settings with the name, address and phone of the store the
+// order was placed at; the configured ones remain for details the store leaves empty
+func (s *ReceiptService) storeHeader(ctx context.Context, storeID string) (config.Store, error) {
+	store, err := s.storeRepo.GetStore(ctx, storeID)
+	if err != nil {
+		s.logger.ErrorContext(ctx, "Error getting store of receipt", "store_id", storeID, "error", err)
+		return config.Store{}, err
+	}
+
+	header := s.storeCfg
+	if store.Name != "" {
+		header.Name = store.Name
+	}
+	if store.Address != "" {
+		header.Address = store.Address
+	}
+	if store.Phone != "" {
+		header.Phone = store.Phone
+	}
+	return header, nil
+}
+
+// render executes the template of a format; escpos output is restricted to the printer's character set
+func render(format string, view receiptView) ([]byte, error) {
+	var buf bytes.Buffer
+	var err error
+	switch format {
+	case "text":
+		err = textTemplate.Execute(&buf, view)
+	case "html":
+		err = htmlTemplate.Execute(&buf, view)
+	case "escpos":
+		err = escposTemplate.Execute(&buf, view)
+	default:
+		return nil, ErrUnknownFormat
+	}
+	if err != nil {
+		return nil, fmt.Errorf("execute %s receipt template: %w", format, err)
+	}
+
+	if format == "escpos" {
+		return toPrinterCharset(buf.Bytes()), nil
+	}
+	return buf.Bytes(), nil
+}
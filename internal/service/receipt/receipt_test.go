@@ -0,0 +1,121 @@
+package receipt
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"flag"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"frappuccino/internal/config"
+	orderdto "frappuccino/internal/dto/order"
+	"frappuccino/internal/entity"
+)
+
+var update = flag.Bool("update", false, "rewrite the golden files of the receipt tests")
+
+type fakeOrders struct {
+	order orderdto.GetOrderResponse
+}
+
+func (f fakeOrders) GetOrderByID(ctx context.Context, id string) (orderdto.GetOrderResponse, error) {
+	return f.order, nil
+}
+
+type fakeStores struct {
+	store entity.Store
+}
+
+func (f fakeStores) GetStore(ctx context.Context, ref string) (entity.Store, error) {
+	return f.store, nil
+}
+
+// goldenOrder is a delivery order with customizations, a fee, a non-ASCII item name and
+// characters HTML must escape, so every format has something to get wrong
+var goldenOrder = orderdto.GetOrderResponse{
+	OrderID:      "7f9c2ba4-e88f-41d2-9e5a-8a1d3b0c6f21",
+	StoreID:      "0b8e5b1e-8d8a-4a59-9c1e-2d4f6a7b8c9d",
+	OrderNumber:  42,
+	BusinessDate: "2026-03-14",
+	CustomerName: "Ana & Bo <3",
+	Subtotal:     13.25,
+	DeliveryFee:  2.5,
+	TotalAmount:  15.75,
+	Status:       "ready",
+	OrderType:    "delivery",
+	Delivery: &orderdto.DeliveryResponse{
+		ZoneID:   "c1d2e3f4-a5b6-4c7d-8e9f-0a1b2c3d4e5f",
+		ZoneName: "Centre",
+		Address: orderdto.DeliveryAddress{
+			Street:   "12 Market Street",
+			City:     "Springfield",
+			Postcode: "SP1 2AB",
+			Notes:    "Ring twice",
+		},
+	},
+	CreatedAt: time.Date(2026, 3, 14, 8, 5, 0, 0, time.UTC),
+	UpdatedAt: time.Date(2026, 3, 14, 8, 20, 0, 0, time.UTC),
+	Items: []orderdto.GetOrderItemResponse{
+		{
+			OrderItemID:    "a0000000-0000-4000-8000-000000000001",
+			MenuItemID:     "b0000000-0000-4000-8000-000000000001",
+			Name:           "Caffè Latte",
+			Quantity:       2,
+			PriceAtTime:    4.5,
+			Customizations: json.RawMessage(`{"milk": "oat", "extra_shot": true, "syrups": ["vanilla", "caramel"]}`),
+		},
+		{
+			OrderItemID: "a0000000-0000-4000-8000-000000000002",
+			MenuItemID:  "b0000000-0000-4000-8000-000000000002",
+			Name:        "Blueberry Muffin with a name too long for one line",
+			Quantity:    1,
+			PriceAtTime: 4.25,
+		},
+	},
+}
+
+func TestGetReceiptMatchesGoldenFiles(t *testing.T) {
+	service := NewReceiptService(
+		fakeOrders{order: goldenOrder},
+		fakeStores{store: entity.Store{Name: "Frappuccino Centre", Address: "1 Bean Lane, Springfield", Phone: "+1 555 0100"}},
+		config.Store{Footer: "Thank you! Wi-Fi: beans4all", Currency: "$"},
+		slog.New(slog.NewTextHandler(io.Discard, nil)),
+	)
+
+	for _, format := range []string{"text", "html", "escpos"} {
+		t.Run(format, func(t *testing.T) {
+			got, err := service.GetReceipt(context.Background(), goldenOrder.OrderID, format)
+			if err != nil {
+				t.Fatalf("GetReceipt: %v", err)
+			}
+
+			golden := filepath.Join("testdata", "receipt."+format+".golden")
+			if *update {
+				if err := os.WriteFile(golden, got.Body, 0o644); err != nil {
+					t.Fatalf("write golden file: %v", err)
+				}
+			}
+
+			want, err := os.ReadFile(golden)
+			if err != nil {
+				t.Fatalf("read golden file (run the test with -update to create it): %v", err)
+			}
+			if !bytes.Equal(got.Body, want) {
+				t.Errorf("%s receipt differs from %s\ngot:\n%q\nwant:\n%q", format, golden, got.Body, want)
+			}
+		})
+	}
+}
+
+func TestGetReceiptRejectsUnknownFormat(t *testing.T) {
+	service := NewReceiptService(fakeOrders{order: goldenOrder}, fakeStores{}, config.Store{}, slog.New(slog.NewTextHandler(io.Discard, nil)))
+
+	if _, err := service.GetReceipt(context.Background(), goldenOrder.OrderID, "pdf"); err != ErrUnknownFormat {
+		t.Fatalf("GetReceipt(pdf) error = %v, want ErrUnknownFormat", err)
+	}
+}
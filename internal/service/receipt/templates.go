@@ -0,0 +1,113 @@
+package receipt
+
+import (
+	htmltemplate "html/template"
+	"text/template"
+)
+
+var layoutFuncs = template.FuncMap{
+	"row":    row,
+	"center": center,
+	"rule":   rule,
+}
+
+var textTemplate = template.Must(template.New("text").Funcs(layoutFuncs).Parse(
+	`{{center .Width .Store.Name}}
+{{with .Store.Address}}{{center $.Width .}}
+{{end}}{{with .Store.Phone}}{{center $.Width .}}
+{{end}}{{rule .Width}}
+{{center .Width (printf "ORDER #%d" .OrderNumber)}}
+{{row .Width "Date" .PlacedAt}}
+{{row .Width "Customer" .CustomerName}}
+{{row .Width "Type" .OrderType}}
+{{range .Delivery}}  {{.}}
+{{end}}{{rule .Width}}
+{{range .Lines}}{{row $.Width (printf "%dx %s" .Quantity .Name) .Amount}}
+{{range .Options}}   + {{.}}
+{{end}}{{end}}{{rule .Width}}
+{{row .Width "Subtotal" .Subtotal}}
+{{with .DeliveryFee}}{{row $.Width "Delivery fee" .}}
+{{end}}{{row .Width "TOTAL" .Total}}
+{{with .Store.Footer}}{{rule $.Width}}
+{{center $.Width .}}
+{{end}}`))
+
+var htmlTemplate = htmltemplate.Must(htmltemplate.New("html").Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>Receipt #{{.OrderNumber}}</title>
+<style>
+body { font-family: monospace; max-width: 24em; margin: 1em auto; }
+header, footer, .number { text-align: center; }
+table { width: 100%; border-collapse: collapse; }
+td.amount { text-align: right; white-space: nowrap; }
+.options { color: #555; padding-left: 1.5em; }
+.total td { font-weight: bold; border-top: 1px dashed #000; }
+</style>
+</head>
+<body>
+<header>
+<h1>{{.Store.Name}}</h1>
+{{with .Store.Address}}<div>{{.}}</div>
+{{end}}{{with .Store.Phone}}<div>{{.}}</div>
+{{end}}</header>
+<h2 class="number">Order #{{.OrderNumber}}</h2>
+<table>
+<tr><td>Date</td><td class="amount">{{.PlacedAt}}</td></tr>
+<tr><td>Customer</td><td class="amount">{{.CustomerName}}</td></tr>
+<tr><td>Type</td><td class="amount">{{.OrderType}}</td></tr>
+{{with .Delivery}}<tr><td colspan="2">{{range .}}<div>{{.}}</div>{{end}}</td></tr>
+{{end}}</table>
+<hr>
+<table>
+{{range .Lines}}<tr><td>{{.Quantity}}x {{.Name}}</td><td class="amount">{{.Amount}}</td></tr>
+{{range .Options}}<tr><td class="options" colspan="2">+ {{.}}</td></tr>
+{{end}}{{end}}</table>
+<hr>
+<table>
+<tr><td>Subtotal</td><td class="amount">{{.Subtotal}}</td></tr>
+{{with .DeliveryFee}}<tr><td>Delivery fee</td><td class="amount">{{.}}</td></tr>
+{{end}}<tr class="total"><td>Total</td><td class="amount">{{.Total}}</td></tr>
+</table>
+{{with .Store.Footer}}<footer><p>{{.}}</p></footer>
+{{end}}</body>
+</html>
+`))
+
+// escposTemplate lays out the same receipt for thermal printers, using the commands in escpos.go
+// for alignment, emphasis and the paper cut
+var escposTemplate = template.Must(template.New("escpos").Funcs(layoutFuncs).Funcs(escposFuncs).Parse(
+	`{{initialize}}{{alignCenter}}{{bold}}{{doubleSize}}{{.Store.Name}}
+{{normalSize}}{{boldOff}}{{with .Store.Address}}{{.}}
+{{end}}{{with .Store.Phone}}{{.}}
+{{end}}{{rule .Width}}
+{{bold}}{{doubleSize}}ORDER #{{.OrderNumber}}
+{{normalSize}}{{boldOff}}{{alignLeft}}{{row .Width "Date" .PlacedAt}}
+{{row .Width "Customer" .CustomerName}}
+{{row .Width "Type" .OrderType}}
+{{range .Delivery}}  {{.}}
+{{end}}{{rule .Width}}
+{{range .Lines}}{{row $.Width (printf "%dx %s" .Quantity .Name) .Amount}}
+{{range .Options}}   + {{.}}
+{{end}}{{end}}{{rule .Width}}
+{{row .Width "Subtotal" .Subtotal}}
+{{with .DeliveryFee}}{{row $.Width "Delivery fee" .}}
+{{end}}{{bold}}{{row .Width "TOTAL" .Total}}
+{{boldOff}}{{with .Store.Footer}}{{alignCenter}}{{.}}
+{{end}}{{feedAndCut}}`))
+
+// kitchenTicketTemplate is printed at the stations: large order number and quantities, no prices
+var kitchenTicketTemplate = template.Must(template.New("kitchen").Funcs(layoutFuncs).Funcs(escposFuncs).Parse(
+	`{{initialize}}{{alignCenter}}{{if .Reprint}}{{bold}}*** REPRINT ***{{boldOff}}
+{{end}}{{with .Station}}{{.}}
+{{end}}{{bold}}{{doubleSize}}#{{.OrderNumber}}
+{{normalSize}}{{.OrderType}}{{boldOff}}
+{{alignLeft}}{{row .Width .CustomerName .PlacedAt}}
+{{with .PickupAt}}{{bold}}{{row $.Width "PICKUP" .}}{{boldOff}}
+{{end}}{{rule .Width}}
+{{range .Lines}}{{bold}}{{doubleSize}}{{.Quantity}}x {{normalSize}}{{.Name}}{{boldOff}}
+{{range .Options}}   + {{.}}
+{{end}}{{end}}{{with .Instructions}}{{rule $.Width}}
+{{bold}}NOTE:{{boldOff}} {{.}}
+{{end}}{{feedAndCut}}`))
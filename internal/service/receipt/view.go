@@ -0,0 +1,144 @@
+package receipt
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"frappuccino/internal/config"
+	orderdto "frappuccino/internal/dto/order"
+)
+
+// receiptView is what the receipt templates see: the order with amounts already formatted
+type receiptView struct {
+	Store        config.Store
+	Width        int
+	OrderID      string
+	OrderNumber  int
+	BusinessDate string
+	PlacedAt     string
+	CustomerName string
+	OrderType    string
+	Delivery     []string // address lines of delivery orders
+	Lines        []receiptLine
+	Subtotal     string
+	DeliveryFee  string // empty when the order has no delivery fee
+	Total        string
+}
+
+type receiptLine struct {
+	Quantity  int
+	Name      string
+	UnitPrice string
+	Amount    string
+	Options   []string // customizations, one per line
+}
+
+func newReceiptView(store config.Store, order orderdto.GetOrderResponse) receiptView {
+	view := receiptView{
+		Store:        store,
+		Width:        store.ReceiptWidth,
+		OrderID:      order.OrderID,
+		OrderNumber:  order.OrderNumber,
+		BusinessDate: order.BusinessDate,
+		PlacedAt:     order.CreatedAt.Format("2006-01-02 15:04"),
+		CustomerName: order.CustomerName,
+		OrderType:    strings.ReplaceAll(order.OrderType, "_", " "),
+		Subtotal:     money(store.Currency, order.Subtotal),
+		Total:        money(store.Currency, order.TotalAmount),
+	}
+	if order.DeliveryFee > 0 {
+		view.DeliveryFee = money(store.Currency, order.DeliveryFee)
+	}
+
+	if order.Delivery != nil {
+		addr := order.Delivery.Address
+		view.Delivery = append(view.Delivery, addr.Street, strings.TrimSpace(addr.Postcode+" "+addr.City))
+		if addr.Notes != "" {
+			view.Delivery = append(view.Delivery, addr.Notes)
+		}
+	}
+
+	for _, item := range order.Items {
+		view.Lines = append(view.Lines, receiptLine{
+			Quantity:  item.Quantity,
+			Name:      item.Name,
+			UnitPrice: money(store.Currency, item.PriceAtTime),
+			Amount:    money(store.Currency, item.PriceAtTime*float64(item.Quantity)),
+			Options:   formatCustomizations(item.Customizations),
+		})
+	}
+
+	return view
+}
+
+func money(currency string, amount float64) string {
+	return fmt.Sprintf("%s%.2f", currency, amount)
+}
+
+// formatCustomizations turns a customization object such as {"milk": "oat", "extra_shot": true}
+// into sorted lines like "extra shot" and "milk: oat". Anything that is not an object is printed as is.
+func formatCustomizations(raw json.RawMessage) []string {
+	if len(raw) == 0 || string(raw) == "null" {
+		return nil
+	}
+
+	var options map[string]interface{}
+	if err := json.Unmarshal(raw, &options); err != nil {
+		return []string{string(raw)}
+	}
+
+	keys := make([]string, 0, len(options))
+	for key := range options {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var lines []string
+	for _, key := range keys {
+		label := strings.ReplaceAll(key, "_", " ")
+		switch value := options[key].(type) {
+		case bool:
+			if value {
+				lines = append(lines, label)
+			}
+		case nil:
+		case []interface{}:
+			parts := make([]string, 0, len(value))
+			for _, v := range value {
+				parts = append(parts, fmt.Sprint(v))
+			}
+			lines = append(lines, label+": "+strings.Join(parts, ", "))
+		default:
+			lines = append(lines, fmt.Sprintf("%s: %v", label, value))
+		}
+	}
+	return lines
+}
+
+// row prints left and right on one line of the given width, shortening left if both do not fit
+func row(width int, left, right string) string {
+	space := width - utf8.RuneCountInString(right) - 1
+	if space < 1 {
+		return left + " " + right
+	}
+	if utf8.RuneCountInString(left) > space {
+		left = string([]rune(left)[:space])
+	}
+	return left + strings.Repeat(" ", width-utf8.RuneCountInString(left)-utf8.RuneCountInString(right)) + right
+}
+
+// center pads s so it sits in the middle of a line of the given width
+func center(width int, s string) string {
+	padding := (width - utf8.RuneCountInString(s)) / 2
+	if padding <= 0 {
+		return s
+	}
+	return strings.Repeat(" ", padding) + s
+}
+
+func rule(width int) string {
+	return strings.Repeat("-", width)
+}
@@ -5,14 +5,18 @@ import (
 	"time"
 
 	"frappuccino/internal/dto/report"
+	"frappuccino/internal/service/access"
 )
 
-// GetTotalSales calculates the total sales for the given date range and status
+// GetTotalSales calculates the total sales for the given date range and status, broken down by
+// store when the request is not scoped to one
 func (s *SearchService) GetTotalSales(ctx context.Context, req report.TotalSalesRequest) (report.TotalSalesResponse, error) {
+	storeID := access.StoreFromContext(ctx)
+
 	// Get total sales from repository
-	totalSales, orderCount, err := s.orderRepo.GetTotalSales(ctx, req.StartDate, req.EndDate, req.Status)
+	totalSales, deliveryFees, orderCount, err := s.orderRepo.GetTotalSales(ctx, storeID, req.StartDate, req.EndDate, req.Status)
 	if err != nil {
-		s.logger.Printf("Error getting total sales: %v", err)
+		s.logger.ErrorContext(ctx, "Error getting total sales", "error", err)
 		return report.TotalSalesResponse{}, err
 	}
 
@@ -25,9 +29,20 @@ func (s *SearchService) GetTotalSales(ctx context.Context, req report.TotalSales
 	// Prepare response
 	response := report.TotalSalesResponse{
 		TotalSales:       totalSales,
+		ItemSales:        totalSales - deliveryFees,
+		DeliveryFees:     deliveryFees,
 		OrderCount:       orderCount,
 		AverageOrderSize: averageOrderSize,
 		Status:           req.Status,
+		StoreID:          storeID,
+	}
+
+	if storeID == "" {
+		response.ByStore, err = s.orderRepo.GetSalesByStore(ctx, req.StartDate, req.EndDate, req.Status)
+		if err != nil {
+			s.logger.ErrorContext(ctx, "Error getting sales by store", "error", err)
+			return report.TotalSalesResponse{}, err
+		}
 	}
 
 	// Set date range in the response
@@ -58,9 +73,9 @@ func (s *SearchService) GetPopularItems(ctx context.Context, req report.PopularI
 	}
 
 	// Get popular items from repository
-	items, totalQuantity, totalRevenue, err := s.orderRepo.GetPopularItems(ctx, req.StartDate, req.EndDate, limit)
+	items, totalQuantity, totalRevenue, err := s.orderRepo.GetPopularItems(ctx, access.StoreFromContext(ctx), req.StartDate, req.EndDate, limit)
 	if err != nil {
-		s.logger.Printf("Error getting popular items: %v", err)
+		s.logger.ErrorContext(ctx, "Error getting popular items", "error", err)
 		return report.PopularItemsResponse{}, err
 	}
 
@@ -5,20 +5,27 @@ import (
 	"time"
 
 	"frappuccino/internal/dto/report"
+	"frappuccino/internal/entity"
 )
 
 type searchRepo interface {
-	SearchMenuItems(ctx context.Context, query string, minPrice, maxPrice *float64) ([]report.SearchResultMenuItem, error)
-	SearchOrders(ctx context.Context, query string, minPrice, maxPrice *float64) ([]report.SearchResultOrder, error)
-	SearchMenuItemsByKeywords(ctx context.Context, keywords []string, minPrice, maxPrice *float64) ([]report.SearchResultMenuItem, error)
-	SearchOrdersByKeywords(ctx context.Context, keywords []string, minPrice, maxPrice *float64) ([]report.SearchResultOrder, error)
+	SearchMenuItems(ctx context.Context, storeID, query string, minPrice, maxPrice *float64) ([]report.SearchResultMenuItem, error)
+	SearchOrders(ctx context.Context, storeID, query string, minPrice, maxPrice *float64) ([]report.SearchResultOrder, error)
+	SearchMenuItemsByKeywords(ctx context.Context, storeID string, keywords []string, minPrice, maxPrice *float64) ([]report.SearchResultMenuItem, error)
+	SearchOrdersByKeywords(ctx context.Context, storeID string, keywords []string, minPrice, maxPrice *float64) ([]report.SearchResultOrder, error)
 }
 
 type orderRepo interface {
-	GetOrderedItemsByDay(ctx context.Context, month time.Month, year int) ([]report.DayCount, error)
-	GetOrderedItemsByMonth(ctx context.Context, year int) ([]report.MonthCount, error)
+	GetOrderedItemsByDay(ctx context.Context, storeID string, month time.Month, year int) ([]report.DayCount, error)
+	GetOrderedItemsByMonth(ctx context.Context, storeID string, year int) ([]report.MonthCount, error)
 
 	// New methods for aggregation reports
-	GetTotalSales(ctx context.Context, startDate, endDate *time.Time, status string) (float64, int, error)
-	GetPopularItems(ctx context.Context, startDate, endDate *time.Time, limit int) ([]report.PopularItem, int, float64, error)
+	GetTotalSales(ctx context.Context, storeID string, startDate, endDate *time.Time, status string) (float64, float64, int, error)
+	GetSalesByStore(ctx context.Context, startDate, endDate *time.Time, status string) ([]report.StoreSales, error)
+	GetPopularItems(ctx context.Context, storeID string, startDate, endDate *time.Time, limit int) ([]report.PopularItem, int, float64, error)
+
+	// Service time analytics
+	GetOrderStateTimings(ctx context.Context, storeID string, startDate, endDate *time.Time) ([]entity.OrderStateTiming, error)
+	GetOrderMenuItems(ctx context.Context, storeID string, startDate, endDate *time.Time) ([]entity.OrderMenuItem, error)
+	GetStationTicketTimings(ctx context.Context, storeID string, startDate, endDate *time.Time) ([]entity.StationTicketTiming, error)
 }
@@ -2,30 +2,42 @@ package report
 
 import (
 	"context"
-	"fmt"
-	"log"
+	"log/slog"
 	"strconv"
 	"strings"
 	"time"
 
+	"frappuccino/internal/apperror"
+	"frappuccino/internal/config"
 	"frappuccino/internal/dto/report"
+	"frappuccino/internal/service/access"
+)
+
+var (
+	ErrInvalidYear   = apperror.InvalidField("invalid_year", "year", "year must be a number like 2024")
+	ErrMonthRequired = apperror.InvalidField("month_required", "month", "month is required when period is day")
+	ErrInvalidMonth  = apperror.InvalidField("invalid_month", "month", "month must be the name of a month like january")
+	ErrInvalidPeriod = apperror.InvalidField("invalid_period", "period", "period must be day or month")
 )
 
 type SearchService struct {
 	searchRepo searchRepo
 	orderRepo  orderRepo // Add this line
-	logger     *log.Logger
+	reportCfg  config.Report
+	logger     *slog.Logger
 }
 
 // Update the constructor to accept orderRepo
 func NewSearchService(
 	searchRepo searchRepo,
 	orderRepo orderRepo, // Add this parameter
-	logger *log.Logger,
+	reportCfg config.Report,
+	logger *slog.Logger,
 ) *SearchService {
 	return &SearchService{
 		searchRepo: searchRepo,
 		orderRepo:  orderRepo, // Initialize this field
+		reportCfg:  reportCfg,
 		logger:     logger,
 	}
 }
@@ -35,6 +47,7 @@ func (s *SearchService) Search(ctx context.Context, req report.SearchRequest) (r
 	var response report.SearchResponse
 	var minPrice, maxPrice *float64
 	var err error
+	storeID := access.StoreFromContext(ctx)
 
 	// Prepare price filters
 	if req.MinPrice > 0 {
@@ -89,13 +102,13 @@ func (s *SearchService) Search(ctx context.Context, req report.SearchRequest) (r
 		var menuItems []report.SearchResultMenuItem
 
 		if useKeywords {
-			menuItems, err = s.searchRepo.SearchMenuItemsByKeywords(ctx, keywords, minPrice, maxPrice)
+			menuItems, err = s.searchRepo.SearchMenuItemsByKeywords(ctx, storeID, keywords, minPrice, maxPrice)
 		} else {
-			menuItems, err = s.searchRepo.SearchMenuItems(ctx, req.Query, minPrice, maxPrice)
+			menuItems, err = s.searchRepo.SearchMenuItems(ctx, storeID, req.Query, minPrice, maxPrice)
 		}
 
 		if err != nil {
-			s.logger.Printf("Error searching menu items: %v", err)
+			s.logger.ErrorContext(ctx, "Error searching menu items", "error", err)
 			// Continue with search instead of returning error
 		} else {
 			response.MenuItems = menuItems
@@ -108,13 +121,13 @@ func (s *SearchService) Search(ctx context.Context, req report.SearchRequest) (r
 		var orders []report.SearchResultOrder
 
 		if useKeywords {
-			orders, err = s.searchRepo.SearchOrdersByKeywords(ctx, keywords, minPrice, maxPrice)
+			orders, err = s.searchRepo.SearchOrdersByKeywords(ctx, storeID, keywords, minPrice, maxPrice)
 		} else {
-			orders, err = s.searchRepo.SearchOrders(ctx, req.Query, minPrice, maxPrice)
+			orders, err = s.searchRepo.SearchOrders(ctx, storeID, req.Query, minPrice, maxPrice)
 		}
 
 		if err != nil {
-			s.logger.Printf("Error searching orders: %v", err)
+			s.logger.ErrorContext(ctx, "Error searching orders", "error", err)
 			// Continue with search instead of returning error
 		} else {
 			response.Orders = orders
@@ -127,6 +140,7 @@ func (s *SearchService) Search(ctx context.Context, req report.SearchRequest) (r
 func (s *SearchService) GetOrderedItemsByPeriod(ctx context.Context, req report.OrderedItemsByPeriodRequest) (report.OrderedItemsByPeriodResponse, error) {
 	var response report.OrderedItemsByPeriodResponse
 	response.Period = req.Period
+	storeID := access.StoreFromContext(ctx)
 
 	// Parse year
 	var year int
@@ -137,7 +151,7 @@ func (s *SearchService) GetOrderedItemsByPeriod(ctx context.Context, req report.
 		var err error
 		year, err = strconv.Atoi(req.Year)
 		if err != nil {
-			return response, fmt.Errorf("invalid year format: %w", err)
+			return response, ErrInvalidYear
 		}
 	}
 	response.Year = strconv.Itoa(year)
@@ -145,7 +159,7 @@ func (s *SearchService) GetOrderedItemsByPeriod(ctx context.Context, req report.
 	if req.Period == "day" {
 		// Parse month
 		if req.Month == "" {
-			return response, fmt.Errorf("month parameter is required when period is day")
+			return response, ErrMonthRequired
 		}
 
 		monthMap := map[string]time.Month{
@@ -165,15 +179,15 @@ func (s *SearchService) GetOrderedItemsByPeriod(ctx context.Context, req report.
 
 		month, ok := monthMap[strings.ToLower(req.Month)]
 		if !ok {
-			return response, fmt.Errorf("invalid month name: %s", req.Month)
+			return response, ErrInvalidMonth
 		}
 
 		response.Month = req.Month
 
 		// Get order data by day
-		dayCounts, err := s.orderRepo.GetOrderedItemsByDay(ctx, month, year)
+		dayCounts, err := s.orderRepo.GetOrderedItemsByDay(ctx, storeID, month, year)
 		if err != nil {
-			s.logger.Printf("Error getting ordered items by day: %v", err)
+			s.logger.ErrorContext(ctx, "Error getting ordered items by day", "error", err)
 			return response, err
 		}
 
@@ -181,16 +195,16 @@ func (s *SearchService) GetOrderedItemsByPeriod(ctx context.Context, req report.
 
 	} else if req.Period == "month" {
 		// Get order data by month
-		monthCounts, err := s.orderRepo.GetOrderedItemsByMonth(ctx, year)
+		monthCounts, err := s.orderRepo.GetOrderedItemsByMonth(ctx, storeID, year)
 		if err != nil {
-			s.logger.Printf("Error getting ordered items by month: %v", err)
+			s.logger.ErrorContext(ctx, "Error getting ordered items by month", "error", err)
 			return response, err
 		}
 
 		response.OrderedItems = monthCounts
 
 	} else {
-		return response, fmt.Errorf("invalid period parameter: %s (must be 'day' or 'month')", req.Period)
+		return response, ErrInvalidPeriod
 	}
 
 	return response, nil
@@ -0,0 +1,261 @@
+package report
+
+import (
+	"context"
+	"math"
+	"sort"
+	"time"
+
+	"frappuccino/internal/dto/report"
+	"frappuccino/internal/entity"
+	"frappuccino/internal/service/access"
+)
+
+// transitionDurations collects raw durations for each kitchen state
+type transitionDurations struct {
+	pendingToPreparing []time.Duration
+	preparingToReady   []time.Duration
+	readyToDelivered   []time.Duration
+}
+
+func (d *transitionDurations) add(t entity.OrderStateTiming) {
+	if t.PreparingAt != nil {
+		d.pendingToPreparing = appendNonNegative(d.pendingToPreparing, t.PreparingAt.Sub(t.CreatedAt))
+		if t.ReadyAt != nil {
+			d.preparingToReady = appendNonNegative(d.preparingToReady, t.ReadyAt.Sub(*t.PreparingAt))
+		}
+	}
+	if t.ReadyAt != nil && t.DeliveredAt != nil {
+		d.readyToDelivered = appendNonNegative(d.readyToDelivered, t.DeliveredAt.Sub(*t.ReadyAt))
+	}
+}
+
+func (d *transitionDurations) stats() report.TransitionTimes {
+	return report.TransitionTimes{
+		PendingToPreparing: durationStats(d.pendingToPreparing),
+		PreparingToReady:   durationStats(d.preparingToReady),
+		ReadyToDelivered:   durationStats(d.readyToDelivered),
+	}
+}
+
+// GetServiceTimes analyzes order_status_history to report how long orders spend in each
+// state, broken down by hour of day, menu item and station, and flags SLA breaches
+func (s *SearchService) GetServiceTimes(ctx context.Context, req report.ServiceTimesRequest) (report.ServiceTimesResponse, error) {
+	sla := req.SLA
+	if sla <= 0 {
+		sla = s.reportCfg.ServiceSLA
+	}
+	storeID := access.StoreFromContext(ctx)
+
+	timings, err := s.orderRepo.GetOrderStateTimings(ctx, storeID, req.StartDate, req.EndDate)
+	if err != nil {
+		s.logger.ErrorContext(ctx, "Error getting order state timings", "error", err)
+		return report.ServiceTimesResponse{}, err
+	}
+
+	orderItems, err := s.orderRepo.GetOrderMenuItems(ctx, storeID, req.StartDate, req.EndDate)
+	if err != nil {
+		s.logger.ErrorContext(ctx, "Error getting order menu items", "error", err)
+		return report.ServiceTimesResponse{}, err
+	}
+
+	ticketTimings, err := s.orderRepo.GetStationTicketTimings(ctx, storeID, req.StartDate, req.EndDate)
+	if err != nil {
+		s.logger.ErrorContext(ctx, "Error getting station ticket timings", "error", err)
+		return report.ServiceTimesResponse{}, err
+	}
+
+	// Index menu items by order so each order's durations count towards its items
+	itemsByOrder := make(map[string][]entity.OrderMenuItem)
+	for _, item := range orderItems {
+		itemsByOrder[item.OrderID] = append(itemsByOrder[item.OrderID], item)
+	}
+
+	now := time.Now()
+	var overall transitionDurations
+	byHour := make(map[int]*transitionDurations)
+	byItem := make(map[string]*transitionDurations)
+	itemNames := make(map[string]string)
+	response := report.ServiceTimesResponse{
+		SLASeconds:  sla.Seconds(),
+		SLABreaches: []report.SLABreach{},
+	}
+
+	for _, t := range timings {
+		overall.add(t)
+
+		hour := t.CreatedAt.Hour()
+		if byHour[hour] == nil {
+			byHour[hour] = &transitionDurations{}
+		}
+		byHour[hour].add(t)
+
+		for _, item := range itemsByOrder[t.OrderID] {
+			if byItem[item.MenuItemID] == nil {
+				byItem[item.MenuItemID] = &transitionDurations{}
+				itemNames[item.MenuItemID] = item.Name
+			}
+			byItem[item.MenuItemID].add(t)
+		}
+
+		if breach, ok := checkSLA(t, sla, now); ok {
+			response.SLABreaches = append(response.SLABreaches, breach)
+		}
+	}
+
+	response.Overall = overall.stats()
+	response.BreachCount = len(response.SLABreaches)
+
+	response.ByHour = make([]report.HourServiceTimes, 0, len(byHour))
+	for hour, durations := range byHour {
+		response.ByHour = append(response.ByHour, report.HourServiceTimes{
+			Hour:            hour,
+			TransitionTimes: durations.stats(),
+		})
+	}
+	sort.Slice(response.ByHour, func(i, j int) bool {
+		return response.ByHour[i].Hour < response.ByHour[j].Hour
+	})
+
+	response.ByMenuItem = make([]report.MenuItemServiceTimes, 0, len(byItem))
+	for id, durations := range byItem {
+		response.ByMenuItem = append(response.ByMenuItem, report.MenuItemServiceTimes{
+			MenuItemID:      id,
+			Name:            itemNames[id],
+			TransitionTimes: durations.stats(),
+		})
+	}
+	sort.Slice(response.ByMenuItem, func(i, j int) bool {
+		return response.ByMenuItem[i].Name < response.ByMenuItem[j].Name
+	})
+
+	response.ByStation = stationServiceTimes(ticketTimings)
+
+	if req.StartDate != nil {
+		response.StartDate = *req.StartDate
+	}
+	if req.EndDate != nil {
+		response.EndDate = *req.EndDate
+	}
+
+	return response, nil
+}
+
+// checkSLA reports whether an order took longer than the SLA to become ready. Orders that
+// went straight to delivered count their delivery time, and orders still in the kitchen are
+// measured against the current time.
+func checkSLA(t entity.OrderStateTiming, sla time.Duration, now time.Time) (report.SLABreach, bool) {
+	if sla <= 0 {
+		return report.SLABreach{}, false
+	}
+
+	readyAt := t.ReadyAt
+	if readyAt == nil {
+		readyAt = t.DeliveredAt
+	}
+
+	var elapsed time.Duration
+	if readyAt != nil {
+		elapsed = readyAt.Sub(t.CreatedAt)
+	} else {
+		switch t.Status {
+		case "pending", "preparing":
+			elapsed = now.Sub(t.CreatedAt)
+		default:
+			// Orders without kitchen history (e.g. still scheduled) cannot breach yet
+			return report.SLABreach{}, false
+		}
+	}
+
+	if elapsed <= sla {
+		return report.SLABreach{}, false
+	}
+
+	return report.SLABreach{
+		OrderID:           t.OrderID,
+		CustomerName:      t.CustomerName,
+		Status:            t.Status,
+		CreatedAt:         t.CreatedAt,
+		ReadyAt:           readyAt,
+		ElapsedSeconds:    elapsed.Seconds(),
+		ExceededBySeconds: (elapsed - sla).Seconds(),
+	}, true
+}
+
+func stationServiceTimes(timings []entity.StationTicketTiming) []report.StationServiceTimes {
+	type stationDurations struct {
+		name  string
+		queue []time.Duration
+		prep  []time.Duration
+	}
+
+	byStation := make(map[string]*stationDurations)
+	for _, t := range timings {
+		d := byStation[t.StationID]
+		if d == nil {
+			d = &stationDurations{name: t.StationName}
+			byStation[t.StationID] = d
+		}
+		if t.StartedAt != nil {
+			d.queue = appendNonNegative(d.queue, t.StartedAt.Sub(t.CreatedAt))
+			if t.CompletedAt != nil {
+				d.prep = appendNonNegative(d.prep, t.CompletedAt.Sub(*t.StartedAt))
+			}
+		}
+	}
+
+	result := make([]report.StationServiceTimes, 0, len(byStation))
+	for id, d := range byStation {
+		result = append(result, report.StationServiceTimes{
+			StationID: id,
+			Name:      d.name,
+			QueueTime: durationStats(d.queue),
+			PrepTime:  durationStats(d.prep),
+		})
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Name < result[j].Name
+	})
+	return result
+}
+
+// durationStats computes the average and nearest-rank percentiles of the durations
+func durationStats(durations []time.Duration) report.DurationStats {
+	if len(durations) == 0 {
+		return report.DurationStats{}
+	}
+
+	sorted := make([]time.Duration, len(durations))
+	copy(sorted, durations)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+
+	var total time.Duration
+	for _, d := range sorted {
+		total += d
+	}
+
+	return report.DurationStats{
+		Count:          len(sorted),
+		AverageSeconds: total.Seconds() / float64(len(sorted)),
+		P50Seconds:     percentile(sorted, 50).Seconds(),
+		P90Seconds:     percentile(sorted, 90).Seconds(),
+		P99Seconds:     percentile(sorted, 99).Seconds(),
+	}
+}
+
+// percentile returns the nearest-rank percentile of an ascending slice
+func percentile(sorted []time.Duration, p float64) time.Duration {
+	rank := int(math.Ceil(p / 100 * float64(len(sorted))))
+	if rank < 1 {
+		rank = 1
+	}
+	return sorted[rank-1]
+}
+
+// appendNonNegative skips durations that went backwards because of manual status edits
+func appendNonNegative(durations []time.Duration, d time.Duration) []time.Duration {
+	if d < 0 {
+		return durations
+	}
+	return append(durations, d)
+}
@@ -0,0 +1,29 @@
+package station
+
+import (
+	"context"
+
+	"frappuccino/internal/entity"
+	"frappuccino/internal/repository/postgres"
+)
+
+type stationRepo interface {
+	CreateStation(ctx context.Context, station entity.Station) (string, error)
+	GetStations(ctx context.Context) ([]entity.Station, error)
+	GetStationByID(ctx context.Context, id string) (entity.Station, error)
+	CreateStationRoute(ctx context.Context, route entity.StationRoute) (string, error)
+	GetStationRoutes(ctx context.Context, stationID string) ([]entity.StationRoute, error)
+	GetStationTickets(ctx context.Context, storeID, stationID string, statuses []string) ([]entity.StationTicket, error)
+	GetTicketByID(ctx context.Context, ticketID string) (entity.StationTicket, error)
+	GetTicketByIDWithTx(ctx context.Context, tx *postgres.Transaction, ticketID string) (entity.StationTicket, error)
+	GetOrderTicketsWithTx(ctx context.Context, tx *postgres.Transaction, orderID string) ([]entity.StationTicket, error)
+	UpdateTicketStatusWithTx(ctx context.Context, tx *postgres.Transaction, ticketID string, status string) error
+}
+
+// orderRepo is used to keep the parent order status in step with its tickets. The order row is
+// locked while its tickets are read, so that stations finishing at once see each other's tickets.
+type orderRepo interface {
+	Begin(ctx context.Context) (*postgres.Transaction, error)
+	LockOrderWithTx(ctx context.Context, tx *postgres.Transaction, orderID string) (entity.Order, error)
+	TransitionOrderStatusWithTx(ctx context.Context, tx *postgres.Transaction, orderID, fromStatus, toStatus, reason string) (bool, error)
+}
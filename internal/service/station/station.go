@@ -0,0 +1,286 @@
+package station
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log/slog"
+
+	"frappuccino/internal/apperror"
+	"frappuccino/internal/dto/station"
+	"frappuccino/internal/entity"
+	"frappuccino/internal/repository/postgres"
+	"frappuccino/internal/service/access"
+)
+
+var (
+	ErrInvalidRoute            = apperror.New(apperror.Invalid, "invalid_station_route", "route must specify exactly one of menu_item_id or category")
+	ErrInvalidTicketStatus     = apperror.InvalidField("invalid_ticket_status", "status", "invalid ticket status")
+	ErrInvalidTicketTransition = apperror.New(apperror.Conflict, "invalid_ticket_transition", "ticket status can only move forward")
+)
+
+// ticketStatusRank orders ticket statuses so that tickets can only advance
+var ticketStatusRank = map[string]int{
+	"pending":     0,
+	"in_progress": 1,
+	"done":        2,
+}
+
+type StationService struct {
+	stationRepo stationRepo
+	orderRepo   orderRepo
+	logger      *slog.Logger
+}
+
+func NewStationService(stationRepo stationRepo, orderRepo orderRepo, logger *slog.Logger) *StationService {
+	return &StationService{
+		stationRepo: stationRepo,
+		orderRepo:   orderRepo,
+		logger:      logger,
+	}
+}
+
+func (s *StationService) CreateStation(ctx context.Context, request station.CreateStationRequest) (string, error) {
+	id, err := s.stationRepo.CreateStation(ctx, entity.Station{
+		Name:        request.Name,
+		Description: request.Description,
+		IsDefault:   request.IsDefault,
+	})
+	if err != nil {
+		s.logger.ErrorContext(ctx, "CreateStation error", "error", err)
+		return "", err
+	}
+	return id, nil
+}
+
+func (s *StationService) GetStations(ctx context.Context) ([]station.GetStationResponse, error) {
+	stations, err := s.stationRepo.GetStations(ctx)
+	if err != nil {
+		s.logger.ErrorContext(ctx, "Error retrieving stations", "error", err)
+		return nil, err
+	}
+
+	response := make([]station.GetStationResponse, 0, len(stations))
+	for _, st := range stations {
+		routes, err := s.stationRepo.GetStationRoutes(ctx, st.StationID)
+		if err != nil {
+			s.logger.ErrorContext(ctx, "Error retrieving station routes", "station_id", st.StationID, "error", err)
+			return nil, err
+		}
+
+		routeResponses := make([]station.GetStationRouteResponse, 0, len(routes))
+		for _, r := range routes {
+			routeResponses = append(routeResponses, station.GetStationRouteResponse{
+				StationRouteID: r.StationRouteID,
+				MenuItemID:     r.MenuItemID,
+				Category:       r.Category,
+			})
+		}
+
+		response = append(response, station.GetStationResponse{
+			StationID:   st.StationID,
+			Name:        st.Name,
+			Description: st.Description,
+			IsDefault:   st.IsDefault,
+			CreatedAt:   st.CreatedAt,
+			Routes:      routeResponses,
+		})
+	}
+
+	return response, nil
+}
+
+func (s *StationService) CreateStationRoute(ctx context.Context, stationID string, request station.CreateStationRouteRequest) (string, error) {
+	if (request.MenuItemID == "") == (request.Category == "") {
+		return "", ErrInvalidRoute
+	}
+
+	// Make sure the station exists before routing anything to it
+	if _, err := s.stationRepo.GetStationByID(ctx, stationID); err != nil {
+		s.logger.ErrorContext(ctx, "Error retrieving station", "error", err)
+		return "", err
+	}
+
+	id, err := s.stationRepo.CreateStationRoute(ctx, entity.StationRoute{
+		StationID:  stationID,
+		MenuItemID: request.MenuItemID,
+		Category:   request.Category,
+	})
+	if err != nil {
+		s.logger.ErrorContext(ctx, "CreateStationRoute error", "error", err)
+		return "", err
+	}
+	return id, nil
+}
+
+// GetStationTickets returns the queue of a station. Without a status filter the open
+// tickets (pending and in progress) are returned. Only tickets of orders placed at the
+// store of the request are listed.
+func (s *StationService) GetStationTickets(ctx context.Context, stationID string, status string) ([]station.GetTicketResponse, error) {
+	statuses := []string{"pending", "in_progress"}
+	if status != "" {
+		if _, ok := ticketStatusRank[status]; !ok {
+			return nil, ErrInvalidTicketStatus
+		}
+		statuses = []string{status}
+	}
+
+	if _, err := s.stationRepo.GetStationByID(ctx, stationID); err != nil {
+		s.logger.ErrorContext(ctx, "Error retrieving station", "error", err)
+		return nil, err
+	}
+
+	tickets, err := s.stationRepo.GetStationTickets(ctx, access.StoreFromContext(ctx), stationID, statuses)
+	if err != nil {
+		s.logger.ErrorContext(ctx, "Error retrieving station tickets", "error", err)
+		return nil, err
+	}
+
+	response := make([]station.GetTicketResponse, 0, len(tickets))
+	for _, t := range tickets {
+		response = append(response, toTicketResponse(t))
+	}
+	return response, nil
+}
+
+// UpdateTicketStatus advances a ticket and re-derives the status of its order. Both happen in one
+// transaction holding the lock of the order, so tickets of the same order are moved one at a time.
+func (s *StationService) UpdateTicketStatus(ctx context.Context, ticketID string, request station.UpdateTicketStatusRequest) (err error) {
+	newRank, ok := ticketStatusRank[request.Status]
+	if !ok {
+		return ErrInvalidTicketStatus
+	}
+
+	ticket, err := s.stationRepo.GetTicketByID(ctx, ticketID)
+	if err != nil {
+		s.logger.ErrorContext(ctx, "Error retrieving ticket", "error", err)
+		return err
+	}
+
+	tx, err := s.orderRepo.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("error starting transaction: %w", err)
+	}
+	defer func() {
+		if err != nil {
+			if rbErr := tx.Rollback(); rbErr != nil {
+				s.logger.ErrorContext(ctx, "Error rolling back transaction", "error", rbErr)
+			}
+		}
+	}()
+
+	// Tickets of another store's orders are reported as missing
+	order, err := s.orderRepo.LockOrderWithTx(ctx, tx, ticket.OrderID)
+	if err != nil {
+		s.logger.ErrorContext(ctx, "Error locking order", "error", err)
+		return err
+	}
+	if !access.InStore(ctx, order.StoreID) {
+		return sql.ErrNoRows
+	}
+
+	// Read the ticket again now that no other ticket of the order can move
+	ticket, err = s.stationRepo.GetTicketByIDWithTx(ctx, tx, ticketID)
+	if err != nil {
+		s.logger.ErrorContext(ctx, "Error retrieving ticket", "error", err)
+		return err
+	}
+	if newRank <= ticketStatusRank[ticket.Status] {
+		return ErrInvalidTicketTransition
+	}
+
+	if err = s.stationRepo.UpdateTicketStatusWithTx(ctx, tx, ticketID, request.Status); err != nil {
+		s.logger.ErrorContext(ctx, "Error updating ticket status", "error", err)
+		return err
+	}
+
+	if err = s.syncOrderStatus(ctx, tx, order); err != nil {
+		return err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("error committing transaction: %w", err)
+	}
+	return nil
+}
+
+// syncOrderStatus derives the order status from its station tickets: the order is
+// preparing as soon as any station has started and ready once every ticket is done.
+// The order must be locked by tx.
+func (s *StationService) syncOrderStatus(ctx context.Context, tx *postgres.Transaction, order entity.Order) error {
+	// Only orders still in the kitchen follow their tickets
+	switch order.Status {
+	case "pending", "preparing", "ready":
+	default:
+		return nil
+	}
+
+	tickets, err := s.stationRepo.GetOrderTicketsWithTx(ctx, tx, order.OrderID)
+	if err != nil {
+		s.logger.ErrorContext(ctx, "Error retrieving order tickets", "error", err)
+		return err
+	}
+
+	derived := deriveOrderStatus(tickets)
+	if derived == order.Status {
+		return nil
+	}
+
+	reason := fmt.Sprintf("Station tickets advanced (%s)", derived)
+	if _, err := s.orderRepo.TransitionOrderStatusWithTx(ctx, tx, order.OrderID, order.Status, derived, reason); err != nil {
+		s.logger.ErrorContext(ctx, "Error updating order status from tickets", "error", err)
+		return err
+	}
+	return nil
+}
+
+func deriveOrderStatus(tickets []entity.StationTicket) string {
+	if len(tickets) == 0 {
+		return "pending"
+	}
+
+	done := 0
+	started := false
+	for _, t := range tickets {
+		switch t.Status {
+		case "done":
+			done++
+			started = true
+		case "in_progress":
+			started = true
+		}
+	}
+
+	switch {
+	case done == len(tickets):
+		return "ready"
+	case started:
+		return "preparing"
+	default:
+		return "pending"
+	}
+}
+
+func toTicketResponse(t entity.StationTicket) station.GetTicketResponse {
+	items := make([]station.TicketItemResponse, 0, len(t.Items))
+	for _, item := range t.Items {
+		items = append(items, station.TicketItemResponse{
+			OrderItemID:    item.OrderItemID,
+			MenuItemID:     item.MenuItemID,
+			Name:           item.Name,
+			Quantity:       item.Quantity,
+			Customizations: item.Customizations,
+		})
+	}
+
+	return station.GetTicketResponse{
+		TicketID:    t.TicketID,
+		OrderID:     t.OrderID,
+		StationID:   t.StationID,
+		Status:      t.Status,
+		CreatedAt:   t.CreatedAt,
+		StartedAt:   t.StartedAt,
+		CompletedAt: t.CompletedAt,
+		Items:       items,
+	}
+}
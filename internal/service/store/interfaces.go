@@ -0,0 +1,13 @@
+package store
+
+import (
+	"context"
+
+	"frappuccino/internal/entity"
+)
+
+type storeRepo interface {
+	CreateStore(ctx context.Context, store entity.Store) (string, error)
+	GetStores(ctx context.Context) ([]entity.Store, error)
+	GetStore(ctx context.Context, ref string) (entity.Store, error)
+}
@@ -0,0 +1,102 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"frappuccino/internal/apperror"
+	"frappuccino/internal/dto/store"
+	"frappuccino/internal/entity"
+)
+
+var (
+	ErrInvalidStore = apperror.New(apperror.Invalid, "invalid_store", "store needs a code without spaces and a name")
+	ErrStoreExists  = apperror.New(apperror.Conflict, "store_exists", "a store with this code already exists")
+	ErrUnknownStore = apperror.New(apperror.Invalid, "unknown_store", "unknown or closed store")
+)
+
+// StoreService manages the locations of the business and resolves the store a request works in
+type StoreService struct {
+	storeRepo storeRepo
+	logger    *slog.Logger
+}
+
+func NewStoreService(storeRepo storeRepo, logger *slog.Logger) *StoreService {
+	return &StoreService{
+		storeRepo: storeRepo,
+		logger:    logger,
+	}
+}
+
+func (s *StoreService) CreateStore(ctx context.Context, request store.CreateStoreRequest) (string, error) {
+	code := strings.ToLower(strings.TrimSpace(request.Code))
+	name := strings.TrimSpace(request.Name)
+	if code == "" || name == "" || strings.ContainsAny(code, " \t") {
+		return "", ErrInvalidStore
+	}
+
+	if _, err := s.storeRepo.GetStore(ctx, code); err == nil {
+		return "", ErrStoreExists
+	} else if !errors.Is(err, sql.ErrNoRows) {
+		return "", fmt.Errorf("error checking store code: %w", err)
+	}
+
+	id, err := s.storeRepo.CreateStore(ctx, entity.Store{
+		Code:     code,
+		Name:     name,
+		Address:  strings.TrimSpace(request.Address),
+		Phone:    strings.TrimSpace(request.Phone),
+		IsActive: true,
+	})
+	if err != nil {
+		s.logger.ErrorContext(ctx, "CreateStore error", "error", err)
+		return "", err
+	}
+	return id, nil
+}
+
+func (s *StoreService) GetStores(ctx context.Context) ([]store.GetStoreResponse, error) {
+	stores, err := s.storeRepo.GetStores(ctx)
+	if err != nil {
+		s.logger.ErrorContext(ctx, "Error retrieving stores", "error", err)
+		return nil, err
+	}
+
+	response := make([]store.GetStoreResponse, 0, len(stores))
+	for _, st := range stores {
+		response = append(response, store.GetStoreResponse{
+			StoreID:   st.StoreID,
+			Code:      st.Code,
+			Name:      st.Name,
+			Address:   st.Address,
+			Phone:     st.Phone,
+			IsActive:  st.IsActive,
+			CreatedAt: st.CreatedAt,
+		})
+	}
+	return response, nil
+}
+
+// ResolveStore finds an open store by its ID or its code, as sent in the X-Store-ID header
+func (s *StoreService) ResolveStore(ctx context.Context, ref string) (entity.Store, error) {
+	ref = strings.TrimSpace(ref)
+	if ref == "" {
+		return entity.Store{}, ErrUnknownStore
+	}
+
+	st, err := s.storeRepo.GetStore(ctx, ref)
+	if errors.Is(err, sql.ErrNoRows) {
+		return entity.Store{}, ErrUnknownStore.WithDetail(ref)
+	}
+	if err != nil {
+		return entity.Store{}, fmt.Errorf("error getting store: %w", err)
+	}
+	if !st.IsActive {
+		return entity.Store{}, ErrUnknownStore.WithDetail(ref)
+	}
+	return st, nil
+}
@@ -0,0 +1,26 @@
+package table
+
+import (
+	"context"
+	"time"
+
+	orderdto "frappuccino/internal/dto/order"
+	"frappuccino/internal/entity"
+)
+
+type tableRepo interface {
+	CreateTable(ctx context.Context, table entity.DiningTable) (string, error)
+	GetTableByID(ctx context.Context, tableID string) (entity.DiningTable, error)
+	GetTableOccupancy(ctx context.Context, storeID string) ([]entity.TableOccupancy, error)
+	GetOpenSession(ctx context.Context, tableID string) (entity.TableSession, error)
+	DeleteSession(ctx context.Context, sessionID string) error
+	GetClosedSessions(ctx context.Context, storeID string, startDate, endDate *time.Time) ([]entity.TableSessionTiming, error)
+}
+
+// orderService opens, extends and settles the order behind a tab, so tabs follow the same
+// inventory, kitchen and audit rules as any other order
+type orderService interface {
+	CreateOrder(ctx context.Context, req orderdto.CreateOrderRequest) (orderdto.CreateOrderResponse, error)
+	AddOrderItem(ctx context.Context, orderID string, req orderdto.AddOrderItemRequest) (orderdto.GetOrderResponse, error)
+	CloseOrder(ctx context.Context, orderID string, reason string) error
+}
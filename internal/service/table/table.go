@@ -0,0 +1,223 @@
+package table
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sort"
+	"strings"
+	"time"
+
+	"frappuccino/internal/apperror"
+	orderdto "frappuccino/internal/dto/order"
+	"frappuccino/internal/dto/table"
+	"frappuccino/internal/entity"
+	"frappuccino/internal/service/access"
+)
+
+var (
+	ErrInvalidTable = apperror.New(apperror.Invalid, "invalid_table", "table number and capacity must be greater than zero")
+	ErrNoOpenTab    = apperror.New(apperror.Conflict, "no_open_tab", "table has no open tab")
+)
+
+type TableService struct {
+	tableRepo    tableRepo
+	orderService orderService
+	logger       *slog.Logger
+}
+
+func NewTableService(tableRepo tableRepo, orderService orderService, logger *slog.Logger) *TableService {
+	return &TableService{
+		tableRepo:    tableRepo,
+		orderService: orderService,
+		logger:       logger,
+	}
+}
+
+func (s *TableService) CreateTable(ctx context.Context, request table.CreateTableRequest) (string, error) {
+	storeID, err := access.RequireStore(ctx)
+	if err != nil {
+		return "", err
+	}
+	if request.TableNumber <= 0 || request.Capacity <= 0 {
+		return "", ErrInvalidTable
+	}
+
+	area := strings.TrimSpace(request.Area)
+	if area == "" {
+		area = "main"
+	}
+
+	id, err := s.tableRepo.CreateTable(ctx, entity.DiningTable{
+		StoreID:     storeID,
+		TableNumber: request.TableNumber,
+		Capacity:    request.Capacity,
+		Area:        area,
+	})
+	if err != nil {
+		s.logger.ErrorContext(ctx, "CreateTable error", "error", err)
+		return "", err
+	}
+	return id, nil
+}
+
+// GetTables returns every table with its occupancy and the running total of its open tab
+func (s *TableService) GetTables(ctx context.Context) ([]table.GetTableResponse, error) {
+	tables, err := s.tableRepo.GetTableOccupancy(ctx, access.StoreFromContext(ctx))
+	if err != nil {
+		s.logger.ErrorContext(ctx, "Error retrieving tables", "error", err)
+		return nil, err
+	}
+
+	now := time.Now()
+	response := make([]table.GetTableResponse, 0, len(tables))
+	for _, t := range tables {
+		item := table.GetTableResponse{
+			TableID:     t.TableID,
+			StoreID:     t.StoreID,
+			TableNumber: t.TableNumber,
+			Capacity:    t.Capacity,
+			Area:        t.Area,
+			Occupied:    t.Session != nil,
+		}
+		if t.Session != nil {
+			session := &table.TableSessionResponse{
+				SessionID:     t.Session.SessionID,
+				OrderStatus:   t.OrderStatus,
+				Guests:        t.Session.Guests,
+				OpenedAt:      t.Session.OpenedAt,
+				SeatedMinutes: now.Sub(t.Session.OpenedAt).Minutes(),
+				RunningTotal:  t.RunningTotal,
+				ItemCount:     t.ItemCount,
+			}
+			if t.Session.OrderID != nil {
+				session.OrderID = *t.Session.OrderID
+			}
+			item.Session = session
+		}
+		response = append(response, item)
+	}
+
+	return response, nil
+}
+
+// OpenTab seats a party at a free table and places the first round as the tab order
+func (s *TableService) OpenTab(ctx context.Context, tableID string, request table.OpenTabRequest) (orderdto.CreateOrderResponse, error) {
+	return s.orderService.CreateOrder(ctx, orderdto.CreateOrderRequest{
+		CustomerName:        request.CustomerName,
+		SpecialInstructions: request.SpecialInstructions,
+		Items:               request.Items,
+		OrderType:           "dine_in",
+		TableID:             &tableID,
+		Guests:              request.Guests,
+	})
+}
+
+// AddTabItem adds a round of items to the open tab of a table
+func (s *TableService) AddTabItem(ctx context.Context, tableID string, request orderdto.AddOrderItemRequest) (orderdto.GetOrderResponse, error) {
+	session, err := s.openSession(ctx, tableID)
+	if err != nil {
+		return orderdto.GetOrderResponse{}, err
+	}
+	if session.OrderID == nil {
+		return orderdto.GetOrderResponse{}, ErrNoOpenTab
+	}
+
+	return s.orderService.AddOrderItem(ctx, *session.OrderID, request)
+}
+
+// CloseTab settles the tab order of a table, which frees the table
+func (s *TableService) CloseTab(ctx context.Context, tableID string, reason string) error {
+	session, err := s.openSession(ctx, tableID)
+	if err != nil {
+		return err
+	}
+
+	// A session without an order was left behind by a tab that failed to open
+	if session.OrderID == nil {
+		return s.tableRepo.DeleteSession(ctx, session.SessionID)
+	}
+
+	if reason == "" {
+		reason = "Tab closed"
+	}
+	return s.orderService.CloseOrder(ctx, *session.OrderID, reason)
+}
+
+// GetTableTurnover reports how long parties stay at each table and how much they spend
+func (s *TableService) GetTableTurnover(ctx context.Context, req table.TableTurnoverRequest) (table.TableTurnoverResponse, error) {
+	sessions, err := s.tableRepo.GetClosedSessions(ctx, access.StoreFromContext(ctx), req.StartDate, req.EndDate)
+	if err != nil {
+		s.logger.ErrorContext(ctx, "Error getting closed table sessions", "error", err)
+		return table.TableTurnoverResponse{}, err
+	}
+
+	response := table.TableTurnoverResponse{
+		StartDate: req.StartDate,
+		EndDate:   req.EndDate,
+		ByTable:   []table.TableTurnoverStats{},
+	}
+
+	byTable := make(map[string]*table.TableTurnoverStats)
+	seated := make(map[string]float64)
+	var totalSeated float64
+	for _, session := range sessions {
+		stats, ok := byTable[session.TableID]
+		if !ok {
+			stats = &table.TableTurnoverStats{
+				TableID:     session.TableID,
+				TableNumber: session.TableNumber,
+				Area:        session.Area,
+			}
+			byTable[session.TableID] = stats
+		}
+
+		minutes := session.ClosedAt.Sub(session.OpenedAt).Minutes()
+		stats.Sessions++
+		stats.Guests += session.Guests
+		stats.Revenue += session.Total
+		seated[session.TableID] += minutes
+
+		response.Sessions++
+		response.Revenue += session.Total
+		totalSeated += minutes
+	}
+
+	for id, stats := range byTable {
+		stats.AverageSeatedMinutes = seated[id] / float64(stats.Sessions)
+		stats.AverageSpend = stats.Revenue / float64(stats.Sessions)
+		response.ByTable = append(response.ByTable, *stats)
+	}
+	sort.Slice(response.ByTable, func(i, j int) bool {
+		return response.ByTable[i].TableNumber < response.ByTable[j].TableNumber
+	})
+
+	if response.Sessions > 0 {
+		response.AverageSeatedMinutes = totalSeated / float64(response.Sessions)
+	}
+
+	return response, nil
+}
+
+// openSession returns the open session of a table, distinguishing an unknown table from a free one.
+// Tables of another store are reported as unknown.
+func (s *TableService) openSession(ctx context.Context, tableID string) (entity.TableSession, error) {
+	diningTable, err := s.tableRepo.GetTableByID(ctx, tableID)
+	if err != nil {
+		return entity.TableSession{}, err
+	}
+	if !access.InStore(ctx, diningTable.StoreID) {
+		return entity.TableSession{}, sql.ErrNoRows
+	}
+
+	session, err := s.tableRepo.GetOpenSession(ctx, tableID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return entity.TableSession{}, ErrNoOpenTab
+	}
+	if err != nil {
+		return entity.TableSession{}, fmt.Errorf("error getting open session: %w", err)
+	}
+	return session, nil
+}
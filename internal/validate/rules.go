@@ -0,0 +1,130 @@
+package validate
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// builtin are the rules every validator knows; enum is added by New, as it needs the enums
+var builtin = map[string]Rule{
+	"required":         required,
+	"required_if":      requiredIf,
+	"required_without": requiredWithout,
+	"excluded_with":    excludedWith,
+	"gt":               compare(func(n, p float64) bool { return n > p }, "must be greater than %s", "must have more than %s %s"),
+	"gte":              compare(func(n, p float64) bool { return n >= p }, "must be at least %s", "must have at least %s %s"),
+	"lt":               compare(func(n, p float64) bool { return n < p }, "must be less than %s", "must have fewer than %s %s"),
+	"lte":              compare(func(n, p float64) bool { return n <= p }, "must be at most %s", "must have at most %s %s"),
+	"min":              compare(func(n, p float64) bool { return n >= p }, "must be at least %s", "must have at least %s %s"),
+	"max":              compare(func(n, p float64) bool { return n <= p }, "must be at most %s", "must have at most %s %s"),
+	"oneof":            oneOf,
+	"uuid":             uuid,
+}
+
+func required(f Field) (string, error) {
+	if f.Missing() {
+		return "is required", nil
+	}
+	return "", nil
+}
+
+// requiredIf, as required_if=OrderType dine_in, requires the field when a sibling has a value
+func requiredIf(f Field) (string, error) {
+	sibling, value, _ := strings.Cut(f.Param, " ")
+	other, name := siblingField(f, sibling)
+	if f.Missing() && other.IsValid() && fmt.Sprint(other.Interface()) == value {
+		return fmt.Sprintf("is required when %s is %s", name, value), nil
+	}
+	return "", nil
+}
+
+// requiredWithout, as required_without=Polygon, requires the field when a sibling is left out
+func requiredWithout(f Field) (string, error) {
+	other, name := siblingField(f, f.Param)
+	if f.Missing() && isMissing(other) {
+		return fmt.Sprintf("is required when %s is not set", name), nil
+	}
+	return "", nil
+}
+
+// excludedWith, as excluded_with=Category, rejects the field when a sibling is set as well
+func excludedWith(f Field) (string, error) {
+	other, name := siblingField(f, f.Param)
+	if !f.Missing() && !isMissing(other) {
+		return fmt.Sprintf("must not be set together with %s", name), nil
+	}
+	return "", nil
+}
+
+// compare builds the rules that compare numbers with their parameter; for strings, slices and
+// maps they compare the length instead
+func compare(ok func(n, param float64) bool, number, length string) Rule {
+	return func(f Field) (string, error) {
+		param, err := strconv.ParseFloat(f.Param, 64)
+		if err != nil {
+			return "", fmt.Errorf("invalid bound %q", f.Param)
+		}
+
+		switch f.Value.Kind() {
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			if !ok(float64(f.Value.Int()), param) {
+				return fmt.Sprintf(number, f.Param), nil
+			}
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+			if !ok(float64(f.Value.Uint()), param) {
+				return fmt.Sprintf(number, f.Param), nil
+			}
+		case reflect.Float32, reflect.Float64:
+			if !ok(f.Value.Float(), param) {
+				return fmt.Sprintf(number, f.Param), nil
+			}
+		case reflect.String:
+			if !ok(float64(utf8.RuneCountInString(f.Value.String())), param) {
+				return fmt.Sprintf(length, f.Param, "characters"), nil
+			}
+		case reflect.Slice, reflect.Array, reflect.Map:
+			if !ok(float64(f.Value.Len()), param) {
+				return fmt.Sprintf(length, f.Param, "items"), nil
+			}
+		}
+		return "", nil
+	}
+}
+
+// oneOf, as oneof=small medium large, accepts only the values listed
+func oneOf(f Field) (string, error) {
+	s := fmt.Sprint(f.Value.Interface())
+	values := strings.Fields(f.Param)
+	for _, value := range values {
+		if s == value {
+			return "", nil
+		}
+	}
+	return "must be one of " + strings.Join(values, ", "), nil
+}
+
+var uuidPattern = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+
+func uuid(f Field) (string, error) {
+	if f.Value.Kind() != reflect.String || !uuidPattern.MatchString(f.Value.String()) {
+		return "must be a UUID", nil
+	}
+	return "", nil
+}
+
+// siblingField finds a field of the struct holding f by its Go name, and the name it has in JSON
+func siblingField(f Field, name string) (reflect.Value, string) {
+	if f.Parent.Kind() != reflect.Struct {
+		return reflect.Value{}, name
+	}
+	sf, ok := f.Parent.Type().FieldByName(name)
+	if !ok {
+		return reflect.Value{}, name
+	}
+	jsonName, _ := jsonName(sf)
+	return indirect(f.Parent.FieldByIndex(sf.Index)), jsonName
+}
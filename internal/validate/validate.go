@@ -0,0 +1,296 @@
+package validate
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+
+	"frappuccino/internal/apperror"
+)
+
+// ErrInvalid is returned for a request that breaks the rules of its validate tags. Its fields
+// list every violation at once, named by their JSON path like items[0].quantity.
+var ErrInvalid = apperror.New(apperror.Invalid, "validation_failed", "request failed validation")
+
+// Field is the value a rule checks
+type Field struct {
+	// Value is the value of the field, with pointers followed; it is not valid for a nil pointer
+	Value reflect.Value
+	// Param is what follows the = of the rule, like the 0 of gt=0
+	Param string
+	// Parent is the struct holding the field, for rules that compare it with its siblings
+	Parent reflect.Value
+
+	raw reflect.Value
+}
+
+// Missing reports whether the field was left out: a nil pointer, or the zero value of anything else
+func (f Field) Missing() bool {
+	return isMissing(f.raw)
+}
+
+// Rule checks a field and describes what is wrong with it, or returns "" when it passes. An
+// error means the rule cannot be applied as written, like a bound that is not a number.
+type Rule func(f Field) (string, error)
+
+// Validator checks structs against the rules named in their validate tags, such as
+// `validate:"required,gt=0"`. Rules are separated by commas and apply in order; the first one a
+// field breaks is the one reported for it. Besides the rules of the validator two words steer
+// the checks: omitempty skips the rest of the rules for a zero value, and dive applies them to
+// each element of a slice instead of the slice. Nested structs are always checked; the elements
+// of a slice only with dive. A nil pointer only breaks the rules about presence, like required.
+type Validator struct {
+	mu    sync.RWMutex
+	rules map[string]Rule
+	enums map[string][]string
+}
+
+// New returns a validator with the built-in rules
+func New() *Validator {
+	v := &Validator{
+		rules: make(map[string]Rule),
+		enums: make(map[string][]string),
+	}
+	for name, rule := range builtin {
+		v.rules[name] = rule
+	}
+	v.rules["enum"] = v.enum
+	return v
+}
+
+// RegisterRule adds a rule, or replaces the one of the same name
+func (v *Validator) RegisterRule(name string, rule Rule) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	v.rules[name] = rule
+}
+
+// RegisterEnum sets the values of an enum, so that fields tagged enum=name only accept them
+func (v *Validator) RegisterEnum(name string, values ...string) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	v.enums[name] = append([]string(nil), values...)
+}
+
+// Enum returns the values of an enum, and whether it is registered
+func (v *Validator) Enum(name string) ([]string, bool) {
+	v.mu.RLock()
+	defer v.mu.RUnlock()
+	values, ok := v.enums[name]
+	return append([]string(nil), values...), ok
+}
+
+// Struct checks s, a struct or a pointer to one. It returns ErrInvalid detailed with every
+// violation, nil when there is none, or another error when a tag names an unknown rule or enum
+// or gives a rule a parameter it cannot use.
+func (v *Validator) Struct(s any) error {
+	val := reflect.ValueOf(s)
+	for val.Kind() == reflect.Pointer {
+		if val.IsNil() {
+			return nil
+		}
+		val = val.Elem()
+	}
+	if val.Kind() != reflect.Struct {
+		return fmt.Errorf("validate: %T is not a struct", s)
+	}
+
+	var violations []apperror.FieldError
+	if err := v.checkStruct(val, "", &violations); err != nil {
+		return err
+	}
+	if len(violations) == 0 {
+		return nil
+	}
+	return ErrInvalid.WithFields(violations...)
+}
+
+type rule struct {
+	name  string
+	param string
+}
+
+// presence rules also apply to nil pointers, as being left out is what they check
+var presence = map[string]bool{
+	"required":         true,
+	"required_if":      true,
+	"required_without": true,
+	"excluded_with":    true,
+}
+
+func (v *Validator) checkStruct(val reflect.Value, prefix string, violations *[]apperror.FieldError) error {
+	t := val.Type()
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		if !sf.IsExported() {
+			continue
+		}
+		name, ok := jsonName(sf)
+		if !ok {
+			continue
+		}
+
+		// Embedded structs without a JSON name of their own are flattened, as encoding/json does
+		if sf.Anonymous && sf.Tag.Get("json") == "" {
+			embedded := indirect(val.Field(i))
+			if embedded.Kind() == reflect.Struct {
+				if err := v.checkStruct(embedded, prefix, violations); err != nil {
+					return err
+				}
+			}
+			continue
+		}
+
+		rules := parseTag(sf.Tag.Get("validate"))
+		if err := v.checkValue(val, val.Field(i), rules, path(prefix, name), violations); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (v *Validator) checkValue(parent, raw reflect.Value, rules []rule, name string, violations *[]apperror.FieldError) error {
+	val := indirect(raw)
+
+	for i, r := range rules {
+		switch r.name {
+		case "omitempty":
+			if isMissing(raw) {
+				return nil
+			}
+			continue
+		case "dive":
+			if val.Kind() != reflect.Slice && val.Kind() != reflect.Array {
+				return fmt.Errorf("validate: dive on %s, which is not a slice", name)
+			}
+			for j := 0; j < val.Len(); j++ {
+				if err := v.checkValue(parent, val.Index(j), rules[i+1:], fmt.Sprintf("%s[%d]", name, j), violations); err != nil {
+					return err
+				}
+			}
+			return nil
+		}
+
+		if !val.IsValid() && !presence[r.name] {
+			continue
+		}
+
+		v.mu.RLock()
+		check, ok := v.rules[r.name]
+		_, known := v.enums[r.param]
+		v.mu.RUnlock()
+		if !ok {
+			return fmt.Errorf("validate: unknown rule %q on %s", r.name, name)
+		}
+		if r.name == "enum" && !known {
+			return fmt.Errorf("validate: enum %q of %s is not registered", r.param, name)
+		}
+
+		message, err := check(Field{Value: val, Param: r.param, Parent: parent, raw: raw})
+		if err != nil {
+			return fmt.Errorf("validate: rule %s on %s: %w", r.name, name, err)
+		}
+		if message != "" {
+			*violations = append(*violations, apperror.Field(name, message))
+			return nil
+		}
+	}
+
+	if val.Kind() == reflect.Struct {
+		return v.checkStruct(val, name, violations)
+	}
+	return nil
+}
+
+func (v *Validator) enum(f Field) (string, error) {
+	v.mu.RLock()
+	values := v.enums[f.Param]
+	v.mu.RUnlock()
+
+	s := fmt.Sprint(f.Value.Interface())
+	for _, value := range values {
+		if s == value {
+			return "", nil
+		}
+	}
+	return "must be one of " + strings.Join(values, ", "), nil
+}
+
+func parseTag(tag string) []rule {
+	if tag == "" {
+		return nil
+	}
+	var rules []rule
+	for _, part := range strings.Split(tag, ",") {
+		name, param, _ := strings.Cut(strings.TrimSpace(part), "=")
+		if name != "" {
+			rules = append(rules, rule{name: name, param: param})
+		}
+	}
+	return rules
+}
+
+// jsonName is the name of a field in request bodies; ok is false for fields JSON leaves out
+func jsonName(sf reflect.StructField) (name string, ok bool) {
+	tag := sf.Tag.Get("json")
+	if tag == "-" {
+		return "", false
+	}
+	if name, _, _ = strings.Cut(tag, ","); name != "" {
+		return name, true
+	}
+	return sf.Name, true
+}
+
+func path(prefix, name string) string {
+	if prefix == "" {
+		return name
+	}
+	return prefix + "." + name
+}
+
+func indirect(v reflect.Value) reflect.Value {
+	for v.Kind() == reflect.Pointer || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return reflect.Value{}
+		}
+		v = v.Elem()
+	}
+	return v
+}
+
+func isMissing(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Invalid:
+		return true
+	case reflect.Pointer, reflect.Interface:
+		return v.IsNil()
+	case reflect.Slice, reflect.Map, reflect.String:
+		return v.Len() == 0
+	default:
+		return v.IsZero()
+	}
+}
+
+var std = New()
+
+// Struct checks s with the validator shared by the whole program
+func Struct(s any) error {
+	return std.Struct(s)
+}
+
+// RegisterRule adds a rule to the shared validator
+func RegisterRule(name string, rule Rule) {
+	std.RegisterRule(name, rule)
+}
+
+// RegisterEnum sets the values of an enum of the shared validator
+func RegisterEnum(name string, values ...string) {
+	std.RegisterEnum(name, values...)
+}
+
+// Enum returns the values of an enum of the shared validator
+func Enum(name string) ([]string, bool) {
+	return std.Enum(name)
+}
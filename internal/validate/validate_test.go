@@ -0,0 +1,216 @@
+package validate
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"frappuccino/internal/apperror"
+)
+
+type testTable struct {
+	Number int `json:"number"`
+}
+
+type testOrder struct {
+	OrderType string     `json:"order_type" validate:"required,oneof=takeaway dine_in delivery"`
+	Table     *testTable `json:"table" validate:"required_if=OrderType dine_in"`
+	Postcodes []string   `json:"postcodes" validate:"required_without=Polygon"`
+	Polygon   []float64  `json:"polygon"`
+	ItemID    *string    `json:"item_id" validate:"omitempty,uuid"`
+	Category  *string    `json:"category" validate:"excluded_with=ItemID"`
+	Quantity  int        `json:"quantity" validate:"gt=0,lte=10"`
+	Price     *float64   `json:"price" validate:"omitempty,gte=0.5,lt=100"`
+	Name      string     `json:"name" validate:"min=2,max=5"`
+	Tags      []string   `json:"tags" validate:"max=2,dive,min=2"`
+}
+
+func strPtr(s string) *string     { return &s }
+func floatPtr(f float64) *float64 { return &f }
+
+// validOrder breaks no rule; each case changes it to break one
+func validOrder() testOrder {
+	return testOrder{
+		OrderType: "takeaway",
+		Postcodes: []string{"SP1"},
+		Quantity:  2,
+		Name:      "Ana",
+	}
+}
+
+func TestStruct(t *testing.T) {
+	tests := []struct {
+		name   string
+		change func(o *testOrder)
+		want   []apperror.FieldError // nil when the struct passes
+	}{
+		{
+			name:   "valid",
+			change: func(o *testOrder) {},
+		},
+		{
+			name:   "required",
+			change: func(o *testOrder) { o.OrderType = "" },
+			want:   []apperror.FieldError{{Field: "order_type", Message: "is required"}},
+		},
+		{
+			name:   "oneof",
+			change: func(o *testOrder) { o.OrderType = "drive_through" },
+			want:   []apperror.FieldError{{Field: "order_type", Message: "must be one of takeaway, dine_in, delivery"}},
+		},
+		{
+			name:   "required_if with the sibling at the value",
+			change: func(o *testOrder) { o.OrderType = "dine_in" },
+			want:   []apperror.FieldError{{Field: "table", Message: "is required when order_type is dine_in"}},
+		},
+		{
+			name:   "required_if met",
+			change: func(o *testOrder) { o.OrderType = "dine_in"; o.Table = &testTable{Number: 4} },
+		},
+		{
+			name:   "required_without with the sibling missing",
+			change: func(o *testOrder) { o.Postcodes = nil },
+			want:   []apperror.FieldError{{Field: "postcodes", Message: "is required when polygon is not set"}},
+		},
+		{
+			name:   "required_without with the sibling set",
+			change: func(o *testOrder) { o.Postcodes = nil; o.Polygon = []float64{51.5, -0.1} },
+		},
+		{
+			name: "excluded_with both set",
+			change: func(o *testOrder) {
+				o.ItemID = strPtr("7f9c2ba4-e88f-41d2-9e5a-8a1d3b0c6f21")
+				o.Category = strPtr("coffee")
+			},
+			want: []apperror.FieldError{{Field: "category", Message: "must not be set together with item_id"}},
+		},
+		{
+			name:   "excluded_with one set",
+			change: func(o *testOrder) { o.Category = strPtr("coffee") },
+		},
+		{
+			name:   "uuid after omitempty",
+			change: func(o *testOrder) { o.ItemID = strPtr("latte") },
+			want:   []apperror.FieldError{{Field: "item_id", Message: "must be a UUID"}},
+		},
+		{
+			name:   "gt",
+			change: func(o *testOrder) { o.Quantity = 0 },
+			want:   []apperror.FieldError{{Field: "quantity", Message: "must be greater than 0"}},
+		},
+		{
+			name:   "lte",
+			change: func(o *testOrder) { o.Quantity = 11 },
+			want:   []apperror.FieldError{{Field: "quantity", Message: "must be at most 10"}},
+		},
+		{
+			name:   "lte at the bound",
+			change: func(o *testOrder) { o.Quantity = 10 },
+		},
+		{
+			name:   "gte on a float pointer",
+			change: func(o *testOrder) { o.Price = floatPtr(0.25) },
+			want:   []apperror.FieldError{{Field: "price", Message: "must be at least 0.5"}},
+		},
+		{
+			name:   "lt on a float pointer",
+			change: func(o *testOrder) { o.Price = floatPtr(100) },
+			want:   []apperror.FieldError{{Field: "price", Message: "must be less than 100"}},
+		},
+		{
+			name:   "min on a string counts characters",
+			change: func(o *testOrder) { o.Name = "É" },
+			want:   []apperror.FieldError{{Field: "name", Message: "must have at least 2 characters"}},
+		},
+		{
+			name:   "max on a string counts characters",
+			change: func(o *testOrder) { o.Name = "Zoë Ü" },
+		},
+		{
+			name:   "max on a slice counts items",
+			change: func(o *testOrder) { o.Tags = []string{"hot", "oat", "extra"} },
+			want:   []apperror.FieldError{{Field: "tags", Message: "must have at most 2 items"}},
+		},
+		{
+			name:   "dive names the element",
+			change: func(o *testOrder) { o.Tags = []string{"hot", "x"} },
+			want:   []apperror.FieldError{{Field: "tags[1]", Message: "must have at least 2 characters"}},
+		},
+		{
+			name:   "every violation is reported",
+			change: func(o *testOrder) { o.OrderType = ""; o.Quantity = -1; o.Name = "" },
+			want: []apperror.FieldError{
+				{Field: "order_type", Message: "is required"},
+				{Field: "quantity", Message: "must be greater than 0"},
+				{Field: "name", Message: "must have at least 2 characters"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			order := validOrder()
+			tt.change(&order)
+
+			err := New().Struct(&order)
+			if tt.want == nil {
+				if err != nil {
+					t.Fatalf("Struct() = %v, want nil", err)
+				}
+				return
+			}
+
+			var appErr *apperror.Error
+			if !errors.Is(err, ErrInvalid) || !errors.As(err, &appErr) {
+				t.Fatalf("Struct() = %v, want ErrInvalid", err)
+			}
+			if !reflect.DeepEqual(appErr.Fields, tt.want) {
+				t.Errorf("Struct() fields = %+v, want %+v", appErr.Fields, tt.want)
+			}
+		})
+	}
+}
+
+func TestStructRejectsBrokenTags(t *testing.T) {
+	tests := []struct {
+		name string
+		s    any
+	}{
+		{"malformed bound", &struct {
+			Quantity int `json:"quantity" validate:"gt=abc"`
+		}{Quantity: 1}},
+		{"unknown rule", &struct {
+			Quantity int `json:"quantity" validate:"positive"`
+		}{Quantity: 1}},
+		{"unregistered enum", &struct {
+			Unit string `json:"unit" validate:"enum=unit_type"`
+		}{Unit: "grams"}},
+		{"dive on a scalar", &struct {
+			Quantity int `json:"quantity" validate:"dive,gt=0"`
+		}{Quantity: 1}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := New().Struct(tt.s)
+			if err == nil || errors.Is(err, ErrInvalid) {
+				t.Fatalf("Struct() = %v, want an error about the tag", err)
+			}
+		})
+	}
+}
+
+func TestEnum(t *testing.T) {
+	v := New()
+	v.RegisterEnum("unit_type", "grams", "milliliters")
+
+	type ingredient struct {
+		Unit string `json:"unit" validate:"enum=unit_type"`
+	}
+	if err := v.Struct(ingredient{Unit: "grams"}); err != nil {
+		t.Errorf("Struct(grams) = %v, want nil", err)
+	}
+	if err := v.Struct(ingredient{Unit: "cups"}); !errors.Is(err, ErrInvalid) {
+		t.Errorf("Struct(cups) = %v, want ErrInvalid", err)
+	}
+}